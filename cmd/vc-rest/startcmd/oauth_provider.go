@@ -11,6 +11,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/go-jose/go-jose/v3"
 	"github.com/ory/fosite"
 	"github.com/ory/fosite/compose"
 	fositeoauth2 "github.com/ory/fosite/handler/oauth2"
@@ -28,11 +29,17 @@ import (
 type oauth2ClientStore interface {
 	InsertClient(ctx context.Context, client *oauth2client.Client) (string, error)
 	GetClient(ctx context.Context, id string) (fosite.Client, error)
+	ListClients(ctx context.Context, profileID, profileVersion string, page, pageSize int) (
+		[]*oauth2client.Client, int, error)
+	SetClientDisabledAt(ctx context.Context, id string, disabledAt *time.Time) error
+	ListClientsWithJWKSURI(ctx context.Context) ([]*oauth2client.Client, error)
+	UpdateClientJWKS(ctx context.Context, clientID string, jwks *jose.JSONWebKeySet) error
 }
 
 func bootstrapOAuthProvider(
 	ctx context.Context,
 	secret string,
+	refreshTokenLifespan time.Duration,
 	transientDataStoreType string,
 	mongoClient *mongodb.Client,
 	redisClient *redis.Client,
@@ -46,7 +53,9 @@ func bootstrapOAuthProvider(
 	config.GlobalSecret = []byte(secret)
 	config.AuthorizeCodeLifespan = 30 * time.Minute
 	config.AccessTokenLifespan = 30 * time.Minute
+	config.RefreshTokenLifespan = refreshTokenLifespan
 	config.SendDebugMessagesToClients = true // TODO: Disable before moving to production.
+	config.EnablePKCEPlainChallengeMethod = true
 
 	var hmacStrategy = &fositeoauth2.HMACSHAStrategy{
 		Enigma: &hmac.HMACStrategy{
@@ -62,6 +71,7 @@ func bootstrapOAuthProvider(
 
 	return compose.Compose(config, store, hmacStrategy,
 		compose.OAuth2AuthorizeExplicitFactory,
+		compose.OAuth2RefreshTokenGrantFactory,
 		compose.OAuth2PKCEFactory,
 		compose.PushedAuthorizeHandlerFactory,
 		compose.OAuth2TokenIntrospectionFactory,
@@ -43,7 +43,7 @@ func TestBoostrapOidc(t *testing.T) {
 
 		t.Run("success", func(t *testing.T) {
 			provider, manager, err := bootstrapOAuthProvider(
-				context.TODO(), secret, "", mongoClient, nil, []oauth2client.Client{oauthClient})
+				context.TODO(), secret, time.Hour, "", mongoClient, nil, []oauth2client.Client{oauthClient})
 			assert.NoError(t, err)
 			assert.NotNil(t, provider)
 			assert.NotNil(t, manager)
@@ -56,7 +56,7 @@ func TestBoostrapOidc(t *testing.T) {
 			}
 
 			provider, manager, err := bootstrapOAuthProvider(
-				context.TODO(), secret, "", mongoClient, nil, oauthClients)
+				context.TODO(), secret, time.Hour, "", mongoClient, nil, oauthClients)
 			assert.NoError(t, err)
 			assert.NotNil(t, provider)
 			assert.NotNil(t, manager)
@@ -74,7 +74,7 @@ func TestBoostrapOidc(t *testing.T) {
 
 		t.Run("success", func(t *testing.T) {
 			provider, manager, err := bootstrapOAuthProvider(
-				context.TODO(), secret, redisStore, nil, redisClient, []oauth2client.Client{oauthClient})
+				context.TODO(), secret, time.Hour, redisStore, nil, redisClient, []oauth2client.Client{oauthClient})
 			assert.NoError(t, err)
 			assert.NotNil(t, provider)
 			assert.NotNil(t, manager)
@@ -87,7 +87,7 @@ func TestBoostrapOidc(t *testing.T) {
 			}
 
 			provider, manager, err := bootstrapOAuthProvider(
-				context.TODO(), secret, redisStore, nil, redisClient, oauthClients)
+				context.TODO(), secret, time.Hour, redisStore, nil, redisClient, oauthClients)
 			assert.NoError(t, err)
 			assert.NotNil(t, provider)
 			assert.NotNil(t, manager)
@@ -97,7 +97,7 @@ func TestBoostrapOidc(t *testing.T) {
 
 func TestBoostrapWithInvalidSecret(t *testing.T) {
 	provider, manager, err := bootstrapOAuthProvider(
-		context.TODO(), "", "", nil, nil, []oauth2client.Client{})
+		context.TODO(), "", time.Hour, "", nil, nil, []oauth2client.Client{})
 	assert.Nil(t, provider)
 	assert.Nil(t, manager)
 	assert.ErrorContains(t, err, "invalid secret")
@@ -117,7 +117,7 @@ func TestBoostrapOidcWithExpiredContext(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.TODO())
 	cancel()
 
-	provider, manager, err := bootstrapOAuthProvider(ctx, secret, "", client, nil, []oauth2client.Client{})
+	provider, manager, err := bootstrapOAuthProvider(ctx, secret, time.Hour, "", client, nil, []oauth2client.Client{})
 
 	assert.Nil(t, provider)
 	assert.Nil(t, manager)
@@ -230,6 +230,11 @@ const (
 	oAuthSecretFlagUsage     = "oauth global secret, any string. Example: secret-for-signing-and-verifying-signatures"
 	oAuthSecretFlagEnvKey    = "VC_OAUTH_SECRET"
 
+	oAuthRefreshTokenLifespanFlagName  = "oauth-refresh-token-lifespan"
+	oAuthRefreshTokenLifespanEnvKey    = "VC_OAUTH_REFRESH_TOKEN_LIFESPAN"
+	oAuthRefreshTokenLifespanFlagUsage = "How long refresh tokens issued to OAuth clients are valid. Defaults to 24h. " +
+		commonEnvVarUsageText + oAuthRefreshTokenLifespanEnvKey
+
 	transientDataStoreTypeFlagName  = "transient-data-store-type"
 	transientDataStoreTypeFlagUsage = "Transient data store type. " +
 		"For now includes Fosite oAuth data, " +
@@ -278,6 +283,18 @@ const (
 	oidc4vpNonceTTLFlagUsage = "VP nonce data TTL in OIDC4VP pre-auth code flow. Defaults to 15m. " +
 		commonEnvVarUsageText + oidc4vpNonceTTLEnvKey
 
+	oidc4ciIdempotencyKeyTTLFlagName  = "vc-oidc4ci-idempotency-key-ttl"
+	oidc4ciIdempotencyKeyTTLEnvKey    = "VC_OIDC4CI_IDEMPOTENCY_KEY_TTL"
+	oidc4ciIdempotencyKeyTTLFlagUsage = "How long OIDC4CI credential responses are kept for credential requests " +
+		"retried with the same Idempotency-Key header. Defaults to 0s, which disables Idempotency-Key support. " +
+		commonEnvVarUsageText + oidc4ciIdempotencyKeyTTLEnvKey
+
+	oauthClientJWKSRefreshIntervalFlagName  = "oauth-client-jwks-refresh-interval"
+	oauthClientJWKSRefreshIntervalEnvKey    = "VC_OAUTH_CLIENT_JWKS_REFRESH_INTERVAL"
+	oauthClientJWKSRefreshIntervalFlagUsage = "How often the key sets of OAuth clients registered with jwks_uri " +
+		"are re-fetched. Defaults to 5m. Set to 0s to disable the refresh. " +
+		commonEnvVarUsageText + oauthClientJWKSRefreshIntervalEnvKey
+
 	metricsProviderFlagName         = "metrics-provider-name"
 	metricsProviderEnvKey           = "VC_METRICS_PROVIDER_NAME"
 	allowedMetricsProviderFlagUsage = "The metrics provider name (for example: 'prometheus' etc.). " +
@@ -291,7 +308,7 @@ const (
 
 	requestObjectRepositoryTypeFlagName  = "request-object-repository-type"
 	requestObjectRepositoryTypeEnvKey    = "REQUEST_OBJECT_REPOSITORY_TYPE"
-	requestObjectRepositoryTypeFlagUsage = "Repository type for request-object. Supported: mongodb,s3. Default: mongodb"
+	requestObjectRepositoryTypeFlagUsage = "Repository type for request-object. Supported: mongodb,s3,redis. Default: mongodb"
 
 	requestObjectRepositoryS3BucketFlagName  = "request-object-repository-s3-bucket"
 	requestObjectRepositoryS3BucketEnvKey    = "REQUEST_OBJECT_REPOSITORY_S3_BUCKET"
@@ -380,6 +397,8 @@ const (
 	defaultOIDC4VPNonceDataTTL          = 15 * time.Minute
 	defaultOIDC4CITransactionDataTTL    = 15 * time.Minute
 	defaultOIDC4CIAuthStateTTL          = 15 * time.Minute
+	defaultOAuthClientJWKSRefresh       = 5 * time.Minute
+	defaultOAuthRefreshTokenLifespan    = 24 * time.Hour
 	defaultDataEncryptionKeyLength      = 256
 )
 
@@ -401,6 +420,7 @@ type startupParameters struct {
 	httpParameters                      *httpParameters
 	devMode                             bool
 	oAuthSecret                         string
+	oAuthRefreshTokenLifespan           time.Duration
 	oAuthClientsFilePath                string
 	metricsProviderName                 string
 	prometheusMetricsProviderParams     *prometheusMetricsProviderParams
@@ -426,6 +446,8 @@ type startupParameters struct {
 	dataEncryptionCompressorAlgo        string
 	enableProfiler                      bool
 	dataEncryptionDisabled              bool
+	oidc4ciIdempotencyKeyTTL            time.Duration
+	oauthClientJWKSRefreshInterval      time.Duration
 }
 
 type transientDataParams struct {
@@ -508,6 +530,12 @@ func getStartupParameters(cmd *cobra.Command) (*startupParameters, error) {
 		return nil, err
 	}
 
+	oAuthRefreshTokenLifespan, err := getDuration(cmd, oAuthRefreshTokenLifespanFlagName,
+		oAuthRefreshTokenLifespanEnvKey, defaultOAuthRefreshTokenLifespan)
+	if err != nil {
+		return nil, err
+	}
+
 	metricsProviderName, err := getMetricsProviderName(cmd)
 	if err != nil {
 		return nil, err
@@ -600,6 +628,18 @@ func getStartupParameters(cmd *cobra.Command) (*startupParameters, error) {
 		dataEncryptionDisabledEnvKey,
 	))
 
+	oidc4ciIdempotencyKeyTTL, err := getDuration(cmd, oidc4ciIdempotencyKeyTTLFlagName,
+		oidc4ciIdempotencyKeyTTLEnvKey, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	oauthClientJWKSRefreshInterval, err := getDuration(cmd, oauthClientJWKSRefreshIntervalFlagName,
+		oauthClientJWKSRefreshIntervalEnvKey, defaultOAuthClientJWKSRefresh)
+	if err != nil {
+		return nil, err
+	}
+
 	requestTokens := getRequestTokens(cmd)
 
 	loggingLevel := cmdutils.GetUserSetOptionalVarFromString(cmd, common.LogLevelFlagName, common.LogLevelEnvKey)
@@ -737,6 +777,7 @@ func getStartupParameters(cmd *cobra.Command) (*startupParameters, error) {
 		contextEnableRemote:                 contextEnableRemote,
 		devMode:                             devMode,
 		oAuthSecret:                         oAuthSecret,
+		oAuthRefreshTokenLifespan:           oAuthRefreshTokenLifespan,
 		oAuthClientsFilePath:                oAuthClientsFilePath,
 		metricsProviderName:                 metricsProviderName,
 		prometheusMetricsProviderParams:     prometheusMetricsProviderParamsVal,
@@ -761,6 +802,8 @@ func getStartupParameters(cmd *cobra.Command) (*startupParameters, error) {
 		enableProfiler:                      enableProfiler,
 		dataEncryptionCompressorAlgo:        dataEncryptionCompressionAlgo,
 		dataEncryptionDisabled:              dataEncryptionDisabled,
+		oidc4ciIdempotencyKeyTTL:            oidc4ciIdempotencyKeyTTL,
+		oauthClientJWKSRefreshInterval:      oauthClientJWKSRefreshInterval,
 		transientDataParams:                 transientDataParameters,
 	}, nil
 }
@@ -1101,6 +1144,7 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(hostURLFlagName, hostURLFlagShorthand, "", hostURLFlagUsage)
 	startCmd.Flags().StringP(apiGatewayURLFlagName, apiGatewayURLFlagShorthand, "", apiGatewayURLFlagUsage)
 	startCmd.Flags().StringP(oAuthSecretFlagName, oAuthSecretFlagShorthand, "", oAuthSecretFlagUsage)
+	startCmd.Flags().StringP(oAuthRefreshTokenLifespanFlagName, "", "", oAuthRefreshTokenLifespanFlagUsage)
 	startCmd.Flags().StringP(hostURLExternalFlagName, hostURLExternalFlagShorthand, "", hostURLExternalFlagUsage)
 	startCmd.Flags().StringP(universalResolverURLFlagName, universalResolverURLFlagShorthand, "",
 		universalResolverURLFlagUsage)
@@ -1164,6 +1208,8 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(oidc4vpNonceTTLFlagName, "", "", oidc4vpNonceTTLFlagUsage)
 	startCmd.Flags().StringP(oidc4ciTransactionDataTTLFlagName, "", "", oidc4ciTransactionDataTTLFlagUsage)
 	startCmd.Flags().StringP(oidc4ciAuthStateTTLFlagName, "", "", oidc4ciAuthStateTTLFlagUsage)
+	startCmd.Flags().StringP(oidc4ciIdempotencyKeyTTLFlagName, "", "", oidc4ciIdempotencyKeyTTLFlagUsage)
+	startCmd.Flags().StringP(oauthClientJWKSRefreshIntervalFlagName, "", "", oauthClientJWKSRefreshIntervalFlagUsage)
 
 	startCmd.Flags().StringP(otelServiceNameFlagName, "", "", otelServiceNameFlagUsage)
 	startCmd.Flags().StringP(otelExporterTypeFlagName, "", "", otelExporterTypeFlagUsage)
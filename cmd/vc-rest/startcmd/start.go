@@ -11,6 +11,7 @@ import (
 	"crypto/subtle"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -109,6 +110,7 @@ import (
 	oidc4vpclaimsstoreredis "github.com/trustbloc/vcs/pkg/storage/redis/oidc4vpclaimsstore"
 	oidc4vpnoncestoreredis "github.com/trustbloc/vcs/pkg/storage/redis/oidc4vpnoncestore"
 	oidc4vptxstoreredis "github.com/trustbloc/vcs/pkg/storage/redis/oidc4vptxstore"
+	requestobjectstoreredis "github.com/trustbloc/vcs/pkg/storage/redis/requestobjectstore"
 	"github.com/trustbloc/vcs/pkg/storage/s3/credentialoffer"
 	cslstores3 "github.com/trustbloc/vcs/pkg/storage/s3/cslvcstore"
 	requestobjectstore2 "github.com/trustbloc/vcs/pkg/storage/s3/requestobjectstore"
@@ -119,6 +121,7 @@ const (
 	statusEndpoint                  = "/status"
 	oidc4VPCheckEndpoint            = "/oidc/present"
 	defaultGracefulShutdownDuration = 1 * time.Second
+	oidc4vpTokenLifetime            = 15 * time.Minute
 	defaultHealthCheckTimeout       = 5 * time.Second
 	cslSize                         = 10000
 	devApiRequestObjectEndpoint     = "/request-object/:uuid"
@@ -630,6 +633,46 @@ func buildEchoHandler(
 		return nil, fmt.Errorf("failed to instantiate credentialOfferStore: %w", err)
 	}
 
+	var oauth2Clients []oauth2client.Client
+
+	if conf.StartupParameters.oAuthClientsFilePath != "" {
+		if oauth2Clients, err = getOAuth2Clients(conf.StartupParameters.oAuthClientsFilePath); err != nil {
+			return nil, fmt.Errorf("failed to get oauth clients: %w", err)
+		}
+	}
+
+	oauthProvider, fositeStore, err := bootstrapOAuthProvider(
+		context.Background(),
+		conf.StartupParameters.oAuthSecret,
+		conf.StartupParameters.oAuthRefreshTokenLifespan,
+		conf.StartupParameters.transientDataParams.storeType,
+		mongodbClient,
+		redisClient,
+		oauth2Clients,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate new oauth provider: %w", err)
+	}
+
+	if conf.IsTraceEnabled {
+		oauthProvider = fositetracing.Wrap(oauthProvider, conf.Tracer)
+	}
+
+	clientManager := clientmanager.New(
+		&clientmanager.Config{
+			Store:          fositeStore.(oauth2ClientStore),
+			ProfileService: issuerProfileSvc,
+			HTTPClient:     getHTTPClient(metricsProvider.ClientClientManager),
+		},
+	)
+
+	if conf.StartupParameters.oauthClientJWKSRefreshInterval > 0 {
+		clientmanager.NewJWKSRefresher(&clientmanager.JWKSRefresherConfig{
+			Store:      fositeStore.(oauth2ClientStore),
+			HTTPClient: getHTTPClient(metricsProvider.ClientClientManager),
+		}).Start(context.Background(), conf.StartupParameters.oauthClientJWKSRefreshInterval)
+	}
+
 	var oidc4ciService oidc4ci.ServiceInterface
 
 	var dataKeyEncryptor dataprotect.Crypto
@@ -644,10 +687,12 @@ func buildEchoHandler(
 		dataprotect.NewCompressor(conf.StartupParameters.dataEncryptionCompressorAlgo),
 	)
 
+	wellKnownService := wellknown.NewService(getHTTPClient(metricsProvider.ClientWellKnown))
+
 	oidc4ciService, err = oidc4ci.NewService(&oidc4ci.Config{
 		TransactionStore:              oidc4ciTransactionStore,
 		ClaimDataStore:                oidc4ciClaimDataStore,
-		WellKnownService:              wellknown.NewService(getHTTPClient(metricsProvider.ClientWellKnown)),
+		WellKnownService:              wellKnownService,
 		ProfileService:                issuerProfileSvc,
 		IssuerVCSPublicHost:           conf.StartupParameters.apiGatewayURL,
 		HTTPClient:                    getHTTPClient(metricsProvider.ClientOIDC4CI),
@@ -659,6 +704,8 @@ func buildEchoHandler(
 		DataProtector:                 claimsDataProtector,
 		KMSRegistry:                   kmsRegistry,
 		CryptoJWTSigner:               vcCrypto,
+		WalletDiscoveryClient:         wellKnownService,
+		ClientManager:                 clientManager,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to instantiate new oidc4ci service: %w", err)
@@ -695,37 +742,6 @@ func buildEchoHandler(
 		return nil, fmt.Errorf("failed to create issuer interaction client: %w", err)
 	}
 
-	var oauth2Clients []oauth2client.Client
-
-	if conf.StartupParameters.oAuthClientsFilePath != "" {
-		if oauth2Clients, err = getOAuth2Clients(conf.StartupParameters.oAuthClientsFilePath); err != nil {
-			return nil, fmt.Errorf("failed to get oauth clients: %w", err)
-		}
-	}
-
-	oauthProvider, fositeStore, err := bootstrapOAuthProvider(
-		context.Background(),
-		conf.StartupParameters.oAuthSecret,
-		conf.StartupParameters.transientDataParams.storeType,
-		mongodbClient,
-		redisClient,
-		oauth2Clients,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to instantiate new oauth provider: %w", err)
-	}
-
-	if conf.IsTraceEnabled {
-		oauthProvider = fositetracing.Wrap(oauthProvider, conf.Tracer)
-	}
-
-	clientManager := clientmanager.New(
-		&clientmanager.Config{
-			Store:          fositeStore.(oauth2ClientStore),
-			ProfileService: issuerProfileSvc,
-		},
-	)
-
 	clientIDSchemeSvc := clientidscheme.NewService(&clientidscheme.Config{
 		ClientManager:    clientManager,
 		HTTPClient:       getHTTPClient(metricsProvider.ClientDiscoverableClientIDScheme),
@@ -744,6 +760,9 @@ func buildEchoHandler(
 		JWTVerifier:             jwt.NewVerifier(jwt.KeyResolverFunc(verifiable.NewVDRKeyResolver(conf.VDR).PublicKeyFetcher())),
 		ClientManager:           clientManager,
 		ClientIDSchemeService:   clientIDSchemeSvc,
+		ProofVerifier:           oidc4ciService,
+		DiscoveryService:        oidc4ciService,
+		IdempotencyKeyTTL:       conf.StartupParameters.oidc4ciIdempotencyKeyTTL,
 		Tracer:                  conf.Tracer,
 	}))
 
@@ -837,6 +856,8 @@ func buildEchoHandler(
 		conf.StartupParameters.requestObjectRepositoryS3Bucket,
 		conf.StartupParameters.requestObjectRepositoryS3HostName,
 		mongodbClient,
+		redisClient,
+		oidc4vpTokenLifetime,
 		conf.IsTraceEnabled,
 	)
 	if err != nil {
@@ -860,7 +881,7 @@ func buildEchoHandler(
 
 	oidc4vpService = oidc4vp.NewService(&oidc4vp.Config{
 		EventSvc:                 eventSvc,
-		EventTopic:               conf.StartupParameters.verifierEventTopic,
+		EventTopics:              []string{conf.StartupParameters.verifierEventTopic},
 		TransactionManager:       oidc4vpTxManager,
 		RequestObjectPublicStore: requestObjectStoreService,
 		KMSRegistry:              kmsRegistry,
@@ -869,7 +890,8 @@ func buildEchoHandler(
 		ProfileService:           verifierProfileSvc,
 		PresentationVerifier:     verifyPresentationSvc,
 		RedirectURL:              conf.StartupParameters.apiGatewayURL + oidc4VPCheckEndpoint,
-		TokenLifetime:            15 * time.Minute,
+		PublicURL:                conf.StartupParameters.apiGatewayURL,
+		TokenLifetime:            oidc4vpTokenLifetime,
 		Metrics:                  metrics,
 	})
 
@@ -1094,9 +1116,17 @@ func createRequestObjectStore(
 	s3Bucket string,
 	s3HostName string,
 	mongoDbClient *mongodb.Client,
+	redisClient *redis.Client,
+	ttl time.Duration,
 	isTraceEnabled bool,
 ) (requestObjectStore, error) {
 	switch strings.ToLower(repoType) {
+	case redisStore:
+		if redisClient == nil {
+			return nil, errors.New("redis request object repository requires redis transient data store")
+		}
+
+		return requestobjectstoreredis.NewStore(redisClient, ttl), nil
 	case "s3":
 		cfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(s3Region))
 		if err != nil {
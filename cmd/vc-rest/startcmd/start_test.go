@@ -544,13 +544,52 @@ func TestInvalidVPReceivedClaimsDataTTLEnvVar(t *testing.T) {
 	setEnvVars(t, databaseTypeMongoDBOption, "")
 
 	defer unsetEnvVars(t)
-	require.NoError(t, os.Setenv(oidc4vpReceivedClaimsDataTTLEnvKey, "not int"))
+	t.Setenv(oidc4vpReceivedClaimsDataTTLEnvKey, "not int")
 
 	err := startCmd.Execute()
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "invalid duration")
 }
 
+func TestInvalidOIDC4CIIdempotencyKeyTTLEnvVar(t *testing.T) {
+	startCmd := GetStartCmd()
+
+	setEnvVars(t, databaseTypeMongoDBOption, "")
+
+	defer unsetEnvVars(t)
+	t.Setenv(oidc4ciIdempotencyKeyTTLEnvKey, "not a duration")
+
+	err := startCmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid value [not a duration]")
+}
+
+func TestInvalidOAuthRefreshTokenLifespanEnvVar(t *testing.T) {
+	startCmd := GetStartCmd()
+
+	setEnvVars(t, databaseTypeMongoDBOption, "")
+
+	defer unsetEnvVars(t)
+	t.Setenv(oAuthRefreshTokenLifespanEnvKey, "not a duration")
+
+	err := startCmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid value [not a duration]")
+}
+
+func TestInvalidOAuthClientJWKSRefreshIntervalEnvVar(t *testing.T) {
+	startCmd := GetStartCmd()
+
+	setEnvVars(t, databaseTypeMongoDBOption, "")
+
+	defer unsetEnvVars(t)
+	t.Setenv(oauthClientJWKSRefreshIntervalEnvKey, "not a duration")
+
+	err := startCmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid value [not a duration]")
+}
+
 func TestDidWeb(t *testing.T) {
 	v := webVDR{}
 
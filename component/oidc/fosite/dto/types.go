@@ -27,6 +27,9 @@ const (
 
 var ErrDataNotFound = errors.New("data not found")
 
+// ErrClientDisabled is returned when disabled client is requested. Fosite rejects such client as invalid.
+var ErrClientDisabled = fosite.ErrInvalidClient.WithHint("The client is disabled.")
+
 type AuthorizeRequest struct {
 	ResponseTypes        fosite.Arguments
 	RedirectURI          *url.URL
@@ -8,6 +8,7 @@ go 1.21
 
 require (
 	github.com/cenkalti/backoff/v4 v4.2.0
+	github.com/go-jose/go-jose/v3 v3.0.1-0.20221117193127-916db76e8214
 	github.com/google/uuid v1.3.0
 	github.com/ory/dockertest/v3 v3.9.1
 	github.com/ory/fosite v0.44.0
@@ -41,7 +42,6 @@ require (
 	github.com/ecordell/optgen v0.0.9 // indirect
 	github.com/fatih/structtag v1.2.0 // indirect
 	github.com/fsnotify/fsnotify v1.6.0 // indirect
-	github.com/go-jose/go-jose/v3 v3.0.1-0.20221117193127-916db76e8214 // indirect
 	github.com/go-logr/logr v1.2.3 // indirect
 	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/gogo/protobuf v1.3.2 // indirect
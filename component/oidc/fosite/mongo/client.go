@@ -11,14 +11,26 @@ import (
 	"errors"
 	"time"
 
+	"github.com/go-jose/go-jose/v3"
 	"github.com/ory/fosite"
 	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/trustbloc/vcs/component/oidc/fosite/dto"
 	"github.com/trustbloc/vcs/pkg/oauth2client"
 )
 
+const (
+	clientProfileIDField      = "record.profileid"
+	clientProfileVersionField = "record.profileversion"
+	clientCreatedAtField      = "record.createdat"
+	clientDisabledAtField     = "record.disabledat"
+	clientJWKSURIField        = "record.jsonwebkeysuri"
+	clientJWKSField           = "record.jsonwebkeys"
+)
+
 // GetClient loads the client by its ID or returns an error
 // if the client does not exist or another error occurred.
 func (s *Store) GetClient(ctx context.Context, id string) (fosite.Client, error) {
@@ -26,7 +38,16 @@ func (s *Store) GetClient(ctx context.Context, id string) (fosite.Client, error)
 		return &fosite.DefaultClient{}, nil
 	}
 
-	return getInternal[oauth2client.Client](ctx, s.mongoClient, dto.ClientsSegment, id)
+	client, err := getInternal[oauth2client.Client](ctx, s.mongoClient, dto.ClientsSegment, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsDisabled() {
+		return nil, dto.ErrClientDisabled
+	}
+
+	return client, nil
 }
 
 // ClientAssertionJWTValid returns an error if the JTI is
@@ -78,3 +99,110 @@ func (s *Store) InsertClient(ctx context.Context, client *oauth2client.Client) (
 
 	return insertedID.Hex(), nil
 }
+
+// ListClients returns a page of clients registered for the given profile, ordered by creation time,
+// and the total number of clients registered for the profile. Pages are numbered starting from 1.
+func (s *Store) ListClients(
+	ctx context.Context,
+	profileID, profileVersion string,
+	page, pageSize int,
+) ([]*oauth2client.Client, int, error) {
+	collection := s.mongoClient.Database().Collection(dto.ClientsSegment)
+
+	filter := bson.M{
+		clientProfileIDField:      profileID,
+		clientProfileVersionField: profileVersion,
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: clientCreatedAtField, Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize))
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var docs []genericDocument[*oauth2client.Client]
+
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, 0, err
+	}
+
+	clients := make([]*oauth2client.Client, 0, len(docs))
+
+	for _, doc := range docs {
+		clients = append(clients, doc.Record)
+	}
+
+	return clients, int(total), nil
+}
+
+// SetClientDisabledAt sets the time the client was disabled at. Nil enables the client.
+func (s *Store) SetClientDisabledAt(ctx context.Context, id string, disabledAt *time.Time) error {
+	collection := s.mongoClient.Database().Collection(dto.ClientsSegment)
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_lookupId": id},
+		bson.M{"$set": bson.M{clientDisabledAtField: disabledAt}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return dto.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// ListClientsWithJWKSURI returns all clients registered with a remote JWKS URI.
+func (s *Store) ListClientsWithJWKSURI(ctx context.Context) ([]*oauth2client.Client, error) {
+	collection := s.mongoClient.Database().Collection(dto.ClientsSegment)
+
+	cursor, err := collection.Find(ctx, bson.M{clientJWKSURIField: bson.M{"$nin": bson.A{nil, ""}}})
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []genericDocument[*oauth2client.Client]
+
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	clients := make([]*oauth2client.Client, 0, len(docs))
+
+	for _, doc := range docs {
+		clients = append(clients, doc.Record)
+	}
+
+	return clients, nil
+}
+
+// UpdateClientJWKS sets the key set of the client. Only the key set is written, so concurrent
+// changes to other client fields are preserved.
+func (s *Store) UpdateClientJWKS(ctx context.Context, id string, jwks *jose.JSONWebKeySet) error {
+	collection := s.mongoClient.Database().Collection(dto.ClientsSegment)
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_lookupId": id},
+		bson.M{"$set": bson.M{clientJWKSField: jwks}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return dto.ErrDataNotFound
+	}
+
+	return nil
+}
@@ -11,10 +11,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-jose/go-jose/v3"
 	"github.com/google/uuid"
 	"github.com/ory/fosite"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/trustbloc/vcs/component/oidc/fosite/dto"
 	"github.com/trustbloc/vcs/pkg/oauth2client"
 	"github.com/trustbloc/vcs/pkg/storage/mongodb"
 )
@@ -104,3 +107,130 @@ func TestInsertClient(t *testing.T) {
 
 	assert.ErrorContains(t, err, "context canceled")
 }
+
+func TestListClients(t *testing.T) {
+	pool, mongoDBResource := startMongoDBContainer(t)
+
+	defer func() {
+		assert.NoError(t, pool.Purge(mongoDBResource), "failed to purge MongoDB resource")
+	}()
+
+	client, mongoErr := mongodb.New(mongoDBConnString, "testdb", mongodb.WithTimeout(time.Second*10))
+	assert.NoError(t, mongoErr)
+
+	s, err := NewStore(context.Background(), client)
+	assert.NoError(t, err)
+
+	createdAt := time.Now().UTC().Truncate(time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		_, err = s.InsertClient(context.Background(), &oauth2client.Client{
+			ID:             uuid.New().String(),
+			ProfileID:      "profileID",
+			ProfileVersion: "v1.0",
+			CreatedAt:      createdAt.Add(time.Duration(i) * time.Second),
+		})
+		assert.NoError(t, err)
+	}
+
+	_, err = s.InsertClient(context.Background(), &oauth2client.Client{
+		ID:             uuid.New().String(),
+		ProfileID:      "otherProfileID",
+		ProfileVersion: "v1.0",
+	})
+	assert.NoError(t, err)
+
+	clients, total, err := s.ListClients(context.Background(), "profileID", "v1.0", 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, clients, 2)
+	assert.Equal(t, createdAt, clients[0].CreatedAt.UTC())
+
+	clients, total, err = s.ListClients(context.Background(), "profileID", "v1.0", 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, clients, 1)
+	assert.Equal(t, createdAt.Add(2*time.Second), clients[0].CreatedAt.UTC())
+
+	clients, total, err = s.ListClients(context.Background(), "unknownProfileID", "v1.0", 1, 2)
+	assert.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, clients)
+}
+
+func TestSetClientDisabledAt(t *testing.T) {
+	pool, mongoDBResource := startMongoDBContainer(t)
+
+	defer func() {
+		assert.NoError(t, pool.Purge(mongoDBResource), "failed to purge MongoDB resource")
+	}()
+
+	client, mongoErr := mongodb.New(mongoDBConnString, "testdb", mongodb.WithTimeout(time.Second*10))
+	assert.NoError(t, mongoErr)
+
+	s, err := NewStore(context.Background(), client)
+	assert.NoError(t, err)
+
+	clientID := uuid.New().String()
+
+	_, err = s.InsertClient(context.Background(), &oauth2client.Client{ID: clientID})
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.SetClientDisabledAt(context.Background(), clientID, lo.ToPtr(time.Now())))
+
+	_, err = s.GetClient(context.Background(), clientID)
+	assert.ErrorIs(t, err, fosite.ErrInvalidClient)
+
+	assert.NoError(t, s.SetClientDisabledAt(context.Background(), clientID, nil))
+
+	cl, err := s.GetClient(context.Background(), clientID)
+	assert.NoError(t, err)
+	assert.Equal(t, clientID, cl.GetID())
+
+	err = s.SetClientDisabledAt(context.Background(), uuid.New().String(), nil)
+	assert.ErrorIs(t, err, dto.ErrDataNotFound)
+}
+
+func TestUpdateClientJWKS(t *testing.T) {
+	pool, mongoDBResource := startMongoDBContainer(t)
+
+	defer func() {
+		assert.NoError(t, pool.Purge(mongoDBResource), "failed to purge MongoDB resource")
+	}()
+
+	client, mongoErr := mongodb.New(mongoDBConnString, "testdb", mongodb.WithTimeout(time.Second*10))
+	assert.NoError(t, mongoErr)
+
+	s, err := NewStore(context.Background(), client)
+	assert.NoError(t, err)
+
+	withURI := &oauth2client.Client{ID: uuid.New().String(), JSONWebKeysURI: "https://example.com/jwks.json"}
+
+	_, err = s.InsertClient(context.Background(), withURI)
+	assert.NoError(t, err)
+
+	_, err = s.InsertClient(context.Background(), &oauth2client.Client{ID: uuid.New().String()})
+	assert.NoError(t, err)
+
+	clients, err := s.ListClientsWithJWKSURI(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, clients, 1)
+	assert.Equal(t, withURI.ID, clients[0].ID)
+
+	jwks := &jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{KeyID: "key-1", Use: "sig"}}}
+
+	assert.NoError(t, s.UpdateClientJWKS(context.Background(), withURI.ID, jwks))
+
+	cl, err := s.GetClient(context.Background(), withURI.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "key-1", cl.(*oauth2client.Client).JSONWebKeys.Keys[0].KeyID)
+
+	assert.NoError(t, s.SetClientDisabledAt(context.Background(), withURI.ID, lo.ToPtr(time.Now())))
+	assert.NoError(t, s.UpdateClientJWKS(context.Background(), withURI.ID, jwks))
+
+	_, err = s.GetClient(context.Background(), withURI.ID)
+	assert.ErrorIs(t, err, fosite.ErrInvalidClient)
+
+	err = s.UpdateClientJWKS(context.Background(), uuid.New().String(), jwks)
+	assert.ErrorIs(t, err, dto.ErrDataNotFound)
+}
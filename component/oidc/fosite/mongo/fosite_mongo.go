@@ -62,6 +62,12 @@ func (s *Store) migrate(ctx context.Context) error {
 				},
 				Options: options.Index().SetUnique(true),
 			},
+			{
+				Keys: map[string]interface{}{
+					clientProfileIDField:      1,
+					clientProfileVersionField: 1,
+				},
+			},
 		},
 		dto.ParSegment:             baseSessionIndexes,
 		dto.AuthCodeSegment:        baseSessionIndexes,
@@ -8,10 +8,15 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 
+	"github.com/go-jose/go-jose/v3"
 	"github.com/ory/fosite"
+	redisapi "github.com/redis/go-redis/v9"
 
 	"github.com/trustbloc/vcs/component/oidc/fosite/dto"
 	"github.com/trustbloc/vcs/pkg/oauth2client"
@@ -24,7 +29,16 @@ func (s *Store) GetClient(ctx context.Context, id string) (fosite.Client, error)
 		return &fosite.DefaultClient{}, nil
 	}
 
-	return getInternal[oauth2client.Client](ctx, s.redisClient, dto.ClientsSegment, id)
+	client, err := getInternal[oauth2client.Client](ctx, s.redisClient, dto.ClientsSegment, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsDisabled() {
+		return nil, dto.ErrClientDisabled
+	}
+
+	return client, nil
 }
 
 // ClientAssertionJWTValid returns an error if the JTI is
@@ -53,3 +67,118 @@ func (s *Store) SetClientAssertionJWT(ctx context.Context, jti string, exp time.
 
 	return s.redisClient.API().Set(ctx, key, obj, exp.Sub(time.Now().UTC())).Err()
 }
+
+// ListClients returns a page of clients registered for the given profile, ordered by creation time,
+// and the total number of clients registered for the profile. Pages are numbered starting from 1.
+// Clients are found by scanning client keys, so the listing is not intended for hot paths.
+func (s *Store) ListClients(
+	ctx context.Context,
+	profileID, profileVersion string,
+	page, pageSize int,
+) ([]*oauth2client.Client, int, error) {
+	var clients []*oauth2client.Client
+
+	iter := s.redisClient.API().Scan(ctx, 0, resolveRedisKey(dto.ClientsSegment, "*"), 0).Iterator()
+
+	for iter.Next(ctx) {
+		client, err := get[oauth2client.Client](ctx, s.redisClient, iter.Val())
+		if err != nil {
+			if errors.Is(err, dto.ErrDataNotFound) {
+				continue
+			}
+
+			return nil, 0, err
+		}
+
+		if client.ProfileID == profileID && client.ProfileVersion == profileVersion {
+			clients = append(clients, client)
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	sort.SliceStable(clients, func(i, j int) bool {
+		return clients[i].CreatedAt.Before(clients[j].CreatedAt)
+	})
+
+	total := len(clients)
+	start := min((page-1)*pageSize, total)
+	end := min(start+pageSize, total)
+
+	return clients[start:end], total, nil
+}
+
+// SetClientDisabledAt sets the time the client was disabled at. Nil enables the client.
+func (s *Store) SetClientDisabledAt(ctx context.Context, id string, disabledAt *time.Time) error {
+	return s.updateClient(ctx, id, func(client *oauth2client.Client) {
+		client.DisabledAt = disabledAt
+	})
+}
+
+// ListClientsWithJWKSURI returns all clients registered with a remote JWKS URI.
+// Clients are found by scanning client keys, so the listing is not intended for hot paths.
+func (s *Store) ListClientsWithJWKSURI(ctx context.Context) ([]*oauth2client.Client, error) {
+	var clients []*oauth2client.Client
+
+	iter := s.redisClient.API().Scan(ctx, 0, resolveRedisKey(dto.ClientsSegment, "*"), 0).Iterator()
+
+	for iter.Next(ctx) {
+		client, err := get[oauth2client.Client](ctx, s.redisClient, iter.Val())
+		if err != nil {
+			if errors.Is(err, dto.ErrDataNotFound) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		if client.JSONWebKeysURI != "" {
+			clients = append(clients, client)
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+// UpdateClientJWKS sets the key set of the client.
+func (s *Store) UpdateClientJWKS(ctx context.Context, id string, jwks *jose.JSONWebKeySet) error {
+	return s.updateClient(ctx, id, func(client *oauth2client.Client) {
+		client.JSONWebKeys = jwks
+	})
+}
+
+// updateClient rewrites the stored client changed by update. The client key is watched while the client is
+// rewritten, so the update fails with redis.TxFailedErr instead of overwriting a concurrent change.
+func (s *Store) updateClient(ctx context.Context, id string, update func(client *oauth2client.Client)) error {
+	key := resolveRedisKey(dto.ClientsSegment, id)
+
+	return s.redisClient.API().Watch(ctx, func(tx *redisapi.Tx) error {
+		docBytes, err := tx.Get(ctx, key).Bytes()
+		if err != nil {
+			if errors.Is(err, redisapi.Nil) {
+				return dto.ErrDataNotFound
+			}
+
+			return err
+		}
+
+		var doc genericDocument[*oauth2client.Client]
+		if err = json.Unmarshal(docBytes, &doc); err != nil {
+			return fmt.Errorf("genericDocument unmarshal %w", err)
+		}
+
+		update(doc.Record)
+
+		_, err = tx.TxPipelined(ctx, func(pipe redisapi.Pipeliner) error {
+			return pipe.Set(ctx, key, &doc, redisapi.KeepTTL).Err()
+		})
+
+		return err
+	}, key)
+}
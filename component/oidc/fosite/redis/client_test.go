@@ -11,9 +11,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-jose/go-jose/v3"
 	"github.com/ory/fosite"
+	"github.com/pborman/uuid"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/trustbloc/vcs/component/oidc/fosite/dto"
+	"github.com/trustbloc/vcs/pkg/oauth2client"
 	"github.com/trustbloc/vcs/pkg/storage/redis"
 )
 
@@ -76,3 +81,127 @@ func TestClientAssertingWithExpiration(t *testing.T) {
 		})
 	}
 }
+
+func TestListClients(t *testing.T) {
+	pool, redisResource := startRedisContainer(t)
+
+	defer func() {
+		assert.NoError(t, pool.Purge(redisResource), "failed to purge Redis resource")
+	}()
+
+	client, err := redis.New([]string{redisConnString})
+	assert.NoError(t, err)
+
+	s := NewStore(client)
+
+	createdAt := time.Now().UTC()
+
+	for i := 0; i < 3; i++ {
+		_, err = s.InsertClient(context.Background(), &oauth2client.Client{
+			ID:             uuid.New(),
+			ProfileID:      "profileID",
+			ProfileVersion: "v1.0",
+			CreatedAt:      createdAt.Add(time.Duration(i) * time.Second),
+		})
+		assert.NoError(t, err)
+	}
+
+	_, err = s.InsertClient(context.Background(), &oauth2client.Client{
+		ID:             uuid.New(),
+		ProfileID:      "otherProfileID",
+		ProfileVersion: "v1.0",
+	})
+	assert.NoError(t, err)
+
+	clients, total, err := s.ListClients(context.Background(), "profileID", "v1.0", 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, clients, 2)
+	assert.True(t, createdAt.Equal(clients[0].CreatedAt))
+
+	clients, total, err = s.ListClients(context.Background(), "profileID", "v1.0", 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, clients, 1)
+	assert.True(t, createdAt.Add(2*time.Second).Equal(clients[0].CreatedAt))
+
+	clients, total, err = s.ListClients(context.Background(), "profileID", "v1.0", 3, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Empty(t, clients)
+}
+
+func TestSetClientDisabledAt(t *testing.T) {
+	pool, redisResource := startRedisContainer(t)
+
+	defer func() {
+		assert.NoError(t, pool.Purge(redisResource), "failed to purge Redis resource")
+	}()
+
+	client, err := redis.New([]string{redisConnString})
+	assert.NoError(t, err)
+
+	s := NewStore(client)
+
+	clientID := uuid.New()
+
+	_, err = s.InsertClient(context.Background(), &oauth2client.Client{ID: clientID})
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.SetClientDisabledAt(context.Background(), clientID, lo.ToPtr(time.Now())))
+
+	_, err = s.GetClient(context.Background(), clientID)
+	assert.ErrorIs(t, err, fosite.ErrInvalidClient)
+
+	assert.NoError(t, s.SetClientDisabledAt(context.Background(), clientID, nil))
+
+	cl, err := s.GetClient(context.Background(), clientID)
+	assert.NoError(t, err)
+	assert.Equal(t, clientID, cl.GetID())
+
+	err = s.SetClientDisabledAt(context.Background(), uuid.New(), nil)
+	assert.ErrorIs(t, err, dto.ErrDataNotFound)
+}
+
+func TestUpdateClientJWKS(t *testing.T) {
+	pool, redisResource := startRedisContainer(t)
+
+	defer func() {
+		assert.NoError(t, pool.Purge(redisResource), "failed to purge Redis resource")
+	}()
+
+	client, err := redis.New([]string{redisConnString})
+	assert.NoError(t, err)
+
+	s := NewStore(client)
+
+	withURI := &oauth2client.Client{ID: uuid.New(), JSONWebKeysURI: "https://example.com/jwks.json"}
+
+	_, err = s.InsertClient(context.Background(), withURI)
+	assert.NoError(t, err)
+
+	_, err = s.InsertClient(context.Background(), &oauth2client.Client{ID: uuid.New()})
+	assert.NoError(t, err)
+
+	clients, err := s.ListClientsWithJWKSURI(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, clients, 1)
+	assert.Equal(t, withURI.ID, clients[0].ID)
+
+	jwks := &jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{KeyID: "key-1", Use: "sig"}}}
+
+	assert.NoError(t, s.UpdateClientJWKS(context.Background(), withURI.ID, jwks))
+
+	cl, err := s.GetClient(context.Background(), withURI.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "key-1", cl.(*oauth2client.Client).JSONWebKeys.Keys[0].KeyID)
+
+	assert.NoError(t, s.SetClientDisabledAt(context.Background(), withURI.ID, lo.ToPtr(time.Now())))
+	assert.NoError(t, s.UpdateClientJWKS(context.Background(), withURI.ID, jwks))
+
+	_, err = s.GetClient(context.Background(), withURI.ID)
+	assert.ErrorIs(t, err, fosite.ErrInvalidClient)
+
+	err = s.UpdateClientJWKS(context.Background(), uuid.New(), jwks)
+	assert.ErrorIs(t, err, dto.ErrDataNotFound)
+}
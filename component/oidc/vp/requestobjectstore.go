@@ -10,8 +10,10 @@ package vp
 
 import (
 	"context"
+	"errors"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/trustbloc/vcs/pkg/event/spi"
 	"github.com/trustbloc/vcs/pkg/service/requestobject"
@@ -49,15 +51,24 @@ func NewRequestObjectStore(
 	}
 }
 
+// Publish stores request object and returns its URI. If ttl is non-zero, the request object
+// can't be fetched once ttl elapses.
 func (s *RequestObjectStore) Publish(
 	ctx context.Context,
 	requestObject string,
 	accessRequestObjectEvent *spi.Event,
+	ttl time.Duration,
 ) (string, error) {
-	resp, err := s.repo.Create(ctx, requestobject.RequestObject{
+	ro := requestobject.RequestObject{
 		Content:                  requestObject,
 		AccessRequestObjectEvent: accessRequestObjectEvent,
-	})
+	}
+
+	if ttl > 0 {
+		ro.ExpireAt = time.Now().UTC().Add(ttl)
+	}
+
+	resp, err := s.repo.Create(ctx, ro)
 	if err != nil {
 		return "", err
 	}
@@ -74,10 +85,27 @@ func (s *RequestObjectStore) Remove(
 	ctx context.Context,
 	id string,
 ) error {
-	splitResult := strings.Split(id, "/")
-	lastSegment := splitResult[len(splitResult)-1]
+	return s.repo.Delete(ctx, lastSegment(id))
+}
+
+// Retrieve returns the content of the request object with the given ID or URI. Unlike Get, it doesn't publish
+// the access request object event. Returns requestobject.ErrObjectExpiredOrNotFound if the request object
+// doesn't exist or has expired.
+func (s *RequestObjectStore) Retrieve(ctx context.Context, objectID string) (string, error) {
+	result, err := s.repo.Find(ctx, lastSegment(objectID))
+	if err != nil {
+		if errors.Is(err, requestobject.ErrDataNotFound) {
+			return "", requestobject.ErrObjectExpiredOrNotFound
+		}
 
-	return s.repo.Delete(ctx, lastSegment)
+		return "", err
+	}
+
+	if !result.ExpireAt.IsZero() && result.ExpireAt.Before(time.Now().UTC()) {
+		return "", requestobject.ErrObjectExpiredOrNotFound
+	}
+
+	return result.Content, nil
 }
 
 func (s *RequestObjectStore) Get(ctx context.Context, id string) (*requestobject.RequestObject, error) {
@@ -86,6 +114,10 @@ func (s *RequestObjectStore) Get(ctx context.Context, id string) (*requestobject
 		return nil, err
 	}
 
+	if !result.ExpireAt.IsZero() && result.ExpireAt.Before(time.Now().UTC()) {
+		return nil, requestobject.ErrDataNotFound
+	}
+
 	err = s.eventSvc.Publish(ctx, s.eventTopic, result.AccessRequestObjectEvent)
 	if err != nil {
 		return nil, err
@@ -93,3 +125,9 @@ func (s *RequestObjectStore) Get(ctx context.Context, id string) (*requestobject
 
 	return result, nil
 }
+
+func lastSegment(id string) string {
+	splitResult := strings.Split(id, "/")
+
+	return splitResult[len(splitResult)-1]
+}
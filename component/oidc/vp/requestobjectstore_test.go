@@ -12,6 +12,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -49,7 +50,7 @@ func TestRequestObjectStore(t *testing.T) {
 
 		store := NewRequestObjectStore(repo, eventSvc, uri, spi.VerifierEventTopic)
 
-		finalURI, err := store.Publish(context.TODO(), string(dataBytes), &spi.Event{})
+		finalURI, err := store.Publish(context.TODO(), string(dataBytes), &spi.Event{}, 0)
 
 		assert.NoError(t, err)
 
@@ -73,13 +74,39 @@ func TestRequestObjectStore(t *testing.T) {
 
 		store := NewRequestObjectStore(repo, eventSvc, uri, spi.VerifierEventTopic)
 
-		finalURI, err := store.Publish(context.TODO(), string(dataBytes), &spi.Event{})
+		finalURI, err := store.Publish(context.TODO(), string(dataBytes), &spi.Event{}, 0)
 
 		assert.NoError(t, err)
 
 		assert.Equal(t, "https://awesome-url/resources/2135321", finalURI)
 	})
 
+	t.Run("Test Publish with ttl", func(t *testing.T) {
+		randomID := "2135321"
+
+		repo := NewMockRequestObjectStoreRepository(gomock.NewController(t))
+		repo.EXPECT().Create(context.TODO(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, request requestobject.RequestObject) (*requestobject.RequestObject, error) {
+				assert.Equal(t, strData, request.Content)
+				assert.WithinDuration(t, time.Now().UTC().Add(time.Minute), request.ExpireAt, time.Second)
+
+				request.ID = randomID
+
+				return &request, nil
+			})
+		repo.EXPECT().GetResourceURL(randomID).Return("")
+
+		eventSvc := NewMockEventService(gomock.NewController(t))
+
+		store := NewRequestObjectStore(repo, eventSvc, uri, spi.VerifierEventTopic)
+
+		finalURI, err := store.Publish(context.TODO(), string(dataBytes), &spi.Event{}, time.Minute)
+
+		assert.NoError(t, err)
+
+		assert.Equal(t, fmt.Sprintf("%s/%s", uri, randomID), finalURI)
+	})
+
 	t.Run("Publish with error", func(t *testing.T) {
 		errorStr := "unexpected error"
 
@@ -90,7 +117,7 @@ func TestRequestObjectStore(t *testing.T) {
 
 		store := NewRequestObjectStore(repo, eventSvc, uri, spi.VerifierEventTopic)
 
-		finalURI, err := store.Publish(context.TODO(), string(dataBytes), &spi.Event{})
+		finalURI, err := store.Publish(context.TODO(), string(dataBytes), &spi.Event{}, 0)
 		assert.Empty(t, finalURI)
 		assert.ErrorContains(t, err, errorStr)
 	})
@@ -113,6 +140,23 @@ func TestRequestObjectStore(t *testing.T) {
 		assert.Equal(t, id, resp.ID)
 	})
 
+	t.Run("Get expired", func(t *testing.T) {
+		id := "21342315231w"
+		repo := NewMockRequestObjectStoreRepository(gomock.NewController(t))
+		repo.EXPECT().Find(gomock.Any(), gomock.Any()).Return(&requestobject.RequestObject{
+			ID:       id,
+			ExpireAt: time.Now().UTC().Add(-time.Minute),
+		}, nil)
+
+		eventSvc := NewMockEventService(gomock.NewController(t))
+
+		store := NewRequestObjectStore(repo, eventSvc, uri, spi.VerifierEventTopic)
+
+		_, err := store.Get(context.TODO(), id)
+
+		assert.ErrorIs(t, err, requestobject.ErrDataNotFound)
+	})
+
 	t.Run("Get store failed", func(t *testing.T) {
 		id := "21342315231w"
 		repo := NewMockRequestObjectStoreRepository(gomock.NewController(t))
@@ -177,3 +221,60 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestRetrieve(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := NewMockRequestObjectStoreRepository(gomock.NewController(t))
+		repo.EXPECT().Find(gomock.Any(), "2131421312").Return(&requestobject.RequestObject{
+			ID:       "2131421312",
+			Content:  "request object",
+			ExpireAt: time.Now().UTC().Add(time.Minute),
+		}, nil)
+
+		eventSvc := NewMockEventService(gomock.NewController(t))
+		eventSvc.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		store := NewRequestObjectStore(repo, eventSvc, "", spi.VerifierEventTopic)
+
+		content, err := store.Retrieve(context.TODO(), "https://example.com/some/endpoint/2131421312")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "request object", content)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		repo := NewMockRequestObjectStoreRepository(gomock.NewController(t))
+		repo.EXPECT().Find(gomock.Any(), "2131421312").Return(nil, requestobject.ErrDataNotFound)
+
+		store := NewRequestObjectStore(repo, NewMockEventService(gomock.NewController(t)), "", spi.VerifierEventTopic)
+
+		_, err := store.Retrieve(context.TODO(), "2131421312")
+
+		assert.ErrorIs(t, err, requestobject.ErrObjectExpiredOrNotFound)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		repo := NewMockRequestObjectStoreRepository(gomock.NewController(t))
+		repo.EXPECT().Find(gomock.Any(), "2131421312").Return(&requestobject.RequestObject{
+			ID:       "2131421312",
+			ExpireAt: time.Now().UTC().Add(-time.Minute),
+		}, nil)
+
+		store := NewRequestObjectStore(repo, NewMockEventService(gomock.NewController(t)), "", spi.VerifierEventTopic)
+
+		_, err := store.Retrieve(context.TODO(), "2131421312")
+
+		assert.ErrorIs(t, err, requestobject.ErrObjectExpiredOrNotFound)
+	})
+
+	t.Run("Store failed", func(t *testing.T) {
+		repo := NewMockRequestObjectStoreRepository(gomock.NewController(t))
+		repo.EXPECT().Find(gomock.Any(), "2131421312").Return(nil, errors.New("store failed"))
+
+		store := NewRequestObjectStore(repo, NewMockEventService(gomock.NewController(t)), "", spi.VerifierEventTopic)
+
+		_, err := store.Retrieve(context.TODO(), "2131421312")
+
+		assert.ErrorContains(t, err, "store failed")
+	})
+}
@@ -47,6 +47,8 @@ type oidc4ciCommandFlags struct {
 	Pin                      string
 	DiscoverableClientID     bool
 	JWTSignedCredentialOffer bool
+	SignHTTPRequests         bool
+	ProxyURL                 string
 
 	WalletUserId     string
 	WalletPassPhrase string
@@ -149,6 +151,8 @@ func NewOIDC4CICommand() *cobra.Command {
 					c.TLS.InsecureSkipVerify = flags.InsecureTls
 					c.DidMethod = flags.DidMethod
 					c.DidKeyType = flags.DidKeyType
+					c.SignHTTPRequests = flags.SignHTTPRequests
+					c.ProxyURL = flags.ProxyURL
 				},
 			}
 
@@ -207,6 +211,8 @@ func NewOIDC4CICommand() *cobra.Command {
 	cmd.Flags().BoolVar(&flags.InsecureTls, "insecure", false, "this option allows to skip the verification of ssl\\tls")
 	cmd.Flags().BoolVar(&flags.DiscoverableClientID, "discoverable-client-id", false, "use discoverable client id scheme")
 	cmd.Flags().BoolVar(&flags.JWTSignedCredentialOffer, "jwt-signed-credential-offer", false, "allow wallet cli to parse JWT signed credential offer")
+	cmd.Flags().BoolVar(&flags.SignHTTPRequests, "sign-http-requests", false, "sign requests to the issuer with RFC 9421 HTTP message signatures")
+	cmd.Flags().StringVar(&flags.ProxyURL, "proxy-url", "", "route outbound HTTP requests through the proxy")
 
 	cmd.Flags().StringVar(&flags.WalletUserId, "wallet-user-id", "", "existing wallet user id")
 	cmd.Flags().StringVar(&flags.WalletPassPhrase, "wallet-passphrase", "", "existing wallet pass phrase")
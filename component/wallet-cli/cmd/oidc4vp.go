@@ -14,6 +14,7 @@ import (
 	_ "image/jpeg"
 	_ "image/png"
 	"os"
+	"time"
 
 	"github.com/makiuchi-d/gozxing"
 	gozxingqr "github.com/makiuchi-d/gozxing/qrcode"
@@ -33,6 +34,8 @@ type oidc4vpCommandFlags struct {
 	WalletDidKeyID                  string
 	WalletDidID                     string
 	LinkedDomainVerificationEnabled bool
+	LinkedDomainVerificationTimeout time.Duration
+	ProxyURL                        string
 
 	InsecureTls bool
 	DidMethod   string
@@ -110,6 +113,8 @@ func createFlags(cmd *cobra.Command, flags *oidc4vpCommandFlags) {
 	cmd.Flags().StringVar(&flags.DidKeyType, "did-key-type", "ECDSAP384DER", "did key type. default: ECDSAP384DER")
 
 	cmd.Flags().BoolVar(&flags.LinkedDomainVerificationEnabled, "linked-domain-verification-enabled", false, "enables Linked Domain Verification")
+	cmd.Flags().DurationVar(&flags.LinkedDomainVerificationTimeout, "linked-domain-verification-timeout", 0, "timeout of Linked Domain Verification requests. default: 10s")
+	cmd.Flags().StringVar(&flags.ProxyURL, "proxy-url", "", "route outbound HTTP requests through the proxy")
 }
 
 type runnerConfig struct {
@@ -198,6 +203,8 @@ func getWalletRunnerConfig(cmd *cobra.Command, flags *oidc4vpCommandFlags) (*run
 		c.DidMethod = flags.DidMethod
 		c.DidKeyType = flags.DidKeyType
 		c.LinkedDomainVerificationEnabled = flags.LinkedDomainVerificationEnabled
+		c.LinkedDomainVerificationTimeout = flags.LinkedDomainVerificationTimeout
+		c.ProxyURL = flags.ProxyURL
 	})
 
 	return &runnerConfig{
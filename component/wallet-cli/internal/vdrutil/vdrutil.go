@@ -100,6 +100,90 @@ func (v *VDRUtil) CreateJWK(keyType kms.KeyType, registry vdrapi.Registry, keyMa
 	}, nil
 }
 
+// CreateWeb creates a did:web DID for the domain and registers its DID document in the registry. The document
+// still has to be served from https://<domain>/.well-known/did.json for other parties to resolve it.
+func (v *VDRUtil) CreateWeb(
+	domain string,
+	keyType kms.KeyType,
+	registry vdrapi.Registry,
+	keyManager keyManager,
+) (*CreateResult, error) {
+	verMethod, err := v.newVerMethods(1, keyManager, keyType)
+	if err != nil {
+		return nil, fmt.Errorf("did:web: failed to create new ver method: %w", err)
+	}
+
+	didID := "did:web:" + strings.ReplaceAll(domain, ":", "%3A")
+	keyID := verMethod[0].ID
+
+	vm := verMethod[0]
+	vm.ID = didID + "#" + keyID
+	vm.Controller = didID
+
+	didResolution, err := registry.Create(
+		"web",
+		&did.Doc{
+			Context:            []string{did.ContextV1},
+			ID:                 didID,
+			VerificationMethod: []did.VerificationMethod{*vm},
+			AssertionMethod: []did.Verification{
+				*did.NewReferencedVerification(vm, did.AssertionMethod),
+			},
+			Authentication: []did.Verification{
+				*did.NewReferencedVerification(vm, did.Authentication),
+			},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("did:web: failed to create did: %w", err)
+	}
+
+	return &CreateResult{
+		DidID: didResolution.DIDDocument.ID,
+		KeyID: didResolution.DIDDocument.ID + "#" + keyID,
+	}, nil
+}
+
+// RotateWebKey adds a new key to the registered DID document of the did:web DID and makes it the only
+// authentication and assertion method. Previous verification methods are kept in the document, so that
+// proofs made with the old keys can still be resolved.
+func (v *VDRUtil) RotateWebKey(
+	didID string,
+	keyType kms.KeyType,
+	registry vdrapi.Registry,
+	keyManager keyManager,
+) (*CreateResult, error) {
+	docRes, err := registry.Resolve(didID)
+	if err != nil {
+		return nil, fmt.Errorf("did:web: failed to resolve %s: %w", didID, err)
+	}
+
+	verMethod, err := v.newVerMethods(1, keyManager, keyType)
+	if err != nil {
+		return nil, fmt.Errorf("did:web: failed to create new ver method: %w", err)
+	}
+
+	keyID := verMethod[0].ID
+
+	vm := verMethod[0]
+	vm.ID = didID + "#" + keyID
+	vm.Controller = didID
+
+	doc := *docRes.DIDDocument
+	doc.VerificationMethod = append(append([]did.VerificationMethod{}, doc.VerificationMethod...), *vm)
+	doc.AssertionMethod = []did.Verification{*did.NewReferencedVerification(vm, did.AssertionMethod)}
+	doc.Authentication = []did.Verification{*did.NewReferencedVerification(vm, did.Authentication)}
+
+	if _, err = registry.Create("web", &doc); err != nil {
+		return nil, fmt.Errorf("did:web: failed to update did: %w", err)
+	}
+
+	return &CreateResult{
+		DidID: didID,
+		KeyID: vm.ID,
+	}, nil
+}
+
 func (v *VDRUtil) createION(keyType kms.KeyType, registry vdrapi.Registry, keyManager keyManager) (*CreateResult, error) {
 	verMethod, err := v.newVerMethods(1, keyManager, keyType)
 	if err != nil {
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package walletrunner
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	jsonld "github.com/piprate/json-gold/ld"
@@ -16,6 +18,14 @@ import (
 	"github.com/trustbloc/kms-go/spi/storage"
 )
 
+const (
+	// healthCheckDID is a did:key resolved locally to check that VDR registry is functional.
+	healthCheckDID        = "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+	healthCheckStoreName  = "healthcheck"
+	healthCheckStoreKey   = "ping"
+	healthCheckStoreValue = "pong"
+)
+
 type ariesServices struct {
 	storageProvider   storage.Provider
 	vdrRegistry       vdrapi.Registry
@@ -70,3 +80,55 @@ func (p *ariesServices) Close() error {
 
 	return nil
 }
+
+// HealthCheck checks that VDR registry and storage are functional. Returned error lists all unhealthy subsystems.
+func (p *ariesServices) HealthCheck(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	if err := p.checkVDRRegistry(); err != nil {
+		errs = append(errs, fmt.Errorf("vdr registry: %w", err))
+	}
+
+	if err := p.checkStorage(); err != nil {
+		errs = append(errs, fmt.Errorf("storage: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *ariesServices) checkVDRRegistry() error {
+	if p.vdrRegistry == nil {
+		return errors.New("not initialized")
+	}
+
+	if _, err := p.vdrRegistry.Resolve(healthCheckDID); err != nil {
+		return fmt.Errorf("resolve %s: %w", healthCheckDID, err)
+	}
+
+	return nil
+}
+
+func (p *ariesServices) checkStorage() error {
+	if p.storageProvider == nil {
+		return errors.New("not initialized")
+	}
+
+	store, err := p.storageProvider.OpenStore(healthCheckStoreName)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+
+	if err = store.Put(healthCheckStoreKey, []byte(healthCheckStoreValue)); err != nil {
+		return fmt.Errorf("put: %w", err)
+	}
+
+	if _, err = store.Get(healthCheckStoreKey); err != nil {
+		return fmt.Errorf("get: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/legacy/mem"
+	"github.com/trustbloc/did-go/method/key"
+	"github.com/trustbloc/did-go/vdr"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+	"github.com/trustbloc/kms-go/spi/storage"
+)
+
+func TestAriesServicesHealthCheck(t *testing.T) {
+	t.Run("Healthy", func(t *testing.T) {
+		services := &ariesServices{
+			storageProvider: mem.NewProvider(),
+			vdrRegistry:     vdr.New(vdr.WithVDR(key.New())),
+		}
+
+		require.NoError(t, services.HealthCheck(context.Background()))
+	})
+
+	t.Run("VDR failure", func(t *testing.T) {
+		services := &ariesServices{
+			storageProvider: mem.NewProvider(),
+			vdrRegistry:     vdr.New(),
+		}
+
+		err := services.HealthCheck(context.Background())
+		require.ErrorContains(t, err, "vdr registry: resolve "+healthCheckDID)
+		require.NotContains(t, err.Error(), "storage")
+	})
+
+	t.Run("Storage failure", func(t *testing.T) {
+		storageErr := errors.New("storage unavailable")
+
+		services := &ariesServices{
+			storageProvider: &failingStorageProvider{Provider: mem.NewProvider(), err: storageErr},
+			vdrRegistry:     vdr.New(vdr.WithVDR(key.New())),
+		}
+
+		err := services.HealthCheck(context.Background())
+		require.ErrorIs(t, err, storageErr)
+		require.ErrorContains(t, err, "storage: open store: storage unavailable")
+		require.NotContains(t, err.Error(), "vdr registry")
+	})
+
+	t.Run("VDR and storage failure", func(t *testing.T) {
+		vdrErr := errors.New("vdr unavailable")
+		storageErr := errors.New("storage unavailable")
+
+		services := &ariesServices{
+			storageProvider: &failingStorageProvider{Provider: mem.NewProvider(), err: storageErr},
+			vdrRegistry:     &failingVDRRegistry{Registry: vdr.New(), err: vdrErr},
+		}
+
+		err := services.HealthCheck(context.Background())
+		require.ErrorIs(t, err, vdrErr)
+		require.ErrorIs(t, err, storageErr)
+		require.EqualError(t, err, "vdr registry: resolve "+healthCheckDID+": vdr unavailable\n"+
+			"storage: open store: storage unavailable")
+	})
+
+	t.Run("Not initialized", func(t *testing.T) {
+		err := (&ariesServices{}).HealthCheck(context.Background())
+		require.EqualError(t, err, "vdr registry: not initialized\nstorage: not initialized")
+	})
+
+	t.Run("Context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := (&ariesServices{}).HealthCheck(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+type failingStorageProvider struct {
+	storage.Provider
+	err error
+}
+
+func (p *failingStorageProvider) OpenStore(string) (storage.Store, error) {
+	return nil, p.err
+}
+
+type failingVDRRegistry struct {
+	vdrapi.Registry
+	err error
+}
+
+func (r *failingVDRRegistry) Resolve(string, ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	return nil, r.err
+}
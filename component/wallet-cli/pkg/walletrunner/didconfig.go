@@ -7,8 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package walletrunner
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	didconfigclient "github.com/trustbloc/vc-go/didconfig/client"
@@ -22,6 +24,18 @@ type serviceEndpoint struct {
 	Origins []string `json:"origins"`
 }
 
+// UnmarshalJSON unmarshals service endpoint expressed either as {"origins": [...]} object
+// or as an array of origin strings.
+func (e *serviceEndpoint) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return json.Unmarshal(trimmed, &e.Origins)
+	}
+
+	type endpoint serviceEndpoint
+
+	return json.Unmarshal(data, (*endpoint)(e))
+}
+
 func (s *Service) runLinkedDomainVerification(didID string) error {
 	didDocResolution, vdrErr := s.ariesServices.vdrRegistry.Resolve(didID)
 	if vdrErr != nil {
@@ -45,10 +59,14 @@ func (s *Service) runLinkedDomainVerification(didID string) error {
 			return err
 		}
 
+		if len(serviceEndpoint.Origins) == 0 {
+			return fmt.Errorf("no origins in LinkedDomains service endpoint of DID %s", didID)
+		}
+
 		didConfigurationClient := didconfigclient.New(
 			didconfigclient.WithJSONLDDocumentLoader(s.ariesServices.documentLoader),
 			didconfigclient.WithVDRegistry(s.ariesServices.vdrRegistry),
-			didconfigclient.WithHTTPClient(s.httpClient),
+			didconfigclient.WithHTTPClient(s.linkedDomainHTTPClient),
 		)
 
 		if err = didConfigurationClient.VerifyDIDAndDomain(didID,
@@ -62,23 +80,59 @@ func (s *Service) runLinkedDomainVerification(didID string) error {
 	return fmt.Errorf("no LinkedDomains service in DID %s", didID)
 }
 
+// getServiceType returns LinkedDomains if it is one of the service types, otherwise the first service type.
 func getServiceType(serviceType interface{}) string {
-	var val string
+	var types []string
 
 	switch t := serviceType.(type) {
 	case string:
-		val = t
+		types = []string{t}
 	case []string:
-		if len(t) > 0 {
-			val = t[0]
-		}
+		types = t
 	case []interface{}:
-		if len(t) > 0 {
-			if str, ok := t[0].(string); ok {
-				val = str
+		for _, v := range t {
+			if str, ok := v.(string); ok {
+				types = append(types, str)
 			}
 		}
+	case map[string]interface{}:
+		if str := getNodeType(t); str != "" {
+			types = []string{str}
+		}
+	}
+
+	for _, t := range types {
+		if t == linkedDomainsService {
+			return t
+		}
+	}
+
+	if len(types) > 0 {
+		return types[0]
+	}
+
+	return ""
+}
+
+// getNodeType returns the type of a JSON-LD typed node, i.e. its @id, falling back to the first string value
+// of the node in key order.
+func getNodeType(node map[string]interface{}) string {
+	if id, ok := node["@id"].(string); ok {
+		return id
+	}
+
+	keys := make([]string, 0, len(node))
+	for k := range node {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if str, ok := node[k].(string); ok {
+			return str
+		}
 	}
 
-	return val
+	return ""
 }
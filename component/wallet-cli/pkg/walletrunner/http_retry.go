@@ -0,0 +1,109 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/trustbloc/vcs/component/wallet-cli/pkg/walletrunner/vcprovider"
+)
+
+const (
+	defaultRetryInitialBackoff = 500 * time.Millisecond
+	defaultRetryMaxBackoff     = 10 * time.Second
+)
+
+// retryTransport retries requests that failed with a network error or with 429 Too Many Requests or
+// 503 Service Unavailable status, waiting with exponential backoff between attempts. Other responses,
+// e.g. 400 or 401, are returned as is.
+type retryTransport struct {
+	base           http.RoundTripper
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	sleep          func(d time.Duration) <-chan time.Time
+}
+
+// newRetryTransport wraps base with retryTransport. Base is returned unchanged if retries are not configured.
+func newRetryTransport(base http.RoundTripper, conf *vcprovider.RetryConfig) http.RoundTripper {
+	if conf == nil || conf.MaxAttempts <= 1 {
+		return base
+	}
+
+	t := &retryTransport{
+		base:           base,
+		maxAttempts:    conf.MaxAttempts,
+		initialBackoff: conf.InitialBackoff,
+		maxBackoff:     conf.MaxBackoff,
+		sleep:          time.After,
+	}
+
+	if t.initialBackoff <= 0 {
+		t.initialBackoff = defaultRetryInitialBackoff
+	}
+
+	if t.maxBackoff <= 0 {
+		t.maxBackoff = defaultRetryMaxBackoff
+	}
+
+	return t
+}
+
+// RoundTrip executes the request with the base transport, retrying transient failures.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a request body that can't be recreated can be sent only once
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	backoff := t.initialBackoff
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if attempt == t.maxAttempts || !isRetryable(resp, err) {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body) //nolint:errcheck
+			_ = resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-t.sleep(backoff):
+		}
+
+		backoff *= 2
+		if backoff > t.maxBackoff {
+			backoff = t.maxBackoff
+		}
+	}
+}
+
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+}
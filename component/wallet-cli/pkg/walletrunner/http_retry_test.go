@@ -0,0 +1,182 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/vcs/component/wallet-cli/pkg/walletrunner/vcprovider"
+)
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newTestRetryTransport(
+	t *testing.T,
+	conf *vcprovider.RetryConfig,
+	statuses []int,
+	errs []error,
+) (*retryTransport, *[]string, *[]time.Duration) {
+	t.Helper()
+
+	var (
+		bodies  []string
+		backoff []time.Duration
+	)
+
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempt := len(bodies)
+
+		body := ""
+		if req.Body != nil {
+			b, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+
+			body = string(b)
+		}
+
+		bodies = append(bodies, body)
+
+		if attempt < len(errs) && errs[attempt] != nil {
+			return nil, errs[attempt]
+		}
+
+		return &http.Response{
+			StatusCode: statuses[attempt],
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	})
+
+	rt, ok := newRetryTransport(base, conf).(*retryTransport)
+	require.True(t, ok)
+
+	rt.sleep = func(d time.Duration) <-chan time.Time {
+		backoff = append(backoff, d)
+
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+
+		return ch
+	}
+
+	return rt, &bodies, &backoff
+}
+
+func TestRetryTransport(t *testing.T) {
+	conf := &vcprovider.RetryConfig{
+		MaxAttempts:    4,
+		InitialBackoff: time.Second,
+		MaxBackoff:     3 * time.Second,
+	}
+
+	t.Run("retries 429 and 503 with exponential backoff", func(t *testing.T) {
+		rt, bodies, backoff := newTestRetryTransport(t, conf,
+			[]int{http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			nil)
+
+		req, err := http.NewRequest(http.MethodPost, "https://example.com", bytes.NewBufferString("body"))
+		require.NoError(t, err)
+
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, []string{"body", "body", "body", "body"}, *bodies)
+		require.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, *backoff)
+	})
+
+	t.Run("retries network errors", func(t *testing.T) {
+		rt, bodies, _ := newTestRetryTransport(t, conf,
+			[]int{0, http.StatusOK}, []error{errors.New("connection reset")})
+
+		req, err := http.NewRequest(http.MethodGet, "https://example.com", http.NoBody)
+		require.NoError(t, err)
+
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Len(t, *bodies, 2)
+	})
+
+	t.Run("returns last response after max attempts", func(t *testing.T) {
+		rt, bodies, _ := newTestRetryTransport(t, conf,
+			[]int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable,
+				http.StatusTooManyRequests}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, "https://example.com", http.NoBody)
+		require.NoError(t, err)
+
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		require.Len(t, *bodies, 4)
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized} {
+			rt, bodies, _ := newTestRetryTransport(t, conf, []int{status}, nil)
+
+			req, err := http.NewRequest(http.MethodGet, "https://example.com", http.NoBody)
+			require.NoError(t, err)
+
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			require.Equal(t, status, resp.StatusCode)
+			require.Len(t, *bodies, 1)
+		}
+	})
+
+	t.Run("does not retry request with body that can't be recreated", func(t *testing.T) {
+		rt, bodies, _ := newTestRetryTransport(t, conf, []int{http.StatusServiceUnavailable}, nil)
+
+		req, err := http.NewRequest(http.MethodPost, "https://example.com", io.NopCloser(strings.NewReader("body")))
+		require.NoError(t, err)
+
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		require.Len(t, *bodies, 1)
+	})
+
+	t.Run("stops when request context is done", func(t *testing.T) {
+		rt, bodies, _ := newTestRetryTransport(t, conf, []int{http.StatusServiceUnavailable}, nil)
+		rt.sleep = func(time.Duration) <-chan time.Time { return nil }
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", http.NoBody)
+		require.NoError(t, err)
+
+		_, err = rt.RoundTrip(req)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Len(t, *bodies, 1)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		base := http.DefaultTransport
+
+		require.Equal(t, base, newRetryTransport(base, nil))
+		require.Equal(t, base, newRetryTransport(base, &vcprovider.RetryConfig{MaxAttempts: 1}))
+
+		rt, ok := newRetryTransport(base, &vcprovider.RetryConfig{MaxAttempts: 2}).(*retryTransport)
+		require.True(t, ok)
+		require.Equal(t, defaultRetryInitialBackoff, rt.initialBackoff)
+		require.Equal(t, defaultRetryMaxBackoff, rt.maxBackoff)
+	})
+}
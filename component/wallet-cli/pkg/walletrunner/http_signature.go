@@ -0,0 +1,206 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	vcs "github.com/trustbloc/vcs/pkg/doc/verifiable"
+	"github.com/trustbloc/vcs/pkg/kms/signer"
+)
+
+const httpSignatureLabel = "sig1"
+
+type signerAlgorithm interface {
+	Sign(data []byte) ([]byte, error)
+}
+
+// httpRequestSigner holds the wallet key used to sign HTTP requests.
+type httpRequestSigner struct {
+	keyID  string
+	alg    string // RFC 9421 algorithm name, empty if the signature type has no registered algorithm
+	signer signerAlgorithm
+	// ecdsaSize is the size in bytes of the r and s values of ECDSA signatures, zero for other algorithms.
+	ecdsaSize int
+}
+
+// httpSignatureTransport adds RFC 9421 HTTP message signatures to outgoing requests. The signature covers
+// the method and target URI of the request and, for requests with a body, the RFC 9530 Content-Digest header.
+type httpSignatureTransport struct {
+	base      http.RoundTripper
+	getSigner func() (*httpRequestSigner, error)
+	now       func() time.Time
+}
+
+// RoundTrip signs the request and executes it with the base transport.
+func (t *httpSignatureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	signed, err := t.signRequest(req)
+	if err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+
+		return nil, fmt.Errorf("sign http request: %w", err)
+	}
+
+	return t.base.RoundTrip(signed)
+}
+
+func (t *httpSignatureTransport) signRequest(req *http.Request) (*http.Request, error) {
+	s, err := t.getSigner()
+	if err != nil {
+		return nil, err
+	}
+
+	signed := req.Clone(req.Context())
+	components := []string{"@method", "@target-uri"}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, readErr := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+
+		if readErr != nil {
+			return nil, fmt.Errorf("read body: %w", readErr)
+		}
+
+		signed.Body = io.NopCloser(bytes.NewReader(body))
+		signed.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+
+		digest := sha256.Sum256(body)
+
+		signed.Header.Set("Content-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(digest[:])+":")
+		components = append(components, "content-digest")
+	}
+
+	params := signatureParams(components, t.now().Unix(), s.keyID, s.alg)
+
+	var base strings.Builder
+
+	for _, component := range components {
+		var value string
+
+		switch component {
+		case "@method":
+			value = signed.Method
+		case "@target-uri":
+			value = signed.URL.String()
+		default:
+			value = signed.Header.Get(component)
+		}
+
+		base.WriteString(`"` + component + `": ` + value + "\n")
+	}
+
+	base.WriteString(`"@signature-params": ` + params)
+
+	sig, err := s.signer.Sign([]byte(base.String()))
+	if err != nil {
+		return nil, fmt.Errorf("sign signature base: %w", err)
+	}
+
+	if s.ecdsaSize > 0 {
+		sig = ecdsaSignatureToRaw(sig, s.ecdsaSize)
+	}
+
+	signed.Header.Set("Signature-Input", httpSignatureLabel+"="+params)
+	signed.Header.Set("Signature", httpSignatureLabel+"=:"+base64.StdEncoding.EncodeToString(sig)+":")
+
+	return signed, nil
+}
+
+func signatureParams(components []string, created int64, keyID, alg string) string {
+	quoted := make([]string, len(components))
+
+	for i, component := range components {
+		quoted[i] = strconv.Quote(component)
+	}
+
+	params := "(" + strings.Join(quoted, " ") + ");created=" + strconv.FormatInt(created, 10) +
+		";keyid=" + strconv.Quote(keyID)
+
+	if alg != "" {
+		params += ";alg=" + strconv.Quote(alg)
+	}
+
+	return params
+}
+
+// ecdsaSignatureToRaw converts an ASN.1 DER encoded ECDSA signature to the r||s form required by RFC 9421.
+// Signatures that are not DER encoded are returned unchanged.
+func ecdsaSignatureToRaw(sig []byte, size int) []byte {
+	var der struct {
+		R, S *big.Int
+	}
+
+	if rest, err := asn1.Unmarshal(sig, &der); err != nil || len(rest) > 0 {
+		return sig
+	}
+
+	raw := make([]byte, 2*size)
+
+	der.R.FillBytes(raw[:size])
+	der.S.FillBytes(raw[size:])
+
+	return raw
+}
+
+// httpRequestSigner returns the signer for the first key of the wallet DID.
+func (s *Service) httpRequestSigner() (*httpRequestSigner, error) {
+	if s.ariesServices == nil || s.vcProviderConf.WalletParams == nil ||
+		len(s.vcProviderConf.WalletParams.DidKeyID) == 0 {
+		return nil, errors.New("wallet signing key is not initialized")
+	}
+
+	didKeyID := s.vcProviderConf.WalletParams.DidKeyID[0]
+	signType := s.vcProviderConf.WalletParams.SignType
+
+	_, kmsKeyID, found := strings.Cut(didKeyID, "#")
+	if !found {
+		return nil, fmt.Errorf("invalid wallet did key id: %s", didKeyID)
+	}
+
+	kmsSigner, err := signer.NewKMSSigner(s.ariesServices.kms, s.ariesServices.crypto, kmsKeyID, signType, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create kms signer: %w", err)
+	}
+
+	keyID, err := resolveSignerKeyID(didKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	reqSigner := &httpRequestSigner{
+		keyID:  keyID,
+		signer: kmsSigner,
+	}
+
+	switch signType { //nolint:exhaustive
+	case vcs.EdDSA:
+		reqSigner.alg = "ed25519"
+	case vcs.ES256:
+		reqSigner.alg = "ecdsa-p256-sha256"
+		reqSigner.ecdsaSize = 32
+	case vcs.ES384:
+		reqSigner.alg = "ecdsa-p384-sha384"
+		reqSigner.ecdsaSize = 48
+	}
+
+	return reqSigner, nil
+}
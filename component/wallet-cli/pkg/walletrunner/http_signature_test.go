@@ -0,0 +1,233 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/vcs/component/wallet-cli/pkg/walletrunner/vcprovider"
+)
+
+type signerFunc func(data []byte) ([]byte, error)
+
+func (f signerFunc) Sign(data []byte) ([]byte, error) {
+	return f(data)
+}
+
+func TestHTTPSignatureTransport(t *testing.T) {
+	created := time.Unix(1700000000, 0)
+
+	t.Run("Ed25519", func(t *testing.T) {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		srv := newSignatureVerifyingServer(t, "did:example:wallet#key-1", "ed25519", created,
+			func(base, sig []byte) bool {
+				return ed25519.Verify(pub, base, sig)
+			})
+
+		client := &http.Client{Transport: &httpSignatureTransport{
+			base: http.DefaultTransport,
+			getSigner: func() (*httpRequestSigner, error) {
+				return &httpRequestSigner{
+					keyID: "did:example:wallet#key-1",
+					alg:   "ed25519",
+					signer: signerFunc(func(data []byte) ([]byte, error) {
+						return ed25519.Sign(priv, data), nil
+					}),
+				}, nil
+			},
+			now: func() time.Time { return created },
+		}}
+
+		resp, err := client.Get(srv.URL + "/credential?format=jwt")
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp, err = client.Post(srv.URL+"/credential", "application/json", strings.NewReader(`{"format":"jwt"}`))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("ECDSA DER signature", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		srv := newSignatureVerifyingServer(t, "did:example:wallet#key-1", "ecdsa-p256-sha256", created,
+			func(base, sig []byte) bool {
+				if len(sig) != 64 {
+					return false
+				}
+
+				digest := sha256.Sum256(base)
+
+				return ecdsa.Verify(&key.PublicKey, digest[:],
+					new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:]))
+			})
+
+		client := &http.Client{Transport: &httpSignatureTransport{
+			base: http.DefaultTransport,
+			getSigner: func() (*httpRequestSigner, error) {
+				return &httpRequestSigner{
+					keyID: "did:example:wallet#key-1",
+					alg:   "ecdsa-p256-sha256",
+					signer: signerFunc(func(data []byte) ([]byte, error) {
+						digest := sha256.Sum256(data)
+
+						return ecdsa.SignASN1(rand.Reader, key, digest[:])
+					}),
+					ecdsaSize: 32,
+				}, nil
+			},
+			now: func() time.Time { return created },
+		}}
+
+		resp, err := client.Post(srv.URL+"/credential", "application/json", strings.NewReader(`{"format":"jwt"}`))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Signer error", func(t *testing.T) {
+		client := &http.Client{Transport: &httpSignatureTransport{
+			base: http.DefaultTransport,
+			getSigner: func() (*httpRequestSigner, error) {
+				return nil, errors.New("signer error")
+			},
+			now: time.Now,
+		}}
+
+		_, err := client.Post("http://localhost/credential", "application/json", strings.NewReader("{}"))
+		require.ErrorContains(t, err, "sign http request: signer error")
+	})
+
+	t.Run("Wallet not initialized", func(t *testing.T) {
+		s, err := New(vcprovider.ProviderVCS, func(c *vcprovider.Config) {
+			c.SignHTTPRequests = true
+		})
+		require.NoError(t, err)
+
+		_, err = s.httpClient.Get("http://localhost/credential")
+		require.ErrorContains(t, err, "wallet signing key is not initialized")
+	})
+}
+
+// newSignatureVerifyingServer starts a server that responds with 401 to requests without a valid signature.
+func newSignatureVerifyingServer(t *testing.T, keyID, alg string, created time.Time,
+	verify func(base, sig []byte) bool) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := verifyHTTPSignature(r, keyID, alg, created, verify); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func verifyHTTPSignature(r *http.Request, keyID, alg string, created time.Time,
+	verify func(base, sig []byte) bool) error {
+	params, ok := strings.CutPrefix(r.Header.Get("Signature-Input"), "sig1=")
+	if !ok {
+		return errors.New("missing signature input")
+	}
+
+	list, paramsSuffix, ok := strings.Cut(strings.TrimPrefix(params, "("), ")")
+	if !ok {
+		return errors.New("invalid signature input")
+	}
+
+	expectedSuffix := fmt.Sprintf(";created=%d;keyid=%q;alg=%q", created.Unix(), keyID, alg)
+	if paramsSuffix != expectedSuffix {
+		return fmt.Errorf("unexpected signature params: %s", paramsSuffix)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	var base strings.Builder
+
+	var coversDigest bool
+
+	for _, quoted := range strings.Fields(list) {
+		component, unquoteErr := strconv.Unquote(quoted)
+		if unquoteErr != nil {
+			return unquoteErr
+		}
+
+		var value string
+
+		switch component {
+		case "@method":
+			value = r.Method
+		case "@target-uri":
+			value = "http://" + r.Host + r.URL.RequestURI()
+		case "content-digest":
+			digest := sha256.Sum256(body)
+			value = "sha-256=:" + base64.StdEncoding.EncodeToString(digest[:]) + ":"
+
+			if r.Header.Get("Content-Digest") != value {
+				return errors.New("content digest mismatch")
+			}
+
+			coversDigest = true
+		default:
+			return fmt.Errorf("unexpected component: %s", component)
+		}
+
+		fmt.Fprintf(&base, "%q: %s\n", component, value)
+	}
+
+	if len(body) > 0 && !coversDigest {
+		return errors.New("signature does not cover the body")
+	}
+
+	fmt.Fprintf(&base, "%q: %s", "@signature-params", params)
+
+	sig, ok := strings.CutPrefix(r.Header.Get("Signature"), "sig1=:")
+	if !ok {
+		return errors.New("missing signature")
+	}
+
+	sigBytes, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(sig, ":"))
+	if err != nil {
+		return err
+	}
+
+	if !verify([]byte(base.String()), sigBytes) {
+		return errors.New("invalid signature")
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/trustbloc/vcs/component/wallet-cli/pkg/walletrunner/vcprovider"
+)
+
+// Operations the errors are recorded for.
+const (
+	opOIDC4CI                = "oidc4ci"
+	opOIDC4CIWalletInitiated = "oidc4ci_wallet_initiated"
+	opOIDC4CIPreAuth         = "oidc4ci_pre_auth"
+	opOIDC4VP                = "oidc4vp"
+	opPresentCredentials     = "present_credentials"
+)
+
+const (
+	metricsNamespace = "vcs"
+	metricsSubsystem = "wallet"
+	opLabel          = "op"
+)
+
+// MetricsProvider records latencies and errors of the wallet flows. It is set with vcprovider.Config.Metrics.
+type MetricsProvider = vcprovider.MetricsProvider
+
+type noopMetrics struct{}
+
+func (noopMetrics) RecordIssuanceLatency(time.Duration) {}
+
+func (noopMetrics) RecordPresentationLatency(time.Duration) {}
+
+func (noopMetrics) RecordError(string) {}
+
+// PrometheusMetrics is MetricsProvider that exposes the wallet metrics as Prometheus histograms and counters
+// prefixed with vcs_wallet_.
+type PrometheusMetrics struct {
+	issuanceLatency     prometheus.Histogram
+	presentationLatency prometheus.Histogram
+	errors              *prometheus.CounterVec
+}
+
+// NewPrometheusMetrics creates PrometheusMetrics and registers its collectors with the registerer.
+func NewPrometheusMetrics(registerer prometheus.Registerer) (*PrometheusMetrics, error) {
+	m := &PrometheusMetrics{
+		issuanceLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "issuance_latency_seconds",
+			Help:      "The time (in seconds) it takes to run a credential issuance flow.",
+		}),
+		presentationLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "presentation_latency_seconds",
+			Help:      "The time (in seconds) it takes to run a presentation flow.",
+		}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "errors_total",
+			Help:      "The number of failed wallet operations.",
+		}, []string{opLabel}),
+	}
+
+	for _, c := range []prometheus.Collector{m.issuanceLatency, m.presentationLatency, m.errors} {
+		if err := registerer.Register(c); err != nil {
+			return nil, fmt.Errorf("register wallet metrics: %w", err)
+		}
+	}
+
+	return m, nil
+}
+
+// RecordIssuanceLatency records the duration of a successful credential issuance flow.
+func (m *PrometheusMetrics) RecordIssuanceLatency(d time.Duration) {
+	m.issuanceLatency.Observe(d.Seconds())
+}
+
+// RecordPresentationLatency records the duration of a successful presentation flow.
+func (m *PrometheusMetrics) RecordPresentationLatency(d time.Duration) {
+	m.presentationLatency.Observe(d.Seconds())
+}
+
+// RecordError counts a failure of the given operation.
+func (m *PrometheusMetrics) RecordError(op string) {
+	m.errors.WithLabelValues(op).Inc()
+}
+
+func (s *Service) recordIssuance(op string, startTime time.Time, err error) {
+	if err != nil {
+		s.metrics.RecordError(op)
+
+		return
+	}
+
+	s.metrics.RecordIssuanceLatency(time.Since(startTime))
+}
+
+func (s *Service) recordPresentation(op string, startTime time.Time, err error) {
+	if err != nil {
+		s.metrics.RecordError(op)
+
+		return
+	}
+
+	s.metrics.RecordPresentationLatency(time.Since(startTime))
+}
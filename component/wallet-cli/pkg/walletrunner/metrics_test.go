@@ -0,0 +1,62 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	m, err := NewPrometheusMetrics(registry)
+	require.NoError(t, err)
+
+	s := &Service{metrics: m}
+
+	s.recordIssuance(opOIDC4CIPreAuth, time.Now(), nil)
+	s.recordIssuance(opOIDC4CIPreAuth, time.Now(), errors.New("issuance failed"))
+	s.recordPresentation(opOIDC4VP, time.Now(), nil)
+	s.recordPresentation(opOIDC4VP, time.Now(), errors.New("presentation failed"))
+	s.recordPresentation(opOIDC4VP, time.Now(), errors.New("presentation failed"))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	names := map[string]bool{}
+	for _, f := range families {
+		names[f.GetName()] = true
+	}
+
+	require.True(t, names["vcs_wallet_issuance_latency_seconds"])
+	require.True(t, names["vcs_wallet_presentation_latency_seconds"])
+	require.True(t, names["vcs_wallet_errors_total"])
+
+	require.Equal(t, 1, testutil.CollectAndCount(m.issuanceLatency))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.errors.WithLabelValues(opOIDC4CIPreAuth)))
+	require.Equal(t, 2.0, testutil.ToFloat64(m.errors.WithLabelValues(opOIDC4VP)))
+
+	t.Run("already registered", func(t *testing.T) {
+		_, err = NewPrometheusMetrics(registry)
+		require.ErrorContains(t, err, "register wallet metrics")
+	})
+}
+
+func TestNoopMetrics(t *testing.T) {
+	s := &Service{metrics: noopMetrics{}}
+
+	require.NotPanics(t, func() {
+		s.recordIssuance(opOIDC4CI, time.Now(), nil)
+		s.recordPresentation(opPresentCredentials, time.Now(), errors.New("failed"))
+	})
+}
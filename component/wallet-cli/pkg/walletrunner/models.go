@@ -21,7 +21,8 @@ type RequestObject struct {
 	Scope        string                    `json:"scope"`
 	Nonce        string                    `json:"nonce"`
 	ClientID     string                    `json:"client_id"`
-	RedirectURI  string                    `json:"redirect_uri"`
+	RedirectURI  string                    `json:"redirect_uri,omitempty"`
+	ResponseURI  string                    `json:"response_uri,omitempty"`
 	State        string                    `json:"state"`
 	Exp          int64                     `json:"exp"`
 	Registration RequestObjectRegistration `json:"registration"`
@@ -93,6 +94,12 @@ type JWTProof struct {
 	ProofType string `json:"proof_type"`
 }
 
+type CredentialErrorResponse struct {
+	Error           string `json:"error"`
+	CNonce          string `json:"c_nonce,omitempty"`
+	CNonceExpiresIn int    `json:"c_nonce_expires_in,omitempty"`
+}
+
 type CredentialResponse struct {
 	AcceptanceToken string                 `json:"acceptance_token,omitempty"`
 	CNonce          string                 `json:"c_nonce,omitempty"`
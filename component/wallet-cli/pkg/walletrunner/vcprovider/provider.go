@@ -9,6 +9,7 @@ package vcprovider
 import (
 	"crypto/tls"
 	"fmt"
+	"time"
 
 	vcs "github.com/trustbloc/vcs/pkg/doc/verifiable"
 )
@@ -48,6 +49,42 @@ type Config struct {
 	DidKeyType                      string
 	KeepWalletOpen                  bool
 	LinkedDomainVerificationEnabled bool
+	LinkedDomainVerificationTimeout time.Duration // defaults to 10 seconds
+	SignHTTPRequests                bool          // sign requests with RFC 9421 HTTP message signatures
+	ProxyURL                        string        // route outbound HTTP requests through the proxy
+	VDRMethods                      []VDRMethodConfig
+	Metrics                         MetricsProvider // records flow latencies and errors, not recorded if nil
+	Retry                           *RetryConfig    // retries transient HTTP failures, no retries if nil
+}
+
+// RetryConfig configures the retry of outbound HTTP requests of the issuance and presentation flows that failed
+// with a network error or with 429 or 503 status. Backoff between attempts starts at InitialBackoff and doubles
+// up to MaxBackoff.
+type RetryConfig struct {
+	MaxAttempts    int           // total number of attempts, including the first one
+	InitialBackoff time.Duration // defaults to 500 milliseconds
+	MaxBackoff     time.Duration // defaults to 10 seconds
+}
+
+// MetricsProvider records latencies and errors of the wallet flows.
+type MetricsProvider interface {
+	// RecordIssuanceLatency records the duration of a successful credential issuance flow.
+	RecordIssuanceLatency(d time.Duration)
+	// RecordPresentationLatency records the duration of a successful presentation flow.
+	RecordPresentationLatency(d time.Duration)
+	// RecordError counts a failure of the given operation.
+	RecordError(op string)
+}
+
+// VDRMethodConfig configures a resolver endpoint for a DID method. DIDs of the method are resolved with the
+// configured endpoints in order before falling back to the built-in VDRs. If Endpoint is empty, only the
+// built-in VDRs are used for the method.
+type VDRMethodConfig struct {
+	Method   string
+	Endpoint string
+	// ResolveConcurrently makes the endpoints of the method be queried concurrently instead of in order;
+	// the first successful resolution is used. It applies to the method if set on any of its endpoints.
+	ResolveConcurrently bool
 }
 
 type WalletParams struct {
@@ -57,6 +94,9 @@ type WalletParams struct {
 	DidID      []string
 	DidKeyID   []string
 	SignType   vcs.SignatureType
+	// RevokedKeys holds the IDs of the wallet keys retired by key rotation. Their key handles are kept in the
+	// wallet KMS.
+	RevokedKeys []string
 }
 
 type ConfigOption func(c *Config)
@@ -0,0 +1,155 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/method/httpbinding"
+	"github.com/trustbloc/did-go/vdr"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+
+	"github.com/trustbloc/vcs/component/wallet-cli/pkg/walletrunner/vcprovider"
+)
+
+// methodResolver resolves DIDs of a single method with the configured endpoints.
+type methodResolver struct {
+	endpoints  []vdrapi.VDR
+	concurrent bool
+}
+
+func (r *methodResolver) read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if r.concurrent {
+		return r.readConcurrently(didID, opts...)
+	}
+
+	var errs []error
+
+	for _, endpoint := range r.endpoints {
+		docRes, err := endpoint.Read(didID, opts...)
+		if err == nil {
+			return docRes, nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+func (r *methodResolver) readConcurrently(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	type result struct {
+		docRes *did.DocResolution
+		err    error
+	}
+
+	results := make(chan result, len(r.endpoints))
+
+	for _, endpoint := range r.endpoints {
+		go func(endpoint vdrapi.VDR) {
+			docRes, err := endpoint.Read(didID, opts...)
+			results <- result{docRes: docRes, err: err}
+		}(endpoint)
+	}
+
+	var errs []error
+
+	for range r.endpoints {
+		res := <-results
+		if res.err == nil {
+			return res.docRes, nil
+		}
+
+		errs = append(errs, res.err)
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// methodsRegistry resolves DIDs of the configured methods with their endpoints and falls back to
+// the built-in registry. DID creation, update and deactivation are always handled by the built-in registry.
+type methodsRegistry struct {
+	vdrapi.Registry
+	methods map[string]*methodResolver
+}
+
+func newMethodsRegistry(
+	registry vdrapi.Registry,
+	methods []vcprovider.VDRMethodConfig,
+	tlsConfig *tls.Config,
+) (*methodsRegistry, error) {
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+	}
+
+	r := &methodsRegistry{
+		Registry: registry,
+		methods:  map[string]*methodResolver{},
+	}
+
+	for _, m := range methods {
+		if m.Method == "" {
+			return nil, errors.New("vdr method is not set")
+		}
+
+		if m.Endpoint == "" {
+			continue
+		}
+
+		method := m.Method
+
+		endpoint, err := httpbinding.New(m.Endpoint,
+			httpbinding.WithAccept(func(didMethod string) bool { return didMethod == method }),
+			httpbinding.WithHTTPClient(httpClient))
+		if err != nil {
+			return nil, fmt.Errorf("create vdr for method %s: %w", method, err)
+		}
+
+		resolver, ok := r.methods[method]
+		if !ok {
+			resolver = &methodResolver{}
+			r.methods[method] = resolver
+		}
+
+		resolver.endpoints = append(resolver.endpoints, endpoint)
+		resolver.concurrent = resolver.concurrent || m.ResolveConcurrently
+	}
+
+	return r, nil
+}
+
+// Resolve resolves the DID with the endpoints configured for its method and, if none of them succeeds,
+// with the built-in registry.
+func (r *methodsRegistry) Resolve(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	method, err := vdr.GetDidMethod(didID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, ok := r.methods[method]
+	if !ok {
+		return r.Registry.Resolve(didID, opts...)
+	}
+
+	docRes, err := resolver.read(didID, opts...)
+	if err == nil {
+		return docRes, nil
+	}
+
+	docRes, fallbackErr := r.Registry.Resolve(didID, opts...)
+	if fallbackErr != nil {
+		return nil, fmt.Errorf("resolve %s: %w", didID, errors.Join(err, fallbackErr))
+	}
+
+	return docRes, nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/did-go/method/key"
+	"github.com/trustbloc/did-go/vdr"
+
+	"github.com/trustbloc/vcs/component/wallet-cli/pkg/walletrunner/vcprovider"
+)
+
+const (
+	testOrbDID = "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+	testKeyDID = "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+)
+
+func TestMethodsRegistry(t *testing.T) {
+	builtIn := vdr.New(vdr.WithVDR(key.New()))
+
+	t.Run("Resolve with endpoints in order", func(t *testing.T) {
+		var failingHits, resolvingHits atomic.Int32
+
+		failing := newDIDResolverServer(t, &failingHits, http.StatusInternalServerError, 0)
+		resolving := newDIDResolverServer(t, &resolvingHits, http.StatusOK, 0)
+
+		registry, err := newMethodsRegistry(builtIn, []vcprovider.VDRMethodConfig{
+			{Method: "orb", Endpoint: failing.URL},
+			{Method: "orb", Endpoint: resolving.URL},
+		}, nil)
+		require.NoError(t, err)
+
+		docRes, err := registry.Resolve(testOrbDID)
+		require.NoError(t, err)
+		require.Equal(t, testOrbDID, docRes.DIDDocument.ID)
+		require.EqualValues(t, 1, failingHits.Load())
+		require.EqualValues(t, 1, resolvingHits.Load())
+
+		docRes, err = registry.Resolve(testKeyDID)
+		require.NoError(t, err)
+		require.Equal(t, testKeyDID, docRes.DIDDocument.ID)
+	})
+
+	t.Run("Resolve concurrently", func(t *testing.T) {
+		var slowHits, resolvingHits atomic.Int32
+
+		slow := newDIDResolverServer(t, &slowHits, http.StatusOK, 500*time.Millisecond)
+		resolving := newDIDResolverServer(t, &resolvingHits, http.StatusOK, 0)
+
+		registry, err := newMethodsRegistry(builtIn, []vcprovider.VDRMethodConfig{
+			{Method: "orb", Endpoint: slow.URL},
+			{Method: "orb", Endpoint: resolving.URL, ResolveConcurrently: true},
+		}, nil)
+		require.NoError(t, err)
+
+		start := time.Now()
+
+		docRes, err := registry.Resolve(testOrbDID)
+		require.NoError(t, err)
+		require.Equal(t, testOrbDID, docRes.DIDDocument.ID)
+		require.Less(t, time.Since(start), 500*time.Millisecond)
+		require.EqualValues(t, 1, resolvingHits.Load())
+	})
+
+	t.Run("Fall back to built-in vdr", func(t *testing.T) {
+		var hits atomic.Int32
+
+		failing := newDIDResolverServer(t, &hits, http.StatusInternalServerError, 0)
+
+		registry, err := newMethodsRegistry(builtIn, []vcprovider.VDRMethodConfig{
+			{Method: "key", Endpoint: failing.URL},
+			{Method: "web"},
+		}, nil)
+		require.NoError(t, err)
+
+		docRes, err := registry.Resolve(testKeyDID)
+		require.NoError(t, err)
+		require.Equal(t, testKeyDID, docRes.DIDDocument.ID)
+		require.EqualValues(t, 1, hits.Load())
+	})
+
+	t.Run("All endpoints failed", func(t *testing.T) {
+		var hits atomic.Int32
+
+		failing := newDIDResolverServer(t, &hits, http.StatusInternalServerError, 0)
+
+		registry, err := newMethodsRegistry(builtIn, []vcprovider.VDRMethodConfig{
+			{Method: "orb", Endpoint: failing.URL, ResolveConcurrently: true},
+			{Method: "orb", Endpoint: failing.URL},
+		}, nil)
+		require.NoError(t, err)
+
+		_, err = registry.Resolve(testOrbDID)
+		require.ErrorContains(t, err, "resolve "+testOrbDID)
+		require.ErrorContains(t, err, "did method orb not supported for vdr")
+		require.EqualValues(t, 2, hits.Load())
+	})
+
+	t.Run("Method not set", func(t *testing.T) {
+		_, err := newMethodsRegistry(builtIn, []vcprovider.VDRMethodConfig{{Endpoint: "https://example.com"}}, nil)
+		require.ErrorContains(t, err, "vdr method is not set")
+	})
+}
+
+func newDIDResolverServer(t *testing.T, hits *atomic.Int32, status int, delay time.Duration) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/did+ld+json")
+
+		_, _ = fmt.Fprintf(w, `{"@context":["https://www.w3.org/ns/did/v1"],"id":%q}`, testOrbDID)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
@@ -7,13 +7,20 @@ SPDX-License-Identifier: Apache-2.0
 package walletrunner
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/trustbloc/did-go/doc/did"
 	"github.com/trustbloc/kms-go/spi/kms"
 	"github.com/trustbloc/vc-go/verifiable"
 	"github.com/trustbloc/vcs/component/wallet-cli/internal/vdrutil"
@@ -31,6 +38,11 @@ const (
 	didMethodION     = "ion"
 )
 
+var (
+	ErrCredentialAlreadyStored = errors.New("credential already stored")
+	ErrCredentialNotFound      = errors.New("credential not found")
+)
+
 // Wallet provides verifiable credential storing, fetching, and presentation definition querying.
 type Wallet interface {
 	// Open opens wallet.
@@ -39,6 +51,10 @@ type Wallet interface {
 	Close() bool
 	// Add adds a marshalled credential to the wallet.
 	Add(content json.RawMessage) error
+	// AddUnique adds a marshalled credential to the wallet unless a credential with the same ID is stored.
+	AddUnique(content json.RawMessage) error
+	// Delete removes the credential with the given ID from the wallet.
+	Delete(credentialID string) error
 	// GetAll returns all stored credentials.
 	GetAll() (map[string]json.RawMessage, error)
 	// Query runs the given presentation definition on the stored credentials.
@@ -112,13 +128,8 @@ func (s *Service) CreateWallet() error {
 		s.vcProviderConf.WalletParams.DidKeyID = append(s.vcProviderConf.WalletParams.DidKeyID, s.vcProviderConf.WalletDidKeyID)
 	}
 
-	switch s.vcProviderConf.DidKeyType {
-	case "ED25519":
-		s.vcProviderConf.WalletParams.SignType = vcs.EdDSA
-	case "ECDSAP256DER":
-		s.vcProviderConf.WalletParams.SignType = vcs.ES256
-	case "ECDSAP384DER":
-		s.vcProviderConf.WalletParams.SignType = vcs.ES384
+	if signType, ok := keySignatureType(kms.KeyType(s.vcProviderConf.DidKeyType)); ok {
+		s.vcProviderConf.WalletParams.SignType = signType
 	}
 
 	for i := 0; i < s.vcProviderConf.WalletDidCount; i++ {
@@ -145,6 +156,20 @@ func (s *Service) CreateWallet() error {
 	return nil
 }
 
+// keySignatureType returns the signature type the wallet signs with keys of the given type.
+func keySignatureType(keyType kms.KeyType) (vcs.SignatureType, bool) {
+	switch keyType {
+	case kms.ED25519Type:
+		return vcs.EdDSA, true
+	case kms.ECDSAP256TypeDER:
+		return vcs.ES256, true
+	case kms.ECDSAP384TypeDER:
+		return vcs.ES384, true
+	default:
+		return "", false
+	}
+}
+
 func newWallet(shouldCreate bool, userID string, passphrase string, services *ariesServices) (Wallet, error) {
 	store, err := services.storageProvider.OpenStore("wallet:credential")
 	if err != nil {
@@ -158,6 +183,65 @@ func newWallet(shouldCreate bool, userID string, passphrase string, services *ar
 	}, nil
 }
 
+// CreateWebDID creates a did:web DID for the domain with a new wallet key and writes its DID document to
+// <path>/.well-known/did.json, ready to be served from the domain. The DID is registered in the wallet VDR
+// so that it resolves locally before the document is published, and is added to the wallet DIDs.
+func (s *Service) CreateWebDID(_ context.Context, domain, path string) (*did.Doc, error) {
+	if err := s.ensureAriesServices(); err != nil {
+		return nil, err
+	}
+
+	createRes, err := vdrutil.DefaultVdrUtil.CreateWeb(
+		domain,
+		kms.KeyType(s.vcProviderConf.DidKeyType),
+		s.ariesServices.vdrRegistry,
+		s.ariesServices.kms,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	docRes, err := s.ariesServices.vdrRegistry.Resolve(createRes.DidID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", createRes.DidID, err)
+	}
+
+	if err = writeWebDIDDocument(docRes.DIDDocument, path); err != nil {
+		return nil, err
+	}
+
+	if s.webDIDPaths == nil {
+		s.webDIDPaths = map[string]string{}
+	}
+
+	s.webDIDPaths[createRes.DidID] = path
+
+	s.vcProviderConf.WalletParams.DidID = append(s.vcProviderConf.WalletParams.DidID, createRes.DidID)
+	s.vcProviderConf.WalletParams.DidKeyID = append(s.vcProviderConf.WalletParams.DidKeyID, createRes.KeyID)
+
+	return docRes.DIDDocument, nil
+}
+
+// writeWebDIDDocument writes the did:web DID document to <path>/.well-known/did.json.
+func writeWebDIDDocument(doc *did.Doc, path string) error {
+	b, err := doc.JSONBytes()
+	if err != nil {
+		return fmt.Errorf("marshal did document: %w", err)
+	}
+
+	dir := filepath.Join(path, ".well-known")
+
+	if err = os.MkdirAll(dir, 0o755); err != nil { //nolint:gosec // served publicly
+		return fmt.Errorf("create %s: %w", dir, err)
+	}
+
+	if err = os.WriteFile(filepath.Join(dir, "did.json"), b, 0o644); err != nil { //nolint:gosec // served publicly
+		return fmt.Errorf("write did document: %w", err)
+	}
+
+	return nil
+}
+
 func (s *Service) SaveCredentialInWallet(vc []byte) error {
 	err := s.wallet.Add(vc)
 	if err != nil {
@@ -166,3 +250,102 @@ func (s *Service) SaveCredentialInWallet(vc []byte) error {
 
 	return nil
 }
+
+// StoreCredential stores the credential in the wallet. It fails with ErrCredentialAlreadyStored if a credential
+// with the same ID is already stored.
+func (s *Service) StoreCredential(_ context.Context, vc *verifiable.Credential) error {
+	if s.wallet == nil {
+		return errors.New("wallet is not created")
+	}
+
+	content, err := vc.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("marshal credential: %w", err)
+	}
+
+	if err = s.wallet.AddUnique(content); err != nil {
+		return fmt.Errorf("wallet add credential failed: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCredential deletes the credential with the given ID from the wallet credential store. It fails with
+// ErrCredentialNotFound if the wallet doesn't hold the credential.
+func (s *Service) DeleteCredential(_ context.Context, credentialID string) error {
+	if s.wallet == nil {
+		return errors.New("wallet is not created")
+	}
+
+	if err := s.wallet.Delete(credentialID); err != nil {
+		return fmt.Errorf("wallet delete credential failed: %w", err)
+	}
+
+	return nil
+}
+
+// CredentialFilter filters credentials listed with ListCredentials. Empty fields are ignored.
+type CredentialFilter struct {
+	IssuerDID    string
+	Types        []string // credential must have all the types
+	IssuedAfter  *time.Time
+	IssuedBefore *time.Time
+}
+
+func (f *CredentialFilter) match(vc *verifiable.Credential) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.IssuerDID != "" && vc.Issuer.ID != f.IssuerDID {
+		return false
+	}
+
+	for _, t := range f.Types {
+		if !lo.Contains(vc.Types, t) {
+			return false
+		}
+	}
+
+	if f.IssuedAfter == nil && f.IssuedBefore == nil {
+		return true
+	}
+
+	if vc.Issued == nil {
+		return false
+	}
+
+	if f.IssuedAfter != nil && !vc.Issued.Time.After(*f.IssuedAfter) {
+		return false
+	}
+
+	return f.IssuedBefore == nil || vc.Issued.Time.Before(*f.IssuedBefore)
+}
+
+// ListCredentials returns the credentials stored in the wallet that match the filter, ordered by ID.
+// The credential store has no query support for credential fields, so the filter is applied after fetching.
+func (s *Service) ListCredentials(_ context.Context, filter *CredentialFilter) ([]*verifiable.Credential, error) {
+	if s.wallet == nil {
+		return nil, errors.New("wallet is not created")
+	}
+
+	contents, err := s.wallet.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("get credentials: %w", err)
+	}
+
+	credentials, err := parseCredentialContents(contents, s.ariesServices.documentLoader)
+	if err != nil {
+		return nil, fmt.Errorf("parse credentials: %w", err)
+	}
+
+	result := lo.Filter(credentials, func(vc *verifiable.Credential, _ int) bool {
+		return filter.match(vc)
+	})
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result, nil
+}
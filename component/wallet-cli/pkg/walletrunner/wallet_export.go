@@ -0,0 +1,273 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/trustbloc/kms-go/kms"
+	"golang.org/x/crypto/argon2"
+
+	vcs "github.com/trustbloc/vcs/pkg/doc/verifiable"
+)
+
+const (
+	argon2idHeader = "argon2id"
+
+	argon2idSaltSize = 16
+	argon2idTime     = 3
+	argon2idMemory   = 64 * 1024 // KiB
+	argon2idThreads  = 4
+	walletKeySize    = 32
+
+	// Upper bounds of the KDF parameters accepted on import. The parameters are read from the export before
+	// it can be authenticated, so they are bounded to keep a crafted export from exhausting memory or CPU.
+	argon2idMaxSaltSize = 64
+	argon2idMaxTime     = 10
+	argon2idMaxMemory   = 256 * 1024 // KiB
+	argon2idMaxThreads  = 16
+)
+
+// walletExport is the content of the exported wallet.
+type walletExport struct {
+	Keys        map[string][]byte          `json:"keys"`
+	Credentials map[string]json.RawMessage `json:"credentials"`
+	DIDs        []string                   `json:"dids,omitempty"`
+	DIDKeyIDs   []string                   `json:"didKeyIDs,omitempty"`
+	SignType    vcs.SignatureType          `json:"signType,omitempty"`
+}
+
+// argon2idParams are the parameters used to derive the wallet export key from the passphrase.
+type argon2idParams struct {
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+func (p *argon2idParams) deriveKey(passphrase string) []byte {
+	return argon2.IDKey([]byte(passphrase), p.Salt, p.Time, p.Memory, p.Threads, walletKeySize)
+}
+
+// ExportWallet serializes the KMS keys and credentials of the wallet into a compact JWE encrypted with
+// AES-256-GCM. The content encryption key is derived from passphrase with Argon2id; the KDF parameters are
+// carried in the protected header.
+func (s *Service) ExportWallet(_ context.Context, passphrase string) ([]byte, error) {
+	if s.ariesServices == nil || s.wallet == nil {
+		return nil, errors.New("wallet is not created")
+	}
+
+	keys, err := s.exportKeys()
+	if err != nil {
+		return nil, fmt.Errorf("export keys: %w", err)
+	}
+
+	credentials, err := s.wallet.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("export credentials: %w", err)
+	}
+
+	content, err := json.Marshal(&walletExport{
+		Keys:        keys,
+		Credentials: credentials,
+		DIDs:        s.vcProviderConf.WalletParams.DidID,
+		DIDKeyIDs:   s.vcProviderConf.WalletParams.DidKeyID,
+		SignType:    s.vcProviderConf.WalletParams.SignType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal wallet: %w", err)
+	}
+
+	params := &argon2idParams{
+		Salt:    make([]byte, argon2idSaltSize),
+		Time:    argon2idTime,
+		Memory:  argon2idMemory,
+		Threads: argon2idThreads,
+	}
+
+	if _, err = rand.Read(params.Salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+
+	encrypter, err := jose.NewEncrypter(jose.A256GCM,
+		jose.Recipient{Algorithm: jose.DIRECT, Key: params.deriveKey(passphrase)},
+		(&jose.EncrypterOptions{}).WithContentType("application/json").WithHeader(argon2idHeader, params))
+	if err != nil {
+		return nil, fmt.Errorf("create encrypter: %w", err)
+	}
+
+	jwe, err := encrypter.Encrypt(content)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt wallet: %w", err)
+	}
+
+	serialized, err := jwe.CompactSerialize()
+	if err != nil {
+		return nil, fmt.Errorf("serialize wallet: %w", err)
+	}
+
+	return []byte(serialized), nil
+}
+
+// ImportWallet decrypts the wallet exported with ExportWallet and restores its KMS keys and credentials.
+// Wallet services are set up if the wallet has not been created yet.
+func (s *Service) ImportWallet(_ context.Context, data []byte, passphrase string) error {
+	jwe, err := jose.ParseEncrypted(string(data))
+	if err != nil {
+		return fmt.Errorf("parse wallet: %w", err)
+	}
+
+	params, err := parseArgon2idParams(jwe.Header.ExtraHeaders[argon2idHeader])
+	if err != nil {
+		return err
+	}
+
+	content, err := jwe.Decrypt(params.deriveKey(passphrase))
+	if err != nil {
+		return fmt.Errorf("decrypt wallet: %w", err)
+	}
+
+	var export walletExport
+
+	if err = json.Unmarshal(content, &export); err != nil {
+		return fmt.Errorf("unmarshal wallet: %w", err)
+	}
+
+	if err = s.ensureWalletServices(); err != nil {
+		return err
+	}
+
+	store, err := newKMSStore(s.ariesServices.storageProvider)
+	if err != nil {
+		return fmt.Errorf("open kms store: %w", err)
+	}
+
+	for keysetID, key := range export.Keys {
+		if err = store.Put(keysetID, key); err != nil {
+			return fmt.Errorf("import key %s: %w", keysetID, err)
+		}
+	}
+
+	for _, credential := range export.Credentials {
+		if err = s.wallet.Add(credential); err != nil {
+			return fmt.Errorf("import credential: %w", err)
+		}
+	}
+
+	if len(export.DIDs) > 0 {
+		s.vcProviderConf.WalletParams.DidID = export.DIDs
+		s.vcProviderConf.WalletParams.DidKeyID = export.DIDKeyIDs
+		s.vcProviderConf.WalletParams.SignType = export.SignType
+	}
+
+	return nil
+}
+
+func (s *Service) exportKeys() (map[string][]byte, error) {
+	store, err := s.ariesServices.storageProvider.OpenStore(kms.AriesWrapperStoreName)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := store.Query(kmsKeyTag)
+	if err != nil {
+		return nil, err
+	}
+
+	defer iter.Close() //nolint:errcheck
+
+	keys := make(map[string][]byte)
+
+	for {
+		ok, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			break
+		}
+
+		keysetID, err := iter.Key()
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := iter.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		keys[keysetID] = key
+	}
+
+	return keys, nil
+}
+
+func (s *Service) ensureWalletServices() error {
+	if err := s.ensureAriesServices(); err != nil {
+		return err
+	}
+
+	if s.wallet == nil {
+		w, err := newWallet(false, s.vcProviderConf.WalletParams.UserID, s.vcProviderConf.WalletParams.Passphrase,
+			s.ariesServices)
+		if err != nil {
+			return err
+		}
+
+		s.wallet = w
+	}
+
+	return nil
+}
+
+func (s *Service) ensureAriesServices() error {
+	if s.ariesServices == nil {
+		services, err := s.createAgentServices(s.vcProviderConf)
+		if err != nil {
+			return fmt.Errorf("wallet services setup failed: %w", err)
+		}
+
+		s.ariesServices = services
+	}
+
+	return nil
+}
+
+func parseArgon2idParams(header interface{}) (*argon2idParams, error) {
+	if header == nil {
+		return nil, fmt.Errorf("missing %s header", argon2idHeader)
+	}
+
+	b, err := json.Marshal(header)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s header: %w", argon2idHeader, err)
+	}
+
+	var params argon2idParams
+
+	if err = json.Unmarshal(b, &params); err != nil {
+		return nil, fmt.Errorf("unmarshal %s header: %w", argon2idHeader, err)
+	}
+
+	if len(params.Salt) == 0 || params.Time == 0 || params.Memory == 0 || params.Threads == 0 {
+		return nil, fmt.Errorf("invalid %s header", argon2idHeader)
+	}
+
+	if len(params.Salt) > argon2idMaxSaltSize || params.Time > argon2idMaxTime ||
+		params.Memory > argon2idMaxMemory || params.Threads > argon2idMaxThreads {
+		return nil, fmt.Errorf("%s header parameters exceed the supported maximum", argon2idHeader)
+	}
+
+	return &params, nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/kms-go/spi/kms"
+
+	"github.com/trustbloc/vcs/component/wallet-cli/pkg/walletrunner/vcprovider"
+	vcs "github.com/trustbloc/vcs/pkg/doc/verifiable"
+)
+
+func TestExportImportWallet(t *testing.T) {
+	const passphrase = "export-passphrase"
+
+	credential := json.RawMessage(`{"id":"http://example.edu/credentials/1872","type":["VerifiableCredential"]}`)
+
+	src := newTestWalletService(t)
+	require.NoError(t, src.ensureWalletServices())
+	require.NoError(t, src.wallet.Add(credential))
+
+	keyID, _, err := src.ariesServices.kms.Create(kms.ED25519Type)
+	require.NoError(t, err)
+
+	pubKey, _, err := src.ariesServices.kms.ExportPubKeyBytes(keyID)
+	require.NoError(t, err)
+
+	src.vcProviderConf.WalletParams.DidID = []string{"did:example:wallet"}
+	src.vcProviderConf.WalletParams.DidKeyID = []string{"did:example:wallet#" + keyID}
+	src.vcProviderConf.WalletParams.SignType = vcs.EdDSA
+
+	data, err := src.ExportWallet(context.Background(), passphrase)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "credentials/1872")
+
+	t.Run("Round trip", func(t *testing.T) {
+		dst := newTestWalletService(t)
+
+		require.NoError(t, dst.ImportWallet(context.Background(), data, passphrase))
+
+		credentials, err := dst.wallet.GetAll()
+		require.NoError(t, err)
+		require.Len(t, credentials, 1)
+		require.JSONEq(t, string(credential), string(credentials["http://example.edu/credentials/1872"]))
+
+		importedPubKey, _, err := dst.ariesServices.kms.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+		require.Equal(t, pubKey, importedPubKey)
+
+		require.Equal(t, src.vcProviderConf.WalletParams.DidID, dst.vcProviderConf.WalletParams.DidID)
+		require.Equal(t, src.vcProviderConf.WalletParams.DidKeyID, dst.vcProviderConf.WalletParams.DidKeyID)
+		require.Equal(t, vcs.EdDSA, dst.vcProviderConf.WalletParams.SignType)
+	})
+
+	t.Run("Wrong passphrase", func(t *testing.T) {
+		dst := newTestWalletService(t)
+
+		err := dst.ImportWallet(context.Background(), data, "wrong-passphrase")
+		require.ErrorContains(t, err, "decrypt wallet")
+		require.Nil(t, dst.wallet)
+	})
+
+	t.Run("Invalid data", func(t *testing.T) {
+		dst := newTestWalletService(t)
+
+		err := dst.ImportWallet(context.Background(), []byte("invalid"), passphrase)
+		require.ErrorContains(t, err, "parse wallet")
+	})
+
+	t.Run("Argon2id params out of bounds", func(t *testing.T) {
+		for name, params := range map[string]*argon2idParams{
+			"salt":    {Salt: make([]byte, argon2idMaxSaltSize+1), Time: 1, Memory: 1, Threads: 1},
+			"time":    {Salt: []byte("salt"), Time: argon2idMaxTime + 1, Memory: 1, Threads: 1},
+			"memory":  {Salt: []byte("salt"), Time: 1, Memory: argon2idMaxMemory + 1, Threads: 1},
+			"threads": {Salt: []byte("salt"), Time: 1, Memory: 1, Threads: argon2idMaxThreads + 1},
+		} {
+			t.Run(name, func(t *testing.T) {
+				encrypter, err := jose.NewEncrypter(jose.A256GCM,
+					jose.Recipient{Algorithm: jose.DIRECT, Key: make([]byte, walletKeySize)},
+					(&jose.EncrypterOptions{}).WithHeader(argon2idHeader, params))
+				require.NoError(t, err)
+
+				jwe, err := encrypter.Encrypt([]byte("{}"))
+				require.NoError(t, err)
+
+				crafted, err := jwe.CompactSerialize()
+				require.NoError(t, err)
+
+				err = newTestWalletService(t).ImportWallet(context.Background(), []byte(crafted), passphrase)
+				require.ErrorContains(t, err, "exceed the supported maximum")
+			})
+		}
+	})
+
+	t.Run("Wallet not created", func(t *testing.T) {
+		_, err := newTestWalletService(t).ExportWallet(context.Background(), passphrase)
+		require.ErrorContains(t, err, "wallet is not created")
+	})
+}
+
+func newTestWalletService(t *testing.T) *Service {
+	t.Helper()
+
+	s, err := New(vcprovider.ProviderVCS, func(c *vcprovider.Config) {
+		c.ContextProviderURL = ""
+	})
+	require.NoError(t, err)
+
+	return s
+}
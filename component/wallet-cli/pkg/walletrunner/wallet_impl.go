@@ -17,6 +17,7 @@ import (
 	"sync"
 
 	"github.com/piprate/json-gold/ld"
+	"github.com/samber/lo"
 	"github.com/trustbloc/kms-go/spi/storage"
 	"github.com/trustbloc/vc-go/presexch"
 	"github.com/trustbloc/vc-go/verifiable"
@@ -55,6 +56,48 @@ func (w *walletImpl) Add(content json.RawMessage) error {
 	return nil
 }
 
+func (w *walletImpl) AddUnique(content json.RawMessage) error {
+	key, err := getContentID(content)
+	if err != nil {
+		return err
+	}
+
+	w.storeLock.Lock()
+	defer w.storeLock.Unlock()
+
+	_, err = w.credStore.Get(key)
+	if err == nil {
+		return fmt.Errorf("%w: %s", ErrCredentialAlreadyStored, key)
+	}
+
+	if !errors.Is(err, storage.ErrDataNotFound) {
+		return err
+	}
+
+	return w.credStore.Put(key, content, storage.Tag{Name: credentialTag})
+}
+
+func (w *walletImpl) Delete(credentialID string) error {
+	w.storeLock.Lock()
+	defer w.storeLock.Unlock()
+
+	tags, err := w.credStore.GetTags(credentialID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return fmt.Errorf("%w: %s", ErrCredentialNotFound, credentialID)
+		}
+
+		return err
+	}
+
+	// only entries stored by the wallet itself are tagged as credentials
+	if !lo.ContainsBy(tags, func(tag storage.Tag) bool { return tag.Name == credentialTag }) {
+		return fmt.Errorf("%w: %s", ErrCredentialNotFound, credentialID)
+	}
+
+	return w.credStore.Delete(credentialID)
+}
+
 type contentID struct {
 	ID string `json:"id"`
 }
@@ -0,0 +1,166 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/trustbloc/did-go/vdr"
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/vc-go/verifiable"
+
+	"github.com/trustbloc/vcs/component/wallet-cli/internal/vdrutil"
+	"github.com/trustbloc/vcs/pkg/doc/vc"
+	vccrypto "github.com/trustbloc/vcs/pkg/doc/vc/crypto"
+	vcs "github.com/trustbloc/vcs/pkg/doc/verifiable"
+	vcskms "github.com/trustbloc/vcs/pkg/kms"
+	"github.com/trustbloc/vcs/pkg/observability/metrics/noop"
+)
+
+const jsonWebSignature2020Context = "https://w3id.org/security/suites/jws-2020/v1"
+
+var ErrUnsupportedKeyType = errors.New("unsupported key type")
+
+// RotateSigningKey replaces the signing key of the first wallet DID with a new key of the given type.
+//
+// A did:web DID keeps its identifier: the new key is added to its DID document and becomes the only
+// authentication and assertion method, and the document is rewritten if it was created with CreateWebDID.
+// The old verification method stays in the document, so that proofs made with it can still be resolved.
+// DIDs of other methods can't be updated and are replaced with a new DID of the same method.
+//
+// Stored credentials issued by the rotated DID are re-signed with the new key. Credentials of other issuers
+// are left as they are, as the wallet can't create their issuer proofs. The old key ID is archived in
+// WalletParams.RevokedKeys; its key handle is kept in the KMS.
+func (s *Service) RotateSigningKey(ctx context.Context, keyType kms.KeyType) error {
+	if s.wallet == nil {
+		return errors.New("wallet is not created")
+	}
+
+	params := s.vcProviderConf.WalletParams
+
+	if len(params.DidID) == 0 {
+		return errors.New("wallet has no DID")
+	}
+
+	signType, ok := keySignatureType(keyType)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedKeyType, keyType)
+	}
+
+	oldDID, oldKeyID := params.DidID[0], params.DidKeyID[0]
+
+	createRes, err := s.rotateDIDKey(oldDID, keyType)
+	if err != nil {
+		return fmt.Errorf("rotate key of %s: %w", oldDID, err)
+	}
+
+	credentials, err := s.ListCredentials(ctx, &CredentialFilter{IssuerDID: oldDID})
+	if err != nil {
+		return err
+	}
+
+	signer := &vc.Signer{
+		DID:                     createRes.DidID,
+		KMSKeyID:                strings.Split(createRes.KeyID, "#")[1],
+		SignatureType:           signType,
+		KeyType:                 keyType,
+		SignatureRepresentation: verifiable.SignatureProofValue,
+		KMS: vcskms.GetAriesKeyManager(
+			s.ariesServices.kms, s.ariesServices.crypto, vcskms.Local, noop.GetMetrics()),
+	}
+
+	if signer.Creator, err = resolveSignerKeyID(createRes.KeyID); err != nil {
+		return fmt.Errorf("resolve signer key id: %w", err)
+	}
+
+	for _, credential := range credentials {
+		if err = s.resignCredential(credential, signer); err != nil {
+			return fmt.Errorf("re-sign credential %s: %w", credential.ID, err)
+		}
+	}
+
+	params.DidID[0], params.DidKeyID[0] = createRes.DidID, createRes.KeyID
+	params.SignType = signType
+	params.RevokedKeys = append(params.RevokedKeys, oldKeyID)
+	s.vcProviderConf.DidKeyType = string(keyType)
+
+	return nil
+}
+
+// rotateDIDKey creates a new key for the DID, updating the DID document for did:web and creating a new DID
+// for the other methods.
+func (s *Service) rotateDIDKey(didID string, keyType kms.KeyType) (*vdrutil.CreateResult, error) {
+	method, err := vdr.GetDidMethod(didID)
+	if err != nil {
+		return nil, err
+	}
+
+	if method != didMethodWeb {
+		return vdrutil.DefaultVdrUtil.Create(method, keyType, s.ariesServices.vdrRegistry, s.ariesServices.kms)
+	}
+
+	createRes, err := vdrutil.DefaultVdrUtil.RotateWebKey(didID, keyType, s.ariesServices.vdrRegistry,
+		s.ariesServices.kms)
+	if err != nil {
+		return nil, err
+	}
+
+	path, ok := s.webDIDPaths[didID]
+	if !ok {
+		return createRes, nil
+	}
+
+	docRes, err := s.ariesServices.vdrRegistry.Resolve(didID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", didID, err)
+	}
+
+	if err = writeWebDIDDocument(docRes.DIDDocument, path); err != nil {
+		return nil, err
+	}
+
+	return createRes, nil
+}
+
+// resignCredential replaces the proofs of the self-issued credential with a proof made by the signer and
+// stores the credential in place of the old one. JWT credentials stay JWT; the others get a JsonWebSignature2020
+// linked data proof.
+func (s *Service) resignCredential(credential *verifiable.Credential, signer *vc.Signer) error {
+	var opts []vccrypto.SigningOpts
+
+	if credential.JWT != "" {
+		signer.Format = vcs.Jwt
+	} else {
+		signer.Format = vcs.Ldp
+		opts = append(opts, vccrypto.WithSignatureType(vcs.JSONWebSignature2020))
+
+		if !lo.Contains(credential.Context, jsonWebSignature2020Context) {
+			credential.Context = append(credential.Context, jsonWebSignature2020Context)
+		}
+	}
+
+	credential.Issuer.ID = signer.DID
+	credential.Proofs = nil
+	credential.JWT = ""
+
+	signed, err := vccrypto.New(s.ariesServices.vdrRegistry, s.ariesServices.documentLoader).
+		SignCredential(signer, credential, opts...)
+	if err != nil {
+		return err
+	}
+
+	content, err := signed.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("marshal credential: %w", err)
+	}
+
+	return s.wallet.Add(content)
+}
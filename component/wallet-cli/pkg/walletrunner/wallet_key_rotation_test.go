@@ -0,0 +1,181 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/did-go/doc/did"
+	utiltime "github.com/trustbloc/did-go/doc/util/time"
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/vc-go/verifiable"
+
+	"github.com/trustbloc/vcs/component/wallet-cli/internal/vdrutil"
+	"github.com/trustbloc/vcs/pkg/doc/vc"
+	vccrypto "github.com/trustbloc/vcs/pkg/doc/vc/crypto"
+	vcs "github.com/trustbloc/vcs/pkg/doc/verifiable"
+	vcskms "github.com/trustbloc/vcs/pkg/kms"
+	"github.com/trustbloc/vcs/pkg/observability/metrics/noop"
+)
+
+func TestRotateSigningKey(t *testing.T) {
+	newCredential := func(id, issuer string) *verifiable.Credential {
+		return &verifiable.Credential{
+			Context: []string{verifiable.ContextURI},
+			ID:      id,
+			Types:   []string{verifiable.VCType},
+			Issuer:  verifiable.Issuer{ID: issuer},
+			Issued:  utiltime.NewTime(time.Now()),
+			Subject: "did:example:subject",
+		}
+	}
+
+	getCredential := func(t *testing.T, s *Service, id string) *verifiable.Credential {
+		t.Helper()
+
+		credentials, err := s.ListCredentials(context.Background(), nil)
+		require.NoError(t, err)
+
+		for _, credential := range credentials {
+			if credential.ID == id {
+				return credential
+			}
+		}
+
+		require.Failf(t, "credential not found", id)
+
+		return nil
+	}
+
+	verifyCredential := func(t *testing.T, s *Service, credential *verifiable.Credential) {
+		t.Helper()
+
+		b, err := credential.MarshalJSON()
+		require.NoError(t, err)
+
+		_, err = verifiable.ParseCredential(b,
+			verifiable.WithPublicKeyFetcher(verifiable.NewVDRKeyResolver(s.ariesServices.vdrRegistry).PublicKeyFetcher()),
+			verifiable.WithJSONLDDocumentLoader(s.ariesServices.documentLoader))
+		require.NoError(t, err)
+	}
+
+	t.Run("did:web", func(t *testing.T) {
+		s := newTestWalletService(t)
+		require.NoError(t, s.ensureWalletServices())
+
+		dir := t.TempDir()
+
+		doc, err := s.CreateWebDID(context.Background(), "example.com", dir)
+		require.NoError(t, err)
+
+		oldKeyID := s.vcProviderConf.WalletParams.DidKeyID[0]
+
+		require.NoError(t, s.StoreCredential(context.Background(),
+			newCredential("http://example.edu/credentials/1", doc.ID)))
+		require.NoError(t, s.StoreCredential(context.Background(),
+			newCredential("http://example.edu/credentials/2", "did:example:issuer")))
+
+		require.NoError(t, s.RotateSigningKey(context.Background(), kms.ECDSAP256TypeDER))
+
+		params := s.vcProviderConf.WalletParams
+		newKeyID := params.DidKeyID[0]
+
+		require.Equal(t, doc.ID, params.DidID[0])
+		require.NotEqual(t, oldKeyID, newKeyID)
+		require.Equal(t, []string{oldKeyID}, params.RevokedKeys)
+		require.Equal(t, vcs.ES256, params.SignType)
+
+		docRes, err := s.ariesServices.vdrRegistry.Resolve(doc.ID)
+		require.NoError(t, err)
+		require.Len(t, docRes.DIDDocument.VerificationMethod, 2)
+		require.Equal(t, oldKeyID, docRes.DIDDocument.VerificationMethod[0].ID)
+		require.Equal(t, newKeyID, docRes.DIDDocument.Authentication[0].VerificationMethod.ID)
+		require.Equal(t, newKeyID, docRes.DIDDocument.AssertionMethod[0].VerificationMethod.ID)
+
+		b, err := os.ReadFile(filepath.Join(dir, ".well-known", "did.json"))
+		require.NoError(t, err)
+
+		written, err := did.ParseDocument(b)
+		require.NoError(t, err)
+		require.Len(t, written.VerificationMethod, 2)
+
+		selfIssued := getCredential(t, s, "http://example.edu/credentials/1")
+		require.Len(t, selfIssued.Proofs, 1)
+		require.Equal(t, newKeyID, selfIssued.Proofs[0]["verificationMethod"])
+		verifyCredential(t, s, selfIssued)
+
+		require.Empty(t, getCredential(t, s, "http://example.edu/credentials/2").Proofs)
+	})
+
+	t.Run("did:key", func(t *testing.T) {
+		s := newTestWalletService(t)
+		require.NoError(t, s.ensureWalletServices())
+
+		createRes, err := vdrutil.DefaultVdrUtil.CreateKey(kms.ED25519Type, s.ariesServices.vdrRegistry,
+			s.ariesServices.kms)
+		require.NoError(t, err)
+
+		s.vcProviderConf.WalletParams.DidID = []string{createRes.DidID}
+		s.vcProviderConf.WalletParams.DidKeyID = []string{createRes.KeyID}
+
+		creator, err := resolveSignerKeyID(createRes.KeyID)
+		require.NoError(t, err)
+
+		jwtVC, err := vccrypto.New(s.ariesServices.vdrRegistry, s.ariesServices.documentLoader).SignCredential(
+			&vc.Signer{
+				DID:           createRes.DidID,
+				Creator:       creator,
+				KMSKeyID:      strings.Split(createRes.KeyID, "#")[1],
+				SignatureType: vcs.EdDSA,
+				KeyType:       kms.ED25519Type,
+				Format:        vcs.Jwt,
+				KMS: vcskms.GetAriesKeyManager(
+					s.ariesServices.kms, s.ariesServices.crypto, vcskms.Local, noop.GetMetrics()),
+			},
+			newCredential("http://example.edu/credentials/1", createRes.DidID),
+		)
+		require.NoError(t, err)
+		require.NoError(t, s.StoreCredential(context.Background(), jwtVC))
+		require.NotEmpty(t, getCredential(t, s, "http://example.edu/credentials/1").JWT)
+
+		require.NoError(t, s.RotateSigningKey(context.Background(), kms.ED25519Type))
+
+		params := s.vcProviderConf.WalletParams
+
+		require.NotEqual(t, createRes.DidID, params.DidID[0])
+		require.True(t, strings.HasPrefix(params.DidID[0], "did:key:"))
+		require.Equal(t, []string{createRes.KeyID}, params.RevokedKeys)
+
+		resigned := getCredential(t, s, "http://example.edu/credentials/1")
+		require.NotEmpty(t, resigned.JWT)
+		require.Equal(t, params.DidID[0], resigned.Issuer.ID)
+		verifyCredential(t, s, resigned)
+	})
+
+	t.Run("Unsupported key type", func(t *testing.T) {
+		s := newTestWalletService(t)
+		require.NoError(t, s.ensureWalletServices())
+
+		_, err := s.CreateWebDID(context.Background(), "example.com", t.TempDir())
+		require.NoError(t, err)
+
+		err = s.RotateSigningKey(context.Background(), kms.BLS12381G2Type)
+		require.ErrorIs(t, err, ErrUnsupportedKeyType)
+		require.Empty(t, s.vcProviderConf.WalletParams.RevokedKeys)
+	})
+
+	t.Run("Wallet not created", func(t *testing.T) {
+		err := newTestWalletService(t).RotateSigningKey(context.Background(), kms.ED25519Type)
+		require.ErrorContains(t, err, "wallet is not created")
+	})
+}
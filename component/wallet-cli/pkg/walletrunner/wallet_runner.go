@@ -7,12 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package walletrunner
 
 import (
+	"context"
 	"crypto/tls"
 	_ "embed"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/cookiejar"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/henvic/httpretty"
@@ -101,18 +105,23 @@ var extraContexts = []ldcontext.Document{ //nolint:gochecknoglobals
 	},
 }
 
+const defaultLinkedDomainVerificationTimeout = 10 * time.Second
+
 type Service struct {
-	ariesServices  *ariesServices
-	wallet         Wallet
-	vcProvider     vcprovider.VCProvider
-	vcProviderConf *vcprovider.Config
-	httpClient     *http.Client
-	oauthClient    *oauth2.Config
-	token          *oauth2.Token
-	perfInfo       *PerfInfo
-	vpFlowExecutor *VPFlowExecutor
-	keepWalletOpen bool
-	debug          bool
+	ariesServices          *ariesServices
+	wallet                 Wallet
+	vcProvider             vcprovider.VCProvider
+	vcProviderConf         *vcprovider.Config
+	httpClient             *http.Client
+	linkedDomainHTTPClient *http.Client
+	oauthClient            *oauth2.Config
+	token                  *oauth2.Token
+	perfInfo               *PerfInfo
+	vpFlowExecutor         *VPFlowExecutor
+	metrics                MetricsProvider
+	webDIDPaths            map[string]string // did:web DID -> path its DID document is written to
+	keepWalletOpen         bool
+	debug                  bool
 }
 
 func New(vcProviderType string, opts ...vcprovider.ConfigOption) (*Service, error) {
@@ -128,11 +137,22 @@ func New(vcProviderType string, opts ...vcprovider.ConfigOption) (*Service, erro
 		return nil, fmt.Errorf("init cookie jar: %w", err)
 	}
 
+	transport := &http.Transport{
+		TLSClientConfig: config.TLS,
+	}
+
+	if config.ProxyURL != "" {
+		proxyURL, parseErr := url.Parse(config.ProxyURL)
+		if parseErr != nil {
+			return nil, fmt.Errorf("parse proxy url: %w", parseErr)
+		}
+
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
 	httpClient := &http.Client{
-		Jar: cookie,
-		Transport: &http.Transport{
-			TLSClientConfig: config.TLS,
-		},
+		Jar:       cookie,
+		Transport: transport,
 	}
 
 	if config.Debug {
@@ -151,14 +171,51 @@ func New(vcProviderType string, opts ...vcprovider.ConfigOption) (*Service, erro
 		httpClient.Transport = httpLogger.RoundTripper(httpClient.Transport)
 	}
 
-	return &Service{
+	httpClient.Transport = newRetryTransport(httpClient.Transport, config.Retry)
+
+	metrics := config.Metrics
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+
+	linkedDomainVerificationTimeout := config.LinkedDomainVerificationTimeout
+	if linkedDomainVerificationTimeout == 0 {
+		linkedDomainVerificationTimeout = defaultLinkedDomainVerificationTimeout
+	}
+
+	s := &Service{
 		vcProvider:     vcProvider,
 		vcProviderConf: config,
 		httpClient:     httpClient,
+		linkedDomainHTTPClient: &http.Client{
+			Jar:       httpClient.Jar,
+			Transport: httpClient.Transport,
+			Timeout:   linkedDomainVerificationTimeout,
+		},
 		perfInfo:       &PerfInfo{},
+		metrics:        metrics,
 		debug:          config.Debug,
 		keepWalletOpen: config.KeepWalletOpen,
-	}, nil
+	}
+
+	if config.SignHTTPRequests {
+		s.httpClient.Transport = &httpSignatureTransport{
+			base:      httpClient.Transport,
+			getSigner: s.httpRequestSigner,
+			now:       time.Now,
+		}
+	}
+
+	return s, nil
+}
+
+// HealthCheck checks that wallet services (VDR registry and storage) are functional.
+func (s *Service) HealthCheck(ctx context.Context) error {
+	if s.ariesServices == nil {
+		return errors.New("wallet services are not initialized")
+	}
+
+	return s.ariesServices.HealthCheck(ctx)
 }
 
 func (s *Service) GetConfig() *vcprovider.Config {
@@ -223,9 +280,9 @@ func (s *Service) createAgentServices(vcProviderConf *vcprovider.Config) (*aries
 
 	provider.crypto = cryptoImpl
 
-	kmsStore, err := kms.NewAriesProviderWrapper(provider.storageProvider)
+	kmsStore, err := newKMSStore(provider.storageProvider)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Aries KMS store wrapper")
+		return nil, fmt.Errorf("failed to create KMS store: %w", err)
 	}
 
 	kmsProv := kmsProvider{
@@ -342,12 +399,19 @@ func createVDR(vcProviderConf *vcprovider.Config) (vdrapi.Registry, error) {
 						TLSClientConfig: vcProviderConf.TLS,
 					},
 				},
-				VDR: web.New(),
+				VDR:  web.New(),
+				docs: map[string]*did.Doc{},
 			},
 		),
 	)
 
-	return vdr.New(opts...), nil
+	registry := vdr.New(opts...)
+
+	if len(vcProviderConf.VDRMethods) > 0 {
+		return newMethodsRegistry(registry, vcProviderConf.VDRMethods, vcProviderConf.TLS)
+	}
+
+	return registry, nil
 }
 
 type kmsProvider struct {
@@ -363,6 +427,44 @@ func (k kmsProvider) SecretLock() secretlock.Service {
 	return k.secretLockService
 }
 
+const kmsKeyTag = "kms_key"
+
+// kmsStore is a KMS keyset store that tags the keysets so that they can be listed on wallet export.
+// It uses the same underlying store as kms.NewAriesProviderWrapper.
+type kmsStore struct {
+	store storage.Store
+}
+
+func newKMSStore(provider storage.Provider) (*kmsStore, error) {
+	store, err := provider.OpenStore(kms.AriesWrapperStoreName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kmsStore{store: store}, nil
+}
+
+func (k *kmsStore) Put(keysetID string, key []byte) error {
+	return k.store.Put(keysetID, key, storage.Tag{Name: kmsKeyTag})
+}
+
+func (k *kmsStore) Get(keysetID string) ([]byte, error) {
+	key, err := k.store.Get(keysetID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, fmt.Errorf("%w: %s", kms.ErrKeyNotFound, err.Error())
+		}
+
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (k *kmsStore) Delete(keysetID string) error {
+	return k.store.Delete(keysetID)
+}
+
 type ldStoreProvider struct {
 	ContextStore        ldstore.ContextStore
 	RemoteProviderStore ldstore.RemoteProviderStore
@@ -376,12 +478,38 @@ func (p *ldStoreProvider) JSONLDRemoteProviderStore() ldstore.RemoteProviderStor
 	return p.RemoteProviderStore
 }
 
+// webVDR resolves did:web DIDs over HTTP. DIDs created by the wallet are kept in memory and resolved locally,
+// as their documents may not be served yet.
 type webVDR struct {
 	http *http.Client
 	*web.VDR
+
+	mu   sync.RWMutex
+	docs map[string]*did.Doc
+}
+
+func (w *webVDR) Create(didDoc *did.Doc, _ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if !strings.HasPrefix(didDoc.ID, "did:web:") {
+		return nil, fmt.Errorf("invalid did:web id: %s", didDoc.ID)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.docs[didDoc.ID] = didDoc
+
+	return &did.DocResolution{DIDDocument: didDoc}, nil
 }
 
 func (w *webVDR) Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	w.mu.RLock()
+	doc, ok := w.docs[didID]
+	w.mu.RUnlock()
+
+	if ok {
+		return &did.DocResolution{DIDDocument: doc}, nil
+	}
+
 	docRes, err := w.VDR.Read(didID, append(opts, vdrapi.WithOption(web.HTTPClientOpt, w.http))...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read did web: %w", err)
@@ -0,0 +1,347 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/vc-go/presexch"
+	"github.com/trustbloc/vc-go/verifiable"
+
+	"github.com/trustbloc/vcs/component/wallet-cli/internal/httputil"
+)
+
+const (
+	oobInvitationMsgType       = "https://didcomm.org/out-of-band/2.0/invitation"
+	proposePresentationMsgType = "https://didcomm.org/present-proof/3.0/propose-presentation"
+	requestPresentationMsgType = "https://didcomm.org/present-proof/3.0/request-presentation"
+	presentationMsgType        = "https://didcomm.org/present-proof/3.0/presentation"
+
+	didCommMessagingServiceType = "DIDCommMessaging"
+	didCommPlainMediaType       = "application/didcomm-plain+json"
+
+	peDefinitionAttachFormat = "dif/presentation-exchange/definitions@v1.0"
+	peSubmissionAttachFormat = "dif/presentation-exchange/submission@v1.0"
+
+	oobQueryParam = "_oob"
+)
+
+// didCommMessage is a DIDComm v2 plaintext message.
+type didCommMessage struct {
+	ID          string              `json:"id"`
+	Type        string              `json:"type"`
+	From        string              `json:"from,omitempty"`
+	To          []string            `json:"to,omitempty"`
+	ThID        string              `json:"thid,omitempty"`
+	PThID       string              `json:"pthid,omitempty"`
+	ReturnRoute string              `json:"return_route,omitempty"`
+	Body        json.RawMessage     `json:"body,omitempty"`
+	Attachments []didCommAttachment `json:"attachments,omitempty"`
+}
+
+type didCommAttachment struct {
+	ID        string                `json:"id,omitempty"`
+	MediaType string                `json:"media_type,omitempty"`
+	Format    string                `json:"format,omitempty"`
+	Data      didCommAttachmentData `json:"data"`
+}
+
+type didCommAttachmentData struct {
+	JSON   json.RawMessage `json:"json,omitempty"`
+	Base64 string          `json:"base64,omitempty"`
+}
+
+// peDefinitionAttachment is the data of the present-proof request attachment in the DIF presentation exchange format.
+type peDefinitionAttachment struct {
+	Options struct {
+		Challenge string `json:"challenge"`
+		Domain    string `json:"domain"`
+	} `json:"options"`
+	PresentationDefinition *presexch.PresentationDefinition `json:"presentation_definition"`
+}
+
+// didCommConnection is the DIDComm messaging endpoint of the verifier resolved from the invitation.
+type didCommConnection struct {
+	theirDID string
+	endpoint string
+}
+
+// AcceptDIDCommPresentation accepts the DIDComm out-of-band invitation from the verifier and presents the matching
+// wallet credentials with the present-proof 3.0 protocol. The present-proof request is taken from the invitation
+// attachment or, if the invitation has none, requested from the verifier with a propose-presentation message.
+// Messages are exchanged over HTTP as DIDComm v2 plaintext messages.
+func (s *Service) AcceptDIDCommPresentation(ctx context.Context, invitationURL string) error {
+	if s.wallet == nil {
+		return errors.New("wallet is not created")
+	}
+
+	invitation, err := s.fetchOOBInvitation(ctx, invitationURL)
+	if err != nil {
+		return fmt.Errorf("fetch invitation: %w", err)
+	}
+
+	conn, err := s.connectDIDComm(invitation)
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", invitation.From, err)
+	}
+
+	request, err := s.receivePresentationRequest(ctx, invitation, conn)
+	if err != nil {
+		return fmt.Errorf("receive presentation request: %w", err)
+	}
+
+	attachment, err := presentationDefinitionAttachment(request)
+	if err != nil {
+		return err
+	}
+
+	credentials, err := s.selectCredentials(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	vp, err := attachment.PresentationDefinition.CreateVP(credentials, s.ariesServices.documentLoader,
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(s.ariesServices.documentLoader))
+	if err != nil {
+		return fmt.Errorf("match credentials with presentation definition: %w", err)
+	}
+
+	executor := s.NewVPFlowExecutor(s.vcProviderConf.SkipSchemaValidation)
+	executor.requestObject = &RequestObject{
+		Nonce:    attachment.Options.Challenge,
+		ClientID: attachment.Options.Domain,
+	}
+
+	didID, err := executor.GetSubjectID(vp.Credentials())
+	if err != nil {
+		return err
+	}
+
+	didIDIndex := executor.getDIDIndex(didID)
+	if didIDIndex < 0 {
+		return fmt.Errorf("wallet has no key for credential subject %s", didID)
+	}
+
+	signedVP, err := executor.signPresentationJWT(vp, executor.walletSignType, didID,
+		executor.walletDidKeyID[didIDIndex])
+	if err != nil {
+		return fmt.Errorf("sign presentation: %w", err)
+	}
+
+	presentation := &didCommMessage{
+		ID:    uuid.NewString(),
+		Type:  presentationMsgType,
+		From:  didID,
+		To:    []string{conn.theirDID},
+		ThID:  request.ID,
+		PThID: invitation.ID,
+		Body:  json.RawMessage(`{}`),
+		Attachments: []didCommAttachment{{
+			ID:        uuid.NewString(),
+			MediaType: "application/jwt",
+			Format:    peSubmissionAttachFormat,
+			Data: didCommAttachmentData{
+				Base64: base64.StdEncoding.EncodeToString([]byte(signedVP)),
+			},
+		}},
+	}
+
+	if _, err = s.sendDIDCommMessage(ctx, conn, presentation); err != nil {
+		return fmt.Errorf("send presentation: %w", err)
+	}
+
+	return nil
+}
+
+// fetchOOBInvitation decodes the invitation from the _oob query parameter of the invitation URL or, if the URL
+// has no such parameter, fetches the invitation from the URL.
+func (s *Service) fetchOOBInvitation(ctx context.Context, invitationURL string) (*didCommMessage, error) {
+	u, err := url.Parse(invitationURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse invitation url: %w", err)
+	}
+
+	var raw []byte
+
+	if oob := u.Query().Get(oobQueryParam); oob != "" {
+		raw, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(oob, "="))
+		if err != nil {
+			return nil, fmt.Errorf("decode %s: %w", oobQueryParam, err)
+		}
+	} else {
+		raw, err = s.httpGet(ctx, invitationURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var invitation didCommMessage
+
+	if err = json.Unmarshal(raw, &invitation); err != nil {
+		return nil, fmt.Errorf("unmarshal invitation: %w", err)
+	}
+
+	if invitation.Type != oobInvitationMsgType {
+		return nil, fmt.Errorf("unsupported invitation type %q", invitation.Type)
+	}
+
+	if invitation.From == "" {
+		return nil, errors.New("invitation has no sender did")
+	}
+
+	return &invitation, nil
+}
+
+// connectDIDComm resolves the DID of the invitation sender and returns the connection to its DIDComm messaging
+// service endpoint.
+func (s *Service) connectDIDComm(invitation *didCommMessage) (*didCommConnection, error) {
+	docResolution, err := s.ariesServices.vdrRegistry.Resolve(invitation.From)
+	if err != nil {
+		return nil, fmt.Errorf("resolve did: %w", err)
+	}
+
+	svc, ok := did.LookupService(docResolution.DIDDocument, didCommMessagingServiceType)
+	if !ok {
+		return nil, fmt.Errorf("did document has no %s service", didCommMessagingServiceType)
+	}
+
+	endpoint, err := svc.ServiceEndpoint.URI()
+	if err != nil {
+		return nil, fmt.Errorf("get %s service endpoint: %w", didCommMessagingServiceType, err)
+	}
+
+	return &didCommConnection{
+		theirDID: invitation.From,
+		endpoint: endpoint,
+	}, nil
+}
+
+// receivePresentationRequest returns the present-proof request attached to the invitation. If there is no such
+// attachment, it sends a propose-presentation message to the verifier and returns the request from the response.
+func (s *Service) receivePresentationRequest(
+	ctx context.Context,
+	invitation *didCommMessage,
+	conn *didCommConnection,
+) (*didCommMessage, error) {
+	for _, attachment := range invitation.Attachments {
+		var msg didCommMessage
+
+		if err := json.Unmarshal(attachment.Data.JSON, &msg); err != nil {
+			continue
+		}
+
+		if msg.Type == requestPresentationMsgType {
+			return &msg, nil
+		}
+	}
+
+	propose := &didCommMessage{
+		ID:          uuid.NewString(),
+		Type:        proposePresentationMsgType,
+		To:          []string{conn.theirDID},
+		PThID:       invitation.ID,
+		ReturnRoute: "all",
+		Body:        json.RawMessage(`{}`),
+	}
+
+	resp, err := s.sendDIDCommMessage(ctx, conn, propose)
+	if err != nil {
+		return nil, fmt.Errorf("send propose presentation: %w", err)
+	}
+
+	var request didCommMessage
+
+	if err = json.Unmarshal(resp, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	if request.Type != requestPresentationMsgType {
+		return nil, fmt.Errorf("unexpected response message type %q", request.Type)
+	}
+
+	return &request, nil
+}
+
+func presentationDefinitionAttachment(request *didCommMessage) (*peDefinitionAttachment, error) {
+	for _, attachment := range request.Attachments {
+		if attachment.Format != peDefinitionAttachFormat {
+			continue
+		}
+
+		var data peDefinitionAttachment
+
+		if err := json.Unmarshal(attachment.Data.JSON, &data); err != nil {
+			return nil, fmt.Errorf("unmarshal presentation definition attachment: %w", err)
+		}
+
+		if data.PresentationDefinition == nil {
+			return nil, errors.New("presentation definition attachment has no presentation definition")
+		}
+
+		return &data, nil
+	}
+
+	return nil, fmt.Errorf("presentation request has no %s attachment", peDefinitionAttachFormat)
+}
+
+func (s *Service) sendDIDCommMessage(ctx context.Context, conn *didCommConnection, msg *didCommMessage) ([]byte, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("marshal message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, conn.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", didCommPlainMediaType)
+
+	return s.doDIDCommRequest(ctx, req)
+}
+
+func (s *Service) httpGet(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.doDIDCommRequest(ctx, req)
+}
+
+func (s *Service) doDIDCommRequest(ctx context.Context, req *http.Request) ([]byte, error) {
+	resp, err := HttpClientFromContext(ctx, s.httpClient).Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer httputil.CloseResponseBody(resp.Body)
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("expected status code 2xx but got status code %d with response body %s instead",
+			resp.StatusCode, respBytes)
+	}
+
+	return respBytes, nil
+}
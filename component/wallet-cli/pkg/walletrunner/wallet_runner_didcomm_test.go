@@ -0,0 +1,191 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/doc/did/endpoint"
+	utiltime "github.com/trustbloc/did-go/doc/util/time"
+	mockvdr "github.com/trustbloc/did-go/vdr/mock"
+	"github.com/trustbloc/vc-go/presexch"
+	"github.com/trustbloc/vc-go/verifiable"
+)
+
+const verifierDID = "did:example:verifier"
+
+func TestAcceptDIDCommPresentation(t *testing.T) {
+	s := newTestWalletService(t)
+	require.NoError(t, s.ensureWalletServices())
+
+	require.NoError(t, s.StoreCredential(context.Background(), &verifiable.Credential{
+		Context: []string{verifiable.ContextURI},
+		ID:      "http://example.edu/credentials/1",
+		Types:   []string{verifiable.VCType},
+		Issuer:  verifiable.Issuer{ID: "did:example:issuer"},
+		Issued:  utiltime.NewTime(time.Now()),
+		Subject: "did:example:subject",
+	}))
+
+	request := newPresentationRequest(t, &presexch.PresentationDefinition{
+		ID: "pd-1",
+		InputDescriptors: []*presexch.InputDescriptor{{
+			ID: "driver-license",
+			Constraints: &presexch.Constraints{
+				Fields: []*presexch.Field{{
+					Path: []string{"$.type"},
+					Filter: &presexch.Filter{
+						Type:     strPtr("array"),
+						Contains: map[string]interface{}{"const": "DriverLicenseCredential"},
+					},
+				}},
+			},
+		}},
+	})
+
+	var received []*didCommMessage
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, didCommPlainMediaType, r.Header.Get("Content-Type"))
+
+		var msg didCommMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+
+		received = append(received, &msg)
+
+		require.NoError(t, json.NewEncoder(w).Encode(request))
+	}))
+	defer srv.Close()
+
+	s.ariesServices.vdrRegistry = &mockvdr.VDRegistry{
+		ResolveValue: &did.Doc{
+			ID: verifierDID,
+			Service: []did.Service{{
+				ID:              verifierDID + "#didcomm",
+				Type:            didCommMessagingServiceType,
+				ServiceEndpoint: endpoint.NewDIDCommV2Endpoint([]endpoint.DIDCommV2Endpoint{{URI: srv.URL}}),
+			}},
+		},
+	}
+
+	t.Run("Request from propose presentation", func(t *testing.T) {
+		received = nil
+
+		err := s.AcceptDIDCommPresentation(context.Background(), newOOBInvitationURL(t, &didCommMessage{
+			ID:   "invitation-1",
+			Type: oobInvitationMsgType,
+			From: verifierDID,
+		}))
+		require.ErrorContains(t, err, "match credentials with presentation definition")
+
+		require.Len(t, received, 1)
+		require.Equal(t, proposePresentationMsgType, received[0].Type)
+		require.Equal(t, "invitation-1", received[0].PThID)
+		require.Equal(t, "all", received[0].ReturnRoute)
+		require.Equal(t, []string{verifierDID}, received[0].To)
+	})
+
+	t.Run("Request attached to invitation fetched from url", func(t *testing.T) {
+		received = nil
+
+		requestJSON, err := json.Marshal(&didCommMessage{
+			ID:   "request-1",
+			Type: requestPresentationMsgType,
+		})
+		require.NoError(t, err)
+
+		invitationSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, json.NewEncoder(w).Encode(&didCommMessage{
+				ID:   "invitation-2",
+				Type: oobInvitationMsgType,
+				From: verifierDID,
+				Attachments: []didCommAttachment{{
+					ID:   "request-0",
+					Data: didCommAttachmentData{JSON: requestJSON},
+				}},
+			}))
+		}))
+		defer invitationSrv.Close()
+
+		err = s.AcceptDIDCommPresentation(context.Background(), invitationSrv.URL)
+		require.ErrorContains(t, err, "presentation request has no "+peDefinitionAttachFormat+" attachment")
+		require.Empty(t, received)
+	})
+
+	t.Run("Unsupported invitation type", func(t *testing.T) {
+		err := s.AcceptDIDCommPresentation(context.Background(), newOOBInvitationURL(t, &didCommMessage{
+			ID:   "invitation-3",
+			Type: "https://didcomm.org/out-of-band/1.1/invitation",
+			From: verifierDID,
+		}))
+		require.ErrorContains(t, err, "unsupported invitation type")
+	})
+
+	t.Run("No DIDComm messaging service", func(t *testing.T) {
+		withoutService := newTestWalletService(t)
+		require.NoError(t, withoutService.ensureWalletServices())
+
+		withoutService.ariesServices.vdrRegistry = &mockvdr.VDRegistry{
+			ResolveValue: &did.Doc{ID: verifierDID},
+		}
+
+		err := withoutService.AcceptDIDCommPresentation(context.Background(), newOOBInvitationURL(t, &didCommMessage{
+			ID:   "invitation-4",
+			Type: oobInvitationMsgType,
+			From: verifierDID,
+		}))
+		require.ErrorContains(t, err, "did document has no DIDCommMessaging service")
+	})
+
+	t.Run("Wallet not created", func(t *testing.T) {
+		err := newTestWalletService(t).AcceptDIDCommPresentation(context.Background(), "https://example.com/oob")
+		require.ErrorContains(t, err, "wallet is not created")
+	})
+}
+
+func newOOBInvitationURL(t *testing.T, invitation *didCommMessage) string {
+	t.Helper()
+
+	invitationJSON, err := json.Marshal(invitation)
+	require.NoError(t, err)
+
+	return "https://verifier.example.com/oob?_oob=" + base64.RawURLEncoding.EncodeToString(invitationJSON)
+}
+
+func newPresentationRequest(t *testing.T, pd *presexch.PresentationDefinition) *didCommMessage {
+	t.Helper()
+
+	data := &peDefinitionAttachment{PresentationDefinition: pd}
+	data.Options.Challenge = "challenge"
+	data.Options.Domain = verifierDID
+
+	dataJSON, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	return &didCommMessage{
+		ID:   "request-1",
+		Type: requestPresentationMsgType,
+		From: verifierDID,
+		Attachments: []didCommAttachment{{
+			ID:     "pd",
+			Format: peDefinitionAttachFormat,
+			Data:   didCommAttachmentData{JSON: dataJSON},
+		}},
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
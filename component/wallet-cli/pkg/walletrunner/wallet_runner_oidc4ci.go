@@ -70,6 +70,15 @@ func WithClientID(clientID string) OauthClientOpt {
 }
 
 func (s *Service) RunOIDC4CI(config *OIDC4CIConfig, hooks *Hooks) error {
+	startTime := time.Now()
+
+	err := s.runOIDC4CI(config, hooks)
+	s.recordIssuance(opOIDC4CI, startTime, err)
+
+	return err
+}
+
+func (s *Service) runOIDC4CI(config *OIDC4CIConfig, hooks *Hooks) error {
 	log.Println("Starting OIDC4VCI authorized code flow")
 	ctx := context.Background()
 	log.Printf("Initiate issuance URL:\n\n\t%s\n\n", config.InitiateIssuanceURL)
@@ -247,6 +256,15 @@ func (s *Service) RunOIDC4CI(config *OIDC4CIConfig, hooks *Hooks) error {
 }
 
 func (s *Service) RunOIDC4CIWalletInitiated(config *OIDC4CIConfig, hooks *Hooks) error {
+	startTime := time.Now()
+
+	err := s.runOIDC4CIWalletInitiated(config, hooks)
+	s.recordIssuance(opOIDC4CIWalletInitiated, startTime, err)
+
+	return err
+}
+
+func (s *Service) runOIDC4CIWalletInitiated(config *OIDC4CIConfig, hooks *Hooks) error {
 	log.Println("Starting OIDC4VCI authorized code flow Wallet initiated")
 	ctx := context.Background()
 
@@ -541,11 +559,42 @@ func (s *Service) getAuthCodeFromBrowser(
 	}
 }
 
+// getCredential requests the credential from the issuer. If the issuer rejects the proof because the c_nonce has
+// expired and supplies a fresh one, the nonce is updated and the request is retried once.
 func (s *Service) getCredential(
 	credentialEndpoint,
 	credentialType,
 	credentialFormat,
 	issuerURI string,
+) (interface{}, time.Duration, error) {
+	vc, duration, err := s.requestCredential(credentialEndpoint, credentialType, credentialFormat, issuerURI)
+	if err == nil {
+		return vc, duration, nil
+	}
+
+	var credentialErr *credentialRequestError
+
+	if !errors.As(err, &credentialErr) || !credentialErr.isNonceRefresh() {
+		return nil, duration, err
+	}
+
+	s.print("Retrying credential request with refreshed c_nonce")
+
+	s.token = s.token.WithExtra(map[string]interface{}{
+		"c_nonce":            credentialErr.response.CNonce,
+		"c_nonce_expires_in": credentialErr.response.CNonceExpiresIn,
+	})
+
+	vc, retryDuration, err := s.requestCredential(credentialEndpoint, credentialType, credentialFormat, issuerURI)
+
+	return vc, duration + retryDuration, err
+}
+
+func (s *Service) requestCredential(
+	credentialEndpoint,
+	credentialType,
+	credentialFormat,
+	issuerURI string,
 ) (interface{}, time.Duration, error) {
 	km := s.ariesServices.KMS()
 	cr := s.ariesServices.Crypto()
@@ -629,11 +678,15 @@ func (s *Service) getCredential(
 
 	if resp.StatusCode != http.StatusOK {
 		b, _ := io.ReadAll(resp.Body)
-		return nil, finalDuration, fmt.Errorf(
-			"get credential: status %s and body %s",
-			resp.Status,
-			string(b),
-		)
+
+		credentialErr := &credentialRequestError{
+			status: resp.Status,
+			body:   string(b),
+		}
+
+		_ = json.Unmarshal(b, &credentialErr.response)
+
+		return nil, finalDuration, credentialErr
 	}
 
 	var credentialResp CredentialResponse
@@ -645,6 +698,31 @@ func (s *Service) getCredential(
 	return credentialResp.Credential, finalDuration, nil
 }
 
+// credentialRequestError is returned when the issuer rejects the credential request.
+type credentialRequestError struct {
+	status   string
+	body     string
+	response CredentialErrorResponse
+}
+
+func (e *credentialRequestError) Error() string {
+	return fmt.Sprintf("get credential: status %s and body %s", e.status, e.body)
+}
+
+// isNonceRefresh reports whether the issuer rejected the proof and supplied a fresh c_nonce to retry with.
+func (e *credentialRequestError) isNonceRefresh() bool {
+	if e.response.CNonce == "" {
+		return false
+	}
+
+	switch e.response.Error {
+	case "invalid_proof", "invalid_or_missing_proof", "use_dpop_nonce":
+		return true
+	default:
+		return false
+	}
+}
+
 func (s *Service) print(
 	msg string,
 ) {
@@ -27,6 +27,15 @@ import (
 )
 
 func (s *Service) RunOIDC4CIPreAuth(config *OIDC4CIConfig) (*verifiable.Credential, error) {
+	startTime := time.Now()
+
+	vc, err := s.runOIDC4CIPreAuth(config)
+	s.recordIssuance(opOIDC4CIPreAuth, startTime, err)
+
+	return vc, err
+}
+
+func (s *Service) runOIDC4CIPreAuth(config *OIDC4CIConfig) (*verifiable.Credential, error) {
 	log.Println("Starting OIDC4VCI pre-authorized code flow")
 
 	ctx := context.Background()
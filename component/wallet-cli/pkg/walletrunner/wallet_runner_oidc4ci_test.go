@@ -0,0 +1,122 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/kms-go/spi/kms"
+	"golang.org/x/oauth2"
+
+	"github.com/trustbloc/vcs/component/wallet-cli/internal/vdrutil"
+	vcs "github.com/trustbloc/vcs/pkg/doc/verifiable"
+)
+
+func TestGetCredentialNonceRefresh(t *testing.T) {
+	newService := func(t *testing.T) *Service {
+		t.Helper()
+
+		s := newTestWalletService(t)
+		require.NoError(t, s.ensureWalletServices())
+
+		createRes, err := vdrutil.DefaultVdrUtil.Create("key", kms.ED25519Type, s.ariesServices.vdrRegistry,
+			s.ariesServices.kms)
+		require.NoError(t, err)
+
+		s.vcProviderConf.WalletParams.DidKeyID = []string{createRes.KeyID}
+		s.vcProviderConf.WalletParams.SignType = vcs.EdDSA
+		s.oauthClient = &oauth2.Config{ClientID: "oidc4vc_client"}
+		s.token = (&oauth2.Token{AccessToken: "access-token"}).WithExtra(map[string]interface{}{
+			"c_nonce": "expired-nonce",
+		})
+
+		return s
+	}
+
+	proofNonce := func(t *testing.T, r *http.Request) string {
+		t.Helper()
+
+		var req CredentialRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		parts := strings.Split(req.Proof.JWT, ".")
+		require.Len(t, parts, 3)
+
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+
+		var claims JWTProofClaims
+		require.NoError(t, json.Unmarshal(payload, &claims))
+
+		return claims.Nonce
+	}
+
+	t.Run("Retry with refreshed nonce", func(t *testing.T) {
+		var requests atomic.Int32
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+
+			if proofNonce(t, r) == "expired-nonce" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_proof","c_nonce":"fresh-nonce","c_nonce_expires_in":300}`))
+
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"credential":"vc","format":"jwt_vc_json"}`))
+		}))
+		defer srv.Close()
+
+		s := newService(t)
+
+		vc, _, err := s.getCredential(srv.URL, "UniversityDegreeCredential", "jwt_vc_json", srv.URL)
+		require.NoError(t, err)
+		require.Equal(t, "vc", vc)
+		require.EqualValues(t, 2, requests.Load())
+		require.Equal(t, "fresh-nonce", s.token.Extra("c_nonce"))
+	})
+
+	t.Run("Retry only once", func(t *testing.T) {
+		var requests atomic.Int32
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"use_dpop_nonce","c_nonce":"fresh-nonce"}`))
+		}))
+		defer srv.Close()
+
+		_, _, err := newService(t).getCredential(srv.URL, "UniversityDegreeCredential", "jwt_vc_json", srv.URL)
+		require.ErrorContains(t, err, "use_dpop_nonce")
+		require.EqualValues(t, 2, requests.Load())
+	})
+
+	t.Run("No retry without new nonce", func(t *testing.T) {
+		var requests atomic.Int32
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_proof"}`))
+		}))
+		defer srv.Close()
+
+		_, _, err := newService(t).getCredential(srv.URL, "UniversityDegreeCredential", "jwt_vc_json", srv.URL)
+		require.ErrorContains(t, err, "status 400 Bad Request")
+		require.EqualValues(t, 1, requests.Load())
+	})
+}
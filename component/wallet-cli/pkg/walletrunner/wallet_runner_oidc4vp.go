@@ -11,6 +11,7 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -20,6 +21,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/samber/lo"
 	"github.com/trustbloc/did-go/method/jwk"
 	"github.com/valyala/fastjson"
 
@@ -53,6 +55,15 @@ type OIDC4VPHooks struct {
 }
 
 func (s *Service) RunOIDC4VPFlow(ctx context.Context, authorizationRequest string, hooks *OIDC4VPHooks) error {
+	startTime := time.Now()
+
+	err := s.runOIDC4VPFlow(ctx, authorizationRequest, hooks)
+	s.recordPresentation(opOIDC4VP, startTime, err)
+
+	return err
+}
+
+func (s *Service) runOIDC4VPFlow(ctx context.Context, authorizationRequest string, hooks *OIDC4VPHooks) error {
 	log.Println("Start OIDC4VP flow")
 	log.Println("AuthorizationRequest:", authorizationRequest)
 
@@ -143,6 +154,109 @@ func (s *Service) RunOIDC4VPFlow(ctx context.Context, authorizationRequest strin
 	return nil
 }
 
+// PresentCredentials presents the wallet credentials with the given IDs to the verifier of the authorization
+// request. If no IDs are given, all stored credentials are matched against the presentation definition of the
+// request object. The matching credentials are presented in a single VP signed with the wallet DID key.
+func (s *Service) PresentCredentials(
+	ctx context.Context,
+	authorizationRequestURI string,
+	credentialIDs []string,
+) error {
+	startTime := time.Now()
+
+	err := s.presentCredentials(ctx, authorizationRequestURI, credentialIDs)
+	s.recordPresentation(opPresentCredentials, startTime, err)
+
+	return err
+}
+
+func (s *Service) presentCredentials(
+	ctx context.Context,
+	authorizationRequestURI string,
+	credentialIDs []string,
+) error {
+	if s.wallet == nil {
+		return errors.New("wallet is not created")
+	}
+
+	credentials, err := s.selectCredentials(ctx, credentialIDs)
+	if err != nil {
+		return err
+	}
+
+	executor := s.NewVPFlowExecutor(s.vcProviderConf.SkipSchemaValidation)
+
+	rawRequestObject, _, err := executor.FetchRequestObject(authorizationRequestURI)
+	if err != nil {
+		return fmt.Errorf("fetch request object: %w", err)
+	}
+
+	if err = executor.VerifyAuthorizationRequestAndDecodeClaims(rawRequestObject); err != nil {
+		return fmt.Errorf("verify request object: %w", err)
+	}
+
+	pd := executor.requestObject.Claims.VPToken.PresentationDefinition
+	if pd == nil {
+		return errors.New("request object has no presentation definition")
+	}
+
+	vp, err := pd.CreateVP(credentials, s.ariesServices.documentLoader,
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(s.ariesServices.documentLoader))
+	if err != nil {
+		return fmt.Errorf("match credentials with presentation definition: %w", err)
+	}
+
+	submission, ok := vp.CustomFields["presentation_submission"].(*presexch.PresentationSubmission)
+	if !ok {
+		return errors.New("missing presentation submission")
+	}
+
+	executor.requestPresentation = []*verifiable.Presentation{vp}
+	executor.requestPresentationSubmission = submission
+
+	authorizedResponse, err := executor.CreateAuthorizedResponse()
+	if err != nil {
+		return fmt.Errorf("create authorized response: %w", err)
+	}
+
+	if _, err = executor.SendAuthorizedResponse(ctx, authorizedResponse); err != nil {
+		return fmt.Errorf("send authorized response: %w", err)
+	}
+
+	return nil
+}
+
+// selectCredentials returns the stored credentials with the given IDs, or all stored credentials if no IDs
+// are given. It fails with ErrCredentialNotFound if any of the credentials is not stored.
+func (s *Service) selectCredentials(ctx context.Context, credentialIDs []string) ([]*verifiable.Credential, error) {
+	credentials, err := s.ListCredentials(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(credentialIDs) == 0 {
+		return credentials, nil
+	}
+
+	byID := lo.KeyBy(credentials, func(vc *verifiable.Credential) string {
+		return vc.ID
+	})
+
+	selected := make([]*verifiable.Credential, 0, len(credentialIDs))
+
+	for _, id := range credentialIDs {
+		vc, found := byID[id]
+		if !found {
+			return nil, fmt.Errorf("%w: %s", ErrCredentialNotFound, id)
+		}
+
+		selected = append(selected, vc)
+	}
+
+	return selected, nil
+}
+
 type VPFlowExecutor struct {
 	tlsConfig                     *tls.Config
 	ariesServices                 *ariesServices
@@ -170,9 +284,9 @@ func (s *Service) NewVPFlowExecutor(skipSchemaValidation bool) *VPFlowExecutor {
 		walletSignType:       s.vcProviderConf.WalletParams.SignType,
 		skipSchemaValidation: skipSchemaValidation,
 		httpClient: &http.Client{
-			Transport: &http.Transport{
+			Transport: newRetryTransport(&http.Transport{
 				TLSClientConfig: s.vcProviderConf.TLS,
-			},
+			}, s.vcProviderConf.Retry),
 		},
 	}
 }
@@ -621,6 +735,28 @@ func signTokenJWT(claims interface{}, didKeyID string, crpt crypto.Crypto,
 		return "", fmt.Errorf("create kms signer: %w", err)
 	}
 
+	signerKeyID, err := resolveSignerKeyID(didKeyID)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := jwt.NewSigned(claims, map[string]interface{}{"typ": "JWT"}, NewJWSSigner(signerKeyID,
+		string(signType), kmsSigner))
+	if err != nil {
+		return "", fmt.Errorf("initiate oidc interaction: sign token failed: %w", err)
+	}
+
+	tokenBytes, err := token.Serialize(false)
+	if err != nil {
+		return "", fmt.Errorf("initiate oidc interaction: serialize token failed: %w", err)
+	}
+
+	return tokenBytes, nil
+}
+
+// resolveSignerKeyID returns the verification method ID for the wallet did key ID. The IDs of did:key and
+// did:jwk verification methods differ from the key ID the wallet is created with.
+func resolveSignerKeyID(didKeyID string) (string, error) {
 	signerKeyID := didKeyID
 
 	if strings.Contains(didKeyID, "did:key") {
@@ -639,27 +775,22 @@ func signTokenJWT(claims interface{}, didKeyID string, crpt crypto.Crypto,
 		signerKeyID = res.DIDDocument.VerificationMethod[0].ID
 	}
 
-	token, err := jwt.NewSigned(claims, map[string]interface{}{"typ": "JWT"}, NewJWSSigner(signerKeyID,
-		string(signType), kmsSigner))
-	if err != nil {
-		return "", fmt.Errorf("initiate oidc interaction: sign token failed: %w", err)
-	}
-
-	tokenBytes, err := token.Serialize(false)
-	if err != nil {
-		return "", fmt.Errorf("initiate oidc interaction: serialize token failed: %w", err)
-	}
-
-	return tokenBytes, nil
+	return signerKeyID, nil
 }
 
 func (e *VPFlowExecutor) SendAuthorizedResponse(ctx context.Context, responseBody string) (time.Duration, error) {
 	log.Printf("auth req: %s\n", responseBody)
 
+	// with direct_post response mode the authorization response is sent to response_uri
+	responseURI := e.requestObject.ResponseURI
+	if responseURI == "" {
+		responseURI = e.requestObject.RedirectURI
+	}
+
 	req, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodPost,
-		e.requestObject.RedirectURI,
+		responseURI,
 		bytes.NewBuffer([]byte(responseBody)),
 	)
 	if err != nil {
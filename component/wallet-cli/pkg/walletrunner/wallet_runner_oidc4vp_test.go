@@ -0,0 +1,55 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	utiltime "github.com/trustbloc/did-go/doc/util/time"
+	"github.com/trustbloc/vc-go/verifiable"
+)
+
+func TestPresentCredentials(t *testing.T) {
+	s := newTestWalletService(t)
+	require.NoError(t, s.ensureWalletServices())
+
+	require.NoError(t, s.StoreCredential(context.Background(), &verifiable.Credential{
+		Context: []string{verifiable.ContextURI},
+		ID:      "http://example.edu/credentials/1",
+		Types:   []string{verifiable.VCType},
+		Issuer:  verifiable.Issuer{ID: "did:example:issuer"},
+		Issued:  utiltime.NewTime(time.Now()),
+		Subject: "did:example:subject",
+	}))
+
+	t.Run("Select credentials", func(t *testing.T) {
+		credentials, err := s.selectCredentials(context.Background(), []string{"http://example.edu/credentials/1"})
+		require.NoError(t, err)
+		require.Len(t, credentials, 1)
+		require.Equal(t, "http://example.edu/credentials/1", credentials[0].ID)
+
+		credentials, err = s.selectCredentials(context.Background(), nil)
+		require.NoError(t, err)
+		require.Len(t, credentials, 1)
+	})
+
+	t.Run("Credential not found", func(t *testing.T) {
+		err := s.PresentCredentials(context.Background(), "openid-vc://?request_uri=https://example.com/request",
+			[]string{"http://example.edu/credentials/2"})
+		require.ErrorIs(t, err, ErrCredentialNotFound)
+		require.ErrorContains(t, err, "http://example.edu/credentials/2")
+	})
+
+	t.Run("Wallet not created", func(t *testing.T) {
+		err := newTestWalletService(t).PresentCredentials(context.Background(),
+			"openid-vc://?request_uri=https://example.com/request", nil)
+		require.ErrorContains(t, err, "wallet is not created")
+	})
+}
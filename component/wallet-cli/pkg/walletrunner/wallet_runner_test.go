@@ -0,0 +1,91 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/vcs/component/wallet-cli/pkg/walletrunner/vcprovider"
+)
+
+func TestLinkedDomainVerificationTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	t.Run("Default timeout", func(t *testing.T) {
+		s, err := New(vcprovider.ProviderVCS)
+		require.NoError(t, err)
+
+		require.Equal(t, defaultLinkedDomainVerificationTimeout, s.linkedDomainHTTPClient.Timeout)
+	})
+
+	t.Run("Timeout exceeded", func(t *testing.T) {
+		s, err := New(vcprovider.ProviderVCS, func(c *vcprovider.Config) {
+			c.LinkedDomainVerificationTimeout = 50 * time.Millisecond
+		})
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodGet, srv.URL, http.NoBody)
+		require.NoError(t, err)
+
+		resp, err := s.linkedDomainHTTPClient.Do(req)
+		if resp != nil {
+			require.NoError(t, resp.Body.Close())
+		}
+
+		require.ErrorContains(t, err, "Client.Timeout exceeded")
+	})
+}
+
+func TestProxyURL(t *testing.T) {
+	var proxied atomic.Int32
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Add(1)
+
+		require.Equal(t, "http://issuer.example.com/health", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	t.Run("Requests routed through proxy", func(t *testing.T) {
+		s, err := New(vcprovider.ProviderVCS, func(c *vcprovider.Config) {
+			c.ProxyURL = proxy.URL
+		})
+		require.NoError(t, err)
+
+		for _, client := range []*http.Client{s.httpClient, s.linkedDomainHTTPClient} {
+			resp, err := client.Get("http://issuer.example.com/health")
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+
+		require.EqualValues(t, 2, proxied.Load())
+	})
+
+	t.Run("Invalid proxy URL", func(t *testing.T) {
+		_, err := New(vcprovider.ProviderVCS, func(c *vcprovider.Config) {
+			c.ProxyURL = "http://[::1"
+		})
+		require.ErrorContains(t, err, "parse proxy url")
+	})
+}
@@ -0,0 +1,229 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/did-go/doc/did"
+	utiltime "github.com/trustbloc/did-go/doc/util/time"
+	"github.com/trustbloc/vc-go/verifiable"
+)
+
+func TestStoreCredential(t *testing.T) {
+	newCredential := func(id string) *verifiable.Credential {
+		return &verifiable.Credential{
+			Context: []string{verifiable.ContextURI},
+			ID:      id,
+			Types:   []string{verifiable.VCType},
+			Issuer:  verifiable.Issuer{ID: "did:example:issuer"},
+			Issued:  utiltime.NewTime(time.Now()),
+			Subject: "did:example:subject",
+		}
+	}
+
+	t.Run("Store and refuse duplicate", func(t *testing.T) {
+		s := newTestWalletService(t)
+		require.NoError(t, s.ensureWalletServices())
+
+		require.NoError(t, s.StoreCredential(context.Background(), newCredential("http://example.edu/credentials/1")))
+		require.NoError(t, s.StoreCredential(context.Background(), newCredential("http://example.edu/credentials/2")))
+
+		err := s.StoreCredential(context.Background(), newCredential("http://example.edu/credentials/1"))
+		require.ErrorIs(t, err, ErrCredentialAlreadyStored)
+		require.ErrorContains(t, err, "http://example.edu/credentials/1")
+
+		credentials, err := s.wallet.GetAll()
+		require.NoError(t, err)
+		require.Len(t, credentials, 2)
+	})
+
+	t.Run("Wallet not created", func(t *testing.T) {
+		err := newTestWalletService(t).StoreCredential(context.Background(), newCredential("http://example.edu/1"))
+		require.ErrorContains(t, err, "wallet is not created")
+	})
+}
+
+func TestListCredentials(t *testing.T) {
+	s := newTestWalletService(t)
+	require.NoError(t, s.ensureWalletServices())
+
+	day := func(d int) time.Time {
+		return time.Date(2023, 6, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	for _, vc := range []*verifiable.Credential{
+		{
+			ID:     "http://example.edu/credentials/1",
+			Types:  []string{verifiable.VCType, "UniversityDegreeCredential"},
+			Issuer: verifiable.Issuer{ID: "did:example:university"},
+			Issued: utiltime.NewTime(day(1)),
+		},
+		{
+			ID:     "http://example.edu/credentials/2",
+			Types:  []string{verifiable.VCType},
+			Issuer: verifiable.Issuer{ID: "did:example:university"},
+			Issued: utiltime.NewTime(day(10)),
+		},
+		{
+			ID:     "http://example.edu/credentials/3",
+			Types:  []string{verifiable.VCType, "UniversityDegreeCredential"},
+			Issuer: verifiable.Issuer{ID: "did:example:college"},
+			Issued: utiltime.NewTime(day(20)),
+		},
+	} {
+		vc.Context = []string{verifiable.ContextURI, "https://www.w3.org/2018/credentials/examples/v1"}
+		vc.Subject = "did:example:subject"
+
+		require.NoError(t, s.StoreCredential(context.Background(), vc))
+	}
+
+	ids := func(t *testing.T, filter *CredentialFilter) []string {
+		t.Helper()
+
+		credentials, err := s.ListCredentials(context.Background(), filter)
+		require.NoError(t, err)
+
+		var result []string
+
+		for _, vc := range credentials {
+			result = append(result, vc.ID)
+		}
+
+		return result
+	}
+
+	after, before := day(5), day(15)
+
+	t.Run("No filter", func(t *testing.T) {
+		require.Equal(t, []string{
+			"http://example.edu/credentials/1",
+			"http://example.edu/credentials/2",
+			"http://example.edu/credentials/3",
+		}, ids(t, nil))
+	})
+
+	t.Run("Issuer DID", func(t *testing.T) {
+		require.Equal(t, []string{"http://example.edu/credentials/3"},
+			ids(t, &CredentialFilter{IssuerDID: "did:example:college"}))
+	})
+
+	t.Run("Types", func(t *testing.T) {
+		require.Equal(t, []string{"http://example.edu/credentials/1", "http://example.edu/credentials/3"},
+			ids(t, &CredentialFilter{Types: []string{"UniversityDegreeCredential"}}))
+		require.Empty(t, ids(t, &CredentialFilter{Types: []string{"UniversityDegreeCredential", "Unknown"}}))
+	})
+
+	t.Run("Issued after", func(t *testing.T) {
+		require.Equal(t, []string{"http://example.edu/credentials/2", "http://example.edu/credentials/3"},
+			ids(t, &CredentialFilter{IssuedAfter: &after}))
+	})
+
+	t.Run("Issued before", func(t *testing.T) {
+		require.Equal(t, []string{"http://example.edu/credentials/1", "http://example.edu/credentials/2"},
+			ids(t, &CredentialFilter{IssuedBefore: &before}))
+	})
+
+	t.Run("Combined", func(t *testing.T) {
+		require.Equal(t, []string{"http://example.edu/credentials/2"}, ids(t, &CredentialFilter{
+			IssuerDID:    "did:example:university",
+			IssuedAfter:  &after,
+			IssuedBefore: &before,
+		}))
+	})
+
+	t.Run("Wallet not created", func(t *testing.T) {
+		_, err := newTestWalletService(t).ListCredentials(context.Background(), nil)
+		require.ErrorContains(t, err, "wallet is not created")
+	})
+}
+
+func TestDeleteCredential(t *testing.T) {
+	s := newTestWalletService(t)
+	require.NoError(t, s.ensureWalletServices())
+
+	vc := &verifiable.Credential{
+		Context: []string{verifiable.ContextURI},
+		ID:      "http://example.edu/credentials/1",
+		Types:   []string{verifiable.VCType},
+		Issuer:  verifiable.Issuer{ID: "did:example:issuer"},
+		Issued:  utiltime.NewTime(time.Now()),
+		Subject: "did:example:subject",
+	}
+
+	require.NoError(t, s.StoreCredential(context.Background(), vc))
+
+	t.Run("Delete is idempotent", func(t *testing.T) {
+		require.NoError(t, s.DeleteCredential(context.Background(), vc.ID))
+
+		credentials, err := s.wallet.GetAll()
+		require.NoError(t, err)
+		require.Empty(t, credentials)
+
+		err = s.DeleteCredential(context.Background(), vc.ID)
+		require.ErrorIs(t, err, ErrCredentialNotFound)
+		require.ErrorContains(t, err, vc.ID)
+	})
+
+	t.Run("Entry not stored by the wallet", func(t *testing.T) {
+		store, err := s.ariesServices.storageProvider.OpenStore("wallet:credential")
+		require.NoError(t, err)
+		require.NoError(t, store.Put("http://example.edu/credentials/2", []byte(`{}`)))
+
+		err = s.DeleteCredential(context.Background(), "http://example.edu/credentials/2")
+		require.ErrorIs(t, err, ErrCredentialNotFound)
+
+		_, err = store.Get("http://example.edu/credentials/2")
+		require.NoError(t, err)
+	})
+
+	t.Run("Wallet not created", func(t *testing.T) {
+		err := newTestWalletService(t).DeleteCredential(context.Background(), vc.ID)
+		require.ErrorContains(t, err, "wallet is not created")
+	})
+}
+
+func TestCreateWebDID(t *testing.T) {
+	s := newTestWalletService(t)
+	dir := t.TempDir()
+
+	doc, err := s.CreateWebDID(context.Background(), "example.com:8443", dir)
+	require.NoError(t, err)
+	require.Equal(t, "did:web:example.com%3A8443", doc.ID)
+	require.Len(t, doc.VerificationMethod, 1)
+	require.Equal(t, doc.ID, doc.VerificationMethod[0].Controller)
+
+	t.Run("DID document written", func(t *testing.T) {
+		b, err := os.ReadFile(filepath.Join(dir, ".well-known", "did.json"))
+		require.NoError(t, err)
+
+		written, err := did.ParseDocument(b)
+		require.NoError(t, err)
+		require.Equal(t, doc.ID, written.ID)
+		require.Equal(t, doc.VerificationMethod[0].ID, written.VerificationMethod[0].ID)
+	})
+
+	t.Run("DID registered", func(t *testing.T) {
+		docRes, err := s.ariesServices.vdrRegistry.Resolve(doc.ID)
+		require.NoError(t, err)
+		require.Equal(t, doc.ID, docRes.DIDDocument.ID)
+	})
+
+	t.Run("Wallet key created", func(t *testing.T) {
+		require.Contains(t, s.vcProviderConf.WalletParams.DidID, doc.ID)
+		require.Contains(t, s.vcProviderConf.WalletParams.DidKeyID, doc.VerificationMethod[0].ID)
+
+		_, err := s.ariesServices.kms.Get(strings.Split(doc.VerificationMethod[0].ID, "#")[1])
+		require.NoError(t, err)
+	})
+}
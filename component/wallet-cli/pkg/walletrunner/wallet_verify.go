@@ -0,0 +1,115 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/piprate/json-gold/ld"
+	"github.com/trustbloc/vc-go/verifiable"
+
+	"github.com/trustbloc/vcs/pkg/doc/vc/statustype"
+	"github.com/trustbloc/vcs/pkg/service/verifycredential"
+)
+
+// VerificationResult is the outcome of a standalone credential integrity check.
+type VerificationResult struct {
+	ProofValid        bool     `json:"proofValid"`
+	CredentialExpired bool     `json:"credentialExpired"`
+	CredentialRevoked bool     `json:"credentialRevoked"`
+	Errors            []string `json:"errors,omitempty"`
+}
+
+// VerifyCredential checks the proof, expiry and revocation status of the credential without a verifier profile.
+// Failed checks are reported in the result; an error is returned only if the checks could not be run.
+func (s *Service) VerifyCredential(ctx context.Context, vc *verifiable.Credential) (*VerificationResult, error) {
+	if err := s.ensureAriesServices(); err != nil {
+		return nil, err
+	}
+
+	verifier := verifycredential.New(&verifycredential.Config{
+		VCStatusProcessorGetter: statustype.GetVCStatusProcessor,
+		StatusListVCResolver: &statusListVCResolver{
+			httpClient:     s.httpClient,
+			documentLoader: s.ariesServices.JSONLDDocumentLoader(),
+		},
+		DocumentLoader: s.ariesServices.JSONLDDocumentLoader(),
+		VDR:            s.ariesServices.vdrRegistry,
+		HTTPClient:     s.httpClient,
+	})
+
+	result := &VerificationResult{}
+
+	vcBytes, err := json.Marshal(vc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal vc: %w", err)
+	}
+
+	if err = verifier.ValidateCredentialProof(ctx, vcBytes, "", "", true, vc.JWT != ""); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("proof: %v", err))
+	} else {
+		result.ProofValid = true
+	}
+
+	if vc.Expired != nil && vc.Expired.Before(time.Now()) {
+		result.CredentialExpired = true
+		result.Errors = append(result.Errors, fmt.Sprintf("expired at %s", vc.Expired.FormatToString()))
+	}
+
+	if vc.Status != nil {
+		if err = verifier.ValidateVCStatus(ctx, vc.Status, vc.Issuer.ID); err != nil {
+			result.CredentialRevoked = errors.Is(err, verifycredential.ErrRevoked)
+			result.Errors = append(result.Errors, fmt.Sprintf("credentialStatus: %v", err))
+		}
+	}
+
+	return result, nil
+}
+
+// statusListVCResolver fetches the status list credential referenced by credentialStatus.statusListCredential.
+type statusListVCResolver struct {
+	httpClient     *http.Client
+	documentLoader ld.DocumentLoader
+}
+
+func (r *statusListVCResolver) Resolve(ctx context.Context, statusListVCURL string) (*verifiable.Credential, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, statusListVCURL, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get status list vc: %w", err)
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read status list vc: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get status list vc: status %s and body %s", resp.Status, string(b))
+	}
+
+	vc, err := verifiable.ParseCredential(b,
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(r.documentLoader))
+	if err != nil {
+		return nil, fmt.Errorf("parse status list vc: %w", err)
+	}
+
+	return vc, nil
+}
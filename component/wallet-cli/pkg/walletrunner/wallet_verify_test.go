@@ -0,0 +1,99 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package walletrunner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	utiltime "github.com/trustbloc/did-go/doc/util/time"
+	"github.com/trustbloc/vc-go/verifiable"
+
+	"github.com/trustbloc/vcs/pkg/doc/vc/bitstring"
+	"github.com/trustbloc/vcs/pkg/doc/vc/statustype"
+)
+
+func TestVerifyCredential(t *testing.T) {
+	bits := bitstring.NewBitString(16)
+	require.NoError(t, bits.Set(1, true))
+
+	encodedList, err := bits.EncodeBits()
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"@context": ["https://www.w3.org/2018/credentials/v1"],
+			"id": "http://example.edu/status/1",
+			"type": ["VerifiableCredential", "StatusList2021Credential"],
+			"issuer": "did:example:issuer",
+			"issuanceDate": "2023-06-01T00:00:00Z",
+			"credentialSubject": {
+				"id": "http://example.edu/status/1#list",
+				"type": "StatusList2021",
+				"statusPurpose": "revocation",
+				"encodedList": "` + encodedList + `"
+			}
+		}`))
+	}))
+	defer srv.Close()
+
+	newCredential := func(statusListIndex string) *verifiable.Credential {
+		return &verifiable.Credential{
+			Context: []string{verifiable.ContextURI},
+			ID:      "http://example.edu/credentials/1",
+			Types:   []string{verifiable.VCType},
+			Issuer:  verifiable.Issuer{ID: "did:example:issuer"},
+			Issued:  utiltime.NewTime(time.Now().Add(-2 * time.Hour)),
+			Expired: utiltime.NewTime(time.Now().Add(-time.Hour)),
+			Subject: "did:example:subject",
+			Status: &verifiable.TypedID{
+				ID:   "http://example.edu/status/1#" + statusListIndex,
+				Type: "StatusList2021Entry",
+				CustomFields: verifiable.CustomFields{
+					statustype.StatusPurpose:        "revocation",
+					statustype.StatusListIndex:      statusListIndex,
+					statustype.StatusListCredential: srv.URL,
+				},
+			},
+		}
+	}
+
+	s := newTestWalletService(t)
+
+	t.Run("Revoked and expired", func(t *testing.T) {
+		result, err := s.VerifyCredential(context.Background(), newCredential("1"))
+		require.NoError(t, err)
+
+		require.False(t, result.ProofValid)
+		require.True(t, result.CredentialExpired)
+		require.True(t, result.CredentialRevoked)
+		require.Len(t, result.Errors, 3)
+	})
+
+	t.Run("Not revoked", func(t *testing.T) {
+		result, err := s.VerifyCredential(context.Background(), newCredential("2"))
+		require.NoError(t, err)
+
+		require.False(t, result.CredentialRevoked)
+		require.Len(t, result.Errors, 2)
+	})
+
+	t.Run("Status list unavailable", func(t *testing.T) {
+		vc := newCredential("1")
+		vc.Status.CustomFields[statustype.StatusListCredential] = "http://127.0.0.1:0/status"
+
+		result, err := s.VerifyCredential(context.Background(), vc)
+		require.NoError(t, err)
+
+		require.False(t, result.CredentialRevoked)
+		require.Contains(t, result.Errors[len(result.Errors)-1], "get status list vc")
+	})
+}
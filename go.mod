@@ -24,6 +24,8 @@ require (
 	github.com/jinzhu/copier v0.3.5
 	github.com/klauspost/compress v1.15.9
 	github.com/labstack/echo/v4 v4.9.0
+	github.com/lib/pq v1.10.7
+	github.com/makiuchi-d/gozxing v0.1.1
 	github.com/ory/dockertest/v3 v3.9.1
 	github.com/ory/fosite v0.44.0
 	github.com/ory/x v0.0.573
@@ -52,6 +54,7 @@ require (
 	go.opentelemetry.io/otel/trace v1.14.0
 	go.uber.org/zap v1.23.0
 	golang.org/x/oauth2 v0.7.0
+	golang.org/x/sync v0.3.0
 )
 
 require (
@@ -176,10 +179,10 @@ require (
 	golang.org/x/exp v0.0.0-20230817173708-d852ddb80c63 // indirect
 	golang.org/x/mod v0.12.0 // indirect
 	golang.org/x/net v0.14.0 // indirect
-	golang.org/x/sync v0.3.0 // indirect
 	golang.org/x/sys v0.11.0 // indirect
 	golang.org/x/text v0.12.0 // indirect
 	golang.org/x/tools v0.12.1-0.20230815132531-74c255bcf846 // indirect
+	golang.org/x/xerrors v0.0.0-20220907171357-04be3eba64a2 // indirect
 	google.golang.org/appengine v1.6.7 // indirect
 	google.golang.org/protobuf v1.30.0 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
@@ -23,6 +23,7 @@ func TestProfileStore_ValidateVCSignatureAlgorithm(t *testing.T) {
 		kms.ECDSAP256TypeDER,
 		kms.ECDSAP384TypeDER,
 		kms.RSAPS256Type,
+		kms.RSARS256Type,
 		kms.BLS12381G2Type,
 	}
 
@@ -32,6 +33,7 @@ func TestProfileStore_ValidateVCSignatureAlgorithm(t *testing.T) {
 			"ES256",
 			"ES384",
 			"PS256",
+			"RS256",
 		}
 
 		for _, sigType := range validSignatureTypes {
@@ -76,6 +78,7 @@ func TestCrypto_ValidateSignatureKeyType(t *testing.T) {
 			ES256,
 			ES384,
 			PS256,
+			RS256,
 		}
 
 		for _, signature := range signatures {
@@ -193,6 +196,14 @@ func TestGetSignatureTypeByName(t *testing.T) {
 			want:    PS256,
 			wantErr: false,
 		},
+		{
+			name: "OK RS256",
+			args: args{
+				signatureType: string(RS256),
+			},
+			want:    RS256,
+			wantErr: false,
+		},
 		{
 			name: "OK Ed25519Signature2018",
 			args: args{
@@ -33,6 +33,10 @@ const (
 	VerifierOIDCInteractionSucceeded = "verifier.oidc-interaction-succeeded.v1"
 	// VerifierOIDCInteractionFailed verifier oidc event.
 	VerifierOIDCInteractionFailed = "verifier.oidc-interaction-failed.v1"
+	// VerifierOIDCInteractionInitiationFailed verifier oidc event, published when oidc interaction could not be initiated.
+	VerifierOIDCInteractionInitiationFailed = "verifier.oidc-interaction-initiation-failed.v1"
+	// VerifierOIDCVerificationFailed verifier oidc event, published when verified claims could not be stored.
+	VerifierOIDCVerificationFailed = "verifier.oidc-verification-failed.v1"
 
 	// IssuerOIDCInteractionInitiated Issuer oidc event.
 	IssuerOIDCInteractionInitiated = EventType("issuer.oidc-interaction-initiated.v1")
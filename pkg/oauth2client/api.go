@@ -18,12 +18,18 @@ var _ fosite.Client = (*Client)(nil)
 const (
 	GrantTypeAuthorizationCode = "authorization_code"
 	GrantTypePreAuthorizedCode = "urn:ietf:params:oauth:grant-type:pre-authorized_code"
+	GrantTypeRefreshToken      = "refresh_token"
 
 	ResponseTypeCode = "code"
 
 	TokenEndpointAuthMethodNone              = "none"
 	TokenEndpointAuthMethodClientSecretBasic = "client_secret_basic"
 	TokenEndpointAuthMethodClientSecretPost  = "client_secret_post"
+	TokenEndpointAuthMethodPrivateKeyJWT     = "private_key_jwt"
+
+	// MetadataKeyPrefix is a required prefix for keys of the client extension metadata. It keeps extension
+	// properties from clashing with client metadata names defined by RFC 7591.
+	MetadataKeyPrefix = "vcs:"
 )
 
 // Client represents an OAuth2 client.
@@ -48,7 +54,12 @@ type Client struct {
 	SoftwareID              string              `json:"software_id,omitempty"`
 	SoftwareVersion         string              `json:"software_version,omitempty"`
 	TokenEndpointAuthMethod string              `json:"token_endpoint_auth_method,omitempty"`
+	ProfileID               string              `json:"profile_id,omitempty"`
+	ProfileVersion          string              `json:"profile_version,omitempty"`
 	CreatedAt               time.Time           `json:"created_at,omitempty" db:"created_at"`
+	DisabledAt              *time.Time          `json:"disabled_at,omitempty"`
+	// Metadata contains ecosystem-specific client properties, e.g. "vcs:allowed_credential_types".
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // GetID returns the client id.
@@ -94,6 +105,11 @@ func (c *Client) IsPublic() bool {
 	return c.TokenEndpointAuthMethod == "none"
 }
 
+// IsDisabled returns true if the client is disabled.
+func (c *Client) IsDisabled() bool {
+	return c.DisabledAt != nil
+}
+
 // GetAudience returns the client audience.
 func (c *Client) GetAudience() fosite.Arguments {
 	return c.Audience
@@ -104,6 +120,7 @@ func GrantTypesSupported() []string {
 	return []string{
 		GrantTypeAuthorizationCode,
 		GrantTypePreAuthorizedCode,
+		GrantTypeRefreshToken,
 	}
 }
 
@@ -120,5 +137,6 @@ func TokenEndpointAuthMethodsSupported() []string {
 		TokenEndpointAuthMethodNone,
 		TokenEndpointAuthMethodClientSecretBasic,
 		TokenEndpointAuthMethodClientSecretPost,
+		TokenEndpointAuthMethodPrivateKeyJWT,
 	}
 }
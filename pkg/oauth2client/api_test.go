@@ -0,0 +1,123 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oauth2client_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/vcs/pkg/oauth2client"
+)
+
+func TestClient_JSONRoundTrip(t *testing.T) {
+	edPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecPrivateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	createdAt := time.Date(2023, 7, 1, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		client *oauth2client.Client
+	}{
+		{
+			name: "all fields set",
+			client: &oauth2client.Client{
+				ID:                "client-id",
+				Name:              "client-name",
+				URI:               "https://wallet.example.com",
+				Secret:            []byte("secret"),
+				SecretExpiresAt:   1688207400,
+				RotatedSecrets:    [][]byte{[]byte("rotated-secret-1"), []byte("rotated-secret-2")},
+				RedirectURIs:      []string{"https://wallet.example.com/callback"},
+				GrantTypes:        []string{oauth2client.GrantTypeAuthorizationCode, oauth2client.GrantTypeRefreshToken},
+				ResponseTypes:     []string{oauth2client.ResponseTypeCode},
+				Scopes:            []string{"openid", "offline_access"},
+				Audience:          []string{"https://vcs.example.com"},
+				LogoURI:           "https://wallet.example.com/logo.png",
+				Contacts:          []string{"admin@wallet.example.com"},
+				TermsOfServiceURI: "https://wallet.example.com/tos",
+				PolicyURI:         "https://wallet.example.com/policy",
+				JSONWebKeysURI:    "https://wallet.example.com/jwks.json",
+				JSONWebKeys: &jose.JSONWebKeySet{
+					Keys: []jose.JSONWebKey{
+						newJSONWebKey(edPublicKey, "ed25519-key", jose.EdDSA),
+						newJSONWebKey(&ecPrivateKey.PublicKey, "p256-key", jose.ES256),
+					},
+				},
+				SoftwareID:              "software-id",
+				SoftwareVersion:         "1.0.0",
+				TokenEndpointAuthMethod: oauth2client.TokenEndpointAuthMethodPrivateKeyJWT,
+				ProfileID:               "profile-id",
+				ProfileVersion:          "v1.0",
+				CreatedAt:               createdAt,
+				DisabledAt:              lo.ToPtr(createdAt.Add(time.Hour)),
+				Metadata: map[string]interface{}{
+					"vcs:allowed_credential_types": []interface{}{"PermanentResidentCard"},
+				},
+			},
+		},
+		{
+			name: "required fields only",
+			client: &oauth2client.Client{
+				ID:            "client-id",
+				RedirectURIs:  []string{"https://wallet.example.com/callback"},
+				GrantTypes:    []string{oauth2client.GrantTypeAuthorizationCode},
+				ResponseTypes: []string{oauth2client.ResponseTypeCode},
+				Scopes:        []string{"openid"},
+				Audience:      []string{},
+				CreatedAt:     createdAt,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.client)
+			require.NoError(t, err)
+
+			var client oauth2client.Client
+			require.NoError(t, json.Unmarshal(b, &client))
+
+			require.Equal(t, tt.client, &client)
+		})
+	}
+
+	t.Run("all fields covered", func(t *testing.T) {
+		client := reflect.ValueOf(*tests[0].client)
+
+		for i := 0; i < client.NumField(); i++ {
+			require.False(t, client.Field(i).IsZero(), "field %s is not set", client.Type().Field(i).Name)
+		}
+	})
+}
+
+// newJSONWebKey creates a public JWK the way go-jose decodes it, with empty certificate fields.
+func newJSONWebKey(key interface{}, keyID string, alg jose.SignatureAlgorithm) jose.JSONWebKey {
+	return jose.JSONWebKey{
+		Key:                         key,
+		KeyID:                       keyID,
+		Algorithm:                   string(alg),
+		Use:                         "sig",
+		Certificates:                []*x509.Certificate{},
+		CertificateThumbprintSHA1:   []byte{},
+		CertificateThumbprintSHA256: []byte{},
+	}
+}
@@ -12,6 +12,7 @@ package oidc4ci
 import (
 	"context"
 
+	"github.com/go-jose/go-jose/v3"
 	"github.com/samber/lo"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -109,3 +110,46 @@ func (w *Wrapper) PrepareCredential(
 
 	return res, nil
 }
+
+func (w *Wrapper) VerifyProofOfPossession(
+	ctx context.Context,
+	proof *oidc4ci.JWTProof,
+	clientID string,
+	txID oidc4ci.TxID,
+	nonce string,
+) (*jose.JSONWebKey, error) {
+	ctx, span := w.tracer.Start(ctx, "oidc4ci.VerifyProofOfPossession")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("proof_type", proof.ProofType))
+	span.SetAttributes(attribute.String("client_id", clientID))
+	span.SetAttributes(attribute.String("tx_id", string(txID)))
+
+	return w.svc.VerifyProofOfPossession(ctx, proof, clientID, txID, nonce)
+}
+
+func (w *Wrapper) GetDiscoveryDocument(
+	ctx context.Context,
+	profileID, profileVersion string,
+) (*oidc4ci.OIDCDiscoveryDocument, error) {
+	ctx, span := w.tracer.Start(ctx, "oidc4ci.GetDiscoveryDocument")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("profile_id", profileID))
+	span.SetAttributes(attribute.String("profile_version", profileVersion))
+
+	return w.svc.GetDiscoveryDocument(ctx, profileID, profileVersion)
+}
+
+func (w *Wrapper) BuildCredentialIssuerMetadata(
+	ctx context.Context,
+	profileID, profileVersion string,
+) (*oidc4ci.CredentialIssuerMetadata, error) {
+	ctx, span := w.tracer.Start(ctx, "oidc4ci.BuildCredentialIssuerMetadata")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("profile_id", profileID))
+	span.SetAttributes(attribute.String("profile_version", profileVersion))
+
+	return w.svc.BuildCredentialIssuerMetadata(ctx, profileID, profileVersion)
+}
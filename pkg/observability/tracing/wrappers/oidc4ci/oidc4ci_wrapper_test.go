@@ -108,3 +108,44 @@ func TestWrapper_PrepareCredential(t *testing.T) {
 	_, err := w.PrepareCredential(context.Background(), &oidc4ci.PrepareCredential{})
 	require.NoError(t, err)
 }
+
+func TestWrapper_VerifyProofOfPossession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	svc := NewMockService(ctrl)
+	svc.EXPECT().VerifyProofOfPossession(gomock.Any(), &oidc4ci.JWTProof{}, "client-id", oidc4ci.TxID("tx-id"), "nonce").
+		Times(1)
+
+	w := Wrap(svc, trace.NewNoopTracerProvider().Tracer(""))
+
+	_, err := w.VerifyProofOfPossession(context.Background(), &oidc4ci.JWTProof{}, "client-id", "tx-id", "nonce")
+	require.NoError(t, err)
+}
+
+func TestWrapper_GetDiscoveryDocument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	svc := NewMockService(ctrl)
+	svc.EXPECT().GetDiscoveryDocument(gomock.Any(), "profileID", "v1.0").Times(1).
+		Return(&oidc4ci.OIDCDiscoveryDocument{}, nil)
+
+	w := Wrap(svc, trace.NewNoopTracerProvider().Tracer(""))
+
+	doc, err := w.GetDiscoveryDocument(context.Background(), "profileID", "v1.0")
+	require.NoError(t, err)
+	require.NotNil(t, doc)
+}
+
+func TestWrapper_BuildCredentialIssuerMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	svc := NewMockService(ctrl)
+	svc.EXPECT().BuildCredentialIssuerMetadata(gomock.Any(), "profileID", "v1.0").Times(1).
+		Return(&oidc4ci.CredentialIssuerMetadata{}, nil)
+
+	w := Wrap(svc, trace.NewNoopTracerProvider().Tracer(""))
+
+	metadata, err := w.BuildCredentialIssuerMetadata(context.Background(), "profileID", "v1.0")
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+}
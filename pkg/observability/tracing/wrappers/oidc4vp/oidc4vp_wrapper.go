@@ -33,7 +33,7 @@ func Wrap(svc Service, tracer trace.Tracer) *Wrapper {
 	return &Wrapper{svc: svc, tracer: tracer}
 }
 
-func (w *Wrapper) InitiateOidcInteraction(ctx context.Context, presentationDefinition *presexch.PresentationDefinition, purpose string, profile *profileapi.Verifier) (*oidc4vp.InteractionInfo, error) {
+func (w *Wrapper) InitiateOidcInteraction(ctx context.Context, presentationDefinition *presexch.PresentationDefinition, purpose string, profile *profileapi.Verifier, opts ...oidc4vp.InitiateOidcInteractionOpt) (*oidc4vp.InteractionInfo, error) {
 	ctx, span := w.tracer.Start(ctx, "oidc4vp.InitiateOidcInteraction")
 	defer span.End()
 
@@ -41,7 +41,7 @@ func (w *Wrapper) InitiateOidcInteraction(ctx context.Context, presentationDefin
 	span.SetAttributes(attribute.String("purpose", purpose))
 	span.SetAttributes(attributeutil.JSON("presentation_definition", presentationDefinition))
 
-	resp, err := w.svc.InitiateOidcInteraction(ctx, presentationDefinition, purpose, profile)
+	resp, err := w.svc.InitiateOidcInteraction(ctx, presentationDefinition, purpose, profile, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -49,6 +49,21 @@ func (w *Wrapper) InitiateOidcInteraction(ctx context.Context, presentationDefin
 	return resp, nil
 }
 
+func (w *Wrapper) InitiateOidcInteractionByTemplate(
+	ctx context.Context,
+	templateID, purpose string,
+	profile *profileapi.Verifier,
+) (*oidc4vp.InteractionInfo, error) {
+	ctx, span := w.tracer.Start(ctx, "oidc4vp.InitiateOidcInteractionByTemplate")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("profile_id", profile.ID))
+	span.SetAttributes(attribute.String("template_id", templateID))
+	span.SetAttributes(attribute.String("purpose", purpose))
+
+	return w.svc.InitiateOidcInteractionByTemplate(ctx, templateID, purpose, profile)
+}
+
 func (w *Wrapper) VerifyOIDCVerifiablePresentation(ctx context.Context, txID oidc4vp.TxID, token []*oidc4vp.ProcessedVPToken) error {
 	ctx, span := w.tracer.Start(ctx, "oidc4vp.VerifyOIDCVerifiablePresentation")
 	defer span.End()
@@ -73,18 +88,46 @@ func (w *Wrapper) GetTx(ctx context.Context, id oidc4vp.TxID) (*oidc4vp.Transact
 	return tx, nil
 }
 
+func (w *Wrapper) GetTxByClaimsID(ctx context.Context, claimsID string) (*oidc4vp.Transaction, error) {
+	ctx, span := w.tracer.Start(ctx, "oidc4vp.GetTxByClaimsID")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("claims_id", claimsID))
+
+	return w.svc.GetTxByClaimsID(ctx, claimsID)
+}
+
+func (w *Wrapper) PollTransactionStatus(ctx context.Context, txID oidc4vp.TxID) (oidc4vp.TransactionStatus, error) {
+	ctx, span := w.tracer.Start(ctx, "oidc4vp.PollTransactionStatus")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("tx_id", string(txID)))
+
+	return w.svc.PollTransactionStatus(ctx, txID)
+}
+
 func (w *Wrapper) RetrieveClaims(ctx context.Context, tx *oidc4vp.Transaction) map[string]oidc4vp.CredentialMetadata {
 	ctx, span := w.tracer.Start(ctx, "oidc4vp.RetrieveClaims")
 	defer span.End()
 
 	span.SetAttributes(attribute.String("tx_id", string(tx.ID)))
-	span.SetAttributes(attributeutil.JSON("tx", tx, attributeutil.WithRedacted("ReceivedClaims.credentials")))
+	span.SetAttributes(attributeutil.JSON("tx", tx, attributeutil.WithRedacted("ReceivedClaims.credentials"),
+		attributeutil.WithRedacted("ReceivedClaims.rawVPTokens")))
 
 	cm := w.svc.RetrieveClaims(ctx, tx)
 
 	return cm
 }
 
+func (w *Wrapper) RetrieveRawTokens(ctx context.Context, tx *oidc4vp.Transaction) ([]string, error) {
+	ctx, span := w.tracer.Start(ctx, "oidc4vp.RetrieveRawTokens")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("tx_id", string(tx.ID)))
+
+	return w.svc.RetrieveRawTokens(ctx, tx)
+}
+
 func (w *Wrapper) DeleteClaims(ctx context.Context, claimsID string) error {
 	ctx, span := w.tracer.Start(ctx, "oidc4vp.DeleteClaims")
 	defer span.End()
@@ -93,3 +136,30 @@ func (w *Wrapper) DeleteClaims(ctx context.Context, claimsID string) error {
 
 	return w.svc.DeleteClaims(ctx, claimsID)
 }
+
+func (w *Wrapper) DeleteClaimsForProfile(ctx context.Context, claimsID, profileID, profileVersion string) error {
+	ctx, span := w.tracer.Start(ctx, "oidc4vp.DeleteClaimsForProfile")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("claims_id", claimsID))
+	span.SetAttributes(attribute.String("profile_id", profileID))
+	span.SetAttributes(attribute.String("profile_version", profileVersion))
+
+	return w.svc.DeleteClaimsForProfile(ctx, claimsID, profileID, profileVersion)
+}
+
+func (w *Wrapper) AmendTransaction(
+	ctx context.Context,
+	txID oidc4vp.TxID,
+	profileID, profileVersion string,
+	update *oidc4vp.TransactionUpdate,
+) error {
+	ctx, span := w.tracer.Start(ctx, "oidc4vp.AmendTransaction")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("tx_id", string(txID)))
+	span.SetAttributes(attribute.String("profile_id", profileID))
+	span.SetAttributes(attribute.String("profile_version", profileVersion))
+
+	return w.svc.AmendTransaction(ctx, txID, profileID, profileVersion, update)
+}
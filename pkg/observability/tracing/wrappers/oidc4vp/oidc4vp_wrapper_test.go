@@ -67,6 +67,17 @@ func TestWrapper_RetrieveClaims(t *testing.T) {
 	_ = w.RetrieveClaims(context.Background(), &oidc4vp.Transaction{})
 }
 
+func TestWrapper_RetrieveRawTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	svc := NewMockService(ctrl)
+	svc.EXPECT().RetrieveRawTokens(gomock.Any(), &oidc4vp.Transaction{}).Times(1)
+
+	w := Wrap(svc, trace.NewNoopTracerProvider().Tracer(""))
+
+	_, _ = w.RetrieveRawTokens(context.Background(), &oidc4vp.Transaction{})
+}
+
 func TestWrapper_DeleteClaims(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
@@ -77,3 +88,61 @@ func TestWrapper_DeleteClaims(t *testing.T) {
 
 	_ = w.DeleteClaims(context.Background(), "claimsID")
 }
+
+func TestWrapper_InitiateOidcInteractionByTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	svc := NewMockService(ctrl)
+	svc.EXPECT().InitiateOidcInteractionByTemplate(gomock.Any(), "templateID", "purpose", gomock.Any()).Times(1)
+
+	w := Wrap(svc, trace.NewNoopTracerProvider().Tracer(""))
+
+	_, _ = w.InitiateOidcInteractionByTemplate(context.Background(), "templateID", "purpose",
+		&profileapi.Verifier{ID: "profileID"})
+}
+
+func TestWrapper_GetTxByClaimsID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	svc := NewMockService(ctrl)
+	svc.EXPECT().GetTxByClaimsID(gomock.Any(), "claimsID").Times(1)
+
+	w := Wrap(svc, trace.NewNoopTracerProvider().Tracer(""))
+
+	_, _ = w.GetTxByClaimsID(context.Background(), "claimsID")
+}
+
+func TestWrapper_PollTransactionStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	svc := NewMockService(ctrl)
+	svc.EXPECT().PollTransactionStatus(gomock.Any(), oidc4vp.TxID("txID")).Times(1)
+
+	w := Wrap(svc, trace.NewNoopTracerProvider().Tracer(""))
+
+	_, _ = w.PollTransactionStatus(context.Background(), "txID")
+}
+
+func TestWrapper_DeleteClaimsForProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	svc := NewMockService(ctrl)
+	svc.EXPECT().DeleteClaimsForProfile(gomock.Any(), "claimsID", "profileID", "v1.0").Times(1)
+
+	w := Wrap(svc, trace.NewNoopTracerProvider().Tracer(""))
+
+	_ = w.DeleteClaimsForProfile(context.Background(), "claimsID", "profileID", "v1.0")
+}
+
+func TestWrapper_AmendTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	update := &oidc4vp.TransactionUpdate{PresentationDefinition: &presexch.PresentationDefinition{ID: "pd"}}
+
+	svc := NewMockService(ctrl)
+	svc.EXPECT().AmendTransaction(gomock.Any(), oidc4vp.TxID("txID"), "profileID", "v1.0", update).Times(1)
+
+	w := Wrap(svc, trace.NewNoopTracerProvider().Tracer(""))
+
+	_ = w.AmendTransaction(context.Background(), "txID", "profileID", "v1.0", update)
+}
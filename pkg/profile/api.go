@@ -50,6 +50,38 @@ type Issuer struct {
 	CredentialTemplates []*CredentialTemplate `json:"credentialTemplates,omitempty"`
 	WebHook             string                `json:"webHook,omitempty"`
 	CredentialMetaData  *CredentialMetaData   `json:"credentialMetadata"`
+	// CredentialConfigurations selects the signing key and algorithm per credential type.
+	CredentialConfigurations map[string]CredentialConfiguration `json:"credentialConfigurations,omitempty"`
+}
+
+// CredentialConfiguration defines how credentials of a specific type are signed. Empty fields fall back to
+// the profile SigningDID and VCConfig.
+type CredentialConfiguration struct {
+	// SigningKey is the key of the profile SigningDID the credentials are signed with.
+	SigningKey *CredentialSigningKey `json:"signingKey,omitempty"`
+	// SigningAlgorithm must be supported by the key type of the signing key.
+	SigningAlgorithm  vcsverifiable.SignatureType   `json:"signingAlgorithm,omitempty"`
+	AllowedAlgorithms []vcsverifiable.SignatureType `json:"allowedAlgorithms,omitempty"`
+}
+
+// CredentialSigningKey is a key of the profile SigningDID. Credentials keep the profile SigningDID as issuer,
+// so that they stay on the status list of the profile, which is signed by the same DID.
+type CredentialSigningKey struct {
+	Creator  string      `json:"creator"`
+	KMSKeyID string      `json:"kmsKeyID"`
+	KeyType  kms.KeyType `json:"keyType,omitempty"`
+}
+
+// GetCredentialConfiguration returns the configuration of the most specific of the given credential types, i.e.
+// the last one in the list that has a configuration. Nil is returned if none of the types is configured.
+func (p *Issuer) GetCredentialConfiguration(credentialTypes []string) *CredentialConfiguration {
+	for i := len(credentialTypes) - 1; i >= 0; i-- {
+		if conf, ok := p.CredentialConfigurations[credentialTypes[i]]; ok {
+			return &conf
+		}
+	}
+
+	return nil
 }
 
 type CredentialTemplate struct {
@@ -108,7 +140,51 @@ type OIDCConfig struct {
 	PreAuthorizedGrantAnonymousAccessSupported bool     `json:"pre-authorized_grant_anonymous_access_supported"`
 	WalletInitiatedAuthFlowSupported           bool     `json:"wallet_initiated_auth_flow_supported"`
 	SignedCredentialOfferSupported             bool     `json:"signed_credential_offer_supported"`
+	PKCERequired                               bool     `json:"pkce_required"`
+	DisallowPlainPKCE                          bool     `json:"disallow_plain_pkce"`
+	IssueRefreshToken                          bool     `json:"issue_refresh_token"`
+	SupportedSigningAlgorithms                 []string `json:"supported_signing_algorithms,omitempty"`
 	ClaimsEndpoint                             string   `json:"claims_endpoint"`
+	SoftwareStatementJWKS                      string   `json:"software_statement_jwks,omitempty"`
+	MinUserPinLength                           int      `json:"min_user_pin_length,omitempty"`
+	UserPinPattern                             string   `json:"user_pin_pattern,omitempty"`
+}
+
+// DefaultSupportedSigningAlgorithms are the proof JWT algorithms accepted when the profile doesn't restrict them.
+var DefaultSupportedSigningAlgorithms = []string{"ES256", "EdDSA"} //nolint:gochecknoglobals
+
+// GetSupportedSigningAlgorithms returns the proof JWT algorithms accepted by the profile.
+func (c *OIDCConfig) GetSupportedSigningAlgorithms() []string {
+	if c == nil || len(c.SupportedSigningAlgorithms) == 0 {
+		return DefaultSupportedSigningAlgorithms
+	}
+
+	return c.SupportedSigningAlgorithms
+}
+
+const (
+	// DefaultMinUserPinLength is the minimal length of user_pin when the profile doesn't restrict it.
+	DefaultMinUserPinLength = 4
+	// DefaultUserPinPattern is the pattern user_pin must fully match when the profile doesn't restrict it.
+	DefaultUserPinPattern = `\d+`
+)
+
+// GetMinUserPinLength returns the minimal length of user_pin accepted in the pre-authorized code flow.
+func (c *OIDCConfig) GetMinUserPinLength() int {
+	if c == nil || c.MinUserPinLength == 0 {
+		return DefaultMinUserPinLength
+	}
+
+	return c.MinUserPinLength
+}
+
+// GetUserPinPattern returns the pattern user_pin must fully match in the pre-authorized code flow.
+func (c *OIDCConfig) GetUserPinPattern() string {
+	if c == nil || c.UserPinPattern == "" {
+		return DefaultUserPinPattern
+	}
+
+	return c.UserPinPattern
 }
 
 // VCConfig describes how to sign verifiable credentials.
@@ -152,12 +228,38 @@ type OIDC4VPConfig struct {
 	ROSigningAlgorithm vcsverifiable.SignatureType `json:"roSigningAlgorithm,omitempty"`
 	DIDMethod          Method                      `json:"didMethod,omitempty"`
 	KeyType            kms.KeyType                 `json:"keyType,omitempty"`
+	// WalletScheme is the URL scheme used to invoke the wallet on the same device, e.g. "openid-vc://".
+	// Empty means the default "openid-vc://" scheme.
+	WalletScheme string `json:"walletScheme,omitempty"`
+	// ResponseMode is the response_mode of the request object sent to the wallet. Defaults to post.
+	// With direct_post the wallet posts the authorization response to the endpoint of the profile
+	// given in response_uri.
+	ResponseMode string `json:"responseMode,omitempty"`
+}
+
+const (
+	// ResponseModePost is the response mode in which the wallet posts the authorization response to redirect_uri.
+	ResponseModePost = "post"
+	// ResponseModeDirectPost is the OID4VP response mode in which the wallet POSTs the authorization response
+	// directly to the verifier endpoint given in response_uri.
+	ResponseModeDirectPost = "direct_post"
+)
+
+// GetResponseMode returns the response_mode of the request object sent to the wallet.
+func (c *OIDC4VPConfig) GetResponseMode() string {
+	if c == nil || c.ResponseMode == "" {
+		return ResponseModePost
+	}
+
+	return c.ResponseMode
 }
 
 // VerificationChecks are checks to be performed for verifying credentials and presentations.
 type VerificationChecks struct {
 	Credential   CredentialChecks    `json:"credential,omitempty"`
 	Presentation *PresentationChecks `json:"presentation,omitempty"`
+	// CredentialSchemaURL maps input descriptor ID to the JSON schema URL the matched credential must conform to.
+	CredentialSchemaURL map[string]string `json:"credentialSchemaURL,omitempty"`
 }
 
 // PresentationChecks are checks to be performed during presentation verification.
@@ -165,6 +267,10 @@ type PresentationChecks struct {
 	Proof     bool                   `json:"proof,omitempty"`
 	VCSubject bool                   `json:"vcSubject,omitempty"`
 	Format    []vcsverifiable.Format `json:"format,omitempty"`
+	// RequireHolderBinding requires VP to be signed with an authentication key of the credential subject DID.
+	RequireHolderBinding bool `json:"requireHolderBinding,omitempty"`
+	// MaxPresentationAge is the maximum time elapsed since the VP was created by the holder. 0 means unlimited.
+	MaxPresentationAge time.Duration `json:"maxPresentationAge,omitempty"`
 }
 
 // CredentialChecks are checks to be performed during credential verification.
@@ -176,6 +282,11 @@ type CredentialChecks struct {
 	Strict           bool                   `json:"strict,omitempty"`
 	LinkedDomain     bool                   `json:"linkedDomain,omitempty"`
 	IssuerTrustList  []string               `json:"issuerTrustList,omitempty"`
+	// AllowedIssuers is a list of issuer DIDs accepted for credentials matched by the presentation definition.
+	// Empty list allows any issuer.
+	AllowedIssuers []string `json:"allowedIssuers,omitempty"`
+	// MaxCredentialAge is the maximum time elapsed since issuance of the matched credentials. 0 means unlimited.
+	MaxCredentialAge time.Duration `json:"maxCredentialAge,omitempty"`
 }
 
 // SigningDID contains information about profile signing did.
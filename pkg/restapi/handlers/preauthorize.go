@@ -88,6 +88,10 @@ func (c *PreAuthorizeGrantHandler) PopulateTokenEndpointResponse(
 	}
 
 	if refresh != "" {
+		if rtLifespan := c.Config.GetRefreshTokenLifespan(ctx); rtLifespan > -1 {
+			requester.GetSession().SetExpiresAt(fosite.RefreshToken, time.Now().UTC().Add(rtLifespan).Round(time.Second))
+		}
+
 		if err = c.CoreStorage.CreateRefreshTokenSession(ctx, refreshSignature, requester.Sanitize([]string{})); err != nil {
 			return errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
 		}
@@ -96,6 +96,7 @@ func TestPopulateTokenEndpoint(t *testing.T) {
 	responderMock.EXPECT().SetExtra("refresh_token", refreshToken)
 
 	assert.NoError(t, factory.PopulateTokenEndpointResponse(context.TODO(), originalRequest, responderMock))
+	assert.False(t, originalRequest.GetSession().GetExpiresAt(fosite.RefreshToken).IsZero())
 }
 
 func TestPopulateTokenWithWrongType(t *testing.T) {
@@ -26,8 +26,10 @@ const (
 	OIDCPreAuthorizeExpectPin        ErrorCode = "oidc-pre-authorize-expect-pin"
 	OIDCPreAuthorizeInvalidPin       ErrorCode = "oidc-pre-authorize-invalid-pin"
 	OIDCPreAuthorizeInvalidClientID  ErrorCode = "oidc-pre-authorize-invalid-client-id"
+	OIDCPreAuthorizeWeakPin          ErrorCode = "oidc-pre-authorize-weak-pin"
 	OIDCCredentialFormatNotSupported ErrorCode = "oidc-credential-format-not-supported" //nolint:gosec
 	OIDCCredentialTypeNotSupported   ErrorCode = "oidc-credential-type-not-supported"   //nolint:gosec
+	OIDCInvalidCredentialRequest     ErrorCode = "oidc-invalid-credential-request"      //nolint:gosec
 	InvalidOrMissingProofOIDCErr     ErrorCode = "invalid_or_missing_proof"
 )
 
@@ -148,3 +150,7 @@ type RegistrationError struct {
 func (e *RegistrationError) Error() string {
 	return e.Err.Error()
 }
+
+func (e *RegistrationError) Unwrap() error {
+	return e.Err
+}
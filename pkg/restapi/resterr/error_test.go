@@ -8,6 +8,7 @@ package resterr
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -36,6 +37,15 @@ func TestNewSystemError(t *testing.T) {
 	requireMessage(t, resp, "some error")
 }
 
+func TestRegistrationError(t *testing.T) {
+	cause := errors.New("invalid scope")
+
+	err := &RegistrationError{Code: "invalid_client_metadata", Err: fmt.Errorf("register: %w", cause)}
+
+	require.Equal(t, "register: invalid scope", err.Error())
+	require.ErrorIs(t, err, cause)
+}
+
 func TestNewValidationError(t *testing.T) {
 	t.Run("invalid value error", func(t *testing.T) {
 		err := NewValidationError(InvalidValue, "test.value1", errors.New("some error"))
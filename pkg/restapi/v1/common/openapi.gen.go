@@ -103,25 +103,24 @@ type WalletInitiatedFlowData struct {
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/5xWQW/bOBP9KwOeWkBxiq/fybes3AJGkq1RN+5hWxgUObIYU6SWHNnxFvnvi6Gs2LHl",
-	"ot2LYZGcN29mHmf4QyhfN96hoyjGP0RUFdYy/b1pqfLB/CPJeDdBksamdY1RBdPwqhiLe6/RAnlQ3m1w",
-	"B1Qh6O4wyMK3lFbygBodGWlj920NOoKtdBTZ2BckjRuJTDTBNxjIYPJV+lBLOvc6p2DcCgI2ASMDuxVI",
-	"6E6DcbCtjKpOPIOJEPDvFiOhZqcFgomxRT2ChbRGw0baFiNoLI1DDcUOPk0n+f8XOciA8Lil5UYtH6N3",
-	"V1aDdBqsbpYbNYIpwwRQ0kHAso2YXMvjBPauwZRpc2U26EAd2NGuwQS6j0L5uvDM2XmC2DaND4SaU8Qn",
-	"xVjElAPxnAnrVfIxUJ4bBzIEuQNfQmfABZAE0lq/jSBBdaUgD7FBZcquhD0k2/F3wOjboBAihg2GN/Ft",
-	"h8CJ5/1XYoF5OsSYtXEEstUGnUooFIzi/EulMHLt1+giR2UI6xTAWXj7hRTH4fuCJFJwGglDbRzGgUL0",
-	"6mSYEdw/zL+wEiKmHHwTvkFn9PJQmW+CS9JLYbAAvBAvUuoKoNF56tOVDA657UV5EOvvZOQ5EwxhAmox",
-	"/qvb7Dl9zwQZsnx68D6/YPniERUx+GQ6uUeqvD4PaDKdQJ32eu680l2lNmLSLkSzcsatOAJ0bc2UfChE",
-	"JrbIv2vcJVanMd3ez3PvSrO61GMY+/Z+zo2mNKs2pDjOW4YuZgFL83QO060zcy1JFjLuSRe7JHcL6zoO",
-	"llcXXwYlx6v/Ce7h89052sPnO07lb4Kh0403bqBHcq763UHTiCog3Xm1vsXdTFI1kDJJVWoN6ShTWf8i",
-	"L/ppxtZ17HB4cASU1LW+SD50mlrjLh4rKDl70ZDcxgENDd2DI/0fBHYq+kw8XZFcRbZKEyGI78+ZWOQf",
-	"L42fvh3DIt/361dsX48KkR0viEx0Y+OY24urgUwuZr9AY3aRRtM7bF45nF12+FVaizR1howk1B+t304k",
-	"SfbvWmtlwQgUWjy9espKUy+PFXmGfWirS8K6sZJwafTgUd8sI0nCwc0m+NLYi7b99gZDTLkaOBOV33ft",
-	"y432NN6fNt4jTucMXvxlp2m6mJSjFBxV7lJ1zjo50zOu9AO3MLSR/rBewSKf92+L47cIXwLJA5sv5QaD",
-	"Kc3+OdBGHmFf3+ewyK9uZlOQ1rsVbA1V8KlBN53wc6kJnrzyXc/ubtR1gsEAxhEGqRJaMusCYt1ao9DF",
-	"VHAn6zSzGqkqvPrf6J3IRBusGIuKqInj6+vtdjuSaXvkw+p6bxuv76b5hz/nH9hmRE9povWpy31de7cf",
-	"vkxtkULjAh+/E/n1YhTCm0U+fysy8SIi8W7ETJI20cnGiLF4P3qXyDWSqijGLJjnfwMAAP//r/R+MlUL",
-	"AAA=",
+	"H4sIAAAAAAAC/5xWT2/buBP9KgOeWkBxil9/J9+6cgsYSbZG3biHbWBQ5MhiTJFacmTHW+S7L4aybCeW",
+	"i3YvhsU/b968eRzyh1C+brxDR1GMf4ioKqxl+vuhpcoH848k490ESRqbxjVGFUzDo2Is7rxGC+RBebfB",
+	"HVCFoLvFIAvfUhrJA2p0ZKSN3bc16Ai20lHkzb4gadxIZKIJvsFABlOs0oda0nnUOQXjVhCwCRgZ2K1A",
+	"QrcajINtZVT1KjKYCAH/bjESag5aIJgYW9QjWEhrNGykbTGCxtI41FDs4PN0kv9/kYMMCI9bWm7U8jF6",
+	"d2U1SKfB6ma5USOYMkwAJR0ELNuIKbQ8FbAPDaZMkyuzQQfqyI52DSbQfRbK14Vnzs4TxLZpfCDULBGv",
+	"FGMRkwbiORPWqxRjoDwfHMgQ5A58Cd0GLoAkkNb6bQQJqisFeYgNKlN2JewheR9/B4y+DQohYthgeBPf",
+	"dggsPM+/MAvM0yLGrI0jkK026FRCoWAU6y+Vwsi1X6OLnJUhrFMCZ+ntB1Iex+8LlkjJaSQMtXEYBwrR",
+	"u5NhRnB3P//KToiYNPgufIPO6OWxMt8Fl6S3wmABeCBepNQVQKPz1MuVNhy17U15NOvvKPKcCYYwAbUY",
+	"/9VN9pweMkGGLK8ePM8HLF88oiIGn0wnd0iV1+cJTaYTqNNcz51HuqPURkzehWhWzrgVZ4CurZmSD4XI",
+	"xBb5d4078XAIe8zp5m6ee1ea1aUew9g3d3NuNKVZtSHlcd4ydDELWJqnc5hunJlrSbKQcU+62CW7W1jX",
+	"cbC8uvg6aDke/U9w919uz9Huv9yylL8Jhk433riBHsla9bODWyOqgHTr1foGdzNJ1YBkkqrUGtJSprL+",
+	"RV70U8XWdexw+OIIKKlrfZF86Dy1xl08dVAKdvCQ3MYBDw2dgxP/Hw322vSZeLoiuYq8K90IQTw8Z2KR",
+	"f7p0/fTtGBb5vl+/YPvyqhDZ6YDIRHdtnHI7hBpQcjH7BRqzizSaPmDzIuDscsBv0lqkqTNkJKH+ZP12",
+	"IklyfNdaKwtGoNDi66OnrDT18tSRZ9jHtrokrBsrCZdGDy71zTKSJBycbIIvjb24t5/eYIhJq4E1Ufl9",
+	"177caF/n+9PGe8LpnMEhXvZapouinEhwUrlL1Tnr5EzPuNIPnMLQRvrDegWLfN6/LU7fInwIpFPdodxg",
+	"MKXZPwfayFfYt/c5LPKrD7MpSOvdCraGKvjcoJtO+LnUBE9e+a5ndyfqOsFgAOMIg1QJLW3rEmLfWqPQ",
+	"xVRwJ+t0ZzVSVXj1v9E7kYk2WDEWFVETx9fX2+12JNP0yIfV9X5vvL6d5h//nH/kPSN6SjdaL13u69q7",
+	"/eXL1BYpNS7w6TuRXy9GIbxZ5PO3IhMHE4l3I2aSvIlONkaMxfvRu0SukVRFMWbDPP87AK/0fjJVCwAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
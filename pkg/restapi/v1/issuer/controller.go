@@ -502,6 +502,7 @@ func (c *Controller) prepareClaimDataAuthorizationRequest(
 			Scope:                lo.FromPtr(body.Scope),
 			OpState:              body.OpState,
 			AuthorizationDetails: ad,
+			WalletIssuer:         lo.FromPtr(body.WalletIssuer),
 		},
 	)
 	if err != nil {
@@ -523,7 +524,12 @@ func (c *Controller) prepareClaimDataAuthorizationRequest(
 		},
 		AuthorizationEndpoint:              resp.AuthorizationEndpoint,
 		PushedAuthorizationRequestEndpoint: lo.ToPtr(resp.PushedAuthorizationRequestEndpoint),
+		PkceRequired:                       lo.ToPtr(profile.OIDCConfig.PKCERequired),
+		DisallowPlainPkce:                  lo.ToPtr(profile.OIDCConfig.DisallowPlainPKCE),
+		IssueRefreshToken:                  lo.ToPtr(profile.OIDCConfig.IssueRefreshToken),
+		SupportedSigningAlgorithms:         lo.ToPtr(profile.OIDCConfig.GetSupportedSigningAlgorithms()),
 		TxId:                               string(resp.TxID),
+		WalletPushedAuthorizationRequestSupported: lo.ToPtr(resp.WalletPushedAuthorizationSupported),
 	}, nil
 }
 
@@ -607,10 +613,17 @@ func (c *Controller) ValidatePreAuthorizedCodeRequest(ctx echo.Context) error {
 		return err
 	}
 
+	profile, err := c.profileSvc.GetProfile(result.ProfileID, result.ProfileVersion)
+	if err != nil {
+		return resterr.NewSystemError(issuerProfileSvcComponent, "GetProfile", err)
+	}
+
 	return util.WriteOutput(ctx)(ValidatePreAuthorizedCodeResponse{
-		TxId:    string(result.ID),
-		OpState: result.OpState,
-		Scopes:  result.Scope,
+		TxId:                       string(result.ID),
+		OpState:                    result.OpState,
+		Scopes:                     result.Scope,
+		IssueRefreshToken:          lo.ToPtr(profile.OIDCConfig != nil && profile.OIDCConfig.IssueRefreshToken),
+		SupportedSigningAlgorithms: lo.ToPtr(profile.OIDCConfig.GetSupportedSigningAlgorithms()),
 	}, nil)
 }
 
@@ -623,9 +636,17 @@ func (c *Controller) PrepareCredential(e echo.Context) error {
 		return err
 	}
 
-	vcFormat, err := common.ValidateVCFormat(common.VCFormat(lo.FromPtr(body.Format)))
-	if err != nil {
-		return resterr.NewValidationError(resterr.InvalidValue, "format", err)
+	var (
+		vcFormat vcsverifiable.Format
+		err      error
+	)
+
+	// format is taken from the credential metadata referenced by credential_identifier
+	if body.CredentialIdentifier == nil || body.Format != nil {
+		vcFormat, err = common.ValidateVCFormat(common.VCFormat(lo.FromPtr(body.Format)))
+		if err != nil {
+			return resterr.NewValidationError(resterr.InvalidValue, "format", err)
+		}
 	}
 
 	ctx := e.Request().Context()
@@ -633,11 +654,12 @@ func (c *Controller) PrepareCredential(e echo.Context) error {
 	result, err := c.oidc4ciService.PrepareCredential(
 		ctx,
 		&oidc4ci.PrepareCredential{
-			TxID:             oidc4ci.TxID(body.TxId),
-			CredentialTypes:  body.Types,
-			CredentialFormat: vcFormat,
-			DID:              lo.FromPtr(body.Did),
-			AudienceClaim:    body.AudienceClaim,
+			TxID:                 oidc4ci.TxID(body.TxId),
+			CredentialTypes:      body.Types,
+			CredentialFormat:     vcFormat,
+			CredentialIdentifier: lo.FromPtr(body.CredentialIdentifier),
+			DID:                  lo.FromPtr(body.Did),
+			AudienceClaim:        body.AudienceClaim,
 		},
 	)
 
@@ -1050,6 +1050,114 @@ func TestController_PrepareAuthorizationRequest(t *testing.T) {
 		assert.NoError(t, c.PrepareAuthorizationRequest(ctx))
 	})
 
+	t.Run("success with wallet issuer", func(t *testing.T) {
+		mockOIDC4CIService := NewMockOIDC4CIService(gomock.NewController(t))
+		mockOIDC4CIService.EXPECT().PrepareClaimDataAuthorizationRequest(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(
+				ctx context.Context,
+				req *oidc4ci.PrepareClaimDataAuthorizationRequest,
+			) (*oidc4ci.PrepareClaimDataAuthorizationResponse, error) {
+				assert.Equal(t, "https://wallet.example.com", req.WalletIssuer)
+
+				return &oidc4ci.PrepareClaimDataAuthorizationResponse{
+					ProfileID:                          profileID,
+					ProfileVersion:                     profileVersion,
+					WalletPushedAuthorizationSupported: true,
+				}, nil
+			},
+		)
+
+		mockProfileService := NewMockProfileService(gomock.NewController(t))
+		mockProfileService.EXPECT().GetProfile(profileID, profileVersion).Return(&profileapi.Issuer{
+			OIDCConfig: &profileapi.OIDCConfig{},
+		}, nil)
+
+		c := &Controller{
+			oidc4ciService: mockOIDC4CIService,
+			profileSvc:     mockProfileService,
+		}
+
+		resp, err := c.prepareClaimDataAuthorizationRequest(context.Background(),
+			&PrepareClaimDataAuthorizationRequest{
+				ResponseType: "code",
+				OpState:      "123",
+				AuthorizationDetails: &common.AuthorizationDetails{
+					Type:   "openid_credential",
+					Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+					Format: lo.ToPtr("ldp_vc"),
+				},
+				WalletIssuer: lo.ToPtr("https://wallet.example.com"),
+			})
+		require.NoError(t, err)
+		require.True(t, lo.FromPtr(resp.WalletPushedAuthorizationRequestSupported))
+	})
+
+	t.Run("success with pkce options", func(t *testing.T) {
+		mockOIDC4CIService := NewMockOIDC4CIService(gomock.NewController(t))
+		mockOIDC4CIService.EXPECT().PrepareClaimDataAuthorizationRequest(gomock.Any(), gomock.Any()).Return(
+			&oidc4ci.PrepareClaimDataAuthorizationResponse{
+				ProfileID:      profileID,
+				ProfileVersion: profileVersion,
+			}, nil)
+
+		mockProfileService := NewMockProfileService(gomock.NewController(t))
+		mockProfileService.EXPECT().GetProfile(profileID, profileVersion).Return(&profileapi.Issuer{
+			OIDCConfig: &profileapi.OIDCConfig{PKCERequired: true, DisallowPlainPKCE: true},
+		}, nil)
+
+		c := &Controller{
+			oidc4ciService: mockOIDC4CIService,
+			profileSvc:     mockProfileService,
+		}
+
+		resp, err := c.prepareClaimDataAuthorizationRequest(context.Background(),
+			&PrepareClaimDataAuthorizationRequest{
+				ResponseType: "code",
+				OpState:      "123",
+				AuthorizationDetails: &common.AuthorizationDetails{
+					Type:   "openid_credential",
+					Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+					Format: lo.ToPtr("ldp_vc"),
+				},
+			})
+		require.NoError(t, err)
+		require.True(t, lo.FromPtr(resp.PkceRequired))
+		require.True(t, lo.FromPtr(resp.DisallowPlainPkce))
+		require.Equal(t, profileapi.DefaultSupportedSigningAlgorithms, lo.FromPtr(resp.SupportedSigningAlgorithms))
+	})
+
+	t.Run("success with supported signing algorithms", func(t *testing.T) {
+		mockOIDC4CIService := NewMockOIDC4CIService(gomock.NewController(t))
+		mockOIDC4CIService.EXPECT().PrepareClaimDataAuthorizationRequest(gomock.Any(), gomock.Any()).Return(
+			&oidc4ci.PrepareClaimDataAuthorizationResponse{
+				ProfileID:      profileID,
+				ProfileVersion: profileVersion,
+			}, nil)
+
+		mockProfileService := NewMockProfileService(gomock.NewController(t))
+		mockProfileService.EXPECT().GetProfile(profileID, profileVersion).Return(&profileapi.Issuer{
+			OIDCConfig: &profileapi.OIDCConfig{SupportedSigningAlgorithms: []string{"ES384"}},
+		}, nil)
+
+		c := &Controller{
+			oidc4ciService: mockOIDC4CIService,
+			profileSvc:     mockProfileService,
+		}
+
+		resp, err := c.prepareClaimDataAuthorizationRequest(context.Background(),
+			&PrepareClaimDataAuthorizationRequest{
+				ResponseType: "code",
+				OpState:      "123",
+				AuthorizationDetails: &common.AuthorizationDetails{
+					Type:   "openid_credential",
+					Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+					Format: lo.ToPtr("ldp_vc"),
+				},
+			})
+		require.NoError(t, err)
+		require.Equal(t, []string{"ES384"}, lo.FromPtr(resp.SupportedSigningAlgorithms))
+	})
+
 	t.Run("invalid authorization_details.type", func(t *testing.T) {
 		mockOIDC4CIService := NewMockOIDC4CIService(gomock.NewController(t))
 		mockOIDC4CIService.EXPECT().PrepareClaimDataAuthorizationRequest(gomock.Any(), gomock.Any()).Times(0)
@@ -1209,18 +1317,30 @@ func TestController_ValidatePreAuthorizedCodeRequest(t *testing.T) {
 		mockOIDC4CIService.EXPECT().ValidatePreAuthorizedCodeRequest(gomock.Any(), "1234", "5432", "123").
 			Return(&oidc4ci.Transaction{
 				TransactionData: oidc4ci.TransactionData{
-					OpState: "random_op_state",
-					Scope:   []string{"a", "b"},
+					ProfileID:      profileID,
+					ProfileVersion: profileVersion,
+					OpState:        "random_op_state",
+					Scope:          []string{"a", "b"},
 				},
 			}, nil)
 
+		mockProfileService := NewMockProfileService(gomock.NewController(t))
+		mockProfileService.EXPECT().GetProfile(profileID, profileVersion).Return(&profileapi.Issuer{
+			OIDCConfig: &profileapi.OIDCConfig{IssueRefreshToken: true},
+		}, nil)
+
 		c := &Controller{
 			oidc4ciService: mockOIDC4CIService,
+			profileSvc:     mockProfileService,
 		}
 
 		req := `{"pre-authorized_code":"1234", "user_pin" : "5432", "client_id": "123" }` //nolint:lll
 		ctx := echoContext(withRequestBody([]byte(req)))
 		assert.NoError(t, c.ValidatePreAuthorizedCodeRequest(ctx))
+
+		var resp ValidatePreAuthorizedCodeResponse
+		require.NoError(t, json.Unmarshal(ctx.Response().Writer.(*httptest.ResponseRecorder).Body.Bytes(), &resp))
+		require.True(t, lo.FromPtr(resp.IssueRefreshToken))
 	})
 
 	t.Run("success without pin", func(t *testing.T) {
@@ -1228,13 +1348,19 @@ func TestController_ValidatePreAuthorizedCodeRequest(t *testing.T) {
 		mockOIDC4CIService.EXPECT().ValidatePreAuthorizedCodeRequest(gomock.Any(), "1234", "", "123").
 			Return(&oidc4ci.Transaction{
 				TransactionData: oidc4ci.TransactionData{
-					OpState: "random_op_state",
-					Scope:   []string{"a", "b"},
+					ProfileID:      profileID,
+					ProfileVersion: profileVersion,
+					OpState:        "random_op_state",
+					Scope:          []string{"a", "b"},
 				},
 			}, nil)
 
+		mockProfileService := NewMockProfileService(gomock.NewController(t))
+		mockProfileService.EXPECT().GetProfile(profileID, profileVersion).Return(&profileapi.Issuer{}, nil)
+
 		c := &Controller{
 			oidc4ciService: mockOIDC4CIService,
+			profileSvc:     mockProfileService,
 		}
 
 		req := `{"pre-authorized_code":"1234", "client_id": "123" }` //nolint:lll
@@ -1242,6 +1368,29 @@ func TestController_ValidatePreAuthorizedCodeRequest(t *testing.T) {
 		assert.NoError(t, c.ValidatePreAuthorizedCodeRequest(ctx))
 	})
 
+	t.Run("get profile error", func(t *testing.T) {
+		mockOIDC4CIService := NewMockOIDC4CIService(gomock.NewController(t))
+		mockOIDC4CIService.EXPECT().ValidatePreAuthorizedCodeRequest(gomock.Any(), "1234", "", "123").
+			Return(&oidc4ci.Transaction{
+				TransactionData: oidc4ci.TransactionData{
+					ProfileID:      profileID,
+					ProfileVersion: profileVersion,
+				},
+			}, nil)
+
+		mockProfileService := NewMockProfileService(gomock.NewController(t))
+		mockProfileService.EXPECT().GetProfile(profileID, profileVersion).Return(nil, errors.New("get profile error"))
+
+		c := &Controller{
+			oidc4ciService: mockOIDC4CIService,
+			profileSvc:     mockProfileService,
+		}
+
+		req := `{"pre-authorized_code":"1234", "client_id": "123" }` //nolint:lll
+		ctx := echoContext(withRequestBody([]byte(req)))
+		assert.ErrorContains(t, c.ValidatePreAuthorizedCodeRequest(ctx), "get profile error")
+	})
+
 	t.Run("fail with pin", func(t *testing.T) {
 		mockOIDC4CIService := NewMockOIDC4CIService(gomock.NewController(t))
 		mockOIDC4CIService.EXPECT().ValidatePreAuthorizedCodeRequest(gomock.Any(), "1234", "5432", "123").
@@ -173,6 +173,9 @@ type PrepareClaimDataAuthorizationRequest struct {
 	// Value MUST be set to "code".
 	ResponseType string    `json:"response_type"`
 	Scope        *[]string `json:"scope,omitempty"`
+
+	// Wallet's OpenID Connect Issuer URL. Used to discover wallet's capabilities.
+	WalletIssuer *string `json:"wallet_issuer,omitempty"`
 }
 
 // Model for Prepare Claim Data Authorization Response.
@@ -183,12 +186,27 @@ type PrepareClaimDataAuthorizationResponse struct {
 	// Model with key value pairs containing parameters to build OIDC core authorization request (RFC6749) for Issuer OIDC provider to perform wallet user authorization grant.
 	AuthorizationRequest OAuthParameters `json:"authorization_request"`
 
+	// Whether the issuer profile rejects the plain PKCE code challenge method.
+	DisallowPlainPkce *bool `json:"disallow_plain_pkce,omitempty"`
+
+	// Whether the issuer profile issues refresh tokens.
+	IssueRefreshToken *bool `json:"issue_refresh_token,omitempty"`
+
+	// Whether the issuer profile requires PKCE for the authorization request.
+	PkceRequired *bool `json:"pkce_required,omitempty"`
+
 	// Issuer's OIDC provider PAR endpoint.
 	PushedAuthorizationRequestEndpoint *string `json:"pushed_authorization_request_endpoint,omitempty"`
 
+	// Proof JWT signing algorithms accepted by the issuer profile.
+	SupportedSigningAlgorithms *[]string `json:"supported_signing_algorithms,omitempty"`
+
 	// Transaction ID to correlate upcoming authorization response.
 	TxId                string                                `json:"tx_id"`
 	WalletInitiatedFlow *externalRef0.WalletInitiatedFlowData `json:"wallet_initiated_flow"`
+
+	// Whether the wallet discovered via wallet_issuer supports pushed authorization requests.
+	WalletPushedAuthorizationRequestSupported *bool `json:"wallet_pushed_authorization_request_supported,omitempty"`
 }
 
 // Model for Prepare Credential request.
@@ -196,6 +214,9 @@ type PrepareCredential struct {
 	// The "aud" claim received from the client.
 	AudienceClaim string `json:"audienceClaim"`
 
+	// Identifier of the credential in the credentials_supported issuer metadata.
+	CredentialIdentifier *string `json:"credential_identifier,omitempty"`
+
 	// DID to which issued credential has to be bound.
 	Did *string `json:"did,omitempty"`
 
@@ -266,12 +287,18 @@ type ValidatePreAuthorizedCodeRequest struct {
 
 // Model for validating pre-authorized code and pin.
 type ValidatePreAuthorizedCodeResponse struct {
+	// Whether the issuer profile issues refresh tokens.
+	IssueRefreshToken *bool `json:"issue_refresh_token,omitempty"`
+
 	// Op state.
 	OpState string `json:"op_state"`
 
 	// A list of pre-authorized scopes
 	Scopes []string `json:"scopes"`
 
+	// Proof JWT signing algorithms accepted by the issuer profile.
+	SupportedSigningAlgorithms *[]string `json:"supported_signing_algorithms,omitempty"`
+
 	// transaction id
 	TxId string `json:"tx_id"`
 }
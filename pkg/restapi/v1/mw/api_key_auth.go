@@ -78,7 +78,8 @@ func APIKeyAuth(apiKey string) echo.MiddlewareFunc { //nolint:gocognit
 				strings.HasPrefix(currentPath, oidcCredential) ||
 				strings.HasSuffix(currentPath, oidcWellKnown) ||
 				strings.HasSuffix(currentPath, oidcCredentialWellKnown) ||
-				(strings.HasPrefix(currentPath, "/oidc/") && strings.HasSuffix(currentPath, "/register")) {
+				isDynamicClientRegistration(c.Request().Method, currentPath) ||
+				isProfileAuthorizationResponse(c.Request().Method, currentPath) {
 				return next(c)
 			}
 
@@ -94,3 +95,16 @@ func APIKeyAuth(apiKey string) echo.MiddlewareFunc { //nolint:gocognit
 		}
 	}
 }
+
+// isProfileAuthorizationResponse checks if the wallet posts the authorization response to the verifier profile
+// endpoint (response_mode=direct_post).
+func isProfileAuthorizationResponse(method, path string) bool {
+	return method == http.MethodPost && strings.HasPrefix(path, "/verifier/") &&
+		strings.HasSuffix(path, "/interactions/authorization-response")
+}
+
+// isDynamicClientRegistration checks if the request registers OAuth client. Listing registered clients
+// is served by the same path and requires API key.
+func isDynamicClientRegistration(method, path string) bool {
+	return method == http.MethodPost && strings.HasPrefix(path, "/oidc/") && strings.HasSuffix(path, "/register")
+}
@@ -5,13 +5,14 @@ SPDX-License-Identifier: Apache-2.0
 */
 
 //go:generate oapi-codegen --config=openapi.cfg.yaml ../../../../docs/v1/openapi.yaml
-//go:generate mockgen -destination controller_mocks_test.go -self_package mocks -package oidc4ci_test . StateStore,OAuth2Provider,IssuerInteractionClient,HTTPClient,ClientManager,ProfileService
+//go:generate mockgen -destination controller_mocks_test.go -self_package mocks -package oidc4ci_test . StateStore,OAuth2Provider,IssuerInteractionClient,HTTPClient,ClientManager,ProfileService,ProofVerifier,DiscoveryService
 
 package oidc4ci
 
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -23,6 +24,7 @@ import (
 	"time"
 
 	gojose "github.com/go-jose/go-jose/v3"
+	josejwt "github.com/go-jose/go-jose/v3/jwt"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/ory/fosite"
@@ -34,6 +36,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/oauth2"
 
+	"github.com/trustbloc/vcs/pkg/oauth2client"
 	"github.com/trustbloc/vcs/pkg/observability/tracing/attributeutil"
 	profileapi "github.com/trustbloc/vcs/pkg/profile"
 	"github.com/trustbloc/vcs/pkg/restapi/resterr"
@@ -50,22 +53,32 @@ const (
 	authorizationDetailsKey    = "authDetails"
 	txIDKey                    = "txID"
 	preAuthKey                 = "preAuth"
+	signingAlgorithmsKey       = "signingAlgs"
 	preAuthorizedCodeGrantType = "urn:ietf:params:oauth:grant-type:pre-authorized_code"
+	refreshTokenGrantType      = "refresh_token"
+	offlineAccessScope         = "offline_access"
 	discoverableClientIDScheme = "urn:ietf:params:oauth:client-id-scheme:oauth-discoverable-client"
 	jwtProofTypHeader          = "openid4vci-proof+jwt"
 	cNonceKey                  = "cNonce"
 	cNonceExpiresAtKey         = "cNonceExpiresAt"
 	cNonceSize                 = 15
 	cNonceTTL                  = 5 * time.Minute
+	authorizeSessionCookieName = "vcs_oidc_authorize_session"
+	defaultClientsPageSize     = 20
+	maxClientsPageSize         = 100
 
 	invalidRequestOIDCErr = "invalid_request"
 	invalidGrantOIDCErr   = "invalid_grant"
 	invalidTokenOIDCErr   = "invalid_token"
 	invalidClientOIDCErr  = "invalid_client"
+	serverErrorOIDCErr    = "server_error"
 )
 
 var logger = log.New("oidc4ci")
 
+// registeredClaimNames are JWT claims of the request object that are not authorization request parameters.
+var registeredClaimNames = []string{"iss", "sub", "aud", "exp", "nbf", "iat", "jti"}
+
 // StateStore stores authorization request/response state.
 type StateStore interface {
 	SaveAuthorizeState(
@@ -100,6 +113,26 @@ type ProfileService interface {
 	GetProfile(profileID profileapi.ID, profileVersion profileapi.Version) (*profileapi.Issuer, error)
 }
 
+// ProofVerifier verifies proof of possession of key material registered for the client.
+type ProofVerifier interface {
+	VerifyProofOfPossession(
+		ctx context.Context,
+		proof *oidc4ci.JWTProof,
+		clientID string,
+		txID oidc4ci.TxID,
+		nonce string,
+	) (*gojose.JSONWebKey, error)
+}
+
+// DiscoveryService provides OpenID Provider and Credential Issuer metadata of issuer profiles.
+type DiscoveryService interface {
+	GetDiscoveryDocument(ctx context.Context, profileID, profileVersion string) (*oidc4ci.OIDCDiscoveryDocument, error)
+	BuildCredentialIssuerMetadata(
+		ctx context.Context,
+		profileID, profileVersion string,
+	) (*oidc4ci.CredentialIssuerMetadata, error)
+}
+
 // Config holds configuration options for Controller.
 type Config struct {
 	OAuth2Provider          OAuth2Provider
@@ -109,10 +142,15 @@ type Config struct {
 	ProfileService          ProfileService
 	ClientManager           ClientManager
 	ClientIDSchemeService   ClientIDSchemeService
+	ProofVerifier           ProofVerifier // optional
+	DiscoveryService        DiscoveryService
 	JWTVerifier             jose.SignatureVerifier
 	Tracer                  trace.Tracer
 	IssuerVCSPublicHost     string
 	ExternalHostURL         string
+	// IdempotencyKeyTTL is how long credential responses are kept for retries of the credential request with
+	// the same Idempotency-Key header. 0 disables idempotency key support.
+	IdempotencyKeyTTL time.Duration
 }
 
 // Controller for OIDC credential issuance API.
@@ -124,10 +162,13 @@ type Controller struct {
 	profileService          ProfileService
 	clientManager           ClientManager
 	clientIDSchemeService   ClientIDSchemeService
+	proofVerifier           ProofVerifier // optional
+	discoveryService        DiscoveryService
 	jwtVerifier             jose.SignatureVerifier
 	tracer                  trace.Tracer
 	issuerVCSPublicHost     string
 	internalHostURL         string
+	idempotencyCache        *idempotencyCache
 }
 
 // NewController creates a new Controller instance.
@@ -140,10 +181,13 @@ func NewController(config *Config) *Controller {
 		profileService:          config.ProfileService,
 		clientManager:           config.ClientManager,
 		clientIDSchemeService:   config.ClientIDSchemeService,
+		proofVerifier:           config.ProofVerifier,
+		discoveryService:        config.DiscoveryService,
 		jwtVerifier:             config.JWTVerifier,
 		tracer:                  config.Tracer,
 		issuerVCSPublicHost:     config.IssuerVCSPublicHost,
 		internalHostURL:         config.ExternalHostURL,
+		idempotencyCache:        newIdempotencyCache(config.IdempotencyKeyTTL),
 	}
 }
 
@@ -210,6 +254,12 @@ func (c *Controller) OidcAuthorize(e echo.Context, params OidcAuthorizeParams) e
 	req := e.Request()
 	ctx := req.Context()
 
+	if params.Request != nil {
+		if err := c.applyRequestObject(req, &params); err != nil {
+			return err
+		}
+	}
+
 	if lo.FromPtr(params.IssuerState) == "" {
 		params.IssuerState = lo.ToPtr(uuid.NewString())
 	}
@@ -269,6 +319,7 @@ func (c *Controller) OidcAuthorize(e echo.Context, params OidcAuthorizeParams) e
 			OpState:      lo.FromPtr(params.IssuerState),
 			ResponseType: params.ResponseType,
 			Scope:        lo.ToPtr(scope),
+			WalletIssuer: params.WalletIssuer,
 		},
 	)
 	if err != nil {
@@ -290,6 +341,22 @@ func (c *Controller) OidcAuthorize(e echo.Context, params OidcAuthorizeParams) e
 		return fmt.Errorf("decode claim data authorization response: %w", err)
 	}
 
+	if lo.FromPtr(claimDataAuth.PkceRequired) && params.CodeChallenge == "" {
+		return resterr.NewOIDCError(invalidRequestOIDCErr, errors.New("code_challenge is required by the profile"))
+	}
+
+	if lo.FromPtr(claimDataAuth.DisallowPlainPkce) && isPlainCodeChallenge(params) {
+		return resterr.NewOIDCError(invalidRequestOIDCErr, errors.New("plain code_challenge_method not supported"))
+	}
+
+	if lo.FromPtr(claimDataAuth.IssueRefreshToken) {
+		ar.GrantScope(offlineAccessScope)
+	}
+
+	if claimDataAuth.SupportedSigningAlgorithms != nil {
+		ses.Extra[signingAlgorithmsKey] = strings.Join(*claimDataAuth.SupportedSigningAlgorithms, " ")
+	}
+
 	if claimDataAuth.WalletInitiatedFlow != nil {
 		ses.Extra[sessionOpStateKey] = claimDataAuth.WalletInitiatedFlow.OpState // swap op state
 		params.IssuerState = &claimDataAuth.WalletInitiatedFlow.OpState
@@ -304,6 +371,8 @@ func (c *Controller) OidcAuthorize(e echo.Context, params OidcAuthorizeParams) e
 		return resterr.NewFositeError(resterr.FositeAuthorizeError, e, c.oauth2Provider, err).WithAuthorizeRequester(ar)
 	}
 
+	sessionBinding := uuid.NewString()
+
 	if err = c.stateStore.SaveAuthorizeState(
 		ctx,
 		lo.FromPtr(params.IssuerState),
@@ -313,10 +382,15 @@ func (c *Controller) OidcAuthorize(e echo.Context, params OidcAuthorizeParams) e
 			Header:              resp.GetHeader(),
 			Parameters:          resp.GetParameters(),
 			WalletInitiatedFlow: claimDataAuth.WalletInitiatedFlow,
+			SessionBinding:      sessionBinding,
 		}); err != nil {
 		return fmt.Errorf("save authorize state: %w", err)
 	}
 
+	// the state returned on the redirect is chosen by whoever drives the browser to /oidc/redirect, so the
+	// authorization state is bound to the user agent that started the authorization with a session cookie
+	e.SetCookie(c.authorizeSessionCookie(sessionBinding, 0))
+
 	oauthConfig := oauth2.Config{
 		ClientID:     claimDataAuth.AuthorizationRequest.ClientId,
 		ClientSecret: claimDataAuth.AuthorizationRequest.ClientSecret,
@@ -346,6 +420,99 @@ func (c *Controller) OidcAuthorize(e echo.Context, params OidcAuthorizeParams) e
 	return e.Redirect(http.StatusSeeOther, authCodeURL)
 }
 
+// isPlainCodeChallenge checks whether the authorization request uses the plain PKCE method. A code challenge
+// without code_challenge_method defaults to plain (RFC 7636, section 4.3).
+func isPlainCodeChallenge(params OidcAuthorizeParams) bool {
+	if params.CodeChallengeMethod == nil {
+		return params.CodeChallenge != ""
+	}
+
+	return *params.CodeChallengeMethod == "plain"
+}
+
+// applyRequestObject verifies the request object (RFC 9101) passed in the "request" parameter and merges its claims
+// over the query parameters. Both params and the form of the request, which is read by the OAuth2 provider,
+// are updated.
+func (c *Controller) applyRequestObject(req *http.Request, params *OidcAuthorizeParams) error {
+	_, rawClaims, err := jwt.Parse(*params.Request,
+		jwt.WithSignatureVerifier(c.jwtVerifier),
+		jwt.WithIgnoreClaimsMapDecoding(true),
+	)
+	if err != nil {
+		return resterr.NewValidationError(resterr.InvalidValue, "request", fmt.Errorf("parse request object: %w", err))
+	}
+
+	var registeredClaims josejwt.Claims
+
+	if err = json.Unmarshal(rawClaims, &registeredClaims); err != nil {
+		return resterr.NewValidationError(resterr.InvalidValue, "request", fmt.Errorf("invalid claims: %w", err))
+	}
+
+	if err = registeredClaims.ValidateWithLeeway(josejwt.Expected{
+		Issuer:   params.ClientId,
+		Audience: josejwt.Audience{c.internalHostURL},
+		Time:     time.Now(),
+	}, josejwt.DefaultLeeway); err != nil {
+		return resterr.NewValidationError(resterr.InvalidValue, "request", err)
+	}
+
+	var claims map[string]interface{}
+
+	if err = json.Unmarshal(rawClaims, &claims); err != nil {
+		return resterr.NewValidationError(resterr.InvalidValue, "request", fmt.Errorf("invalid claims: %w", err))
+	}
+
+	if err = req.ParseForm(); err != nil {
+		return resterr.NewValidationError(resterr.InvalidValue, "request", fmt.Errorf("parse form: %w", err))
+	}
+
+	for name, claim := range claims {
+		if lo.Contains(registeredClaimNames, name) {
+			continue
+		}
+
+		value, ok := claim.(string)
+		if !ok {
+			// structured parameters, e.g. authorization_details, are passed as JSON in the query
+			b, marshalErr := json.Marshal(claim)
+			if marshalErr != nil {
+				return resterr.NewValidationError(resterr.InvalidValue, "request", marshalErr)
+			}
+
+			value = string(b)
+		}
+
+		if inline := req.Form.Get(name); inline != "" && inline != value {
+			return resterr.NewValidationError(resterr.InvalidValue, name,
+				errors.New("conflicts with the value in the request object"))
+		}
+
+		req.Form.Set(name, value)
+	}
+
+	// the request object has been processed and must not be handled again by the OAuth2 provider
+	req.Form.Del("request")
+
+	merged := make(map[string]string, len(req.Form))
+
+	for name := range req.Form {
+		merged[name] = req.Form.Get(name)
+	}
+
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("marshal authorize params: %w", err)
+	}
+
+	if err = json.Unmarshal(b, params); err != nil {
+		return resterr.NewValidationError(resterr.InvalidValue, "request", err)
+	}
+
+	params.Request = nil
+
+	return nil
+}
+
 type parResponse struct {
 	RequestURI string `json:"request_uri"`
 	ExpiresIn  int    `json:"expires_in"`
@@ -408,15 +575,29 @@ func (c *Controller) buildAuthCodeURLWithPAR(
 }
 
 // OidcRedirect handles OIDC redirect (GET /oidc/redirect).
+// Authorization code is stored with the issuance transaction and exchanged with the IDP on the token request.
 func (c *Controller) OidcRedirect(e echo.Context, params OidcRedirectParams) error {
 	req := e.Request()
 	ctx := req.Context()
 
 	resp, err := c.stateStore.GetAuthorizeState(ctx, params.State)
 	if err != nil {
+		if errors.Is(err, oidc4ci.ErrDataNotFound) {
+			return resterr.NewValidationError(resterr.InvalidValue, "state",
+				errors.New("authorization request for the given state doesn't exist or expired"))
+		}
+
 		return apiUtil.WriteOutput(e)(nil, err)
 	}
 
+	sessionCookie, _ := e.Cookie(authorizeSessionCookieName) // missing cookie fails the validation
+
+	if err = validateAuthorizeState(resp, sessionCookie); err != nil {
+		logger.Warnc(ctx, "Authorization state mismatch on redirect", log.WithState(params.State), log.WithError(err))
+
+		return resterr.NewOIDCError(invalidRequestOIDCErr, err)
+	}
+
 	storeResp, storeErr := c.issuerInteractionClient.StoreAuthorizationCodeRequest(ctx,
 		issuer.StoreAuthorizationCodeRequestJSONRequestBody{
 			Code:                params.Code,
@@ -436,6 +617,8 @@ func (c *Controller) OidcRedirect(e echo.Context, params OidcRedirectParams) err
 		)
 	}
 
+	e.SetCookie(c.authorizeSessionCookie("", -1))
+
 	responder := &fosite.AuthorizeResponse{}
 	responder.Header = resp.Header
 	responder.Parameters = resp.Parameters
@@ -450,7 +633,36 @@ func (c *Controller) OidcRedirect(e echo.Context, params OidcRedirectParams) err
 	return nil
 }
 
+// authorizeSessionCookie returns the session cookie that binds the authorization state to the user agent. It is
+// sent back on the redirect from the authorization server of the issuer, which is a top-level navigation.
+func (c *Controller) authorizeSessionCookie(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     authorizeSessionCookieName,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   strings.HasPrefix(c.issuerVCSPublicHost, "https://"),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// validateAuthorizeState checks that the authorization state was saved for the user agent that sent the redirect,
+// i.e. that the session cookie set by OidcAuthorize carries the session binding of the state.
+func validateAuthorizeState(state *oidc4ci.AuthorizeState, sessionCookie *http.Cookie) error {
+	if state.SessionBinding == "" || sessionCookie == nil ||
+		subtle.ConstantTimeCompare([]byte(state.SessionBinding), []byte(sessionCookie.Value)) != 1 {
+		return errors.New("authorization request was not started by this user agent")
+	}
+
+	return nil
+}
+
 // OidcToken handles OIDC token request (POST /oidc/token).
+// PKCE code_verifier is validated against code_challenge (S256 or plain) stored with the authorization code
+// by the fosite PKCE handler while creating the access request; mismatch results in invalid_grant error.
+// Refresh tokens are issued if the issuer profile enables them by granting the offline_access scope; on the
+// refresh_token grant the transaction is taken from the session restored by the OAuth2 provider.
 func (c *Controller) OidcToken(e echo.Context) error {
 	req := e.Request()
 
@@ -462,7 +674,7 @@ func (c *Controller) OidcToken(e echo.Context) error {
 
 	ar, err := c.oauth2Provider.NewAccessRequest(ctx, req, new(fosite.DefaultSession))
 	if err != nil {
-		return resterr.NewFositeError(resterr.FositeAccessError, e, c.oauth2Provider, err).WithAccessRequester(ar)
+		return writeTokenErrorFrom(ctx, e, err)
 	}
 
 	session := ar.GetSession().(*fosite.DefaultSession) //nolint:errcheck
@@ -474,7 +686,13 @@ func (c *Controller) OidcToken(e echo.Context) error {
 	var txID string
 
 	isPreAuthFlow := strings.EqualFold(e.FormValue("grant_type"), preAuthorizedCodeGrantType)
-	if isPreAuthFlow { //nolint:nestif
+
+	switch {
+	case strings.EqualFold(e.FormValue("grant_type"), refreshTokenGrantType):
+		// the session of the refreshed token is restored by the OAuth2 provider
+		txID, _ = session.Extra[txIDKey].(string)
+		isPreAuthFlow, _ = session.Extra[preAuthKey].(bool)
+	case isPreAuthFlow:
 		resp, preAuthorizeErr := c.oidcPreAuthorizedCode(
 			ctx,
 			e.FormValue("pre-authorized_code"),
@@ -483,11 +701,19 @@ func (c *Controller) OidcToken(e echo.Context) error {
 		)
 
 		if preAuthorizeErr != nil {
-			return preAuthorizeErr
+			return writeTokenErrorFrom(ctx, e, preAuthorizeErr)
+		}
+
+		if lo.FromPtr(resp.IssueRefreshToken) {
+			ar.GrantScope(offlineAccessScope)
+		}
+
+		if resp.SupportedSigningAlgorithms != nil {
+			session.Extra[signingAlgorithmsKey] = strings.Join(*resp.SupportedSigningAlgorithms, " ")
 		}
 
 		txID = resp.TxId
-	} else {
+	default:
 		exchangeResp, errExchange := c.issuerInteractionClient.ExchangeAuthorizationCodeRequest(
 			ctx,
 			issuer.ExchangeAuthorizationCodeRequestJSONRequestBody{
@@ -495,22 +721,22 @@ func (c *Controller) OidcToken(e echo.Context) error {
 			},
 		)
 		if errExchange != nil {
-			return fmt.Errorf("exchange authorization code request: %w", errExchange)
+			return writeTokenErrorFrom(ctx, e, fmt.Errorf("exchange authorization code request: %w", errExchange))
 		}
 
 		defer exchangeResp.Body.Close()
 
 		if exchangeResp.StatusCode != http.StatusOK {
-			return fmt.Errorf("exchange authorization code request: status code %d, %w",
+			return writeTokenErrorFrom(ctx, e, fmt.Errorf("exchange authorization code request: status code %d, %w",
 				exchangeResp.StatusCode,
 				parseInteractionError(exchangeResp.Body),
-			)
+			))
 		}
 
 		var exchangeResult issuer.ExchangeAuthorizationCodeResponse
 
 		if err = json.NewDecoder(exchangeResp.Body).Decode(&exchangeResult); err != nil {
-			return fmt.Errorf("read exchange auth code response: %w", err)
+			return writeTokenErrorFrom(ctx, e, fmt.Errorf("read exchange auth code response: %w", err))
 		}
 		txID = exchangeResult.TxId
 	}
@@ -519,7 +745,7 @@ func (c *Controller) OidcToken(e echo.Context) error {
 
 	responder, err := c.oauth2Provider.NewAccessResponse(ctx, ar)
 	if err != nil {
-		return resterr.NewFositeError(resterr.FositeAccessError, e, c.oauth2Provider, err).WithAccessRequester(ar)
+		return writeTokenErrorFrom(ctx, e, err)
 	}
 
 	c.setCNonce(responder, nonce)
@@ -528,6 +754,36 @@ func (c *Controller) OidcToken(e echo.Context) error {
 	return nil
 }
 
+// writeTokenErrorFrom writes err as the error response of the token endpoint. OAuth 2.0 errors keep their error code
+// and status, any other error is logged and reported as server_error with status 500.
+func writeTokenErrorFrom(ctx context.Context, e echo.Context, err error) error {
+	var rfcErr *fosite.RFC6749Error
+	if errors.As(err, &rfcErr) {
+		return writeTokenError(e, rfcErr.ErrorField, rfcErr.GetDescription(), rfcErr.CodeField)
+	}
+
+	var customErr *resterr.CustomError
+	if errors.As(err, &customErr) && customErr.Code == resterr.OIDCError {
+		return writeTokenError(e, customErr.Component, customErr.Err.Error(), http.StatusBadRequest)
+	}
+
+	logger.Errorc(ctx, "Failed to process token request", log.WithError(err))
+
+	return writeTokenError(e, serverErrorOIDCErr, err.Error(), http.StatusInternalServerError)
+}
+
+// writeTokenError writes the error response of the token endpoint.
+// Refer to https://datatracker.ietf.org/doc/html/rfc6749#section-5.2 for more details.
+func writeTokenError(ctx echo.Context, code, description string, status int) error {
+	ctx.Response().Header().Set("Cache-Control", "no-store")
+	ctx.Response().Header().Set("Pragma", "no-cache")
+
+	return ctx.JSON(status, &OIDCTokenErrorResponse{
+		Error:            code,
+		ErrorDescription: description,
+	})
+}
+
 func (c *Controller) setCNonce(
 	responder fosite.AccessResponder,
 	nonce string,
@@ -584,10 +840,62 @@ func (c *Controller) OidcCredential(e echo.Context) error {
 		return resterr.NewOIDCError(invalidTokenOIDCErr, fmt.Errorf("introspect token: %w", err))
 	}
 
+	clientID := ar.GetClient().GetID()
 	session := ar.GetSession().(*fosite.DefaultSession) //nolint:errcheck
 
+	idempotencyKey := req.Header.Get(idempotencyKeyHeader)
+	idempotencyScope := idempotencyScope{clientID: clientID}
+	idempotencyScope.txID, _ = session.Extra[txIDKey].(string) //nolint:errcheck
+
+	// the bound request is marshalled again, so that the formatting of the body does not matter
+	idempotencyRequest, err := json.Marshal(credentialRequest)
+	if err != nil {
+		return fmt.Errorf("marshal credential request: %w", err)
+	}
+
+	if idempotencyKey != "" && c.idempotencyCache.enabled() {
+		cached, ok, cacheErr := c.idempotencyCache.get(idempotencyScope, idempotencyKey, idempotencyRequest)
+		if cacheErr != nil {
+			return resterr.NewOIDCError(invalidRequestOIDCErr, cacheErr)
+		}
+
+		if ok {
+			return apiUtil.WriteOutput(e)(cached, nil)
+		}
+	}
+
+	cNonce, ok := session.Extra[cNonceKey].(string)
+	if !ok {
+		return resterr.NewOIDCError(string(resterr.InvalidOrMissingProofOIDCErr), errors.New("missing c_nonce"))
+	}
+
+	signatureVerifier := c.jwtVerifier
+
+	var proofKey *gojose.JSONWebKey
+
+	if c.proofVerifier != nil && hasJSONWebKeys(ar.GetClient()) {
+		txID, _ := session.Extra[txIDKey].(string) //nolint:errcheck
+
+		proofKey, err = c.proofVerifier.VerifyProofOfPossession(ctx,
+			&oidc4ci.JWTProof{
+				JWT:       credentialRequest.Proof.Jwt,
+				ProofType: credentialRequest.Proof.ProofType,
+			},
+			clientID,
+			oidc4ci.TxID(txID),
+			cNonce,
+		)
+		if err != nil {
+			return resterr.NewOIDCError(string(resterr.InvalidOrMissingProofOIDCErr),
+				fmt.Errorf("verify proof of possession: %w", err))
+		}
+
+		// signature has been verified against the key material registered for the client
+		signatureVerifier = jose.SignatureVerifierFunc(func(jose.Headers, []byte, []byte, []byte) error { return nil })
+	}
+
 	jws, rawClaims, err := jwt.Parse(credentialRequest.Proof.Jwt,
-		jwt.WithSignatureVerifier(c.jwtVerifier),
+		jwt.WithSignatureVerifier(signatureVerifier),
 		jwt.WithIgnoreClaimsMapDecoding(true),
 	)
 	if err != nil {
@@ -599,18 +907,24 @@ func (c *Controller) OidcCredential(e echo.Context) error {
 		return resterr.NewOIDCError(invalidRequestOIDCErr, errors.New("invalid jwt claims"))
 	}
 
-	did, err := c.validateProofClaims(ar.GetClient().GetID(), &claims, jws, session)
+	did, err := c.validateProofClaims(clientID, &claims, jws, session, cNonce)
 	if err != nil {
 		return err
 	}
 
+	if proofKey != nil {
+		// bind the credential to the key the proof was verified with rather than to the kid header
+		did = strings.Split(proofKey.KeyID, "#")[0]
+	}
+
 	resp, err := c.issuerInteractionClient.PrepareCredential(ctx,
 		issuer.PrepareCredentialJSONRequestBody{
-			TxId:          session.Extra[txIDKey].(string), //nolint:errcheck
-			Did:           lo.ToPtr(did),
-			Types:         credentialRequest.Types,
-			Format:        credentialRequest.Format,
-			AudienceClaim: claims.Audience,
+			TxId:                 session.Extra[txIDKey].(string), //nolint:errcheck
+			Did:                  lo.ToPtr(did),
+			Types:                lo.FromPtr(credentialRequest.Types),
+			Format:               credentialRequest.Format,
+			CredentialIdentifier: credentialRequest.CredentialIdentifier,
+			AudienceClaim:        claims.Audience,
 		},
 	)
 	if err != nil {
@@ -633,6 +947,8 @@ func (c *Controller) OidcCredential(e echo.Context) error {
 				return resterr.NewOIDCError("unsupported_credential_format", finalErr)
 			case resterr.OIDCCredentialTypeNotSupported:
 				return resterr.NewOIDCError("unsupported_credential_type", finalErr)
+			case resterr.OIDCInvalidCredentialRequest:
+				return resterr.NewOIDCError("invalid_credential_request", finalErr)
 			case resterr.InvalidOrMissingProofOIDCErr:
 				return resterr.NewOIDCError(string(resterr.InvalidOrMissingProofOIDCErr), errors.New(interactionErr.Message))
 			}
@@ -652,12 +968,18 @@ func (c *Controller) OidcCredential(e echo.Context) error {
 	session.Extra[cNonceKey] = nonce
 	session.Extra[cNonceExpiresAtKey] = time.Now().Add(cNonceTTL).Unix()
 
-	return apiUtil.WriteOutput(e)(CredentialResponse{
+	credentialResp := &CredentialResponse{
 		Credential:      result.Credential,
 		Format:          result.OidcFormat,
 		CNonce:          lo.ToPtr(nonce),
 		CNonceExpiresIn: lo.ToPtr(int(cNonceTTL.Seconds())),
-	}, nil)
+	}
+
+	if idempotencyKey != "" && c.idempotencyCache.enabled() {
+		c.idempotencyCache.set(idempotencyScope, idempotencyKey, idempotencyRequest, credentialResp)
+	}
+
+	return apiUtil.WriteOutput(e)(credentialResp, nil)
 }
 
 func validateCredentialRequest(e echo.Context, req *CredentialRequest) error {
@@ -665,9 +987,11 @@ func validateCredentialRequest(e echo.Context, req *CredentialRequest) error {
 		return err
 	}
 
-	_, err := common.ValidateVCFormat(common.VCFormat(lo.FromPtr(req.Format)))
-	if err != nil {
-		return resterr.NewOIDCError(invalidRequestOIDCErr, err)
+	// format and types are taken from the issuer metadata referenced by credential_identifier
+	if req.CredentialIdentifier == nil {
+		if _, err := common.ValidateVCFormat(common.VCFormat(lo.FromPtr(req.Format))); err != nil {
+			return resterr.NewOIDCError(invalidRequestOIDCErr, err)
+		}
 	}
 
 	if req.Proof == nil {
@@ -687,6 +1011,7 @@ func (c *Controller) validateProofClaims(
 	claims *JWTProofClaims,
 	jws *jwt.JSONWebToken,
 	session *fosite.DefaultSession,
+	cNonce string,
 ) (string, error) {
 	if nonceExp, ok := session.Extra[cNonceExpiresAtKey].(int64); ok && nonceExp < time.Now().Unix() {
 		return "", resterr.NewOIDCError(string(resterr.InvalidOrMissingProofOIDCErr), errors.New("nonce expired"))
@@ -704,7 +1029,7 @@ func (c *Controller) validateProofClaims(
 		return "", resterr.NewOIDCError(string(resterr.InvalidOrMissingProofOIDCErr), errors.New("missing iat"))
 	}
 
-	if nonce := session.Extra[cNonceKey].(string); claims.Nonce != nonce { //nolint:errcheck
+	if claims.Nonce != cNonce {
 		return "", resterr.NewOIDCError(string(resterr.InvalidOrMissingProofOIDCErr), errors.New("invalid nonce"))
 	}
 
@@ -712,6 +1037,14 @@ func (c *Controller) validateProofClaims(
 		return "", resterr.NewOIDCError(string(resterr.InvalidOrMissingProofOIDCErr), errors.New("invalid typ"))
 	}
 
+	// signing algorithms are stored space-separated, sessions created before the profile restriction have none
+	if algs, ok := session.Extra[signingAlgorithmsKey].(string); ok {
+		if alg, _ := jws.Headers.Algorithm(); !lo.Contains(strings.Fields(algs), alg) {
+			return "", resterr.NewOIDCError(string(resterr.InvalidOrMissingProofOIDCErr),
+				fmt.Errorf("unsupported proof alg %q", alg))
+		}
+	}
+
 	keyID, ok := jws.Headers.KeyID()
 	if !ok {
 		return "", resterr.NewOIDCError(string(resterr.InvalidOrMissingProofOIDCErr), errors.New("invalid kid"))
@@ -720,6 +1053,12 @@ func (c *Controller) validateProofClaims(
 	return strings.Split(keyID, "#")[0], nil
 }
 
+func hasJSONWebKeys(client fosite.Client) bool {
+	c, ok := client.(*oauth2client.Client)
+
+	return ok && c.JSONWebKeys != nil
+}
+
 // oidcPreAuthorizedCode handles pre-authorized code token request.
 func (c *Controller) oidcPreAuthorizedCode(
 	ctx context.Context,
@@ -762,6 +1101,8 @@ func (c *Controller) oidcPreAuthorizedCode(
 				return nil, resterr.NewOIDCError(invalidGrantOIDCErr, finalErr)
 			case resterr.OIDCPreAuthorizeInvalidClientID:
 				return nil, resterr.NewOIDCError(invalidClientOIDCErr, finalErr)
+			case resterr.OIDCPreAuthorizeWeakPin:
+				return nil, resterr.NewOIDCError(invalidGrantOIDCErr, errors.New(interactionErr.Message))
 			}
 		}
 
@@ -776,6 +1117,66 @@ func (c *Controller) oidcPreAuthorizedCode(
 	return &validateResponse, nil
 }
 
+// OidcDiscovery returns the OpenID Provider metadata of the issuer profile
+// (GET /oidc/{profileID}/{profileVersion}/.well-known/openid-configuration).
+func (c *Controller) OidcDiscovery(e echo.Context, profileID, profileVersion string) error {
+	ctx, span := c.tracer.Start(e.Request().Context(), "OidcDiscovery")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("profile_id", profileID))
+	span.SetAttributes(attribute.String("profile_version", profileVersion))
+
+	doc, err := c.discoveryService.GetDiscoveryDocument(ctx, profileID, profileVersion)
+	if err != nil {
+		if errors.Is(err, oidc4ci.ErrProfileNotActive) || errors.Is(err, oidc4ci.ErrOIDCNotConfigured) {
+			return resterr.NewValidationError(resterr.DoesntExist, "profile", err)
+		}
+
+		return resterr.NewSystemError("DiscoveryService", "GetDiscoveryDocument", err)
+	}
+
+	return apiUtil.WriteOutput(e)(&OIDCDiscoveryDocument{
+		Issuer:                                     doc.Issuer,
+		AuthorizationEndpoint:                      doc.AuthorizationEndpoint,
+		TokenEndpoint:                              doc.TokenEndpoint,
+		PushedAuthorizationRequestEndpoint:         doc.PushedAuthorizationRequestEndpoint,
+		RegistrationEndpoint:                       lo.EmptyableToPtr(doc.RegistrationEndpoint),
+		ScopesSupported:                            lo.EmptyableToPtr(doc.ScopesSupported),
+		ResponseTypesSupported:                     doc.ResponseTypesSupported,
+		GrantTypesSupported:                        lo.EmptyableToPtr(doc.GrantTypesSupported),
+		TokenEndpointAuthMethodsSupported:          lo.EmptyableToPtr(doc.TokenEndpointAuthMethodsSupported),
+		PreAuthorizedGrantAnonymousAccessSupported: doc.PreAuthorizedGrantAnonymousAccessSupported,
+		WalletInitiatedAuthFlowSupported:           doc.WalletInitiatedAuthFlowSupported,
+	}, nil)
+}
+
+// OidcCredentialIssuerMetadata returns the OpenID4VCI Credential Issuer metadata of the issuer profile
+// (GET /oidc/{profileID}/{profileVersion}/credential-issuer-metadata).
+func (c *Controller) OidcCredentialIssuerMetadata(e echo.Context, profileID, profileVersion string) error {
+	ctx, span := c.tracer.Start(e.Request().Context(), "OidcCredentialIssuerMetadata")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("profile_id", profileID))
+	span.SetAttributes(attribute.String("profile_version", profileVersion))
+
+	metadata, err := c.discoveryService.BuildCredentialIssuerMetadata(ctx, profileID, profileVersion)
+	if err != nil {
+		if errors.Is(err, oidc4ci.ErrProfileNotActive) || errors.Is(err, oidc4ci.ErrVCOptionsNotConfigured) {
+			return resterr.NewValidationError(resterr.DoesntExist, "profile", err)
+		}
+
+		return resterr.NewSystemError("DiscoveryService", "BuildCredentialIssuerMetadata", err)
+	}
+
+	return apiUtil.WriteOutput(e)(&CredentialIssuerMetadata{
+		CredentialIssuer:             metadata.CredentialIssuer,
+		AuthorizationEndpoint:        metadata.AuthorizationEndpoint,
+		CredentialEndpoint:           metadata.CredentialEndpoint,
+		CredentialsSupported:         metadata.CredentialsSupported,
+		CryptographicSuitesSupported: lo.EmptyableToPtr(metadata.CryptographicSuitesSupported),
+	}, nil)
+}
+
 // OidcRegisterClient registers dynamically an OAuth 2.0 client with the VCS authorization server.
 //
 //nolint:funlen,gocognit
@@ -819,6 +1220,8 @@ func (c *Controller) OidcRegisterClient(e echo.Context, profileID string, profil
 		SoftwareID:              lo.FromPtr(body.SoftwareId),
 		SoftwareVersion:         lo.FromPtr(body.SoftwareVersion),
 		TokenEndpointAuthMethod: lo.FromPtr(body.TokenEndpointAuthMethod),
+		Metadata:                lo.FromPtr(body.ExtensionFields),
+		SoftwareStatement:       body.SoftwareStatement,
 	}
 
 	client, err := c.clientManager.Create(ctx, profileID, profileVersion, data)
@@ -835,11 +1238,9 @@ func (c *Controller) OidcRegisterClient(e echo.Context, profileID string, profil
 		return resterr.NewSystemError("ClientManager", "Create", err)
 	}
 
-	resp := &RegisterOAuthClientResponse{
-		ClientId:                client.ID,
-		ClientIdIssuedAt:        int(client.CreatedAt.Unix()),
-		GrantTypes:              client.GrantTypes,
-		TokenEndpointAuthMethod: client.TokenEndpointAuthMethod,
+	resp, err := toRegisterOAuthClientResponse(client)
+	if err != nil {
+		return err
 	}
 
 	if client.Secret != nil {
@@ -847,6 +1248,74 @@ func (c *Controller) OidcRegisterClient(e echo.Context, profileID string, profil
 		resp.ClientSecretExpiresAt = lo.ToPtr(int(client.SecretExpiresAt))
 	}
 
+	resp.SoftwareStatement = body.SoftwareStatement
+
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("marshal register oauth client response: %w", err)
+	}
+
+	return e.JSONBlob(http.StatusCreated, b)
+}
+
+// OidcListClients returns paginated metadata of OAuth 2.0 clients registered for the profile.
+// Client secrets are not returned.
+// GET /oidc/{profileID}/{profileVersion}/register.
+func (c *Controller) OidcListClients(
+	e echo.Context,
+	profileID, profileVersion string,
+	params OidcListClientsParams,
+) error {
+	ctx, span := c.tracer.Start(e.Request().Context(), "OidcListClients")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("profile_id", profileID))
+	span.SetAttributes(attribute.String("profile_version", profileVersion))
+
+	page := lo.FromPtrOr(params.Page, 1)
+	if page < 1 {
+		return resterr.NewValidationError(resterr.InvalidValue, "page", errors.New("page must be positive"))
+	}
+
+	pageSize := lo.FromPtrOr(params.PageSize, defaultClientsPageSize)
+	if pageSize < 1 || pageSize > maxClientsPageSize {
+		return resterr.NewValidationError(resterr.InvalidValue, "pageSize",
+			fmt.Errorf("page size must be between 1 and %d", maxClientsPageSize))
+	}
+
+	clients, total, err := c.clientManager.List(ctx, profileID, profileVersion, page, pageSize)
+	if err != nil {
+		return resterr.NewSystemError("ClientManager", "List", err)
+	}
+
+	resp := &ListOAuthClientsResponse{
+		Clients: make([]RegisterOAuthClientResponse, 0, len(clients)),
+		Total:   total,
+	}
+
+	for _, client := range clients {
+		clientResp, convErr := toRegisterOAuthClientResponse(client)
+		if convErr != nil {
+			return convErr
+		}
+
+		resp.Clients = append(resp.Clients, *clientResp)
+	}
+
+	return e.JSON(http.StatusOK, resp)
+}
+
+// toRegisterOAuthClientResponse converts registered client metadata to the response model. Client secret is not set.
+//
+//nolint:gocyclo
+func toRegisterOAuthClientResponse(client *oauth2client.Client) (*RegisterOAuthClientResponse, error) {
+	resp := &RegisterOAuthClientResponse{
+		ClientId:                client.ID,
+		ClientIdIssuedAt:        int(client.CreatedAt.Unix()),
+		GrantTypes:              client.GrantTypes,
+		TokenEndpointAuthMethod: client.TokenEndpointAuthMethod,
+	}
+
 	if client.Name != "" {
 		resp.ClientName = lo.ToPtr(client.Name)
 	}
@@ -859,9 +1328,15 @@ func (c *Controller) OidcRegisterClient(e echo.Context, profileID string, profil
 		resp.Contacts = lo.ToPtr(client.Contacts)
 	}
 
+	if len(client.Metadata) > 0 {
+		resp.ExtensionFields = lo.ToPtr(client.Metadata)
+	}
+
 	if client.JSONWebKeys != nil {
+		var err error
+
 		if resp.Jwks, err = jwksToMap(client.JSONWebKeys); err != nil {
-			return fmt.Errorf("convert jwks to map: %w", err)
+			return nil, fmt.Errorf("convert jwks to map: %w", err)
 		}
 	}
 
@@ -901,12 +1376,7 @@ func (c *Controller) OidcRegisterClient(e echo.Context, profileID string, profil
 		resp.TosUri = lo.ToPtr(client.TermsOfServiceURI)
 	}
 
-	b, err := json.Marshal(resp)
-	if err != nil {
-		return fmt.Errorf("marshal register oauth client response: %w", err)
-	}
-
-	return e.JSONBlob(http.StatusCreated, b)
+	return resp, nil
 }
 
 func jwksToMap(jwks *gojose.JSONWebKeySet) (*map[string]interface{}, error) {
@@ -11,10 +11,14 @@ import (
 	"context"
 	"crypto/ed25519"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/cookiejar"
 	"net/http/httptest"
 	"net/url"
 	"strings"
@@ -23,6 +27,7 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/ory/fosite"
 	"github.com/ory/fosite/compose"
@@ -145,7 +150,7 @@ func TestAuthorizeCodeGrantFlow(t *testing.T) {
 
 	authCodeURL := oauthClient.AuthCodeURL(opState, params...)
 
-	resp, err := http.DefaultClient.Get(authCodeURL)
+	resp, err := newBrowser(t).Get(authCodeURL)
 	require.NoError(t, err)
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 	code := resp.Request.URL.Query().Get("code")
@@ -186,7 +191,7 @@ func TestAuthorizeCodeGrantFlow(t *testing.T) {
 	b, err := json.Marshal(oidc4ci.CredentialRequest{
 		Format: lo.ToPtr(string(common.JwtVcJsonLd)),
 		Proof:  &oidc4ci.JWTProof{ProofType: "jwt", Jwt: jws},
-		Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+		Types:  lo.ToPtr([]string{"VerifiableCredential", "UniversityDegreeCredential"}),
 	})
 	require.NoError(t, err)
 
@@ -195,6 +200,284 @@ func TestAuthorizeCodeGrantFlow(t *testing.T) {
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
+func TestAuthorizeCodeGrantFlowPKCE(t *testing.T) {
+	const codeVerifier = "xalsLDydJtHwIQZukUyj6boam5vMUaJRWv-BnGCAzcZi3ZTs"
+
+	s256Challenge := sha256.Sum256([]byte(codeVerifier))
+
+	tests := []struct {
+		name                string
+		codeChallengeMethod string
+		codeChallenge       string
+		codeVerifier        string
+		expectedErr         string
+	}{
+		{
+			name:                "S256",
+			codeChallengeMethod: "S256",
+			codeChallenge:       base64.RawURLEncoding.EncodeToString(s256Challenge[:]),
+			codeVerifier:        codeVerifier,
+		},
+		{
+			name:                "plain",
+			codeChallengeMethod: "plain",
+			codeChallenge:       codeVerifier,
+			codeVerifier:        codeVerifier,
+		},
+		{
+			name:                "tampered verifier",
+			codeChallengeMethod: "S256",
+			codeChallenge:       base64.RawURLEncoding.EncodeToString(s256Challenge[:]),
+			codeVerifier:        codeVerifier[:len(codeVerifier)-1] + "X",
+			expectedErr:         "invalid_grant",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = resterr.HTTPErrorHandler(trace.NewNoopTracerProvider().Tracer(""))
+
+			opState := uuid.NewString()
+
+			srv := httptest.NewServer(e)
+			defer srv.Close()
+
+			fositeStore := getDefaultStore()
+			fositeStore.Clients[clientID] = &fosite.DefaultClient{
+				ID:            clientID,
+				Secret:        []byte(`$2a$10$IxMdI6d.LIRZPpSfEwNoeu4rY3FhDREsxFJXikcgdRRAStxUlsuEO`), // = "foobar"
+				RedirectURIs:  []string{srv.URL + "/client/cb"},
+				ResponseTypes: []string{"code"},
+				GrantTypes:    []string{"authorization_code"},
+				Scopes:        []string{"openid", "profile"},
+			}
+
+			config := new(fosite.Config)
+			config.EnforcePKCE = true
+			config.EnablePKCEPlainChallengeMethod = true
+
+			var hmacStrategy = &fositeoauth.HMACSHAStrategy{
+				Enigma: &hmac.HMACStrategy{
+					Config: &fosite.Config{
+						GlobalSecret: []byte("secret-for-signing-and-verifying-signatures"),
+					},
+				},
+				Config: &fosite.Config{
+					AuthorizeCodeLifespan: time.Minute,
+					AccessTokenLifespan:   time.Hour,
+				},
+			}
+
+			oauth2Provider := compose.Compose(config, fositeStore, hmacStrategy,
+				compose.OAuth2AuthorizeExplicitFactory,
+				compose.OAuth2PKCEFactory,
+				compose.OAuth2TokenIntrospectionFactory,
+			)
+
+			interaction := NewMockIssuerInteractionClient(gomock.NewController(t))
+
+			interaction.EXPECT().PrepareAuthorizationRequest(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(
+					ctx context.Context,
+					req issuer.PrepareAuthorizationRequestJSONRequestBody,
+					reqEditors ...issuer.RequestEditorFn,
+				) (*http.Response, error) {
+					b, err := json.Marshal(&issuer.PrepareClaimDataAuthorizationResponse{
+						AuthorizationEndpoint: srv.URL + "/third-party/oidc/authorize",
+						AuthorizationRequest: issuer.OAuthParameters{
+							ClientId:     clientID,
+							ClientSecret: "foobar",
+							ResponseType: req.ResponseType,
+							Scope:        lo.FromPtr(req.Scope),
+						},
+					})
+					require.NoError(t, err)
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(bytes.NewBuffer(b)),
+					}, nil
+				})
+
+			interaction.EXPECT().StoreAuthorizationCodeRequest(gomock.Any(), gomock.Any()).
+				Return(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBuffer(nil)),
+				}, nil)
+
+			if tt.expectedErr == "" {
+				interaction.EXPECT().ExchangeAuthorizationCodeRequest(gomock.Any(),
+					issuer.ExchangeAuthorizationCodeRequestJSONRequestBody{OpState: opState},
+				).Return(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"tx_id":"txID"}`)),
+				}, nil)
+			}
+
+			oidc4ci.RegisterHandlers(e, oidc4ci.NewController(&oidc4ci.Config{
+				OAuth2Provider:          oauth2Provider,
+				StateStore:              &memoryStateStore{kv: make(map[string]*oidc4cisrv.AuthorizeState)},
+				IssuerInteractionClient: interaction,
+				IssuerVCSPublicHost:     srv.URL,
+				Tracer:                  trace.NewNoopTracerProvider().Tracer(""),
+			}))
+
+			registerThirdPartyOIDCAuthorizeEndpoint(t, e)
+			registerClientCallback(t, e)
+
+			oauthClient := &oauth2.Config{
+				ClientID:     clientID,
+				ClientSecret: "foobar",
+				RedirectURL:  srv.URL + "/client/cb",
+				Scopes:       []string{"openid", "profile"},
+				Endpoint: oauth2.Endpoint{
+					TokenURL:  srv.URL + "/oidc/token",
+					AuthURL:   srv.URL + "/oidc/authorize",
+					AuthStyle: oauth2.AuthStyleInHeader,
+				},
+			}
+
+			resp, err := newBrowser(t).Get(oauthClient.AuthCodeURL(opState,
+				oauth2.SetAuthURLParam("code_challenge_method", tt.codeChallengeMethod),
+				oauth2.SetAuthURLParam("code_challenge", tt.codeChallenge),
+				oauth2.SetAuthURLParam("issuer_state", opState),
+			))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			code := resp.Request.URL.Query().Get("code")
+			require.NotEmpty(t, code)
+
+			token, err := oauthClient.Exchange(context.Background(), code,
+				oauth2.SetAuthURLParam("code_verifier", tt.codeVerifier),
+			)
+
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				require.Nil(t, token)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotEmpty(t, token.AccessToken)
+		})
+	}
+}
+
+func TestAuthorizeCodeGrantFlowSessionBinding(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = resterr.HTTPErrorHandler(trace.NewNoopTracerProvider().Tracer(""))
+
+	opState := uuid.NewString()
+
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	fositeStore := getDefaultStore()
+	fositeStore.Clients[clientID] = &fosite.DefaultClient{
+		ID:            clientID,
+		Secret:        []byte(`$2a$10$IxMdI6d.LIRZPpSfEwNoeu4rY3FhDREsxFJXikcgdRRAStxUlsuEO`), // = "foobar"
+		RedirectURIs:  []string{srv.URL + "/client/cb"},
+		ResponseTypes: []string{"code"},
+		GrantTypes:    []string{"authorization_code"},
+		Scopes:        []string{"openid", "profile"},
+	}
+
+	oauth2Provider := compose.Compose(new(fosite.Config), fositeStore, &fositeoauth.HMACSHAStrategy{
+		Enigma: &hmac.HMACStrategy{
+			Config: &fosite.Config{
+				GlobalSecret: []byte("secret-for-signing-and-verifying-signatures"),
+			},
+		},
+		Config: &fosite.Config{
+			AuthorizeCodeLifespan: time.Minute,
+			AccessTokenLifespan:   time.Hour,
+		},
+	},
+		compose.OAuth2AuthorizeExplicitFactory,
+		compose.OAuth2PKCEFactory,
+	)
+
+	interaction := NewMockIssuerInteractionClient(gomock.NewController(t))
+
+	interaction.EXPECT().PrepareAuthorizationRequest(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			ctx context.Context,
+			req issuer.PrepareAuthorizationRequestJSONRequestBody,
+			reqEditors ...issuer.RequestEditorFn,
+		) (*http.Response, error) {
+			b, err := json.Marshal(&issuer.PrepareClaimDataAuthorizationResponse{
+				AuthorizationEndpoint: srv.URL + "/third-party/oidc/authorize",
+				AuthorizationRequest: issuer.OAuthParameters{
+					ClientId:     clientID,
+					ClientSecret: "foobar",
+					ResponseType: req.ResponseType,
+					Scope:        lo.FromPtr(req.Scope),
+				},
+			})
+			require.NoError(t, err)
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBuffer(b)),
+			}, nil
+		})
+
+	interaction.EXPECT().StoreAuthorizationCodeRequest(gomock.Any(), gomock.Any()).Times(0)
+
+	oidc4ci.RegisterHandlers(e, oidc4ci.NewController(&oidc4ci.Config{
+		OAuth2Provider:          oauth2Provider,
+		StateStore:              &memoryStateStore{kv: make(map[string]*oidc4cisrv.AuthorizeState)},
+		IssuerInteractionClient: interaction,
+		IssuerVCSPublicHost:     srv.URL,
+		Tracer:                  trace.NewNoopTracerProvider().Tracer(""),
+	}))
+
+	registerThirdPartyOIDCAuthorizeEndpoint(t, e)
+	registerClientCallback(t, e)
+
+	oauthClient := &oauth2.Config{
+		ClientID:    clientID,
+		RedirectURL: srv.URL + "/client/cb",
+		Scopes:      []string{"openid", "profile"},
+		Endpoint:    oauth2.Endpoint{AuthURL: srv.URL + "/oidc/authorize"},
+	}
+
+	// the attacker starts the authorization in their own browser and stops before the redirect to the issuer
+	attacker := newBrowser(t)
+	attacker.CheckRedirect = func(req *http.Request, _ []*http.Request) error {
+		if req.URL.Path == "/oidc/redirect" {
+			return http.ErrUseLastResponse
+		}
+
+		return nil
+	}
+
+	resp, err := attacker.Get(oauthClient.AuthCodeURL(opState,
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+		oauth2.SetAuthURLParam("code_challenge", "MLSjJIlPzeRQoN9YiIsSzziqEuBSmS4kDgI3NDjbfF8"),
+		oauth2.SetAuthURLParam("issuer_state", opState),
+	))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusSeeOther, resp.StatusCode)
+
+	redirectURL := resp.Header.Get("Location")
+	require.Contains(t, redirectURL, "/oidc/redirect")
+
+	// and lures the victim into completing it
+	resp, err = newBrowser(t).Get(redirectURL)
+	require.NoError(t, err)
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.Contains(t, string(b), "invalid_request")
+}
+
 func TestPreAuthorizeCodeGrantFlow(t *testing.T) {
 	e := echo.New()
 	e.HTTPErrorHandler = resterr.HTTPErrorHandler(trace.NewNoopTracerProvider().Tracer(""))
@@ -279,6 +562,143 @@ func TestPreAuthorizeCodeGrantFlow(t *testing.T) {
 	assert.Greater(t, *token.ExpiresIn, 0)
 }
 
+func TestTokenErrorResponse(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = resterr.HTTPErrorHandler(trace.NewNoopTracerProvider().Tracer(""))
+
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	config := new(fosite.Config)
+	config.EnforcePKCE = true
+
+	var hmacStrategy = &fositeoauth.HMACSHAStrategy{
+		Enigma: &hmac.HMACStrategy{
+			Config: &fosite.Config{
+				GlobalSecret: []byte("secret-for-signing-and-verifying-signatures"),
+			},
+		},
+		Config: &fosite.Config{
+			AuthorizeCodeLifespan: time.Minute,
+			AccessTokenLifespan:   time.Hour,
+		},
+	}
+
+	oauth2Provider := compose.Compose(config, getDefaultStore(), hmacStrategy,
+		compose.OAuth2AuthorizeExplicitFactory,
+		compose.OAuth2PKCEFactory,
+		compose.OAuth2TokenIntrospectionFactory,
+		handlers.OAuth2PreAuthorizeFactory,
+	)
+
+	interaction := NewMockIssuerInteractionClient(gomock.NewController(t))
+
+	controller := oidc4ci.NewController(&oidc4ci.Config{
+		OAuth2Provider:          oauth2Provider,
+		StateStore:              &memoryStateStore{kv: make(map[string]*oidc4cisrv.AuthorizeState)},
+		IssuerInteractionClient: interaction,
+		IssuerVCSPublicHost:     srv.URL,
+		ExternalHostURL:         srv.URL,
+		Tracer:                  trace.NewNoopTracerProvider().Tracer(""),
+	})
+
+	oidc4ci.RegisterHandlers(e, controller)
+
+	requestToken := func(t *testing.T, form url.Values, status int) *oidc4ci.OIDCTokenErrorResponse {
+		t.Helper()
+
+		resp, err := http.DefaultClient.PostForm(fmt.Sprintf("%v/oidc/token", srv.URL), form)
+		require.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		require.Equal(t, status, resp.StatusCode)
+		require.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json"))
+
+		var errResp oidc4ci.OIDCTokenErrorResponse
+
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+
+		return &errResp
+	}
+
+	t.Run("Invalid authorization code", func(t *testing.T) {
+		errResp := requestToken(t, url.Values{
+			"grant_type":    {"authorization_code"},
+			"code":          {"invalid-code"},
+			"client_id":     {clientID},
+			"code_verifier": {"verifier"},
+		}, http.StatusUnauthorized)
+
+		require.Equal(t, "invalid_client", errResp.Error)
+	})
+
+	t.Run("Invalid pin", func(t *testing.T) {
+		interaction.EXPECT().ValidatePreAuthorizedCodeRequest(gomock.Any(), gomock.Any()).Return(&http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       io.NopCloser(strings.NewReader(`{"code":"oidc-pre-authorize-invalid-pin"}`)),
+		}, nil)
+
+		errResp := requestToken(t, url.Values{
+			"grant_type":          {"urn:ietf:params:oauth:grant-type:pre-authorized_code"},
+			"pre-authorized_code": {"pre-auth-code"},
+			"user_pin":            {"123"},
+			"client_id":           {clientID},
+		}, http.StatusBadRequest)
+
+		require.Equal(t, "invalid_grant", errResp.Error)
+		require.Contains(t, errResp.ErrorDescription, "oidc-pre-authorize-invalid-pin")
+	})
+
+	t.Run("Weak pin", func(t *testing.T) {
+		interaction.EXPECT().ValidatePreAuthorizedCodeRequest(gomock.Any(), gomock.Any()).Return(&http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body: io.NopCloser(strings.NewReader(
+				`{"code":"oidc-pre-authorize-weak-pin","message":"user_pin does not meet security requirements"}`)),
+		}, nil)
+
+		errResp := requestToken(t, url.Values{
+			"grant_type":          {"urn:ietf:params:oauth:grant-type:pre-authorized_code"},
+			"pre-authorized_code": {"pre-auth-code"},
+			"user_pin":            {"12"},
+			"client_id":           {clientID},
+		}, http.StatusBadRequest)
+
+		require.Equal(t, "invalid_grant", errResp.Error)
+		require.Equal(t, "user_pin does not meet security requirements", errResp.ErrorDescription)
+	})
+
+	t.Run("Pre-authorized code already used", func(t *testing.T) {
+		interaction.EXPECT().ValidatePreAuthorizedCodeRequest(gomock.Any(), gomock.Any()).Return(&http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body: io.NopCloser(strings.NewReader(
+				`{"code":"oidc-tx-not-found","message":"pre-authorization code already used"}`)),
+		}, nil)
+
+		errResp := requestToken(t, url.Values{
+			"grant_type":          {"urn:ietf:params:oauth:grant-type:pre-authorized_code"},
+			"pre-authorized_code": {"pre-auth-code"},
+			"client_id":           {clientID},
+		}, http.StatusBadRequest)
+
+		require.Equal(t, "invalid_grant", errResp.Error)
+		require.Contains(t, errResp.ErrorDescription, "pre-authorization code already used")
+	})
+
+	t.Run("Interaction failure", func(t *testing.T) {
+		interaction.EXPECT().ValidatePreAuthorizedCodeRequest(gomock.Any(), gomock.Any()).Return(
+			nil, errors.New("connection refused"))
+
+		errResp := requestToken(t, url.Values{
+			"grant_type":          {"urn:ietf:params:oauth:grant-type:pre-authorized_code"},
+			"pre-authorized_code": {"pre-auth-code"},
+			"client_id":           {clientID},
+		}, http.StatusInternalServerError)
+
+		require.Equal(t, "server_error", errResp.Error)
+	})
+}
+
 func mockIssuerInteractionClient(
 	t *testing.T,
 	serverURL string,
@@ -398,6 +818,16 @@ func registerClientCallback(t *testing.T, e *echo.Echo) {
 	})
 }
 
+// newBrowser returns an HTTP client that keeps cookies like a user agent does.
+func newBrowser(t *testing.T) *http.Client {
+	t.Helper()
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+
+	return &http.Client{Jar: jar}
+}
+
 type memoryStateStore struct {
 	kv map[string]*oidc4cisrv.AuthorizeState
 	mu sync.RWMutex
@@ -204,6 +204,7 @@ func TestController_OidcAuthorize(t *testing.T) {
 		mockInteractionClient = NewMockIssuerInteractionClient(gomock.NewController(t))
 		mockHTTPClient        = NewMockHTTPClient(gomock.NewController(t))
 		params                oidc4ci.OidcAuthorizeParams
+		sessionBinding        string
 	)
 
 	tests := []struct {
@@ -263,12 +264,29 @@ func TestController_OidcAuthorize(t *testing.T) {
 					})
 
 				mockStateStore.EXPECT().SaveAuthorizeState(gomock.Any(), *params.IssuerState, gomock.Any()).
-					Return(nil)
+					DoAndReturn(func(
+						_ context.Context,
+						_ string,
+						authorizeState *oidc4cisrv.AuthorizeState,
+						_ ...func(insertOptions *oidc4cisrv.InsertOptions),
+					) error {
+						sessionBinding = authorizeState.SessionBinding
+
+						return nil
+					})
 			},
 			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
 				require.NoError(t, err)
 				require.Equal(t, http.StatusSeeOther, rec.Code)
 				require.NotEmpty(t, rec.Header().Get("Location"))
+
+				cookies := rec.Result().Cookies() //nolint:bodyclose
+				require.Len(t, cookies, 1)
+				require.Equal(t, "vcs_oidc_authorize_session", cookies[0].Name)
+				require.NotEmpty(t, sessionBinding)
+				require.Equal(t, sessionBinding, cookies[0].Value)
+				require.True(t, cookies[0].HttpOnly)
+				require.True(t, cookies[0].Secure)
 			},
 		},
 		{
@@ -681,6 +699,147 @@ func TestController_OidcAuthorize(t *testing.T) {
 				require.ErrorContains(t, err, "save authorize state")
 			},
 		},
+		{
+			name: "pkce required and code challenge missing",
+			setup: func() {
+				params = oidc4ci.OidcAuthorizeParams{
+					ResponseType: "code",
+					IssuerState:  lo.ToPtr("opState"),
+				}
+
+				mockOAuthProvider.EXPECT().NewAuthorizeRequest(gomock.Any(), gomock.Any()).Return(&fosite.AuthorizeRequest{
+					Request: fosite.Request{RequestedScope: []string{"openid"}},
+				}, nil)
+
+				b, err := json.Marshal(&issuer.PrepareClaimDataAuthorizationResponse{
+					AuthorizationRequest: issuer.OAuthParameters{},
+					PkceRequired:         lo.ToPtr(true),
+				})
+				require.NoError(t, err)
+
+				mockInteractionClient.EXPECT().PrepareAuthorizationRequest(gomock.Any(), gomock.Any()).Return(
+					&http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(bytes.NewBuffer(b)),
+					}, nil)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				var customErr *resterr.CustomError
+
+				require.ErrorAs(t, err, &customErr)
+				require.Equal(t, resterr.OIDCError, customErr.Code)
+				require.Equal(t, "invalid_request", customErr.Component)
+				require.ErrorContains(t, err, "code_challenge is required")
+			},
+		},
+		{
+			name: "plain pkce disallowed",
+			setup: func() {
+				params = oidc4ci.OidcAuthorizeParams{
+					ResponseType:        "code",
+					IssuerState:         lo.ToPtr("opState"),
+					CodeChallenge:       "code-challenge",
+					CodeChallengeMethod: lo.ToPtr("plain"),
+				}
+
+				mockOAuthProvider.EXPECT().NewAuthorizeRequest(gomock.Any(), gomock.Any()).Return(&fosite.AuthorizeRequest{
+					Request: fosite.Request{RequestedScope: []string{"openid"}},
+				}, nil)
+
+				b, err := json.Marshal(&issuer.PrepareClaimDataAuthorizationResponse{
+					AuthorizationRequest: issuer.OAuthParameters{},
+					DisallowPlainPkce:    lo.ToPtr(true),
+				})
+				require.NoError(t, err)
+
+				mockInteractionClient.EXPECT().PrepareAuthorizationRequest(gomock.Any(), gomock.Any()).Return(
+					&http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(bytes.NewBuffer(b)),
+					}, nil)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				var customErr *resterr.CustomError
+
+				require.ErrorAs(t, err, &customErr)
+				require.Equal(t, resterr.OIDCError, customErr.Code)
+				require.Equal(t, "invalid_request", customErr.Component)
+				require.ErrorContains(t, err, "plain code_challenge_method not supported")
+			},
+		},
+		{
+			name: "plain pkce disallowed and code challenge method defaults to plain",
+			setup: func() {
+				params = oidc4ci.OidcAuthorizeParams{
+					ResponseType:  "code",
+					IssuerState:   lo.ToPtr("opState"),
+					CodeChallenge: "code-challenge",
+				}
+
+				mockOAuthProvider.EXPECT().NewAuthorizeRequest(gomock.Any(), gomock.Any()).Return(&fosite.AuthorizeRequest{
+					Request: fosite.Request{RequestedScope: []string{"openid"}},
+				}, nil)
+
+				b, err := json.Marshal(&issuer.PrepareClaimDataAuthorizationResponse{
+					AuthorizationRequest: issuer.OAuthParameters{},
+					DisallowPlainPkce:    lo.ToPtr(true),
+				})
+				require.NoError(t, err)
+
+				mockInteractionClient.EXPECT().PrepareAuthorizationRequest(gomock.Any(), gomock.Any()).Return(
+					&http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(bytes.NewBuffer(b)),
+					}, nil)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				require.ErrorContains(t, err, "plain code_challenge_method not supported")
+			},
+		},
+		{
+			name: "success with refresh token",
+			setup: func() {
+				params = oidc4ci.OidcAuthorizeParams{
+					ResponseType: "code",
+					IssuerState:  lo.ToPtr("opState"),
+				}
+
+				mockOAuthProvider.EXPECT().NewAuthorizeRequest(gomock.Any(), gomock.Any()).Return(&fosite.AuthorizeRequest{
+					Request: fosite.Request{RequestedScope: []string{"openid"}},
+				}, nil)
+
+				mockOAuthProvider.EXPECT().NewAuthorizeResponse(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(
+						ctx context.Context,
+						ar fosite.AuthorizeRequester,
+						session fosite.Session,
+					) (fosite.AuthorizeResponder, error) {
+						assert.True(t, ar.GetGrantedScopes().Has("offline_access"))
+
+						return &fosite.AuthorizeResponse{}, nil
+					},
+				)
+
+				b, err := json.Marshal(&issuer.PrepareClaimDataAuthorizationResponse{
+					AuthorizationRequest: issuer.OAuthParameters{},
+					IssueRefreshToken:    lo.ToPtr(true),
+				})
+				require.NoError(t, err)
+
+				mockInteractionClient.EXPECT().PrepareAuthorizationRequest(gomock.Any(), gomock.Any()).Return(
+					&http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(bytes.NewBuffer(b)),
+					}, nil)
+
+				mockStateStore.EXPECT().SaveAuthorizeState(gomock.Any(), *params.IssuerState, gomock.Any()).
+					Return(nil)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				require.NoError(t, err)
+				require.Equal(t, http.StatusSeeOther, rec.Code)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -705,12 +864,190 @@ func TestController_OidcAuthorize(t *testing.T) {
 	}
 }
 
+func TestController_OidcAuthorizeRequestObject(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	jwtVerifier, err := jwt.NewEd25519Verifier(publicKey)
+	require.NoError(t, err)
+
+	_, otherPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	newRequestObject := func(t *testing.T, key ed25519.PrivateKey, claims map[string]interface{}) string {
+		t.Helper()
+
+		signedJWT, signErr := jwt.NewSigned(claims, nil, NewJWSSigner("", "EdDSA", jwt.NewEd25519Signer(key)))
+		require.NoError(t, signErr)
+
+		jws, signErr := signedJWT.Serialize(false)
+		require.NoError(t, signErr)
+
+		return jws
+	}
+
+	authorize := func(
+		t *testing.T,
+		provider oidc4ci.OAuth2Provider,
+		interactionClient oidc4ci.IssuerInteractionClient,
+		stateStore oidc4ci.StateStore,
+		query url.Values,
+	) error {
+		t.Helper()
+
+		controller := oidc4ci.NewController(&oidc4ci.Config{
+			OAuth2Provider:          provider,
+			StateStore:              stateStore,
+			IssuerInteractionClient: interactionClient,
+			JWTVerifier:             jwtVerifier,
+			IssuerVCSPublicHost:     "https://issuer.example.com",
+			ExternalHostURL:         aud,
+		})
+
+		params := oidc4ci.OidcAuthorizeParams{
+			ResponseType: query.Get("response_type"),
+			ClientId:     query.Get("client_id"),
+			Request:      lo.ToPtr(query.Get("request")),
+		}
+
+		if query.Has("state") {
+			params.State = lo.ToPtr(query.Get("state"))
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/oidc/authorize?"+query.Encode(), http.NoBody)
+
+		return controller.OidcAuthorize(echo.New().NewContext(req, httptest.NewRecorder()), params)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockOAuthProvider := NewMockOAuth2Provider(gomock.NewController(t))
+		mockStateStore := NewMockStateStore(gomock.NewController(t))
+		mockInteractionClient := NewMockIssuerInteractionClient(gomock.NewController(t))
+
+		mockOAuthProvider.EXPECT().NewAuthorizeRequest(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, req *http.Request) (fosite.AuthorizeRequester, error) {
+				assert.Equal(t, "openid UniversityDegreeCredential", req.Form.Get("scope"))
+				assert.Equal(t, "state", req.Form.Get("state"))
+				assert.Equal(t, clientID, req.Form.Get("client_id"))
+				assert.False(t, req.Form.Has("request"))
+
+				return &fosite.AuthorizeRequest{
+					Request: fosite.Request{RequestedScope: []string{"openid", "UniversityDegreeCredential"}},
+				}, nil
+			})
+
+		mockOAuthProvider.EXPECT().NewAuthorizeResponse(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, ar fosite.AuthorizeRequester, _ fosite.Session) (fosite.AuthorizeResponder, error) {
+				assert.Equal(t, "state", ar.(*fosite.AuthorizeRequest).State)
+
+				return &fosite.AuthorizeResponse{}, nil
+			})
+
+		b, err := json.Marshal(&issuer.PrepareClaimDataAuthorizationResponse{
+			AuthorizationRequest: issuer.OAuthParameters{},
+		})
+		require.NoError(t, err)
+
+		mockInteractionClient.EXPECT().PrepareAuthorizationRequest(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(
+				_ context.Context,
+				req issuer.PrepareAuthorizationRequestJSONRequestBody,
+				_ ...issuer.RequestEditorFn,
+			) (*http.Response, error) {
+				assert.Equal(t, "opState", req.OpState)
+				assert.Equal(t, []string{"VerifiableCredential", "UniversityDegreeCredential"}, req.AuthorizationDetails.Types)
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBuffer(b)),
+				}, nil
+			})
+
+		mockStateStore.EXPECT().SaveAuthorizeState(gomock.Any(), "opState", gomock.Any()).Return(nil)
+
+		err = authorize(t, mockOAuthProvider, mockInteractionClient, mockStateStore, url.Values{
+			"response_type": {"code"},
+			"client_id":     {clientID},
+			"state":         {"state"},
+			"request": {newRequestObject(t, privateKey, map[string]interface{}{
+				"iss":          clientID,
+				"aud":          aud,
+				"exp":          time.Now().Add(time.Minute).Unix(),
+				"client_id":    clientID,
+				"scope":        "openid UniversityDegreeCredential",
+				"state":        "state",
+				"issuer_state": "opState",
+				"authorization_details": map[string]interface{}{
+					"type":   "openid_credential",
+					"types":  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+					"format": "jwt_vc_json",
+				},
+			})},
+		})
+		require.NoError(t, err)
+	})
+
+	for _, tt := range []struct {
+		name   string
+		key    ed25519.PrivateKey
+		claims map[string]interface{}
+		errMsg string
+	}{
+		{
+			name:   "Invalid signature",
+			key:    otherPrivateKey,
+			claims: map[string]interface{}{"iss": clientID, "aud": aud},
+			errMsg: "parse request object",
+		},
+		{
+			name:   "Issuer is not client",
+			key:    privateKey,
+			claims: map[string]interface{}{"iss": "other-client", "aud": aud},
+			errMsg: "invalid issuer",
+		},
+		{
+			name:   "Invalid audience",
+			key:    privateKey,
+			claims: map[string]interface{}{"iss": clientID, "aud": "https://other.example.com"},
+			errMsg: "invalid audience",
+		},
+		{
+			name:   "Expired",
+			key:    privateKey,
+			claims: map[string]interface{}{"iss": clientID, "aud": aud, "exp": time.Now().Add(-time.Hour).Unix()},
+			errMsg: "token is expired",
+		},
+		{
+			name:   "Conflicting parameter",
+			key:    privateKey,
+			claims: map[string]interface{}{"iss": clientID, "aud": aud, "state": "other-state"},
+			errMsg: "conflicts with the value in the request object",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorize(t, nil, nil, nil, url.Values{
+				"response_type": {"code"},
+				"client_id":     {clientID},
+				"state":         {"state"},
+				"request":       {newRequestObject(t, tt.key, tt.claims)},
+			})
+
+			var customErr *resterr.CustomError
+
+			require.ErrorAs(t, err, &customErr)
+			require.Equal(t, resterr.InvalidValue, customErr.Code)
+			require.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
 func TestController_OidcRedirect(t *testing.T) {
 	var (
 		mockOAuthProvider     = NewMockOAuth2Provider(gomock.NewController(t))
 		mockStateStore        = NewMockStateStore(gomock.NewController(t))
 		mockInteractionClient = NewMockIssuerInteractionClient(gomock.NewController(t))
 		params                oidc4ci.OidcRedirectParams
+		sessionBinding        = "session-binding"
 	)
 
 	tests := []struct {
@@ -729,7 +1066,8 @@ func TestController_OidcRedirect(t *testing.T) {
 				redirectURI := &url.URL{Scheme: "https", Host: "example.com", Path: "redirect"}
 
 				mockStateStore.EXPECT().GetAuthorizeState(gomock.Any(), params.State).Return(&oidc4cisrv.AuthorizeState{
-					RedirectURI: redirectURI,
+					RedirectURI:    redirectURI,
+					SessionBinding: sessionBinding,
 				}, nil)
 				mockInteractionClient.EXPECT().StoreAuthorizationCodeRequest(
 					gomock.Any(),
@@ -754,6 +1092,50 @@ func TestController_OidcRedirect(t *testing.T) {
 				require.Equal(t, http.StatusOK, rec.Code)
 			},
 		},
+		{
+			name: "session binding of other user agent",
+			setup: func() {
+				params = oidc4ci.OidcRedirectParams{
+					Code:  "code",
+					State: "state",
+				}
+
+				mockStateStore.EXPECT().GetAuthorizeState(gomock.Any(), params.State).Return(&oidc4cisrv.AuthorizeState{
+					RedirectURI:    &url.URL{Scheme: "https", Host: "example.com", Path: "redirect"},
+					SessionBinding: "other-session-binding",
+				}, nil)
+				mockInteractionClient.EXPECT().StoreAuthorizationCodeRequest(gomock.Any(), gomock.Any()).Times(0)
+				mockOAuthProvider.EXPECT().WriteAuthorizeResponse(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				var customErr *resterr.CustomError
+
+				require.ErrorAs(t, err, &customErr)
+				require.Equal(t, resterr.OIDCError, customErr.Code)
+				require.Equal(t, "invalid_request", customErr.Component)
+				require.ErrorContains(t, err, "authorization request was not started by this user agent")
+			},
+		},
+		{
+			name: "state without session binding",
+			setup: func() {
+				params = oidc4ci.OidcRedirectParams{
+					Code:  "code",
+					State: "state",
+				}
+
+				mockStateStore.EXPECT().GetAuthorizeState(gomock.Any(), params.State).Return(&oidc4cisrv.AuthorizeState{
+					RedirectURI: &url.URL{Scheme: "https", Host: "example.com", Path: "redirect"},
+				}, nil)
+				mockInteractionClient.EXPECT().StoreAuthorizationCodeRequest(gomock.Any(), gomock.Any()).Times(0)
+				mockOAuthProvider.EXPECT().WriteAuthorizeResponse(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				require.ErrorContains(t, err, "authorization request was not started by this user agent")
+			},
+		},
 		{
 			name: "fail to store code",
 			setup: func() {
@@ -765,7 +1147,8 @@ func TestController_OidcRedirect(t *testing.T) {
 				redirectURI := &url.URL{Scheme: "https", Host: "example.com", Path: "redirect"}
 
 				mockStateStore.EXPECT().GetAuthorizeState(gomock.Any(), params.State).Return(&oidc4cisrv.AuthorizeState{
-					RedirectURI: redirectURI,
+					RedirectURI:    redirectURI,
+					SessionBinding: sessionBinding,
 				}, nil)
 				mockInteractionClient.EXPECT().StoreAuthorizationCodeRequest(
 					gomock.Any(),
@@ -792,6 +1175,79 @@ func TestController_OidcRedirect(t *testing.T) {
 				require.ErrorContains(t, err, "get state error")
 			},
 		},
+		{
+			name: "invalid state",
+			setup: func() {
+				params = oidc4ci.OidcRedirectParams{
+					Code:  "code",
+					State: "invalid-state",
+				}
+
+				mockStateStore.EXPECT().GetAuthorizeState(gomock.Any(), params.State).
+					Return(nil, oidc4cisrv.ErrDataNotFound)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				var customErr *resterr.CustomError
+				require.ErrorAs(t, err, &customErr)
+				require.Equal(t, resterr.InvalidValue, customErr.Code)
+				require.Equal(t, "state", customErr.IncorrectValue)
+			},
+		},
+		{
+			name: "expired code",
+			setup: func() {
+				params = oidc4ci.OidcRedirectParams{
+					Code:  "expired-code",
+					State: "state",
+				}
+
+				mockStateStore.EXPECT().GetAuthorizeState(gomock.Any(), params.State).Return(&oidc4cisrv.AuthorizeState{
+					RedirectURI:    &url.URL{Scheme: "https", Host: "example.com", Path: "redirect"},
+					SessionBinding: sessionBinding,
+				}, nil)
+				mockInteractionClient.EXPECT().StoreAuthorizationCodeRequest(
+					gomock.Any(),
+					issuer.StoreAuthorizationCodeRequest{
+						Code:    params.Code,
+						OpState: params.State,
+					}).Return(&http.Response{
+					StatusCode: http.StatusBadRequest,
+					Body: io.NopCloser(bytes.NewBufferString(
+						`{"code":"invalid-value","incorrectValue":"code","message":"authorization code expired"}`)),
+				}, nil)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				require.ErrorContains(t, err, "status code 400")
+				require.ErrorContains(t, err, "authorization code expired")
+			},
+		},
+		{
+			name: "store authorization code unexpected status",
+			setup: func() {
+				params = oidc4ci.OidcRedirectParams{
+					Code:  "code",
+					State: "state",
+				}
+
+				mockStateStore.EXPECT().GetAuthorizeState(gomock.Any(), params.State).Return(&oidc4cisrv.AuthorizeState{
+					RedirectURI:    &url.URL{Scheme: "https", Host: "example.com", Path: "redirect"},
+					SessionBinding: sessionBinding,
+				}, nil)
+				mockInteractionClient.EXPECT().StoreAuthorizationCodeRequest(
+					gomock.Any(),
+					issuer.StoreAuthorizationCodeRequest{
+						Code:    params.Code,
+						OpState: params.State,
+					}).Return(&http.Response{
+					StatusCode: http.StatusInternalServerError,
+					Body:       io.NopCloser(bytes.NewBufferString("idp unavailable")),
+				}, nil)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				require.ErrorContains(t, err, "store authorization code request: status code 500")
+				require.ErrorContains(t, err, "idp unavailable")
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -806,6 +1262,7 @@ func TestController_OidcRedirect(t *testing.T) {
 
 			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+			req.AddCookie(&http.Cookie{Name: "vcs_oidc_authorize_session", Value: sessionBinding})
 
 			rec := httptest.NewRecorder()
 
@@ -868,7 +1325,8 @@ func TestController_OidcToken(t *testing.T) {
 					nil, errors.New("new access request error"))
 			},
 			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
-				require.ErrorContains(t, err, "new access request error")
+				require.NoError(t, err)
+				requireTokenError(t, rec, http.StatusInternalServerError, "server_error", "new access request error")
 			},
 		},
 		{
@@ -896,7 +1354,8 @@ func TestController_OidcToken(t *testing.T) {
 					nil, errors.New("new access response error"))
 			},
 			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
-				require.ErrorContains(t, err, "new access response error")
+				require.NoError(t, err)
+				requireTokenError(t, rec, http.StatusInternalServerError, "server_error", "new access response error")
 			},
 		},
 		{
@@ -917,7 +1376,8 @@ func TestController_OidcToken(t *testing.T) {
 					Return(nil, errors.New("can not exchange token"))
 			},
 			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
-				require.ErrorContains(t, err, "can not exchange token")
+				require.NoError(t, err)
+				requireTokenError(t, rec, http.StatusInternalServerError, "server_error", "can not exchange token")
 			},
 		},
 	}
@@ -942,18 +1402,83 @@ func TestController_OidcToken(t *testing.T) {
 	}
 }
 
-func TestController_OidcCredential(t *testing.T) {
-	var (
-		mockOAuthProvider     = NewMockOAuth2Provider(gomock.NewController(t))
-		mockInteractionClient = NewMockIssuerInteractionClient(gomock.NewController(t))
-		accessToken           string
-		requestBody           []byte
-	)
+func TestController_OidcTokenRefresh(t *testing.T) {
+	mockOAuthProvider := NewMockOAuth2Provider(gomock.NewController(t))
+	mockInteractionClient := NewMockIssuerInteractionClient(gomock.NewController(t))
 
-	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
-	require.NoError(t, err)
+	accessRq := &fosite.AccessRequest{
+		GrantTypes: fosite.Arguments{"refresh_token"},
+		Request: fosite.Request{
+			Session: &fosite.DefaultSession{
+				Extra: map[string]interface{}{
+					"txID":    "txID",
+					"preAuth": true,
+				},
+			},
+		},
+	}
 
-	jwtVerifier, err := jwt.NewEd25519Verifier(publicKey)
+	mockOAuthProvider.EXPECT().NewAccessRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(accessRq, nil)
+	mockInteractionClient.EXPECT().ExchangeAuthorizationCodeRequest(gomock.Any(), gomock.Any()).Times(0)
+	mockInteractionClient.EXPECT().ValidatePreAuthorizedCodeRequest(gomock.Any(), gomock.Any()).Times(0)
+
+	mockOAuthProvider.EXPECT().NewAccessResponse(gomock.Any(), accessRq).DoAndReturn(
+		func(ctx context.Context, requester fosite.AccessRequester) (fosite.AccessResponder, error) {
+			extra := requester.GetSession().(*fosite.DefaultSession).Extra
+
+			assert.Equal(t, "txID", extra["txID"])
+			assert.Equal(t, true, extra["preAuth"])
+			assert.NotEmpty(t, extra["cNonce"])
+
+			return fosite.NewAccessResponse(), nil
+		})
+
+	mockOAuthProvider.EXPECT().WriteAccessResponse(gomock.Any(), gomock.Any(), accessRq, gomock.Any())
+
+	controller := oidc4ci.NewController(&oidc4ci.Config{
+		OAuth2Provider:          mockOAuthProvider,
+		IssuerInteractionClient: mockInteractionClient,
+		Tracer:                  trace.NewNoopTracerProvider().Tracer(""),
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {"refresh-token"},
+	}.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, controller.OidcToken(echo.New().NewContext(req, rec)))
+}
+
+func requireTokenError(t *testing.T, rec *httptest.ResponseRecorder, status int, code, description string) {
+	t.Helper()
+
+	require.Equal(t, status, rec.Code)
+	require.True(t, strings.HasPrefix(rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON))
+	require.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+	var resp oidc4ci.OIDCTokenErrorResponse
+
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	require.Equal(t, code, resp.Error)
+	require.Contains(t, resp.ErrorDescription, description)
+}
+
+func TestController_OidcCredential(t *testing.T) {
+	var (
+		mockOAuthProvider     = NewMockOAuth2Provider(gomock.NewController(t))
+		mockInteractionClient = NewMockIssuerInteractionClient(gomock.NewController(t))
+		mockProofVerifier     = NewMockProofVerifier(gomock.NewController(t))
+		accessToken           string
+		requestBody           []byte
+	)
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	jwtVerifier, err := jwt.NewEd25519Verifier(publicKey)
 	require.NoError(t, err)
 
 	jwtSigner := NewJWSSigner("", "EdDSA", jwt.NewEd25519Signer(privateKey))
@@ -978,7 +1503,7 @@ func TestController_OidcCredential(t *testing.T) {
 	credentialReq := oidc4ci.CredentialRequest{
 		Format: lo.ToPtr(string(common.JwtVcJsonLd)),
 		Proof:  &oidc4ci.JWTProof{ProofType: "jwt", Jwt: jws},
-		Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+		Types:  lo.ToPtr([]string{"VerifiableCredential", "UniversityDegreeCredential"}),
 	}
 
 	tests := []struct {
@@ -1068,6 +1593,186 @@ func TestController_OidcCredential(t *testing.T) {
 				require.Equal(t, http.StatusOK, rec.Code)
 			},
 		},
+		{
+			name: "success preAuth ldp_vc",
+			setup: func() {
+				mockOAuthProvider.EXPECT().IntrospectToken(gomock.Any(), gomock.Any(), fosite.AccessToken, gomock.Any()).
+					Return(
+						fosite.AccessToken,
+						fosite.NewAccessRequest(
+							&fosite.DefaultSession{
+								Extra: map[string]interface{}{
+									"txID":            "tx_id",
+									"cNonce":          "c_nonce",
+									"preAuth":         true,
+									"cNonceExpiresAt": time.Now().Add(time.Minute).Unix(),
+								},
+							},
+						), nil)
+
+				b, marshalErr := json.Marshal(issuer.PrepareCredentialResult{
+					Credential: map[string]interface{}{
+						"@context": []string{"https://www.w3.org/2018/credentials/v1"},
+						"type":     []string{"VerifiableCredential", "UniversityDegreeCredential"},
+						"proof": map[string]interface{}{
+							"type":               "Ed25519Signature2018",
+							"verificationMethod": "did:example:issuer#key-1",
+						},
+					},
+					Format:     string(verifiable.Ldp),
+					OidcFormat: string(verifiable.LdpVC),
+				})
+				require.NoError(t, marshalErr)
+
+				mockInteractionClient.EXPECT().PrepareCredential(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(
+						ctx context.Context,
+						req issuer.PrepareCredentialJSONRequestBody,
+						reqEditors ...issuer.RequestEditorFn,
+					) (*http.Response, error) {
+						assert.Equal(t, string(common.LdpVc), lo.FromPtr(req.Format))
+
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(bytes.NewBuffer(b)),
+						}, nil
+					})
+
+				accessToken = "access-token"
+
+				ldpCredentialReq := credentialReq
+				ldpCredentialReq.Format = lo.ToPtr(string(common.LdpVc))
+
+				requestBody, err = json.Marshal(ldpCredentialReq)
+				require.NoError(t, err)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var resp oidc4ci.CredentialResponse
+
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				require.Equal(t, string(verifiable.LdpVC), resp.Format)
+
+				cred, ok := resp.Credential.(map[string]interface{})
+				require.True(t, ok)
+				require.Contains(t, cred, "proof")
+			},
+		},
+		{
+			name: "success auth with client keys",
+			setup: func() {
+				ar := fosite.NewAccessRequest(
+					&fosite.DefaultSession{
+						Extra: map[string]interface{}{
+							"txID":            "tx_id",
+							"cNonce":          "c_nonce",
+							"preAuth":         false,
+							"cNonceExpiresAt": time.Now().Add(time.Minute).Unix(),
+						},
+					},
+				)
+				ar.Client = &oauth2client.Client{ID: clientID, JSONWebKeys: &gojose.JSONWebKeySet{}}
+
+				mockOAuthProvider.EXPECT().IntrospectToken(gomock.Any(), gomock.Any(), fosite.AccessToken, gomock.Any()).
+					Return(fosite.AccessToken, ar, nil)
+
+				mockProofVerifier.EXPECT().VerifyProofOfPossession(gomock.Any(),
+					&oidc4cisrv.JWTProof{ProofType: "jwt", JWT: jws}, clientID, oidc4cisrv.TxID("tx_id"), "c_nonce").
+					Return(&gojose.JSONWebKey{KeyID: "did:example:client#key-1"}, nil)
+
+				b, marshalErr := json.Marshal(issuer.PrepareCredentialResult{
+					Credential: "credential in jwt format",
+					Format:     string(verifiable.Jwt),
+				})
+				require.NoError(t, marshalErr)
+
+				mockInteractionClient.EXPECT().PrepareCredential(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(
+						_ context.Context,
+						req issuer.PrepareCredentialJSONRequestBody,
+						_ ...issuer.RequestEditorFn,
+					) (*http.Response, error) {
+						require.Equal(t, "did:example:client", lo.FromPtr(req.Did))
+
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(bytes.NewBuffer(b)),
+						}, nil
+					})
+
+				accessToken = "access-token"
+
+				requestBody, err = json.Marshal(credentialReq)
+				require.NoError(t, err)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, rec.Code)
+			},
+		},
+		{
+			name: "proof of possession verification failed",
+			setup: func() {
+				ar := fosite.NewAccessRequest(
+					&fosite.DefaultSession{
+						Extra: map[string]interface{}{
+							"txID":            "tx_id",
+							"cNonce":          "c_nonce",
+							"preAuth":         false,
+							"cNonceExpiresAt": time.Now().Add(time.Minute).Unix(),
+						},
+					},
+				)
+				ar.Client = &oauth2client.Client{ID: clientID, JSONWebKeys: &gojose.JSONWebKeySet{}}
+
+				mockOAuthProvider.EXPECT().IntrospectToken(gomock.Any(), gomock.Any(), fosite.AccessToken, gomock.Any()).
+					Return(fosite.AccessToken, ar, nil)
+
+				mockProofVerifier.EXPECT().VerifyProofOfPossession(gomock.Any(), gomock.Any(), clientID,
+					oidc4cisrv.TxID("tx_id"), "c_nonce").Return(nil, errors.New("proof key not found"))
+
+				accessToken = "access-token"
+
+				requestBody, err = json.Marshal(credentialReq)
+				require.NoError(t, err)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				require.ErrorContains(t, err, "verify proof of possession: proof key not found")
+			},
+		},
+		{
+			name: "missing c_nonce in session",
+			setup: func() {
+				ar := fosite.NewAccessRequest(
+					&fosite.DefaultSession{
+						Extra: map[string]interface{}{
+							"txID":    "tx_id",
+							"preAuth": false,
+						},
+					},
+				)
+				ar.Client = &oauth2client.Client{ID: clientID, JSONWebKeys: &gojose.JSONWebKeySet{}}
+
+				mockOAuthProvider.EXPECT().IntrospectToken(gomock.Any(), gomock.Any(), fosite.AccessToken, gomock.Any()).
+					Return(fosite.AccessToken, ar, nil)
+
+				mockProofVerifier.EXPECT().VerifyProofOfPossession(gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any(), gomock.Any()).Times(0)
+
+				accessToken = "access-token"
+
+				requestBody, err = json.Marshal(credentialReq)
+				require.NoError(t, err)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				var customErr *resterr.CustomError
+				require.ErrorAs(t, err, &customErr)
+				require.Equal(t, string(resterr.InvalidOrMissingProofOIDCErr), customErr.Component)
+				require.ErrorContains(t, customErr.Err, "missing c_nonce")
+			},
+		},
 		{
 			name: "invalid credential format",
 			setup: func() {
@@ -1079,7 +1784,7 @@ func TestController_OidcCredential(t *testing.T) {
 				requestBody, err = json.Marshal(oidc4ci.CredentialRequest{
 					Format: lo.ToPtr("invalid"),
 					Proof:  &oidc4ci.JWTProof{ProofType: "jwt", Jwt: jws},
-					Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+					Types:  lo.ToPtr([]string{"VerifiableCredential", "UniversityDegreeCredential"}),
 				})
 				require.NoError(t, err)
 			},
@@ -1098,7 +1803,7 @@ func TestController_OidcCredential(t *testing.T) {
 				requestBody, err = json.Marshal(oidc4ci.CredentialRequest{
 					Format: lo.ToPtr(string(common.JwtVcJsonLd)),
 					Proof:  nil,
-					Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+					Types:  lo.ToPtr([]string{"VerifiableCredential", "UniversityDegreeCredential"}),
 				})
 				require.NoError(t, err)
 			},
@@ -1117,7 +1822,7 @@ func TestController_OidcCredential(t *testing.T) {
 				requestBody, err = json.Marshal(oidc4ci.CredentialRequest{
 					Format: lo.ToPtr(string(common.JwtVcJsonLd)),
 					Proof:  &oidc4ci.JWTProof{ProofType: "jwt", Jwt: ""},
-					Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+					Types:  lo.ToPtr([]string{"VerifiableCredential", "UniversityDegreeCredential"}),
 				})
 				require.NoError(t, err)
 			},
@@ -1180,7 +1885,7 @@ func TestController_OidcCredential(t *testing.T) {
 				requestBody, err = json.Marshal(oidc4ci.CredentialRequest{
 					Format: lo.ToPtr(string(common.JwtVcJsonLd)),
 					Proof:  &oidc4ci.JWTProof{ProofType: "jwt", Jwt: "invalid jws"},
-					Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+					Types:  lo.ToPtr([]string{"VerifiableCredential", "UniversityDegreeCredential"}),
 				})
 				require.NoError(t, err)
 			},
@@ -1250,7 +1955,7 @@ func TestController_OidcCredential(t *testing.T) {
 				credentialReqInvalid := oidc4ci.CredentialRequest{
 					Format: lo.ToPtr(string(common.JwtVcJsonLd)),
 					Proof:  &oidc4ci.JWTProof{ProofType: "jwt", Jwt: jwsInvalid},
-					Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+					Types:  lo.ToPtr([]string{"VerifiableCredential", "UniversityDegreeCredential"}),
 				}
 
 				requestBody, err = json.Marshal(credentialReqInvalid)
@@ -1388,7 +2093,7 @@ func TestController_OidcCredential(t *testing.T) {
 				credentialReqInvalid := oidc4ci.CredentialRequest{
 					Format: lo.ToPtr(string(common.JwtVcJsonLd)),
 					Proof:  &oidc4ci.JWTProof{ProofType: "jwt", Jwt: jwsInvalid},
-					Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+					Types:  lo.ToPtr([]string{"VerifiableCredential", "UniversityDegreeCredential"}),
 				}
 
 				requestBody, err = json.Marshal(credentialReqInvalid)
@@ -1398,6 +2103,80 @@ func TestController_OidcCredential(t *testing.T) {
 				require.ErrorContains(t, err, "missing iat")
 			},
 		},
+		{
+			name: "success with supported signing algorithm",
+			setup: func() {
+				mockOAuthProvider.EXPECT().IntrospectToken(gomock.Any(), gomock.Any(), fosite.AccessToken, gomock.Any()).
+					Return(
+						fosite.AccessToken,
+						fosite.NewAccessRequest(
+							&fosite.DefaultSession{
+								Extra: map[string]interface{}{
+									"txID":            "tx_id",
+									"cNonce":          "c_nonce",
+									"preAuth":         true,
+									"cNonceExpiresAt": time.Now().Add(time.Minute).Unix(),
+									"signingAlgs":     "ES256 EdDSA",
+								},
+							},
+						), nil)
+
+				b, marshalErr := json.Marshal(issuer.PrepareCredentialResult{
+					Credential: "credential in jwt format",
+					Format:     string(verifiable.Jwt),
+				})
+				require.NoError(t, marshalErr)
+
+				mockInteractionClient.EXPECT().PrepareCredential(gomock.Any(), gomock.Any()).
+					Return(
+						&http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(bytes.NewBuffer(b)),
+						}, nil)
+
+				accessToken = "access-token"
+
+				requestBody, err = json.Marshal(credentialReq)
+				require.NoError(t, err)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, rec.Code)
+			},
+		},
+		{
+			name: "unsupported proof alg",
+			setup: func() {
+				mockOAuthProvider.EXPECT().IntrospectToken(gomock.Any(), gomock.Any(), fosite.AccessToken, gomock.Any()).
+					Return(
+						fosite.AccessToken,
+						fosite.NewAccessRequest(
+							&fosite.DefaultSession{
+								Extra: map[string]interface{}{
+									"txID":            "tx_id",
+									"cNonce":          "c_nonce",
+									"preAuth":         true,
+									"cNonceExpiresAt": time.Now().Add(time.Minute).Unix(),
+									"signingAlgs":     "ES256",
+								},
+							},
+						), nil)
+
+				mockInteractionClient.EXPECT().PrepareCredential(gomock.Any(), gomock.Any()).Times(0)
+
+				accessToken = "access-token"
+
+				requestBody, err = json.Marshal(credentialReq)
+				require.NoError(t, err)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				require.ErrorContains(t, err, `unsupported proof alg "EdDSA"`)
+
+				var customErr *resterr.CustomError
+				require.ErrorAs(t, err, &customErr)
+				require.Equal(t, string(resterr.InvalidOrMissingProofOIDCErr), customErr.Component)
+			},
+		},
 		{
 			name: "invalid nonce",
 			setup: func() {
@@ -1433,7 +2212,7 @@ func TestController_OidcCredential(t *testing.T) {
 				requestBody, err = json.Marshal(oidc4ci.CredentialRequest{
 					Format: lo.ToPtr(string(common.JwtVcJsonLd)),
 					Proof:  &oidc4ci.JWTProof{ProofType: "jwt", Jwt: invalidJWS},
-					Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+					Types:  lo.ToPtr([]string{"VerifiableCredential", "UniversityDegreeCredential"}),
 				})
 				require.NoError(t, err)
 			},
@@ -1476,7 +2255,7 @@ func TestController_OidcCredential(t *testing.T) {
 				requestBody, err = json.Marshal(oidc4ci.CredentialRequest{
 					Format: lo.ToPtr(string(common.JwtVcJsonLd)),
 					Proof:  &oidc4ci.JWTProof{ProofType: "jwt", Jwt: invalidJWS},
-					Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+					Types:  lo.ToPtr([]string{"VerifiableCredential", "UniversityDegreeCredential"}),
 				})
 				require.NoError(t, err)
 
@@ -1533,7 +2312,7 @@ func TestController_OidcCredential(t *testing.T) {
 				requestBody, err = json.Marshal(oidc4ci.CredentialRequest{
 					Format: lo.ToPtr(string(common.JwtVcJsonLd)),
 					Proof:  &oidc4ci.JWTProof{ProofType: "jwt", Jwt: invalidJWS},
-					Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+					Types:  lo.ToPtr([]string{"VerifiableCredential", "UniversityDegreeCredential"}),
 				})
 				require.NoError(t, err)
 			},
@@ -1578,7 +2357,7 @@ func TestController_OidcCredential(t *testing.T) {
 				requestBody, err = json.Marshal(oidc4ci.CredentialRequest{
 					Format: lo.ToPtr(string(common.JwtVcJsonLd)),
 					Proof:  &oidc4ci.JWTProof{ProofType: "jwt", Jwt: invalidJWS},
-					Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+					Types:  lo.ToPtr([]string{"VerifiableCredential", "UniversityDegreeCredential"}),
 				})
 				require.NoError(t, err)
 			},
@@ -1651,7 +2430,7 @@ func TestController_OidcCredential(t *testing.T) {
 			},
 		},
 		{
-			name: "invalid status code in prepare credential response (invalid json)",
+			name: "invalid status code in prepare credential response (credential identifier)",
 			setup: func() {
 				mockOAuthProvider.EXPECT().IntrospectToken(gomock.Any(), gomock.Any(), fosite.AccessToken, gomock.Any()).
 					Return(
@@ -1670,8 +2449,8 @@ func TestController_OidcCredential(t *testing.T) {
 				mockInteractionClient.EXPECT().PrepareCredential(gomock.Any(), gomock.Any()).
 					Return(
 						&http.Response{
-							StatusCode: http.StatusInternalServerError,
-							Body:       io.NopCloser(strings.NewReader(`{`)),
+							StatusCode: http.StatusBadRequest,
+							Body:       io.NopCloser(strings.NewReader(`{"code" : "oidc-invalid-credential-request"}`)),
 						}, nil)
 
 				accessToken = "access-token"
@@ -1680,11 +2459,16 @@ func TestController_OidcCredential(t *testing.T) {
 				require.NoError(t, err)
 			},
 			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
-				require.ErrorContains(t, err, "prepare credential: status code 500, {")
+				var customErr *resterr.CustomError
+
+				require.ErrorAs(t, err, &customErr)
+				require.Equal(t, resterr.OIDCError, customErr.Code)
+				require.Equal(t, "invalid_credential_request", customErr.Component)
+				require.ErrorContains(t, err, "code: oidc-invalid-credential-request")
 			},
 		},
 		{
-			name: "invalid status code in prepare credential response (type)",
+			name: "invalid status code in prepare credential response (invalid json)",
 			setup: func() {
 				mockOAuthProvider.EXPECT().IntrospectToken(gomock.Any(), gomock.Any(), fosite.AccessToken, gomock.Any()).
 					Return(
@@ -1704,7 +2488,7 @@ func TestController_OidcCredential(t *testing.T) {
 					Return(
 						&http.Response{
 							StatusCode: http.StatusInternalServerError,
-							Body:       io.NopCloser(strings.NewReader(`{"code" : "oidc-credential-type-not-supported"}`)),
+							Body:       io.NopCloser(strings.NewReader(`{`)),
 						}, nil)
 
 				accessToken = "access-token"
@@ -1713,13 +2497,11 @@ func TestController_OidcCredential(t *testing.T) {
 				require.NoError(t, err)
 			},
 			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
-				require.ErrorContains(t, err,
-					"oidc-error[]: prepare credential: status code 500, "+
-						"code: oidc-credential-type-not-supported")
+				require.ErrorContains(t, err, "prepare credential: status code 500, {")
 			},
 		},
 		{
-			name: "invalid status code in prepare credential response (random)",
+			name: "invalid status code in prepare credential response (type)",
 			setup: func() {
 				mockOAuthProvider.EXPECT().IntrospectToken(gomock.Any(), gomock.Any(), fosite.AccessToken, gomock.Any()).
 					Return(
@@ -1739,7 +2521,7 @@ func TestController_OidcCredential(t *testing.T) {
 					Return(
 						&http.Response{
 							StatusCode: http.StatusInternalServerError,
-							Body:       io.NopCloser(strings.NewReader(`{"code" : "random", "message": "awesome"}`)),
+							Body:       io.NopCloser(strings.NewReader(`{"code" : "oidc-credential-type-not-supported"}`)),
 						}, nil)
 
 				accessToken = "access-token"
@@ -1749,11 +2531,46 @@ func TestController_OidcCredential(t *testing.T) {
 			},
 			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
 				require.ErrorContains(t, err,
-					"prepare credential: status code 500, code: random; message: awesome")
+					"oidc-error[]: prepare credential: status code 500, "+
+						"code: oidc-credential-type-not-supported")
 			},
 		},
 		{
-			name: "fail to decode prepare credential result",
+			name: "invalid status code in prepare credential response (random)",
+			setup: func() {
+				mockOAuthProvider.EXPECT().IntrospectToken(gomock.Any(), gomock.Any(), fosite.AccessToken, gomock.Any()).
+					Return(
+						fosite.AccessToken,
+						fosite.NewAccessRequest(
+							&fosite.DefaultSession{
+								Extra: map[string]interface{}{
+									"txID":            "tx_id",
+									"cNonce":          "c_nonce",
+									"preAuth":         true,
+									"cNonceExpiresAt": time.Now().Add(time.Minute).Unix(),
+								},
+							},
+						), nil)
+
+				mockInteractionClient.EXPECT().PrepareCredential(gomock.Any(), gomock.Any()).
+					Return(
+						&http.Response{
+							StatusCode: http.StatusInternalServerError,
+							Body:       io.NopCloser(strings.NewReader(`{"code" : "random", "message": "awesome"}`)),
+						}, nil)
+
+				accessToken = "access-token"
+
+				requestBody, err = json.Marshal(credentialReq)
+				require.NoError(t, err)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				require.ErrorContains(t, err,
+					"prepare credential: status code 500, code: random; message: awesome")
+			},
+		},
+		{
+			name: "fail to decode prepare credential result",
 			setup: func() {
 				mockOAuthProvider.EXPECT().IntrospectToken(gomock.Any(), gomock.Any(), fosite.AccessToken, gomock.Any()).
 					Return(
@@ -1794,6 +2611,7 @@ func TestController_OidcCredential(t *testing.T) {
 				OAuth2Provider:          mockOAuthProvider,
 				IssuerInteractionClient: mockInteractionClient,
 				JWTVerifier:             jwtVerifier,
+				ProofVerifier:           mockProofVerifier,
 				Tracer:                  trace.NewNoopTracerProvider().Tracer(""),
 				IssuerVCSPublicHost:     aud,
 			})
@@ -1813,6 +2631,156 @@ func TestController_OidcCredential(t *testing.T) {
 	}
 }
 
+func TestController_OidcCredentialIdempotencyKey(t *testing.T) {
+	mockOAuthProvider := NewMockOAuth2Provider(gomock.NewController(t))
+	mockInteractionClient := NewMockIssuerInteractionClient(gomock.NewController(t))
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	jwtVerifier, err := jwt.NewEd25519Verifier(publicKey)
+	require.NoError(t, err)
+
+	currentTime := time.Now().Unix()
+
+	signedJWT, err := jwt.NewSigned(&oidc4ci.JWTProofClaims{
+		Issuer:   clientID,
+		IssuedAt: &currentTime,
+		Nonce:    "c_nonce",
+		Audience: aud,
+	}, map[string]interface{}{
+		jose.HeaderType: "openid4vci-proof+jwt",
+	}, NewJWSSigner("", "EdDSA", jwt.NewEd25519Signer(privateKey)))
+	require.NoError(t, err)
+
+	jws, err := signedJWT.Serialize(false)
+	require.NoError(t, err)
+
+	newRequestBody := func(credentialType string) []byte {
+		b, marshalErr := json.Marshal(oidc4ci.CredentialRequest{
+			Format: lo.ToPtr(string(common.JwtVcJsonLd)),
+			Proof:  &oidc4ci.JWTProof{ProofType: "jwt", Jwt: jws},
+			Types:  lo.ToPtr([]string{"VerifiableCredential", credentialType}),
+		})
+		require.NoError(t, marshalErr)
+
+		return b
+	}
+
+	requestBody := newRequestBody("UniversityDegreeCredential")
+	txID := "tx_id"
+
+	mockOAuthProvider.EXPECT().IntrospectToken(gomock.Any(), gomock.Any(), fosite.AccessToken, gomock.Any()).
+		AnyTimes().DoAndReturn(func(context.Context, string, fosite.TokenType, fosite.Session,
+		...string) (fosite.TokenType, fosite.AccessRequester, error) {
+		return fosite.AccessToken, fosite.NewAccessRequest(
+			&fosite.DefaultSession{
+				Extra: map[string]interface{}{
+					"txID":            txID,
+					"cNonce":          "c_nonce",
+					"preAuth":         true,
+					"cNonceExpiresAt": time.Now().Add(time.Minute).Unix(),
+				},
+			},
+		), nil
+	})
+
+	prepareCredential := func(credential string) {
+		b, marshalErr := json.Marshal(issuer.PrepareCredentialResult{
+			Credential: credential,
+			Format:     string(verifiable.Jwt),
+		})
+		require.NoError(t, marshalErr)
+
+		mockInteractionClient.EXPECT().PrepareCredential(gomock.Any(), gomock.Any()).Times(1).
+			Return(&http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBuffer(b)),
+			}, nil)
+	}
+
+	controller := oidc4ci.NewController(&oidc4ci.Config{
+		OAuth2Provider:          mockOAuthProvider,
+		IssuerInteractionClient: mockInteractionClient,
+		JWTVerifier:             jwtVerifier,
+		Tracer:                  trace.NewNoopTracerProvider().Tracer(""),
+		IssuerVCSPublicHost:     aud,
+		IdempotencyKeyTTL:       time.Minute,
+	})
+
+	post := func(body []byte, idempotencyKey string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Authorization", "Bearer access-token")
+
+		if idempotencyKey != "" {
+			req.Header.Set("Idempotency-Key", idempotencyKey)
+		}
+
+		rec := httptest.NewRecorder()
+
+		return rec, controller.OidcCredential(echo.New().NewContext(req, rec))
+	}
+
+	postCredential := func(t *testing.T, idempotencyKey string) *oidc4ci.CredentialResponse {
+		t.Helper()
+
+		rec, postErr := post(requestBody, idempotencyKey)
+		require.NoError(t, postErr)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp oidc4ci.CredentialResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+
+		return &resp
+	}
+
+	t.Run("miss issues credential and hit returns cached response", func(t *testing.T) {
+		prepareCredential("credential-1")
+
+		first := postCredential(t, "key-1")
+		require.Equal(t, "credential-1", first.Credential)
+
+		// PrepareCredential is expected to be called once only.
+		retried := postCredential(t, "key-1")
+		require.Equal(t, first, retried)
+	})
+
+	t.Run("different key issues new credential", func(t *testing.T) {
+		prepareCredential("credential-2")
+
+		resp := postCredential(t, "key-2")
+		require.Equal(t, "credential-2", resp.Credential)
+	})
+
+	t.Run("same key in other transaction issues new credential", func(t *testing.T) {
+		txID = "other_tx_id"
+		defer func() { txID = "tx_id" }()
+
+		prepareCredential("credential-5")
+
+		resp := postCredential(t, "key-1")
+		require.Equal(t, "credential-5", resp.Credential)
+	})
+
+	t.Run("same key with different request is rejected", func(t *testing.T) {
+		_, postErr := post(newRequestBody("DriversLicenseCredential"), "key-1")
+
+		var customErr *resterr.CustomError
+		require.ErrorAs(t, postErr, &customErr)
+		require.Equal(t, "invalid_request", customErr.Component)
+		require.ErrorContains(t, customErr.Err, "idempotency key reused with a different credential request")
+	})
+
+	t.Run("no key issues new credential", func(t *testing.T) {
+		prepareCredential("credential-3")
+		require.Equal(t, "credential-3", postCredential(t, "").Credential)
+
+		prepareCredential("credential-4")
+		require.Equal(t, "credential-4", postCredential(t, "").Credential)
+	})
+}
+
 func TestController_OidcPreAuthorize(t *testing.T) {
 	var (
 		mockOAuthProvider     = NewMockOAuth2Provider(gomock.NewController(t))
@@ -1879,6 +2847,54 @@ func TestController_OidcPreAuthorize(t *testing.T) {
 				assert.NotEmpty(t, *resp.ExpiresIn)
 			},
 		},
+		{
+			name: "success with refresh token",
+			body: strings.NewReader(url.Values{
+				"grant_type":          {"urn:ietf:params:oauth:grant-type:pre-authorized_code"},
+				"pre-authorized_code": {"123456"},
+			}.Encode()),
+			setup: func() {
+				mockInteractionClient.EXPECT().ValidatePreAuthorizedCodeRequest(gomock.Any(), gomock.Any()).
+					Return(&http.Response{
+						StatusCode: http.StatusOK,
+						Body: io.NopCloser(strings.NewReader(
+							`{"scopes":["a"],"op_state":"opp123","tx_id":"txID","issue_refresh_token":true}`)),
+					}, nil)
+
+				accessRq := &fosite.AccessRequest{
+					Request: fosite.Request{
+						Session: &fosite.DefaultSession{},
+					},
+				}
+
+				mockOAuthProvider.EXPECT().NewAccessRequest(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(accessRq, nil)
+
+				mockOAuthProvider.EXPECT().NewAccessResponse(gomock.Any(), accessRq).DoAndReturn(
+					func(ctx context.Context, requester fosite.AccessRequester) (fosite.AccessResponder, error) {
+						assert.True(t, requester.GetGrantedScopes().Has("offline_access"))
+
+						return &fosite.AccessResponse{
+							AccessToken: "123456",
+							Extra: map[string]interface{}{
+								"refresh_token": "refresh-token",
+							},
+						}, nil
+					})
+
+				mockOAuthProvider.EXPECT().WriteAccessResponse(gomock.Any(), gomock.Any(), accessRq, gomock.Any()).
+					Do(func(ctx context.Context, rw http.ResponseWriter, requester fosite.AccessRequester, responder fosite.AccessResponder) {
+						_ = json.NewEncoder(rw).Encode(responder.ToMap())
+					})
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				assert.NoError(t, err)
+				var resp oidc4ci.AccessTokenResponse
+
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, "refresh-token", lo.FromPtr(resp.RefreshToken))
+			},
+		},
 		{
 			name: "name invalid pre-auth code",
 			body: strings.NewReader(url.Values{
@@ -1903,7 +2919,8 @@ func TestController_OidcPreAuthorize(t *testing.T) {
 					}, nil)
 			},
 			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
-				assert.ErrorContains(t, err, "invalid pin")
+				require.NoError(t, err)
+				requireTokenError(t, rec, http.StatusInternalServerError, "server_error", "invalid pin")
 			},
 		},
 		{
@@ -1931,7 +2948,8 @@ func TestController_OidcPreAuthorize(t *testing.T) {
 				}, nil)
 			},
 			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
-				assert.ErrorContains(t, err, "unexpected EOF")
+				require.NoError(t, err)
+				requireTokenError(t, rec, http.StatusInternalServerError, "server_error", "unexpected EOF")
 			},
 		},
 		{
@@ -1959,7 +2977,9 @@ func TestController_OidcPreAuthorize(t *testing.T) {
 				}, nil)
 			},
 			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
-				assert.ErrorContains(t, err, "validate pre-authorized code request: status code 400, code")
+				require.NoError(t, err)
+				requireTokenError(t, rec, http.StatusInternalServerError, "server_error",
+					"validate pre-authorized code request: status code 400, code")
 			},
 		},
 		{
@@ -1987,9 +3007,9 @@ func TestController_OidcPreAuthorize(t *testing.T) {
 				}, nil)
 			},
 			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
-				assert.ErrorContains(t, err,
-					"oidc-error[]: validate pre-authorized code request: status code 400, "+
-						"code: oidc-tx-not-found")
+				require.NoError(t, err)
+				requireTokenError(t, rec, http.StatusBadRequest, "invalid_grant",
+					"validate pre-authorized code request: status code 400, code: oidc-tx-not-found")
 			},
 		},
 		{
@@ -2017,9 +3037,9 @@ func TestController_OidcPreAuthorize(t *testing.T) {
 				}, nil)
 			},
 			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
-				assert.ErrorContains(t, err,
-					"oidc-error[]: validate pre-authorized code request: status code 400, "+
-						"code: oidc-pre-authorize-expect-pin")
+				require.NoError(t, err)
+				requireTokenError(t, rec, http.StatusBadRequest, "invalid_request",
+					"validate pre-authorized code request: status code 400, code: oidc-pre-authorize-expect-pin")
 			},
 		},
 		{
@@ -2046,9 +3066,9 @@ func TestController_OidcPreAuthorize(t *testing.T) {
 				}, nil)
 			},
 			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
-				assert.ErrorContains(t, err,
-					"oidc-error[]: validate pre-authorized code request: status code 400, "+
-						"code: oidc-pre-authorize-invalid-client-id")
+				require.NoError(t, err)
+				requireTokenError(t, rec, http.StatusBadRequest, "invalid_client",
+					"validate pre-authorized code request: status code 400, code: oidc-pre-authorize-invalid-client-id")
 			},
 		},
 	}
@@ -2083,6 +3103,9 @@ func TestController_OidcRegisterClient(t *testing.T) {
 		ClientUri:    lo.ToPtr("https://example.com"),
 		GrantTypes:   lo.ToPtr([]string{"authorization_code"}),
 		RedirectUris: lo.ToPtr([]string{"https://example.com/callback"}),
+		ExtensionFields: lo.ToPtr(map[string]interface{}{
+			"vcs:allowed_credential_types": []interface{}{"PermanentResidentCard"},
+		}),
 	})
 	require.NoError(t, err)
 
@@ -2161,6 +3184,38 @@ func TestController_OidcRegisterClient(t *testing.T) {
 				assert.Nil(t, resp.SoftwareId)
 				assert.Nil(t, resp.SoftwareVersion)
 				assert.Nil(t, resp.TosUri)
+				assert.Nil(t, resp.ExtensionFields)
+			},
+		},
+		{
+			name: "extension fields",
+			setup: func() {
+				mockProfileService.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Return(
+					&profileapi.Issuer{
+						OIDCConfig: &profileapi.OIDCConfig{EnableDynamicClientRegistration: true},
+					}, nil)
+
+				mockClientManager.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(
+						_ context.Context,
+						_, _ string,
+						data *clientmanager.ClientMetadata,
+					) (*oauth2client.Client, error) {
+						return &oauth2client.Client{
+							ID:       uuid.New().String(),
+							Metadata: data.Metadata,
+						}, nil
+					})
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				var resp oidc4ci.RegisterOAuthClientResponse
+
+				assert.NoError(t, err)
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, http.StatusCreated, rec.Code)
+				assert.Equal(t, map[string]interface{}{
+					"vcs:allowed_credential_types": []interface{}{"PermanentResidentCard"},
+				}, lo.FromPtr(resp.ExtensionFields))
 			},
 		},
 		{
@@ -2260,3 +3315,303 @@ func TestController_OidcRegisterClient(t *testing.T) {
 		})
 	}
 }
+
+func TestController_OidcListClients(t *testing.T) {
+	mockClientManager := NewMockClientManager(gomock.NewController(t))
+
+	var params oidc4ci.OidcListClientsParams
+
+	tests := []struct {
+		name  string
+		setup func()
+		check func(t *testing.T, rec *httptest.ResponseRecorder, err error)
+	}{
+		{
+			name: "success",
+			setup: func() {
+				params = oidc4ci.OidcListClientsParams{Page: lo.ToPtr(2), PageSize: lo.ToPtr(1)}
+
+				mockClientManager.EXPECT().List(gomock.Any(), profileID, profileVersion, 2, 1).Return(
+					[]*oauth2client.Client{
+						{
+							ID:                      "client-id",
+							Name:                    "client-name",
+							Secret:                  []byte("secret"),
+							GrantTypes:              []string{"authorization_code"},
+							RedirectURIs:            []string{"https://example.com/callback"},
+							TokenEndpointAuthMethod: "client_secret_basic",
+							CreatedAt:               time.Now(),
+						},
+					}, 2, nil)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.NotContains(t, rec.Body.String(), "client_secret\"")
+
+				var resp oidc4ci.ListOAuthClientsResponse
+
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				require.Equal(t, 2, resp.Total)
+				require.Len(t, resp.Clients, 1)
+				require.Equal(t, "client-id", resp.Clients[0].ClientId)
+				require.Equal(t, "client-name", lo.FromPtr(resp.Clients[0].ClientName))
+				require.Nil(t, resp.Clients[0].ClientSecret)
+				require.Nil(t, resp.Clients[0].ClientSecretExpiresAt)
+			},
+		},
+		{
+			name: "default pagination",
+			setup: func() {
+				params = oidc4ci.OidcListClientsParams{}
+
+				mockClientManager.EXPECT().List(gomock.Any(), profileID, profileVersion, 1, 20).Return(nil, 0, nil)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				require.NoError(t, err)
+				require.JSONEq(t, `{"clients":[],"total":0}`, rec.Body.String())
+			},
+		},
+		{
+			name: "invalid page",
+			setup: func() {
+				params = oidc4ci.OidcListClientsParams{Page: lo.ToPtr(0)}
+
+				mockClientManager.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				var customErr *resterr.CustomError
+
+				require.ErrorAs(t, err, &customErr)
+				require.Equal(t, resterr.InvalidValue, customErr.Code)
+				require.Equal(t, "page", customErr.IncorrectValue)
+			},
+		},
+		{
+			name: "page size too large",
+			setup: func() {
+				params = oidc4ci.OidcListClientsParams{PageSize: lo.ToPtr(101)}
+
+				mockClientManager.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				var customErr *resterr.CustomError
+
+				require.ErrorAs(t, err, &customErr)
+				require.Equal(t, resterr.InvalidValue, customErr.Code)
+				require.Equal(t, "pageSize", customErr.IncorrectValue)
+			},
+		},
+		{
+			name: "list clients error",
+			setup: func() {
+				params = oidc4ci.OidcListClientsParams{}
+
+				mockClientManager.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, 0, errors.New("list clients error"))
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				var customErr *resterr.CustomError
+
+				require.ErrorAs(t, err, &customErr)
+				require.ErrorContains(t, customErr, "list clients error")
+				require.Equal(t, resterr.SystemError, customErr.Code)
+				require.Equal(t, "ClientManager", customErr.Component)
+				require.Equal(t, "List", customErr.FailedOperation)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			controller := oidc4ci.NewController(&oidc4ci.Config{
+				ClientManager: mockClientManager,
+				Tracer:        trace.NewNoopTracerProvider().Tracer(""),
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+
+			err := controller.OidcListClients(echo.New().NewContext(req, rec), profileID, profileVersion, params)
+			tt.check(t, rec, err)
+		})
+	}
+}
+
+func TestController_OidcDiscovery(t *testing.T) {
+	mockDiscoveryService := NewMockDiscoveryService(gomock.NewController(t))
+
+	tests := []struct {
+		name  string
+		setup func()
+		check func(t *testing.T, rec *httptest.ResponseRecorder, err error)
+	}{
+		{
+			name: "success",
+			setup: func() {
+				mockDiscoveryService.EXPECT().GetDiscoveryDocument(gomock.Any(), profileID, profileVersion).Return(
+					&oidc4cisrv.OIDCDiscoveryDocument{
+						Issuer:                             "https://vcs.example.com/oidc/testID/v1.0",
+						AuthorizationEndpoint:              "https://vcs.example.com/oidc/authorize",
+						TokenEndpoint:                      "https://vcs.example.com/oidc/token",
+						PushedAuthorizationRequestEndpoint: "https://vcs.example.com/oidc/par",
+						ScopesSupported:                    []string{"openid"},
+						ResponseTypesSupported:             []string{"code"},
+						WalletInitiatedAuthFlowSupported:   true,
+					}, nil)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.JSONEq(t, `{
+					"issuer": "https://vcs.example.com/oidc/testID/v1.0",
+					"authorization_endpoint": "https://vcs.example.com/oidc/authorize",
+					"token_endpoint": "https://vcs.example.com/oidc/token",
+					"pushed_authorization_request_endpoint": "https://vcs.example.com/oidc/par",
+					"scopes_supported": ["openid"],
+					"response_types_supported": ["code"],
+					"pre-authorized_grant_anonymous_access_supported": false,
+					"wallet_initiated_auth_flow_supported": true
+				}`, rec.Body.String())
+			},
+		},
+		{
+			name: "oidc not configured",
+			setup: func() {
+				mockDiscoveryService.EXPECT().GetDiscoveryDocument(gomock.Any(), profileID, profileVersion).Return(
+					nil, fmt.Errorf("%w: profile %s", oidc4cisrv.ErrOIDCNotConfigured, profileID))
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				var customErr *resterr.CustomError
+
+				require.ErrorAs(t, err, &customErr)
+				require.Equal(t, resterr.DoesntExist, customErr.Code)
+				require.Equal(t, "profile", customErr.IncorrectValue)
+			},
+		},
+		{
+			name: "get discovery document error",
+			setup: func() {
+				mockDiscoveryService.EXPECT().GetDiscoveryDocument(gomock.Any(), profileID, profileVersion).Return(
+					nil, errors.New("get profile error"))
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				var customErr *resterr.CustomError
+
+				require.ErrorAs(t, err, &customErr)
+				require.ErrorContains(t, customErr, "get profile error")
+				require.Equal(t, resterr.SystemError, customErr.Code)
+				require.Equal(t, "DiscoveryService", customErr.Component)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			controller := oidc4ci.NewController(&oidc4ci.Config{
+				DiscoveryService: mockDiscoveryService,
+				Tracer:           trace.NewNoopTracerProvider().Tracer(""),
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+
+			err := controller.OidcDiscovery(echo.New().NewContext(req, rec), profileID, profileVersion)
+			tt.check(t, rec, err)
+		})
+	}
+}
+
+func TestController_OidcCredentialIssuerMetadata(t *testing.T) {
+	mockDiscoveryService := NewMockDiscoveryService(gomock.NewController(t))
+
+	tests := []struct {
+		name  string
+		setup func()
+		check func(t *testing.T, rec *httptest.ResponseRecorder, err error)
+	}{
+		{
+			name: "success",
+			setup: func() {
+				mockDiscoveryService.EXPECT().BuildCredentialIssuerMetadata(gomock.Any(), profileID, profileVersion).
+					Return(&oidc4cisrv.CredentialIssuerMetadata{
+						CredentialIssuer:      "https://vcs.example.com/issuer/testID/v1.0",
+						AuthorizationEndpoint: "https://vcs.example.com/oidc/authorize",
+						CredentialEndpoint:    "https://vcs.example.com/oidc/credential",
+						CredentialsSupported: []map[string]interface{}{
+							{
+								"format": "jwt_vc_json",
+								"types":  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+							},
+						},
+						CryptographicSuitesSupported: []string{"EdDSA"},
+					}, nil)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.JSONEq(t, `{
+					"credential_issuer": "https://vcs.example.com/issuer/testID/v1.0",
+					"authorization_endpoint": "https://vcs.example.com/oidc/authorize",
+					"credential_endpoint": "https://vcs.example.com/oidc/credential",
+					"credentials_supported": [
+						{
+							"format": "jwt_vc_json",
+							"types": ["VerifiableCredential", "UniversityDegreeCredential"]
+						}
+					],
+					"cryptographic_suites_supported": ["EdDSA"]
+				}`, rec.Body.String())
+			},
+		},
+		{
+			name: "profile not active",
+			setup: func() {
+				mockDiscoveryService.EXPECT().BuildCredentialIssuerMetadata(gomock.Any(), profileID, profileVersion).
+					Return(nil, oidc4cisrv.ErrProfileNotActive)
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				var customErr *resterr.CustomError
+
+				require.ErrorAs(t, err, &customErr)
+				require.Equal(t, resterr.DoesntExist, customErr.Code)
+				require.Equal(t, "profile", customErr.IncorrectValue)
+			},
+		},
+		{
+			name: "build metadata error",
+			setup: func() {
+				mockDiscoveryService.EXPECT().BuildCredentialIssuerMetadata(gomock.Any(), profileID, profileVersion).
+					Return(nil, errors.New("get profile error"))
+			},
+			check: func(t *testing.T, rec *httptest.ResponseRecorder, err error) {
+				var customErr *resterr.CustomError
+
+				require.ErrorAs(t, err, &customErr)
+				require.ErrorContains(t, customErr, "get profile error")
+				require.Equal(t, resterr.SystemError, customErr.Code)
+				require.Equal(t, "DiscoveryService", customErr.Component)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			controller := oidc4ci.NewController(&oidc4ci.Config{
+				DiscoveryService: mockDiscoveryService,
+				Tracer:           trace.NewNoopTracerProvider().Tracer(""),
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+
+			err := controller.OidcCredentialIssuerMetadata(echo.New().NewContext(req, rec), profileID, profileVersion)
+			tt.check(t, rec, err)
+		})
+	}
+}
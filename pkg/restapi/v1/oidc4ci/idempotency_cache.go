@@ -0,0 +1,102 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4ci
+
+import (
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"time"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+var errIdempotencyKeyReused = errors.New("idempotency key reused with a different credential request")
+
+type cachedCredentialResponse struct {
+	resp        *CredentialResponse
+	requestHash [sha256.Size]byte
+	expiresAt   time.Time
+}
+
+// idempotencyScope identifies the issuance transaction an idempotency key is used in. Public wallet clients share
+// a client ID and the pre-authorized code flow runs without one, so the key is scoped to the transaction of the
+// access token as well.
+type idempotencyScope struct {
+	clientID string
+	txID     string
+}
+
+// idempotencyCache keeps credential responses by issuance transaction and idempotency key, so that the credential
+// request retried by the wallet gets the already issued credential instead of a new one. Zero TTL disables caching.
+type idempotencyCache struct {
+	ttl   time.Duration
+	items map[string]cachedCredentialResponse
+	mutex sync.Mutex
+}
+
+func newIdempotencyCache(ttl time.Duration) *idempotencyCache {
+	return &idempotencyCache{
+		ttl:   ttl,
+		items: make(map[string]cachedCredentialResponse),
+	}
+}
+
+func (c *idempotencyCache) enabled() bool {
+	return c.ttl > 0
+}
+
+// get returns the cached response for the idempotency key. It fails with errIdempotencyKeyReused if the response
+// was cached for a different request.
+func (c *idempotencyCache) get(
+	scope idempotencyScope,
+	idempotencyKey string,
+	request []byte,
+) (*CredentialResponse, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	item, ok := c.items[idempotencyCacheKey(scope, idempotencyKey)]
+	if !ok || !time.Now().Before(item.expiresAt) {
+		return nil, false, nil
+	}
+
+	if item.requestHash != sha256.Sum256(request) {
+		return nil, false, errIdempotencyKeyReused
+	}
+
+	return item.resp, true, nil
+}
+
+func (c *idempotencyCache) set(
+	scope idempotencyScope,
+	idempotencyKey string,
+	request []byte,
+	resp *CredentialResponse,
+) {
+	now := time.Now()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// drop expired responses, so that the cache does not grow with keys that are never retried
+	for key, item := range c.items {
+		if !now.Before(item.expiresAt) {
+			delete(c.items, key)
+		}
+	}
+
+	c.items[idempotencyCacheKey(scope, idempotencyKey)] = cachedCredentialResponse{
+		resp:        resp,
+		requestHash: sha256.Sum256(request),
+		expiresAt:   now.Add(c.ttl),
+	}
+}
+
+func idempotencyCacheKey(scope idempotencyScope, idempotencyKey string) string {
+	return scope.clientID + "\x00" + scope.txID + "\x00" + idempotencyKey
+}
@@ -32,3 +32,10 @@ type JWTProofClaims struct {
 	IssuedAt *int64 `json:"iat,omitempty"`
 	Nonce    string `json:"nonce,omitempty"`
 }
+
+// OIDCTokenErrorResponse is the error response of the token endpoint.
+// Refer to https://datatracker.ietf.org/doc/html/rfc6749#section-5.2 for more details.
+type OIDCTokenErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
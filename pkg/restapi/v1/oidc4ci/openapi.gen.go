@@ -35,14 +35,35 @@ type AccessTokenResponse struct {
 	TokenType string `json:"token_type"`
 }
 
+// OpenID4VCI Credential Issuer metadata of the issuer profile.
+type CredentialIssuerMetadata struct {
+	// URL of the OAuth 2.0 Authorization Endpoint used by the Credential Issuer.
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+
+	// URL of the Credential Issuer's Credential Endpoint.
+	CredentialEndpoint string `json:"credential_endpoint"`
+
+	// The Credential Issuer's identifier.
+	CredentialIssuer string `json:"credential_issuer"`
+
+	// JSON array containing a list of JSON objects, each of them representing metadata about a separate credential type that the Credential Issuer can issue.
+	CredentialsSupported []map[string]interface{} `json:"credentials_supported"`
+
+	// JSON array containing a list of cryptographic suites used to sign the issued credentials.
+	CryptographicSuitesSupported *[]string `json:"cryptographic_suites_supported,omitempty"`
+}
+
 // Model for OIDC Credential request.
 type CredentialRequest struct {
-	// Format of the credential being issued.
+	// Identifier of the credential in the credentials_supported issuer metadata.
+	CredentialIdentifier *string `json:"credential_identifier,omitempty"`
+
+	// Format of the credential being issued. Not used if credential_identifier is set.
 	Format *string   `json:"format,omitempty"`
 	Proof  *JWTProof `json:"proof,omitempty"`
 
-	// Array of types of the credential being issued.
-	Types []string `json:"types"`
+	// Array of types of the credential being issued. Not used if credential_identifier is set.
+	Types *[]string `json:"types,omitempty"`
 }
 
 // Model for OIDC Credential response.
@@ -70,6 +91,51 @@ type JWTProof struct {
 	ProofType string `json:"proof_type"`
 }
 
+// Page of OAuth 2.0 clients registered for the profile.
+type ListOAuthClientsResponse struct {
+	// Registered metadata of OAuth 2.0 clients without client secrets.
+	Clients []RegisterOAuthClientResponse `json:"clients"`
+
+	// Total number of OAuth 2.0 clients registered for the profile.
+	Total int `json:"total"`
+}
+
+// OpenID Provider metadata of the issuer profile.
+type OIDCDiscoveryDocument struct {
+	// URL of the OP's OAuth 2.0 Authorization Endpoint.
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+
+	// JSON array containing a list of the OAuth 2.0 Grant Type values that this OP supports.
+	GrantTypesSupported *[]string `json:"grant_types_supported,omitempty"`
+
+	// URL of the OP's issuer identifier.
+	Issuer string `json:"issuer"`
+
+	// JSON Boolean indicating whether the issuer accepts a Token Request with a Pre-Authorized Code but without a client id.
+	PreAuthorizedGrantAnonymousAccessSupported bool `json:"pre-authorized_grant_anonymous_access_supported"`
+
+	// URL of the OP's OAuth 2.0 Pushed Authorization Request Endpoint.
+	PushedAuthorizationRequestEndpoint string `json:"pushed_authorization_request_endpoint"`
+
+	// URL of the OP's Dynamic Client Registration Endpoint.
+	RegistrationEndpoint *string `json:"registration_endpoint,omitempty"`
+
+	// JSON array containing a list of the OAuth 2.0 response_type values that this OP supports.
+	ResponseTypesSupported []string `json:"response_types_supported"`
+
+	// JSON array containing a list of the OAuth 2.0 [RFC6749] scope values that this server supports.
+	ScopesSupported *[]string `json:"scopes_supported,omitempty"`
+
+	// URL of the OP's OAuth 2.0 Token Endpoint.
+	TokenEndpoint string `json:"token_endpoint"`
+
+	// JSON array containing a list of Client Authentication methods supported by the Token Endpoint.
+	TokenEndpointAuthMethodsSupported *[]string `json:"token_endpoint_auth_methods_supported,omitempty"`
+
+	// JSON Boolean indicating whether the issuer profile supports wallet initiated flow in OIDC4CI.
+	WalletInitiatedAuthFlowSupported bool `json:"wallet_initiated_auth_flow_supported"`
+}
+
 // Model for Pushed Authorization Response.
 type PushedAuthorizationResponse struct {
 	// A JSON number that represents the lifetime of the request URI in seconds as a positive integer. The request URI lifetime is at the discretion of the authorization server but will typically be relatively short (e.g., between 5 and 600 seconds).
@@ -99,6 +165,9 @@ type RegisterOAuthClientRequest struct {
 	// Array of strings representing ways to contact people responsible for this client, typically email addresses.
 	Contacts *[]string `json:"contacts,omitempty"`
 
+	// Ecosystem-specific client properties, e.g. vcs:allowed_credential_types. All keys must start with "vcs:" prefix.
+	ExtensionFields *map[string]interface{} `json:"extension_fields,omitempty"`
+
 	// Array of OAuth 2.0 grant types that the client is allowed to use. Supported values: authorization_code, urn:ietf:params:oauth:grant-type:pre-authorized_code.
 	GrantTypes *[]string `json:"grant_types,omitempty"`
 
@@ -126,10 +195,13 @@ type RegisterOAuthClientRequest struct {
 	// A unique identifier string (e.g. UUID) assigned by the client developer or software publisher used by registration endpoints to identify the client software to be dynamically registered.
 	SoftwareId *string `json:"software_id,omitempty"`
 
+	// A software statement containing client metadata values about the client software as claims. Signed JWT, which is verified with the key set of the profile. Claims take precedence over the request fields.
+	SoftwareStatement *string `json:"software_statement,omitempty"`
+
 	// A version identifier string for the client software identified by "software_id".
 	SoftwareVersion *string `json:"software_version,omitempty"`
 
-	// Requested client authentication method for the token endpoint. Supported values: none, client_secret_post, client_secret_basic. None is used for public clients (native apps, mobile apps) which can not have secrets. Default: client_secret_basic.
+	// Requested client authentication method for the token endpoint. Supported values: none, client_secret_post, client_secret_basic, private_key_jwt. None is used for public clients (native apps, mobile apps) which can not have secrets. Default: client_secret_basic.
 	TokenEndpointAuthMethod *string `json:"token_endpoint_auth_method,omitempty"`
 
 	// URL string that points to a human-readable terms of service document for the client that describes a contractual relationship between the end-user and the client that the end-user accepts when authorizing the client.
@@ -159,6 +231,9 @@ type RegisterOAuthClientResponse struct {
 	// Array of strings representing ways to contact people responsible for this client, typically email addresses.
 	Contacts *[]string `json:"contacts,omitempty"`
 
+	// Ecosystem-specific client properties, e.g. vcs:allowed_credential_types. All keys must start with "vcs:" prefix.
+	ExtensionFields *map[string]interface{} `json:"extension_fields,omitempty"`
+
 	// Array of OAuth 2.0 grant types that the client is allowed to use. Supported values: authorization_code, urn:ietf:params:oauth:grant-type:pre-authorized_code.
 	GrantTypes []string `json:"grant_types"`
 
@@ -186,10 +261,13 @@ type RegisterOAuthClientResponse struct {
 	// A unique identifier string (e.g. UUID) assigned by the client developer or software publisher used by registration endpoints to identify the client software to be dynamically registered.
 	SoftwareId *string `json:"software_id,omitempty"`
 
+	// The software statement sent in the registration request. Returned unmodified.
+	SoftwareStatement *string `json:"software_statement,omitempty"`
+
 	// A version identifier string for the client software identified by "software_id".
 	SoftwareVersion *string `json:"software_version,omitempty"`
 
-	// Requested client authentication method for the token endpoint. Supported values: none, client_secret_post, client_secret_basic. None is used for public clients (native apps, mobile apps) which can not have secrets. Default: client_secret_basic.
+	// Requested client authentication method for the token endpoint. Supported values: none, client_secret_post, client_secret_basic, private_key_jwt. None is used for public clients (native apps, mobile apps) which can not have secrets. Default: client_secret_basic.
 	TokenEndpointAuthMethod string `json:"token_endpoint_auth_method"`
 
 	// URL string that points to a human-readable terms of service document for the client that describes a contractual relationship between the end-user and the client that the end-user accepts when authorizing the client.
@@ -207,7 +285,7 @@ type OidcAuthorizeParams struct {
 	// A challenge derived from the code verifier that is sent in the authorization request, to be verified against later.
 	CodeChallenge string `form:"code_challenge" json:"code_challenge"`
 
-	// A method that was used to derive code challenge.
+	// A method that was used to derive code challenge, either "S256" or "plain". The "plain" method is rejected if the issuer profile disallows plain PKCE.
 	CodeChallengeMethod *string `form:"code_challenge_method,omitempty" json:"code_challenge_method,omitempty"`
 
 	// The authorization server redirects the user-agent to the client's redirection endpoint previously established with the authorization server during the client registration process or when making the authorization request.
@@ -233,6 +311,9 @@ type OidcAuthorizeParams struct {
 
 	// String indicating that client is using an identifier not assigned by the authorization server. The only supported value "urn:ietf:params:oauth:client-id-scheme:oauth-discoverable-client" specifies "client_id" parameter in the request as an HTTPS based URL corresponding to the "client_uri". If the authorization server does not already have the metadata for the identified client, it can retrieve the metadata from client’s well-known location.
 	ClientIdScheme *string `form:"client_id_scheme,omitempty" json:"client_id_scheme,omitempty"`
+
+	// Request Object (RFC 9101). A signed JWT whose claims are authorization request parameters. The JWT MUST be issued by the client ("iss" equal to "client_id") for the authorization server ("aud"). Its claims are merged over the query parameters; a parameter present in both with different values is rejected.
+	Request *string `form:"request,omitempty" json:"request,omitempty"`
 }
 
 // OidcCredentialJSONBody defines parameters for OidcCredential.
@@ -247,6 +328,15 @@ type OidcRedirectParams struct {
 	State string `form:"state" json:"state"`
 }
 
+// OidcListClientsParams defines parameters for OidcListClients.
+type OidcListClientsParams struct {
+	// Page number starting from 1. Default is 1.
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+
+	// Maximum number of clients on a page. Default is 20, maximum is 100.
+	PageSize *int `form:"pageSize,omitempty" json:"pageSize,omitempty"`
+}
+
 // OidcRegisterClientJSONBody defines parameters for OidcRegisterClient.
 type OidcRegisterClientJSONBody = RegisterOAuthClientRequest
 
@@ -273,6 +363,15 @@ type ServerInterface interface {
 	// OIDC Token Request
 	// (POST /oidc/token)
 	OidcToken(ctx echo.Context) error
+	// OIDC Discovery
+	// (GET /oidc/{profileID}/{profileVersion}/.well-known/openid-configuration)
+	OidcDiscovery(ctx echo.Context, profileID string, profileVersion string) error
+	// OIDC Credential Issuer Metadata
+	// (GET /oidc/{profileID}/{profileVersion}/credential-issuer-metadata)
+	OidcCredentialIssuerMetadata(ctx echo.Context, profileID string, profileVersion string) error
+	// OIDC List OAuth Clients
+	// (GET /oidc/{profileID}/{profileVersion}/register)
+	OidcListClients(ctx echo.Context, profileID string, profileVersion string, params OidcListClientsParams) error
 	// OIDC Register OAuth Client
 	// (POST /oidc/{profileID}/{profileVersion}/register)
 	OidcRegisterClient(ctx echo.Context, profileID string, profileVersion string) error
@@ -373,6 +472,13 @@ func (w *ServerInterfaceWrapper) OidcAuthorize(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter client_id_scheme: %s", err))
 	}
 
+	// ------------- Optional query parameter "request" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "request", ctx.QueryParams(), &params.Request)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter request: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.OidcAuthorize(ctx, params)
 	return err
@@ -430,6 +536,94 @@ func (w *ServerInterfaceWrapper) OidcToken(ctx echo.Context) error {
 	return err
 }
 
+// OidcDiscovery converts echo context to params.
+func (w *ServerInterfaceWrapper) OidcDiscovery(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "profileID" -------------
+	var profileID string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "profileID", runtime.ParamLocationPath, ctx.Param("profileID"), &profileID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter profileID: %s", err))
+	}
+
+	// ------------- Path parameter "profileVersion" -------------
+	var profileVersion string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "profileVersion", runtime.ParamLocationPath, ctx.Param("profileVersion"), &profileVersion)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter profileVersion: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.OidcDiscovery(ctx, profileID, profileVersion)
+	return err
+}
+
+// OidcCredentialIssuerMetadata converts echo context to params.
+func (w *ServerInterfaceWrapper) OidcCredentialIssuerMetadata(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "profileID" -------------
+	var profileID string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "profileID", runtime.ParamLocationPath, ctx.Param("profileID"), &profileID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter profileID: %s", err))
+	}
+
+	// ------------- Path parameter "profileVersion" -------------
+	var profileVersion string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "profileVersion", runtime.ParamLocationPath, ctx.Param("profileVersion"), &profileVersion)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter profileVersion: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.OidcCredentialIssuerMetadata(ctx, profileID, profileVersion)
+	return err
+}
+
+// OidcListClients converts echo context to params.
+func (w *ServerInterfaceWrapper) OidcListClients(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "profileID" -------------
+	var profileID string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "profileID", runtime.ParamLocationPath, ctx.Param("profileID"), &profileID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter profileID: %s", err))
+	}
+
+	// ------------- Path parameter "profileVersion" -------------
+	var profileVersion string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "profileVersion", runtime.ParamLocationPath, ctx.Param("profileVersion"), &profileVersion)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter profileVersion: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params OidcListClientsParams
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "pageSize" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pageSize", ctx.QueryParams(), &params.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pageSize: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.OidcListClients(ctx, profileID, profileVersion, params)
+	return err
+}
+
 // OidcRegisterClient converts echo context to params.
 func (w *ServerInterfaceWrapper) OidcRegisterClient(ctx echo.Context) error {
 	var err error
@@ -487,6 +681,9 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.POST(baseURL+"/oidc/par", wrapper.OidcPushedAuthorizationRequest)
 	router.GET(baseURL+"/oidc/redirect", wrapper.OidcRedirect)
 	router.POST(baseURL+"/oidc/token", wrapper.OidcToken)
+	router.GET(baseURL+"/oidc/:profileID/:profileVersion/.well-known/openid-configuration", wrapper.OidcDiscovery)
+	router.GET(baseURL+"/oidc/:profileID/:profileVersion/credential-issuer-metadata", wrapper.OidcCredentialIssuerMetadata)
+	router.GET(baseURL+"/oidc/:profileID/:profileVersion/register", wrapper.OidcListClients)
 	router.POST(baseURL+"/oidc/:profileID/:profileVersion/register", wrapper.OidcRegisterClient)
 
 }
@@ -84,6 +84,7 @@ type VPTokenClaims struct {
 	Nonce         string
 	Aud           string
 	SignerDIDID   string
+	SignerKeyID   string
 	VpTokenFormat vcsverifiable.Format
 	VP            *verifiable.Presentation
 }
@@ -478,6 +479,62 @@ func (c *Controller) CheckAuthorizationResponse(e echo.Context) error {
 	return nil
 }
 
+// CheckProfileAuthorizationResponse is used by wallets to post the authorization response directly to the verifier
+// profile that initiated the interaction (response_mode=direct_post).
+// (POST /verifier/{profileID}/{profileVersion}/interactions/authorization-response).
+func (c *Controller) CheckProfileAuthorizationResponse(e echo.Context, profileID, profileVersion string) error {
+	logger.Debugc(e.Request().Context(), "CheckProfileAuthorizationResponse begin")
+	startTime := time.Now()
+
+	ctx, span := c.tracer.Start(e.Request().Context(), "CheckProfileAuthorizationResponse")
+	defer span.End()
+
+	defer func() {
+		c.metrics.CheckAuthorizationResponseTime(time.Since(startTime))
+		logger.Debugc(e.Request().Context(), "CheckProfileAuthorizationResponse end",
+			log.WithDuration(time.Since(startTime)))
+	}()
+
+	authResp, err := validateAuthorizationResponse(e)
+	if err != nil {
+		return err
+	}
+
+	profile, err := c.getProfile(profileID, profileVersion)
+	if err != nil {
+		return err
+	}
+
+	if responseMode := profile.OIDCConfig.GetResponseMode(); responseMode != profileapi.ResponseModeDirectPost {
+		return resterr.NewValidationError(resterr.InvalidValue, "response_mode",
+			fmt.Errorf("profile %s uses response mode %s", profileID, responseMode))
+	}
+
+	tx, err := c.accessOIDC4VPTx(ctx, authResp.State)
+	if err != nil {
+		return err
+	}
+
+	if tx.ProfileID != profile.ID || tx.ProfileVersion != profile.Version {
+		return resterr.NewValidationError(resterr.InvalidValue, "state",
+			fmt.Errorf("transaction %s does not belong to profile %s", tx.ID, profileID))
+	}
+
+	processedTokens, err := c.verifyAuthorizationResponseTokens(ctx, authResp)
+	if err != nil {
+		return err
+	}
+
+	err = c.oidc4VPService.VerifyOIDCVerifiablePresentation(ctx, tx.ID, processedTokens)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugc(ctx, "CheckProfileAuthorizationResponse succeed")
+
+	return nil
+}
+
 // RetrieveInteractionsClaim is used by verifier applications to get claims obtained during oidc4vp interaction.
 // (GET /verifier/interactions/{txID}/claim).
 func (c *Controller) RetrieveInteractionsClaim(e echo.Context, txID string) error {
@@ -587,7 +644,11 @@ func (c *Controller) verifyAuthorizationResponseTokens(
 
 		vpTokenClaims.VP.Context = append(vpTokenClaims.VP.Context, presexch.PresentationSubmissionJSONLDContextIRI)
 		vpTokenClaims.VP.Type = append(vpTokenClaims.VP.Type, presexch.PresentationSubmissionJSONLDType)
-		vpTokenClaims.VP.CustomFields[vpSubmissionProperty] = idTokenClaims.VPToken.PresentationSubmission
+
+		// A presentation submission embedded in the VP itself takes precedence over the merged one from the id_token.
+		if _, ok := vpTokenClaims.VP.CustomFields[vpSubmissionProperty].(map[string]interface{}); !ok {
+			vpTokenClaims.VP.CustomFields[vpSubmissionProperty] = idTokenClaims.VPToken.PresentationSubmission
+		}
 
 		processedVPTokens = append(processedVPTokens, &oidc4vp.ProcessedVPToken{
 			Nonce:         idTokenClaims.Nonce,
@@ -595,6 +656,8 @@ func (c *Controller) verifyAuthorizationResponseTokens(
 			VpTokenFormat: vpTokenClaims.VpTokenFormat,
 			Presentation:  vpTokenClaims.VP,
 			SignerDIDID:   vpTokenClaims.SignerDIDID,
+			SignerKeyID:   vpTokenClaims.SignerKeyID,
+			ReceivedAt:    startTime,
 		})
 	}
 
@@ -691,6 +754,7 @@ func (c *Controller) validateVPTokenJWT(vpToken string) (*VPTokenClaims, error)
 		Nonce:         string(v.GetStringBytes("nonce")),
 		Aud:           string(v.GetStringBytes("aud")),
 		SignerDIDID:   strings.Split(kid, "#")[0],
+		SignerKeyID:   kid,
 		VpTokenFormat: vcsverifiable.Jwt,
 		VP:            presentation,
 	}, nil
@@ -729,6 +793,7 @@ func (c *Controller) validateVPTokenLDP(vpToken string) (*VPTokenClaims, error)
 	return &VPTokenClaims{
 		Nonce:         nonce,
 		SignerDIDID:   didID,
+		SignerKeyID:   verificationMethod,
 		Aud:           clientID,
 		VpTokenFormat: vcsverifiable.Ldp,
 		VP:            presentation,
@@ -809,6 +874,21 @@ func decodeFormValue(output *string, valName string, values url.Values) error {
 }
 
 func (c *Controller) accessProfile(profileID, profileVersion, tenantID string) (*profileapi.Verifier, error) {
+	profile, err := c.getProfile(profileID, profileVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// Profiles of other organization is not visible.
+	if profile.OrganizationID != tenantID {
+		return nil, resterr.NewValidationError(resterr.DoesntExist, "organizationID",
+			fmt.Errorf("profile with given org id %q, doesn't exist", tenantID))
+	}
+
+	return profile, nil
+}
+
+func (c *Controller) getProfile(profileID, profileVersion string) (*profileapi.Verifier, error) {
 	profile, err := c.profileSvc.GetProfile(profileID, profileVersion)
 	if err != nil {
 		if strings.Contains(err.Error(), "data not found") {
@@ -824,12 +904,6 @@ func (c *Controller) accessProfile(profileID, profileVersion, tenantID string) (
 			fmt.Errorf("profile with given id %s, doesn't exist", profileID))
 	}
 
-	// Profiles of other organization is not visible.
-	if profile.OrganizationID != tenantID {
-		return nil, resterr.NewValidationError(resterr.DoesntExist, "organizationID",
-			fmt.Errorf("profile with given org id %q, doesn't exist", tenantID))
-	}
-
 	return profile, nil
 }
 
@@ -1114,6 +1114,96 @@ func TestController_CheckAuthorizationResponse(t *testing.T) {
 	})
 }
 
+func TestController_CheckProfileAuthorizationResponse(t *testing.T) {
+	signedClaimsJWTResult := testutil.SignedClaimsJWT(t, &IDTokenClaims{
+		VPToken: IDTokenVPToken{
+			PresentationSubmission: map[string]interface{}{}},
+		Nonce: validNonce,
+		Aud:   validAud,
+		Exp:   time.Now().Unix() + 1000,
+	})
+
+	vpToken := testutil.SignedClaimsJWTWithExistingPrivateKey(t,
+		signedClaimsJWTResult.VerMethodDIDKeyID,
+		signedClaimsJWTResult.Kh,
+		&vpTokenClaims{
+			Nonce: validNonce,
+			Aud:   validAud,
+			Exp:   time.Now().Unix() + 1000,
+			VP: &verifiable.Presentation{
+				Context: []string{
+					"https://www.w3.org/2018/credentials/v1",
+					"https://identity.foundation/presentation-exchange/submission/v1",
+				},
+				Type: []string{
+					"VerifiablePresentation",
+					"PresentationSubmission",
+				},
+			},
+		})
+
+	body := "vp_token=" + vpToken +
+		"&id_token=" + signedClaimsJWTResult.JWT +
+		"&state=txid"
+
+	newController := func(t *testing.T, profile *profileapi.Verifier, tx *oidc4vp.Transaction) *Controller {
+		t.Helper()
+
+		profileSvc := NewMockProfileService(gomock.NewController(t))
+		profileSvc.EXPECT().GetProfile("p1", "v1.0").AnyTimes().Return(profile, nil)
+
+		oidc4VPService := NewMockOIDC4VPService(gomock.NewController(t))
+		oidc4VPService.EXPECT().GetTx(gomock.Any(), oidc4vp.TxID("txid")).AnyTimes().Return(tx, nil)
+		oidc4VPService.EXPECT().VerifyOIDCVerifiablePresentation(gomock.Any(), oidc4vp.TxID("txid"), gomock.Any()).
+			AnyTimes().Return(nil)
+
+		return NewController(&Config{
+			VDR:            signedClaimsJWTResult.VDR,
+			ProfileSvc:     profileSvc,
+			OIDCVPService:  oidc4VPService,
+			DocumentLoader: testutil.DocumentLoader(t),
+			Tracer:         trace.NewNoopTracerProvider().Tracer(""),
+		})
+	}
+
+	profile := &profileapi.Verifier{
+		ID:         "p1",
+		Version:    "v1.0",
+		OIDCConfig: &profileapi.OIDC4VPConfig{ResponseMode: profileapi.ResponseModeDirectPost},
+	}
+	tx := &oidc4vp.Transaction{ID: "txid", ProfileID: "p1", ProfileVersion: "v1.0"}
+
+	t.Run("Success", func(t *testing.T) {
+		c := newController(t, profile, tx)
+
+		err := c.CheckProfileAuthorizationResponse(createContextApplicationForm([]byte(body)), "p1", "v1.0")
+		require.NoError(t, err)
+	})
+
+	t.Run("Profile doesn't use direct_post response mode", func(t *testing.T) {
+		for _, oidcConfig := range []*profileapi.OIDC4VPConfig{nil, {ResponseMode: "fragment"}} {
+			c := newController(t, &profileapi.Verifier{ID: "p1", Version: "v1.0", OIDCConfig: oidcConfig}, tx)
+
+			err := c.CheckProfileAuthorizationResponse(createContextApplicationForm([]byte(body)), "p1", "v1.0")
+			requireValidationError(t, resterr.InvalidValue, "response_mode", err)
+		}
+	})
+
+	t.Run("Transaction of another profile", func(t *testing.T) {
+		c := newController(t, profile, &oidc4vp.Transaction{ID: "txid", ProfileID: "p2", ProfileVersion: "v1.0"})
+
+		err := c.CheckProfileAuthorizationResponse(createContextApplicationForm([]byte(body)), "p1", "v1.0")
+		requireValidationError(t, resterr.InvalidValue, "state", err)
+	})
+
+	t.Run("Profile doesn't exist", func(t *testing.T) {
+		c := newController(t, nil, tx)
+
+		err := c.CheckProfileAuthorizationResponse(createContextApplicationForm([]byte(body)), "p1", "v1.0")
+		requireValidationError(t, resterr.DoesntExist, "profile", err)
+	})
+}
+
 func TestController_RetrieveInteractionsClaim(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		oidc4VPService := NewMockOIDC4VPService(gomock.NewController(t))
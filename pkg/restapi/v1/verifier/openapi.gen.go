@@ -131,6 +131,9 @@ type ServerInterface interface {
 	// Verify presentation
 	// (POST /verifier/profiles/{profileID}/{profileVersion}/presentations/verify)
 	PostVerifyPresentation(ctx echo.Context, profileID string, profileVersion string) error
+	// Used by wallets to post the authorization response directly to the verifier profile
+	// (POST /verifier/{profileID}/{profileVersion}/interactions/authorization-response)
+	CheckProfileAuthorizationResponse(ctx echo.Context, profileID string, profileVersion string) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -235,6 +238,30 @@ func (w *ServerInterfaceWrapper) PostVerifyPresentation(ctx echo.Context) error
 	return err
 }
 
+// CheckProfileAuthorizationResponse converts echo context to params.
+func (w *ServerInterfaceWrapper) CheckProfileAuthorizationResponse(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "profileID" -------------
+	var profileID string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "profileID", runtime.ParamLocationPath, ctx.Param("profileID"), &profileID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter profileID: %s", err))
+	}
+
+	// ------------- Path parameter "profileVersion" -------------
+	var profileVersion string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "profileVersion", runtime.ParamLocationPath, ctx.Param("profileVersion"), &profileVersion)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter profileVersion: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CheckProfileAuthorizationResponse(ctx, profileID, profileVersion)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -268,5 +295,6 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.POST(baseURL+"/verifier/profiles/:profileID/:profileVersion/credentials/verify", wrapper.PostVerifyCredentials)
 	router.POST(baseURL+"/verifier/profiles/:profileID/:profileVersion/interactions/initiate-oidc", wrapper.InitiateOidcInteraction)
 	router.POST(baseURL+"/verifier/profiles/:profileID/:profileVersion/presentations/verify", wrapper.PostVerifyPresentation)
+	router.POST(baseURL+"/verifier/:profileID/:profileVersion/interactions/authorization-response", wrapper.CheckProfileAuthorizationResponse)
 
 }
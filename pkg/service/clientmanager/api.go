@@ -20,10 +20,21 @@ import (
 type ServiceInterface interface {
 	Create(ctx context.Context, profileID, profileVersion string, data *ClientMetadata) (*oauth2client.Client, error)
 	Get(ctx context.Context, id string) (fosite.Client, error)
+	List(ctx context.Context, profileID, profileVersion string, page, pageSize int) ([]*oauth2client.Client, int, error)
+	Disable(ctx context.Context, clientID string) error
+	Enable(ctx context.Context, clientID string) error
+	ValidateClientSecret(ctx context.Context, clientID, secret string) error
 }
 
 var (
-	ErrClientNotFound = errors.New("client not found")
+	ErrClientNotFound    = errors.New("client not found")
+	ErrInvalidPagination = errors.New("invalid pagination")
+	// ErrClientDisabled wraps fosite.ErrInvalidClient, so disabled clients are rejected as invalid.
+	ErrClientDisabled = fmt.Errorf("client disabled: %w", fosite.ErrInvalidClient)
+	// ErrInvalidClientSecret wraps fosite.ErrInvalidClient, so clients with a wrong secret are rejected as invalid.
+	ErrInvalidClientSecret = fmt.Errorf("invalid client secret: %w", fosite.ErrInvalidClient)
+	// ErrClientSecretExpired wraps fosite.ErrInvalidClient, so clients with an expired secret are rejected as invalid.
+	ErrClientSecretExpired = fmt.Errorf("client secret expired: %w", fosite.ErrInvalidClient)
 )
 
 // ErrorCode is an error code for client registration error response as defined in
@@ -48,7 +59,7 @@ const (
 type RegistrationError struct {
 	Code         ErrorCode `json:"error"`
 	InvalidValue string    `json:"invalid_value,omitempty"`
-	Err          error     `json:"-"` // wrapped error
+	Err          error     `json:"-"` // wrapped error, the cause of the registration error
 }
 
 // InvalidClientMetadataError creates a new RegistrationError with ErrCodeInvalidClientMetadata error code.
@@ -71,3 +82,8 @@ func (r *RegistrationError) Error() string {
 		return string(r.Code)
 	}
 }
+
+// Unwrap returns the cause of the registration error.
+func (r *RegistrationError) Unwrap() error {
+	return r.Err
+}
@@ -10,6 +10,11 @@ package clientmanager
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -33,29 +38,51 @@ var _ ServiceInterface = (*Manager)(nil)
 type store interface {
 	InsertClient(ctx context.Context, client *oauth2client.Client) (string, error)
 	GetClient(ctx context.Context, id string) (fosite.Client, error)
+	ListClients(ctx context.Context, profileID, profileVersion string, page, pageSize int) (
+		[]*oauth2client.Client, int, error)
+	SetClientDisabledAt(ctx context.Context, id string, disabledAt *time.Time) error
 }
 
 type profileService interface {
 	GetProfile(profileID profileapi.ID, profileVersion profileapi.Version) (*profileapi.Issuer, error)
 }
 
+const secretSize = 32
+
 // Config defines configuration for client manager.
 type Config struct {
 	Store          store
 	ProfileService profileService
+	HTTPClient     httpClient
+	// SecretGenerator generates secrets for clients that authenticate with client_secret_basic or
+	// client_secret_post. Defaults to a base64url-encoded 32-byte random value.
+	SecretGenerator func() (string, error)
+	// ClientSecretLifetime is how long generated client secrets are valid. 0 means secrets never expire.
+	ClientSecretLifetime time.Duration
 }
 
 // Manager implements functionality to manage OAuth2 clients.
 type Manager struct {
-	store          store
-	profileService profileService
+	store                store
+	profileService       profileService
+	httpClient           httpClient
+	secretGenerator      func() (string, error)
+	clientSecretLifetime time.Duration
 }
 
 // New creates a new Manager instance.
 func New(config *Config) *Manager {
+	secretGenerator := config.SecretGenerator
+	if secretGenerator == nil {
+		secretGenerator = generateSecret
+	}
+
 	return &Manager{
-		store:          config.Store,
-		profileService: config.ProfileService,
+		store:                config.Store,
+		profileService:       config.ProfileService,
+		httpClient:           config.HTTPClient,
+		secretGenerator:      secretGenerator,
+		clientSecretLifetime: config.ClientSecretLifetime,
 	}
 }
 
@@ -77,6 +104,9 @@ type ClientMetadata struct {
 	SoftwareID              string
 	SoftwareVersion         string
 	TokenEndpointAuthMethod string
+	Metadata                map[string]interface{}
+	// SoftwareStatement is a signed JWT with client metadata claims vetted by the software publisher.
+	SoftwareStatement *string
 }
 
 // Create creates an OAuth2 client and inserts it into the store.
@@ -90,6 +120,12 @@ func (m *Manager) Create(ctx context.Context, profileID, profileVersion string,
 		return nil, fmt.Errorf("oidc config not set for profile")
 	}
 
+	if data.SoftwareStatement != nil {
+		if data, err = m.applySoftwareStatement(ctx, profile.OIDCConfig.SoftwareStatementJWKS, data); err != nil {
+			return nil, err
+		}
+	}
+
 	client := &oauth2client.Client{
 		ID:                data.ID,
 		Name:              data.Name,
@@ -102,6 +138,8 @@ func (m *Manager) Create(ctx context.Context, profileID, profileVersion string,
 		JSONWebKeysURI:    data.JSONWebKeysURI,
 		SoftwareID:        data.SoftwareID,
 		SoftwareVersion:   data.SoftwareVersion,
+		ProfileID:         profileID,
+		ProfileVersion:    profileVersion,
 		CreatedAt:         time.Now(),
 	}
 
@@ -129,21 +167,30 @@ func (m *Manager) Create(ctx context.Context, profileID, profileVersion string,
 		return nil, InvalidClientMetadataError("token_endpoint_auth_method", err)
 	}
 
-	if client.TokenEndpointAuthMethod != oauth2client.TokenEndpointAuthMethodNone {
-		var secret []byte
+	if client.TokenEndpointAuthMethod == oauth2client.TokenEndpointAuthMethodClientSecretBasic ||
+		client.TokenEndpointAuthMethod == oauth2client.TokenEndpointAuthMethodClientSecretPost {
+		var secret string
 
-		if secret, err = generateSecret(); err != nil {
-			return nil, err
+		if secret, err = m.secretGenerator(); err != nil {
+			return nil, fmt.Errorf("generate secret: %w", err)
 		}
 
-		client.Secret = secret
+		client.Secret = []byte(secret)
 		client.SecretExpiresAt = 0 // never expires
+
+		if m.clientSecretLifetime > 0 {
+			client.SecretExpiresAt = client.CreatedAt.Add(m.clientSecretLifetime).Unix()
+		}
 	}
 
 	if err = setJSONWebKeys(client, data.JSONWebKeys); err != nil {
 		return nil, InvalidClientMetadataError("jwks", err)
 	}
 
+	if err = setMetadata(client, data.Metadata); err != nil {
+		return nil, InvalidClientMetadataError("extension_fields", err)
+	}
+
 	if err = validateClient(client); err != nil {
 		return nil, err
 	}
@@ -223,6 +270,22 @@ func setTokenEndpointAuthMethod(client *oauth2client.Client, methodsSupported []
 	return nil
 }
 
+func setMetadata(client *oauth2client.Client, metadata map[string]interface{}) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	for key := range metadata {
+		if !strings.HasPrefix(key, oauth2client.MetadataKeyPrefix) {
+			return fmt.Errorf("extension field %s must start with %s", key, oauth2client.MetadataKeyPrefix)
+		}
+	}
+
+	client.Metadata = metadata
+
+	return nil
+}
+
 func setJSONWebKeys(client *oauth2client.Client, rawJWKs map[string]interface{}) error {
 	if len(rawJWKs) == 0 {
 		return nil
@@ -244,18 +307,15 @@ func setJSONWebKeys(client *oauth2client.Client, rawJWKs map[string]interface{})
 	return nil
 }
 
-func generateSecret() ([]byte, error) {
-	secret, err := uuid.NewRandom()
-	if err != nil {
-		return nil, fmt.Errorf("generate secret: %w", err)
-	}
+// generateSecret returns a base64url-encoded random value of secretSize bytes read from the CSPRNG.
+func generateSecret() (string, error) {
+	b := make([]byte, secretSize)
 
-	b, err := secret.MarshalBinary()
-	if err != nil {
-		return nil, fmt.Errorf("marshal secret: %w", err)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
 
-	return b, nil
+	return base64.RawURLEncoding.EncodeToString(b), nil
 }
 
 func validateClient(client *oauth2client.Client) error {
@@ -263,16 +323,28 @@ func validateClient(client *oauth2client.Client) error {
 		return InvalidClientMetadataError("", fmt.Errorf("jwks_uri and jwks cannot both be set"))
 	}
 
+	if client.TokenEndpointAuthMethod == oauth2client.TokenEndpointAuthMethodPrivateKeyJWT {
+		if err := validatePrivateKeyJWTKeys(client); err != nil {
+			return InvalidClientMetadataError("token_endpoint_auth_method", err)
+		}
+	}
+
 	if len(client.RedirectURIs) > 0 {
 		for _, uri := range client.RedirectURIs {
-			if u, err := url.Parse(uri); err == nil && isValidRedirectURI(u) {
+			u, err := url.Parse(uri)
+			if err == nil && isValidRedirectURI(u) {
 				continue
 			}
 
+			cause := fmt.Errorf("invalid redirect uri: %s", uri)
+			if err != nil {
+				cause = fmt.Errorf("invalid redirect uri: %w", err)
+			}
+
 			return &RegistrationError{
 				Code:         ErrCodeInvalidRedirectURI,
 				InvalidValue: "redirect_uris",
-				Err:          fmt.Errorf("invalid redirect uri: %s", uri),
+				Err:          cause,
 			}
 		}
 	}
@@ -288,6 +360,32 @@ func validateClient(client *oauth2client.Client) error {
 	return nil
 }
 
+// validatePrivateKeyJWTKeys checks that the client has keys to verify its private_key_jwt assertions with.
+// Keys referenced by jwks_uri are not fetched at registration time.
+func validatePrivateKeyJWTKeys(client *oauth2client.Client) error {
+	if client.JSONWebKeysURI != "" {
+		return nil
+	}
+
+	if client.JSONWebKeys == nil {
+		return fmt.Errorf("jwks or jwks_uri must be set for private_key_jwt token endpoint auth method")
+	}
+
+	for _, key := range client.JSONWebKeys.Keys {
+		if key.Use != "sig" {
+			continue
+		}
+
+		switch key.Key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey:
+			return nil
+		}
+	}
+
+	return fmt.Errorf("jwks must contain RSA or ECDSA public key with use sig for private_key_jwt " +
+		"token endpoint auth method")
+}
+
 func isValidRedirectURI(uri *url.URL) bool {
 	u, err := url.ParseRequestURI(uri.String())
 	if err != nil {
@@ -305,7 +403,7 @@ func isValidRedirectURI(uri *url.URL) bool {
 	return true
 }
 
-// Get returns the fosite client with the given id.
+// Get returns the fosite client with the given id. ErrClientDisabled is returned if the client is disabled.
 func (m *Manager) Get(ctx context.Context, id string) (fosite.Client, error) {
 	c, err := m.store.GetClient(ctx, id)
 	if err != nil {
@@ -313,8 +411,86 @@ func (m *Manager) Get(ctx context.Context, id string) (fosite.Client, error) {
 			return nil, ErrClientNotFound
 		}
 
+		// Stores reject disabled clients with fosite.ErrInvalidClient, so that fosite flows fail for them too.
+		if errors.Is(err, fosite.ErrInvalidClient) {
+			return nil, ErrClientDisabled
+		}
+
 		return nil, fmt.Errorf("get client: %w", err)
 	}
 
+	if client, ok := c.(*oauth2client.Client); ok && client.IsDisabled() {
+		return nil, ErrClientDisabled
+	}
+
 	return c, nil
 }
+
+// ValidateClientSecret authenticates the confidential client with the given id by its secret.
+// ErrInvalidClientSecret is returned if the secret does not match, ErrClientSecretExpired if the secret has expired
+// and ErrClientDisabled if the client is disabled.
+func (m *Manager) ValidateClientSecret(ctx context.Context, clientID, secret string) error {
+	c, err := m.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+
+	hashedSecret := c.GetHashedSecret()
+
+	if len(hashedSecret) == 0 || subtle.ConstantTimeCompare(hashedSecret, []byte(secret)) != 1 {
+		return ErrInvalidClientSecret
+	}
+
+	if client, ok := c.(*oauth2client.Client); ok && client.SecretExpiresAt > 0 &&
+		!time.Now().Before(time.Unix(client.SecretExpiresAt, 0)) {
+		return ErrClientSecretExpired
+	}
+
+	return nil
+}
+
+// Disable disables the client with the given id. Disabled client is kept in the store for audit purposes,
+// but it can't be used anymore.
+func (m *Manager) Disable(ctx context.Context, clientID string) error {
+	return m.setDisabledAt(ctx, clientID, lo.ToPtr(time.Now().UTC()))
+}
+
+// Enable enables previously disabled client with the given id.
+func (m *Manager) Enable(ctx context.Context, clientID string) error {
+	return m.setDisabledAt(ctx, clientID, nil)
+}
+
+func (m *Manager) setDisabledAt(ctx context.Context, clientID string, disabledAt *time.Time) error {
+	if err := m.store.SetClientDisabledAt(ctx, clientID, disabledAt); err != nil {
+		if errors.Is(err, dto.ErrDataNotFound) {
+			return ErrClientNotFound
+		}
+
+		return fmt.Errorf("set client disabled at: %w", err)
+	}
+
+	return nil
+}
+
+// List returns a page of OAuth2 clients registered for the given profile and the total number of such clients.
+// Pages are numbered starting from 1.
+func (m *Manager) List(
+	ctx context.Context,
+	profileID, profileVersion string,
+	page, pageSize int,
+) ([]*oauth2client.Client, int, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, 0, fmt.Errorf("%w: page %d, page size %d", ErrInvalidPagination, page, pageSize)
+	}
+
+	if _, err := m.profileService.GetProfile(profileID, profileVersion); err != nil {
+		return nil, 0, fmt.Errorf("get profile: %w", err)
+	}
+
+	clients, total, err := m.store.ListClients(ctx, profileID, profileVersion, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list clients: %w", err)
+	}
+
+	return clients, total, nil
+}
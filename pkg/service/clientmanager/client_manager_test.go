@@ -8,15 +8,26 @@ package clientmanager_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/go-jose/go-jose/v3"
+	josejwt "github.com/go-jose/go-jose/v3/jwt"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/ory/fosite"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/vcs/component/oidc/fosite/dto"
 
@@ -246,6 +257,10 @@ func TestManager_Create(t *testing.T) {
 				require.Equal(t, clientmanager.ErrCodeInvalidClientMetadata, regErr.Code)
 				require.Equal(t, "jwks", regErr.InvalidValue)
 				require.ErrorContains(t, regErr, "unmarshal raw jwks into key set:")
+
+				var typeErr *json.UnmarshalTypeError
+
+				require.ErrorAs(t, err, &typeErr)
 			},
 		},
 		{
@@ -281,6 +296,178 @@ func TestManager_Create(t *testing.T) {
 				require.ErrorContains(t, regErr, "jwks_uri and jwks cannot both be set")
 			},
 		},
+		{
+			name: "success with extension fields",
+			setup: func() {
+				mockProfileSvc.EXPECT().GetProfile(gomock.Any(), gomock.Any()).
+					Return(
+						&profileapi.Issuer{
+							OIDCConfig: &profileapi.OIDCConfig{
+								EnableDynamicClientRegistration: true,
+							},
+						}, nil)
+
+				mockStore.EXPECT().InsertClient(gomock.Any(), gomock.Any()).Return(uuid.New().String(), nil)
+
+				data = &clientmanager.ClientMetadata{
+					GrantTypes:   []string{"authorization_code"},
+					RedirectURIs: []string{"https://example.com/redirect"},
+					Metadata: map[string]interface{}{
+						"vcs:allowed_credential_types": []interface{}{"PermanentResidentCard"},
+					},
+				}
+			},
+			check: func(t *testing.T, client *oauth2client.Client, err error) {
+				require.NoError(t, err)
+				require.Equal(t, map[string]interface{}{
+					"vcs:allowed_credential_types": []interface{}{"PermanentResidentCard"},
+				}, client.Metadata)
+			},
+		},
+		{
+			name: "extension field without vcs prefix error",
+			setup: func() {
+				mockProfileSvc.EXPECT().GetProfile(gomock.Any(), gomock.Any()).
+					Return(
+						&profileapi.Issuer{
+							OIDCConfig: &profileapi.OIDCConfig{
+								EnableDynamicClientRegistration: true,
+							},
+						}, nil)
+
+				mockStore.EXPECT().InsertClient(gomock.Any(), gomock.Any()).Times(0)
+
+				data = &clientmanager.ClientMetadata{
+					GrantTypes:   []string{"authorization_code"},
+					RedirectURIs: []string{"https://example.com/redirect"},
+					Metadata: map[string]interface{}{
+						"vcs:allowed_credential_types": []interface{}{"PermanentResidentCard"},
+						"client_name":                  "name",
+					},
+				}
+			},
+			check: func(t *testing.T, client *oauth2client.Client, err error) {
+				var regErr *clientmanager.RegistrationError
+
+				require.ErrorAs(t, err, &regErr)
+				require.Equal(t, clientmanager.ErrCodeInvalidClientMetadata, regErr.Code)
+				require.Equal(t, "extension_fields", regErr.InvalidValue)
+				require.ErrorContains(t, regErr, "extension field client_name must start with vcs:")
+			},
+		},
+		{
+			name: "success with private_key_jwt token endpoint auth method",
+			setup: func() {
+				mockProfileSvc.EXPECT().GetProfile(gomock.Any(), gomock.Any()).
+					Return(
+						&profileapi.Issuer{
+							OIDCConfig: &profileapi.OIDCConfig{
+								EnableDynamicClientRegistration: true,
+							},
+						}, nil)
+
+				mockStore.EXPECT().InsertClient(gomock.Any(), gomock.Any()).Return(uuid.New().String(), nil)
+
+				data = &clientmanager.ClientMetadata{
+					GrantTypes:              []string{"authorization_code"},
+					RedirectURIs:            []string{"https://example.com/redirect"},
+					TokenEndpointAuthMethod: "private_key_jwt",
+					JSONWebKeys:             newJWKS(t, newECDSAJWK(t, "sig")),
+				}
+			},
+			check: func(t *testing.T, client *oauth2client.Client, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "private_key_jwt", client.TokenEndpointAuthMethod)
+				require.Nil(t, client.Secret)
+			},
+		},
+		{
+			name: "success with private_key_jwt token endpoint auth method and jwks_uri",
+			setup: func() {
+				mockProfileSvc.EXPECT().GetProfile(gomock.Any(), gomock.Any()).
+					Return(
+						&profileapi.Issuer{
+							OIDCConfig: &profileapi.OIDCConfig{
+								EnableDynamicClientRegistration: true,
+							},
+						}, nil)
+
+				mockStore.EXPECT().InsertClient(gomock.Any(), gomock.Any()).Return(uuid.New().String(), nil)
+
+				data = &clientmanager.ClientMetadata{
+					GrantTypes:              []string{"authorization_code"},
+					RedirectURIs:            []string{"https://example.com/redirect"},
+					TokenEndpointAuthMethod: "private_key_jwt",
+					JSONWebKeysURI:          "https://example.com/jwks.json",
+				}
+			},
+			check: func(t *testing.T, client *oauth2client.Client, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "private_key_jwt token endpoint auth method without jwks error",
+			setup: func() {
+				mockProfileSvc.EXPECT().GetProfile(gomock.Any(), gomock.Any()).
+					Return(
+						&profileapi.Issuer{
+							OIDCConfig: &profileapi.OIDCConfig{
+								EnableDynamicClientRegistration: true,
+							},
+						}, nil)
+
+				mockStore.EXPECT().InsertClient(gomock.Any(), gomock.Any()).Times(0)
+
+				data = &clientmanager.ClientMetadata{
+					GrantTypes:              []string{"authorization_code"},
+					RedirectURIs:            []string{"https://example.com/redirect"},
+					TokenEndpointAuthMethod: "private_key_jwt",
+				}
+			},
+			check: func(t *testing.T, client *oauth2client.Client, err error) {
+				var regErr *clientmanager.RegistrationError
+
+				require.ErrorAs(t, err, &regErr)
+				require.Equal(t, clientmanager.ErrCodeInvalidClientMetadata, regErr.Code)
+				require.Equal(t, "token_endpoint_auth_method", regErr.InvalidValue)
+				require.ErrorContains(t, regErr, "jwks or jwks_uri must be set")
+			},
+		},
+		{
+			name: "private_key_jwt token endpoint auth method without signing key error",
+			setup: func() {
+				mockProfileSvc.EXPECT().GetProfile(gomock.Any(), gomock.Any()).
+					Return(
+						&profileapi.Issuer{
+							OIDCConfig: &profileapi.OIDCConfig{
+								EnableDynamicClientRegistration: true,
+							},
+						}, nil)
+
+				mockStore.EXPECT().InsertClient(gomock.Any(), gomock.Any()).Times(0)
+
+				edPub, _, err := ed25519.GenerateKey(rand.Reader)
+				require.NoError(t, err)
+
+				data = &clientmanager.ClientMetadata{
+					GrantTypes:              []string{"authorization_code"},
+					RedirectURIs:            []string{"https://example.com/redirect"},
+					TokenEndpointAuthMethod: "private_key_jwt",
+					JSONWebKeys: newJWKS(t,
+						newECDSAJWK(t, "enc"),
+						jose.JSONWebKey{Key: edPub, KeyID: "ed", Use: "sig"},
+					),
+				}
+			},
+			check: func(t *testing.T, client *oauth2client.Client, err error) {
+				var regErr *clientmanager.RegistrationError
+
+				require.ErrorAs(t, err, &regErr)
+				require.Equal(t, clientmanager.ErrCodeInvalidClientMetadata, regErr.Code)
+				require.Equal(t, "token_endpoint_auth_method", regErr.InvalidValue)
+				require.ErrorContains(t, regErr, "jwks must contain RSA or ECDSA public key with use sig")
+			},
+		},
 		{
 			name: "redirect_uris must be set for authorization_code grant type error",
 			setup: func() {
@@ -333,6 +520,35 @@ func TestManager_Create(t *testing.T) {
 				require.Equal(t, "invalid redirect uri: invalid", regErr.Error())
 			},
 		},
+		{
+			name: "unparsable redirect uri",
+			setup: func() {
+				mockProfileSvc.EXPECT().GetProfile(gomock.Any(), gomock.Any()).
+					Return(
+						&profileapi.Issuer{
+							OIDCConfig: &profileapi.OIDCConfig{
+								EnableDynamicClientRegistration: true,
+							},
+						}, nil)
+
+				mockStore.EXPECT().InsertClient(gomock.Any(), gomock.Any()).Times(0)
+
+				data = &clientmanager.ClientMetadata{
+					RedirectURIs: []string{"https://example.com/\x7f"},
+				}
+			},
+			check: func(t *testing.T, client *oauth2client.Client, err error) {
+				var regErr *clientmanager.RegistrationError
+
+				require.ErrorAs(t, err, &regErr)
+				require.Equal(t, clientmanager.ErrCodeInvalidRedirectURI, regErr.Code)
+				require.Equal(t, "redirect_uris", regErr.InvalidValue)
+
+				var urlErr *url.Error
+
+				require.ErrorAs(t, err, &urlErr)
+			},
+		},
 		{
 			name: "invalid scheme in redirect uri",
 			setup: func() {
@@ -426,6 +642,289 @@ func TestManager_Create(t *testing.T) {
 	}
 }
 
+func TestManager_CreateClientSecret(t *testing.T) {
+	mockProfileSvc := NewMockProfileService(gomock.NewController(t))
+	mockProfileSvc.EXPECT().GetProfile(gomock.Any(), gomock.Any()).AnyTimes().Return(
+		&profileapi.Issuer{
+			OIDCConfig: &profileapi.OIDCConfig{
+				ScopesSupported:                 []string{"foo"},
+				EnableDynamicClientRegistration: true,
+			},
+		}, nil)
+
+	mockStore := NewMockStore(gomock.NewController(t))
+	mockStore.EXPECT().InsertClient(gomock.Any(), gomock.Any()).AnyTimes().Return(uuid.New().String(), nil)
+
+	data := &clientmanager.ClientMetadata{
+		Scope:                   "foo",
+		GrantTypes:              []string{"authorization_code"},
+		ResponseTypes:           []string{"code"},
+		TokenEndpointAuthMethod: "client_secret_post",
+		RedirectURIs:            []string{"https://example.com/redirect"},
+	}
+
+	t.Run("default generator", func(t *testing.T) {
+		manager := clientmanager.New(&clientmanager.Config{
+			Store:          mockStore,
+			ProfileService: mockProfileSvc,
+		})
+
+		client, err := manager.Create(context.Background(), "test", "v1", data)
+		require.NoError(t, err)
+
+		secret, err := base64.RawURLEncoding.DecodeString(string(client.Secret))
+		require.NoError(t, err)
+		require.Len(t, secret, 32)
+		require.Zero(t, client.SecretExpiresAt)
+
+		other, err := manager.Create(context.Background(), "test", "v1", data)
+		require.NoError(t, err)
+		require.NotEqual(t, client.Secret, other.Secret)
+	})
+
+	t.Run("custom generator and lifetime", func(t *testing.T) {
+		manager := clientmanager.New(&clientmanager.Config{
+			Store:                mockStore,
+			ProfileService:       mockProfileSvc,
+			SecretGenerator:      func() (string, error) { return "custom-secret", nil },
+			ClientSecretLifetime: time.Hour,
+		})
+
+		client, err := manager.Create(context.Background(), "test", "v1", data)
+		require.NoError(t, err)
+		require.Equal(t, []byte("custom-secret"), client.Secret)
+		require.Equal(t, client.CreatedAt.Add(time.Hour).Unix(), client.SecretExpiresAt)
+	})
+
+	t.Run("no secret for none auth method", func(t *testing.T) {
+		manager := clientmanager.New(&clientmanager.Config{
+			Store:          mockStore,
+			ProfileService: mockProfileSvc,
+			SecretGenerator: func() (string, error) {
+				return "", errors.New("must not be called")
+			},
+		})
+
+		client, err := manager.Create(context.Background(), "test", "v1", &clientmanager.ClientMetadata{
+			Scope:                   "foo",
+			GrantTypes:              []string{"authorization_code"},
+			ResponseTypes:           []string{"code"},
+			TokenEndpointAuthMethod: "none",
+			RedirectURIs:            []string{"https://example.com/redirect"},
+		})
+		require.NoError(t, err)
+		require.Nil(t, client.Secret)
+	})
+
+	t.Run("generator error", func(t *testing.T) {
+		manager := clientmanager.New(&clientmanager.Config{
+			Store:           mockStore,
+			ProfileService:  mockProfileSvc,
+			SecretGenerator: func() (string, error) { return "", errors.New("entropy exhausted") },
+		})
+
+		client, err := manager.Create(context.Background(), "test", "v1", data)
+		require.ErrorContains(t, err, "generate secret: entropy exhausted")
+		require.Nil(t, client)
+	})
+}
+
+func TestManager_CreateWithSoftwareStatement(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/jwks" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{
+				{Key: &otherKey.PublicKey, KeyID: "other-key", Algorithm: string(jose.ES256), Use: "sig"},
+				{Key: &privateKey.PublicKey, KeyID: "statement-key", Algorithm: string(jose.ES256), Use: "sig"},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	signStatement := func(t *testing.T, key *ecdsa.PrivateKey, kid string, claims interface{}) *string {
+		t.Helper()
+
+		opts := &jose.SignerOptions{}
+		if kid != "" {
+			opts = opts.WithHeader(jose.HeaderKey("kid"), kid)
+		}
+
+		signer, signErr := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key}, opts)
+		require.NoError(t, signErr)
+
+		statement, signErr := josejwt.Signed(signer).Claims(claims).CompactSerialize()
+		require.NoError(t, signErr)
+
+		return &statement
+	}
+
+	statementClaims := map[string]interface{}{
+		"software_id":   "4NRB1-0XZABZI9E6-5SM3R",
+		"client_name":   "Example Statement-based Client",
+		"redirect_uris": []string{"https://client.example.org/callback"},
+	}
+
+	tests := []struct {
+		name    string
+		jwksURI string
+		data    func(t *testing.T) *clientmanager.ClientMetadata
+		check   func(t *testing.T, client *oauth2client.Client, err error)
+	}{
+		{
+			name:    "success",
+			jwksURI: srv.URL + "/jwks",
+			data: func(t *testing.T) *clientmanager.ClientMetadata {
+				return &clientmanager.ClientMetadata{
+					Name:              "Request Client",
+					RedirectURIs:      []string{"https://example.com/redirect"},
+					PolicyURI:         "https://example.com/policy",
+					SoftwareStatement: signStatement(t, privateKey, "statement-key", statementClaims),
+				}
+			},
+			check: func(t *testing.T, client *oauth2client.Client, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "Example Statement-based Client", client.Name)
+				require.Equal(t, "4NRB1-0XZABZI9E6-5SM3R", client.SoftwareID)
+				require.Equal(t, []string{"https://client.example.org/callback"}, client.RedirectURIs)
+				require.Equal(t, "https://example.com/policy", client.PolicyURI)
+			},
+		},
+		{
+			name:    "success without key id",
+			jwksURI: srv.URL + "/jwks",
+			data: func(t *testing.T) *clientmanager.ClientMetadata {
+				return &clientmanager.ClientMetadata{
+					SoftwareStatement: signStatement(t, privateKey, "", statementClaims),
+				}
+			},
+			check: func(t *testing.T, client *oauth2client.Client, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "4NRB1-0XZABZI9E6-5SM3R", client.SoftwareID)
+			},
+		},
+		{
+			name: "software statement jwks not configured error",
+			data: func(t *testing.T) *clientmanager.ClientMetadata {
+				return &clientmanager.ClientMetadata{
+					SoftwareStatement: signStatement(t, privateKey, "statement-key", statementClaims),
+				}
+			},
+			check: func(t *testing.T, client *oauth2client.Client, err error) {
+				var regErr *clientmanager.RegistrationError
+
+				require.ErrorAs(t, err, &regErr)
+				require.Equal(t, clientmanager.ErrCodeUnapprovedSoftwareStatement, regErr.Code)
+			},
+		},
+		{
+			name:    "fetch jwks error",
+			jwksURI: srv.URL + "/not-found",
+			data: func(t *testing.T) *clientmanager.ClientMetadata {
+				return &clientmanager.ClientMetadata{
+					SoftwareStatement: signStatement(t, privateKey, "statement-key", statementClaims),
+				}
+			},
+			check: func(t *testing.T, client *oauth2client.Client, err error) {
+				var regErr *clientmanager.RegistrationError
+
+				require.False(t, errors.As(err, &regErr))
+				require.ErrorContains(t, err, "fetch software statement jwks: unexpected status code: 404")
+			},
+		},
+		{
+			name:    "malformed software statement error",
+			jwksURI: srv.URL + "/jwks",
+			data: func(t *testing.T) *clientmanager.ClientMetadata {
+				return &clientmanager.ClientMetadata{
+					SoftwareStatement: lo.ToPtr("not a jwt"),
+				}
+			},
+			check: func(t *testing.T, client *oauth2client.Client, err error) {
+				var regErr *clientmanager.RegistrationError
+
+				require.ErrorAs(t, err, &regErr)
+				require.Equal(t, clientmanager.ErrCodeInvalidSoftwareStatement, regErr.Code)
+				require.ErrorContains(t, regErr, "parse software statement")
+			},
+		},
+		{
+			name:    "untrusted signing key error",
+			jwksURI: srv.URL + "/jwks",
+			data: func(t *testing.T) *clientmanager.ClientMetadata {
+				untrustedKey, keyErr := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				require.NoError(t, keyErr)
+
+				return &clientmanager.ClientMetadata{
+					SoftwareStatement: signStatement(t, untrustedKey, "statement-key", statementClaims),
+				}
+			},
+			check: func(t *testing.T, client *oauth2client.Client, err error) {
+				var regErr *clientmanager.RegistrationError
+
+				require.ErrorAs(t, err, &regErr)
+				require.Equal(t, clientmanager.ErrCodeInvalidSoftwareStatement, regErr.Code)
+				require.ErrorContains(t, regErr, "not verified by any of the trusted keys")
+			},
+		},
+		{
+			name:    "expired software statement error",
+			jwksURI: srv.URL + "/jwks",
+			data: func(t *testing.T) *clientmanager.ClientMetadata {
+				return &clientmanager.ClientMetadata{
+					SoftwareStatement: signStatement(t, privateKey, "statement-key", map[string]interface{}{
+						"software_id": "4NRB1-0XZABZI9E6-5SM3R",
+						"exp":         time.Now().Add(-time.Hour).Unix(),
+					}),
+				}
+			},
+			check: func(t *testing.T, client *oauth2client.Client, err error) {
+				var regErr *clientmanager.RegistrationError
+
+				require.ErrorAs(t, err, &regErr)
+				require.Equal(t, clientmanager.ErrCodeInvalidSoftwareStatement, regErr.Code)
+				require.ErrorIs(t, err, josejwt.ErrExpired)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := NewMockStore(gomock.NewController(t))
+			mockStore.EXPECT().InsertClient(gomock.Any(), gomock.Any()).Return(uuid.New().String(), nil).AnyTimes()
+
+			mockProfileSvc := NewMockProfileService(gomock.NewController(t))
+			mockProfileSvc.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Return(
+				&profileapi.Issuer{
+					OIDCConfig: &profileapi.OIDCConfig{
+						EnableDynamicClientRegistration: true,
+						SoftwareStatementJWKS:           tt.jwksURI,
+					},
+				}, nil)
+
+			manager := clientmanager.New(
+				&clientmanager.Config{
+					Store:          mockStore,
+					ProfileService: mockProfileSvc,
+					HTTPClient:     srv.Client(),
+				},
+			)
+
+			client, err := manager.Create(context.Background(), "test", "v1", tt.data(t))
+			tt.check(t, client, err)
+		})
+	}
+}
+
 func TestManager_Get(t *testing.T) {
 	const clientID = "test-client-id"
 
@@ -454,6 +953,28 @@ func TestManager_Get(t *testing.T) {
 				require.ErrorIs(t, err, clientmanager.ErrClientNotFound)
 			},
 		},
+		{
+			name: "client disabled",
+			setup: func() {
+				mockStore.EXPECT().GetClient(gomock.Any(), clientID).Return(
+					&oauth2client.Client{DisabledAt: lo.ToPtr(time.Now())}, nil)
+			},
+			check: func(t *testing.T, client fosite.Client, err error) {
+				require.ErrorIs(t, err, clientmanager.ErrClientDisabled)
+				require.ErrorIs(t, err, fosite.ErrInvalidClient)
+				require.Nil(t, client)
+			},
+		},
+		{
+			name: "client disabled error from store",
+			setup: func() {
+				mockStore.EXPECT().GetClient(gomock.Any(), clientID).Return(
+					nil, fosite.ErrInvalidClient.WithHint("The client is disabled."))
+			},
+			check: func(t *testing.T, client fosite.Client, err error) {
+				require.ErrorIs(t, err, clientmanager.ErrClientDisabled)
+			},
+		},
 		{
 			name: "fail to get client",
 			setup: func() {
@@ -479,3 +1000,301 @@ func TestManager_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_ValidateClientSecret(t *testing.T) {
+	const (
+		clientID = "test-client-id"
+		secret   = "client-secret"
+	)
+
+	mockStore := NewMockStore(gomock.NewController(t))
+
+	tests := []struct {
+		name   string
+		client *oauth2client.Client
+		err    error
+		secret string
+		check  func(t *testing.T, err error)
+	}{
+		{
+			name:   "success",
+			client: &oauth2client.Client{Secret: []byte(secret)},
+			secret: secret,
+			check: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "success with secret not yet expired",
+			client: &oauth2client.Client{
+				Secret:          []byte(secret),
+				SecretExpiresAt: time.Now().Add(time.Hour).Unix(),
+			},
+			secret: secret,
+			check: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "invalid secret",
+			client: &oauth2client.Client{Secret: []byte(secret)},
+			secret: "wrong-secret",
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, clientmanager.ErrInvalidClientSecret)
+				require.ErrorIs(t, err, fosite.ErrInvalidClient)
+			},
+		},
+		{
+			name:   "client without secret",
+			client: &oauth2client.Client{},
+			secret: "",
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, clientmanager.ErrInvalidClientSecret)
+			},
+		},
+		{
+			name: "secret expired",
+			client: &oauth2client.Client{
+				Secret:          []byte(secret),
+				SecretExpiresAt: time.Now().Add(-time.Minute).Unix(),
+			},
+			secret: secret,
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, clientmanager.ErrClientSecretExpired)
+				require.ErrorIs(t, err, fosite.ErrInvalidClient)
+			},
+		},
+		{
+			name: "expired secret is not revealed for wrong secret",
+			client: &oauth2client.Client{
+				Secret:          []byte(secret),
+				SecretExpiresAt: time.Now().Add(-time.Minute).Unix(),
+			},
+			secret: "wrong-secret",
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, clientmanager.ErrInvalidClientSecret)
+			},
+		},
+		{
+			name:   "client disabled",
+			client: &oauth2client.Client{Secret: []byte(secret), DisabledAt: lo.ToPtr(time.Now())},
+			secret: secret,
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, clientmanager.ErrClientDisabled)
+			},
+		},
+		{
+			name:   "client not found",
+			err:    dto.ErrDataNotFound,
+			secret: secret,
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, clientmanager.ErrClientNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var client fosite.Client
+			if tt.client != nil {
+				client = tt.client
+			}
+
+			mockStore.EXPECT().GetClient(gomock.Any(), clientID).Return(client, tt.err)
+
+			manager := clientmanager.New(
+				&clientmanager.Config{
+					Store: mockStore,
+				},
+			)
+
+			tt.check(t, manager.ValidateClientSecret(context.Background(), clientID, tt.secret))
+		})
+	}
+}
+
+func TestManager_List(t *testing.T) {
+	const (
+		profileID      = "test-profile-id"
+		profileVersion = "v1.0"
+	)
+
+	var (
+		mockStore      = NewMockStore(gomock.NewController(t))
+		mockProfileSvc = NewMockProfileService(gomock.NewController(t))
+		page           int
+		pageSize       int
+	)
+
+	tests := []struct {
+		name  string
+		setup func()
+		check func(t *testing.T, clients []*oauth2client.Client, total int, err error)
+	}{
+		{
+			name: "success",
+			setup: func() {
+				page, pageSize = 2, 1
+
+				mockProfileSvc.EXPECT().GetProfile(profileID, profileVersion).Return(&profileapi.Issuer{}, nil)
+				mockStore.EXPECT().ListClients(gomock.Any(), profileID, profileVersion, 2, 1).Return(
+					[]*oauth2client.Client{{ID: "client-2"}}, 2, nil)
+			},
+			check: func(t *testing.T, clients []*oauth2client.Client, total int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 2, total)
+				require.Len(t, clients, 1)
+				require.Equal(t, "client-2", clients[0].ID)
+			},
+		},
+		{
+			name: "invalid page",
+			setup: func() {
+				page, pageSize = 0, 10
+
+				mockProfileSvc.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Times(0)
+				mockStore.EXPECT().ListClients(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			check: func(t *testing.T, clients []*oauth2client.Client, total int, err error) {
+				require.ErrorIs(t, err, clientmanager.ErrInvalidPagination)
+			},
+		},
+		{
+			name: "invalid page size",
+			setup: func() {
+				page, pageSize = 1, 0
+
+				mockProfileSvc.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Times(0)
+				mockStore.EXPECT().ListClients(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			check: func(t *testing.T, clients []*oauth2client.Client, total int, err error) {
+				require.ErrorIs(t, err, clientmanager.ErrInvalidPagination)
+			},
+		},
+		{
+			name: "get profile error",
+			setup: func() {
+				page, pageSize = 1, 10
+
+				mockProfileSvc.EXPECT().GetProfile(gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("get profile error"))
+				mockStore.EXPECT().ListClients(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			check: func(t *testing.T, clients []*oauth2client.Client, total int, err error) {
+				require.ErrorContains(t, err, "get profile:")
+			},
+		},
+		{
+			name: "fail to list clients",
+			setup: func() {
+				page, pageSize = 1, 10
+
+				mockProfileSvc.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Return(&profileapi.Issuer{}, nil)
+				mockStore.EXPECT().ListClients(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, 0, errors.New("list clients error"))
+			},
+			check: func(t *testing.T, clients []*oauth2client.Client, total int, err error) {
+				require.ErrorContains(t, err, "list clients:")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			manager := clientmanager.New(
+				&clientmanager.Config{
+					Store:          mockStore,
+					ProfileService: mockProfileSvc,
+				},
+			)
+
+			clients, total, err := manager.List(context.Background(), profileID, profileVersion, page, pageSize)
+			tt.check(t, clients, total, err)
+		})
+	}
+}
+
+func TestManager_DisableEnable(t *testing.T) {
+	const clientID = "test-client-id"
+
+	t.Run("disable", func(t *testing.T) {
+		mockStore := NewMockStore(gomock.NewController(t))
+		mockStore.EXPECT().SetClientDisabledAt(gomock.Any(), clientID, gomock.Not(gomock.Nil())).Return(nil)
+
+		manager := clientmanager.New(&clientmanager.Config{Store: mockStore})
+
+		require.NoError(t, manager.Disable(context.Background(), clientID))
+	})
+
+	t.Run("enable", func(t *testing.T) {
+		mockStore := NewMockStore(gomock.NewController(t))
+		mockStore.EXPECT().SetClientDisabledAt(gomock.Any(), clientID, gomock.Nil()).Return(nil)
+
+		manager := clientmanager.New(&clientmanager.Config{Store: mockStore})
+
+		require.NoError(t, manager.Enable(context.Background(), clientID))
+	})
+
+	t.Run("client not found", func(t *testing.T) {
+		mockStore := NewMockStore(gomock.NewController(t))
+		mockStore.EXPECT().SetClientDisabledAt(gomock.Any(), clientID, gomock.Any()).Return(dto.ErrDataNotFound)
+
+		manager := clientmanager.New(&clientmanager.Config{Store: mockStore})
+
+		require.ErrorIs(t, manager.Disable(context.Background(), clientID), clientmanager.ErrClientNotFound)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		mockStore := NewMockStore(gomock.NewController(t))
+		mockStore.EXPECT().SetClientDisabledAt(gomock.Any(), clientID, gomock.Any()).Return(errors.New("update error"))
+
+		manager := clientmanager.New(&clientmanager.Config{Store: mockStore})
+
+		require.ErrorContains(t, manager.Enable(context.Background(), clientID), "set client disabled at: update error")
+	})
+}
+
+func newECDSAJWK(t *testing.T, use string) jose.JSONWebKey {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return jose.JSONWebKey{Key: &privateKey.PublicKey, KeyID: uuid.New().String(), Use: use}
+}
+
+func newJWKS(t *testing.T, keys ...jose.JSONWebKey) map[string]interface{} {
+	t.Helper()
+
+	b, err := json.Marshal(jose.JSONWebKeySet{Keys: keys})
+	require.NoError(t, err)
+
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &m))
+
+	return m
+}
+
+func TestRegistrationError(t *testing.T) {
+	cause := errors.New("cause")
+
+	t.Run("with cause", func(t *testing.T) {
+		err := clientmanager.InvalidClientMetadataError("scope", cause)
+
+		require.ErrorIs(t, err, cause)
+		require.Equal(t, "cause", err.Error())
+	})
+
+	t.Run("without cause", func(t *testing.T) {
+		err := &clientmanager.RegistrationError{
+			Code:         clientmanager.ErrCodeInvalidClientMetadata,
+			InvalidValue: "scope",
+		}
+
+		require.NoError(t, errors.Unwrap(err))
+		require.Equal(t, "invalid_client_metadata (scope)", err.Error())
+	})
+}
@@ -0,0 +1,211 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+//go:generate mockgen -destination jwks_refresher_mocks_test.go -package clientmanager_test -source=jwks_refresher.go -mock_names jwksStore=MockJWKSStore,httpClient=MockHTTPClient
+
+package clientmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	"github.com/trustbloc/vcs/pkg/oauth2client"
+)
+
+var logger = log.New("client-manager")
+
+type jwksStore interface {
+	ListClientsWithJWKSURI(ctx context.Context) ([]*oauth2client.Client, error)
+	UpdateClientJWKS(ctx context.Context, clientID string, jwks *jose.JSONWebKeySet) error
+}
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// JWKSRefresherConfig defines configuration for JWKSRefresher.
+type JWKSRefresherConfig struct {
+	Store      jwksStore
+	HTTPClient httpClient
+}
+
+// JWKSRefresher periodically re-fetches the key sets of clients registered with a remote JWKS URI and
+// stores them with the client. A key set is not re-fetched until the max-age from the Cache-Control header
+// of the previous response has elapsed.
+type JWKSRefresher struct {
+	store       jwksStore
+	httpClient  httpClient
+	nextRefresh map[string]time.Time
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewJWKSRefresher creates a new JWKSRefresher instance.
+func NewJWKSRefresher(config *JWKSRefresherConfig) *JWKSRefresher {
+	return &JWKSRefresher{
+		store:       config.Store,
+		httpClient:  config.HTTPClient,
+		nextRefresh: map[string]time.Time{},
+	}
+}
+
+// Start refreshes the key sets immediately and then on every interval in the background
+// until Stop is called or ctx is done. Calling Start on a running refresher does nothing.
+func (r *JWKSRefresher) Start(ctx context.Context, interval time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return
+	}
+
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.done = make(chan struct{})
+
+	go r.run(ctx, interval, r.done)
+}
+
+// Stop stops the background refresh and waits for the refresh in progress to finish.
+func (r *JWKSRefresher) Stop() {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
+
+func (r *JWKSRefresher) run(ctx context.Context, interval time.Duration, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *JWKSRefresher) refresh(ctx context.Context) {
+	clients, err := r.store.ListClientsWithJWKSURI(ctx)
+	if err != nil {
+		logger.Warnc(ctx, "Failed to list clients with JWKS URI", log.WithError(err))
+
+		return
+	}
+
+	listed := make(map[string]struct{}, len(clients))
+
+	for _, client := range clients {
+		listed[client.ID] = struct{}{}
+	}
+
+	for id := range r.nextRefresh {
+		if _, ok := listed[id]; !ok {
+			delete(r.nextRefresh, id)
+		}
+	}
+
+	for _, client := range clients {
+		if ctx.Err() != nil {
+			return
+		}
+
+		now := time.Now()
+
+		if next, ok := r.nextRefresh[client.ID]; ok && now.Before(next) {
+			continue
+		}
+
+		jwks, maxAge, fetchErr := fetchJWKS(ctx, r.httpClient, client.JSONWebKeysURI)
+		if fetchErr != nil {
+			logger.Warnc(ctx, "Failed to fetch client JWKS",
+				log.WithID(client.ID), log.WithURL(client.JSONWebKeysURI), log.WithError(fetchErr))
+
+			continue
+		}
+
+		if err = r.store.UpdateClientJWKS(ctx, client.ID, jwks); err != nil {
+			logger.Warnc(ctx, "Failed to update client JWKS", log.WithID(client.ID), log.WithError(err))
+
+			continue
+		}
+
+		if maxAge > 0 {
+			r.nextRefresh[client.ID] = now.Add(maxAge)
+		} else {
+			delete(r.nextRefresh, client.ID)
+		}
+	}
+}
+
+// fetchJWKS fetches the key set from uri and returns it with the max-age of the response.
+func fetchJWKS(ctx context.Context, client httpClient, uri string) (*jose.JSONWebKeySet, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, http.NoBody)
+	if err != nil {
+		return nil, 0, fmt.Errorf("new request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("send request: %w", err)
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var jwks jose.JSONWebKeySet
+
+	if err = json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return nil, 0, fmt.Errorf("decode jwks: %w", err)
+	}
+
+	return &jwks, parseMaxAge(resp.Header.Get("Cache-Control")), nil
+}
+
+// parseMaxAge returns the max-age directive of the Cache-Control header value, or 0 if it's not set.
+func parseMaxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(directive), "=")
+		if !found || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+
+		seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+		if err != nil || seconds <= 0 {
+			return 0
+		}
+
+		return time.Duration(seconds) * time.Second
+	}
+
+	return 0
+}
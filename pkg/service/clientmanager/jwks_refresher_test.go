@@ -0,0 +1,212 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clientmanager_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/vcs/pkg/oauth2client"
+	"github.com/trustbloc/vcs/pkg/service/clientmanager"
+)
+
+const refreshInterval = 10 * time.Millisecond
+
+func TestJWKSRefresher(t *testing.T) {
+	t.Run("refresh keys", func(t *testing.T) {
+		var hits atomic.Int32
+
+		srv := newJWKSServer(t, "", &hits)
+
+		store := NewMockJWKSStore(gomock.NewController(t))
+
+		store.EXPECT().ListClientsWithJWKSURI(gomock.Any()).
+			DoAndReturn(func(context.Context) ([]*oauth2client.Client, error) {
+				return []*oauth2client.Client{{ID: "client-id", JSONWebKeysURI: srv.URL}}, nil
+			}).AnyTimes()
+
+		updated := make(chan *jose.JSONWebKeySet, 100)
+
+		store.EXPECT().UpdateClientJWKS(gomock.Any(), "client-id", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, jwks *jose.JSONWebKeySet) error {
+				updated <- jwks
+				return nil
+			}).AnyTimes()
+
+		refresher := clientmanager.NewJWKSRefresher(&clientmanager.JWKSRefresherConfig{
+			Store:      store,
+			HTTPClient: srv.Client(),
+		})
+
+		refresher.Start(context.Background(), refreshInterval)
+		refresher.Start(context.Background(), refreshInterval) // no-op when already started
+
+		jwks := <-updated
+
+		require.NotNil(t, jwks)
+		require.Len(t, jwks.Key("key-1"), 1)
+
+		require.Eventually(t, func() bool { return hits.Load() > 1 }, time.Second, refreshInterval)
+
+		refresher.Stop()
+		refresher.Stop() // no-op when already stopped
+	})
+
+	t.Run("honour cache-control max-age", func(t *testing.T) {
+		var hits atomic.Int32
+
+		srv := newJWKSServer(t, "public, max-age=3600", &hits)
+
+		store := NewMockJWKSStore(gomock.NewController(t))
+
+		var lists atomic.Int32
+
+		store.EXPECT().ListClientsWithJWKSURI(gomock.Any()).
+			DoAndReturn(func(context.Context) ([]*oauth2client.Client, error) {
+				lists.Add(1)
+				return []*oauth2client.Client{{ID: "client-id", JSONWebKeysURI: srv.URL}}, nil
+			}).AnyTimes()
+		store.EXPECT().UpdateClientJWKS(gomock.Any(), "client-id", gomock.Any()).Return(nil).Times(1)
+
+		refresher := clientmanager.NewJWKSRefresher(&clientmanager.JWKSRefresherConfig{
+			Store:      store,
+			HTTPClient: srv.Client(),
+		})
+
+		refresher.Start(context.Background(), refreshInterval)
+
+		require.Eventually(t, func() bool { return lists.Load() > 3 }, time.Second, refreshInterval)
+
+		refresher.Stop()
+
+		require.EqualValues(t, 1, hits.Load())
+	})
+
+	t.Run("forget deleted clients", func(t *testing.T) {
+		var hits atomic.Int32
+
+		srv := newJWKSServer(t, "public, max-age=3600", &hits)
+
+		store := NewMockJWKSStore(gomock.NewController(t))
+
+		client := &oauth2client.Client{ID: "client-id", JSONWebKeysURI: srv.URL}
+
+		gomock.InOrder(
+			store.EXPECT().ListClientsWithJWKSURI(gomock.Any()).Return([]*oauth2client.Client{client}, nil).Times(1),
+			store.EXPECT().ListClientsWithJWKSURI(gomock.Any()).Return(nil, nil).Times(1),
+			store.EXPECT().ListClientsWithJWKSURI(gomock.Any()).Return([]*oauth2client.Client{client}, nil).AnyTimes(),
+		)
+		store.EXPECT().UpdateClientJWKS(gomock.Any(), "client-id", gomock.Any()).Return(nil).Times(2)
+
+		refresher := clientmanager.NewJWKSRefresher(&clientmanager.JWKSRefresherConfig{
+			Store:      store,
+			HTTPClient: srv.Client(),
+		})
+
+		refresher.Start(context.Background(), refreshInterval)
+
+		require.Eventually(t, func() bool { return hits.Load() == 2 }, time.Second, refreshInterval)
+
+		refresher.Stop()
+	})
+
+	t.Run("continue on errors", func(t *testing.T) {
+		var hits atomic.Int32
+
+		srv := newJWKSServer(t, "", &hits)
+
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		t.Cleanup(failing.Close)
+
+		store := NewMockJWKSStore(gomock.NewController(t))
+
+		store.EXPECT().ListClientsWithJWKSURI(gomock.Any()).Return(nil, errors.New("list error")).Times(1)
+		store.EXPECT().ListClientsWithJWKSURI(gomock.Any()).
+			Return([]*oauth2client.Client{
+				{ID: "failing-client-id", JSONWebKeysURI: failing.URL},
+				{ID: "update-error-client-id", JSONWebKeysURI: srv.URL},
+				{ID: "client-id", JSONWebKeysURI: srv.URL},
+			}, nil).AnyTimes()
+
+		updated := make(chan string, 100)
+
+		store.EXPECT().UpdateClientJWKS(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, clientID string, _ *jose.JSONWebKeySet) error {
+				if clientID == "update-error-client-id" {
+					return errors.New("update error")
+				}
+
+				updated <- clientID
+
+				return nil
+			}).AnyTimes()
+
+		refresher := clientmanager.NewJWKSRefresher(&clientmanager.JWKSRefresherConfig{
+			Store:      store,
+			HTTPClient: failing.Client(),
+		})
+
+		refresher.Start(context.Background(), refreshInterval)
+		defer refresher.Stop()
+
+		require.Equal(t, "client-id", <-updated)
+	})
+
+	t.Run("stop on context done", func(t *testing.T) {
+		store := NewMockJWKSStore(gomock.NewController(t))
+
+		store.EXPECT().ListClientsWithJWKSURI(gomock.Any()).Return(nil, nil).AnyTimes()
+
+		refresher := clientmanager.NewJWKSRefresher(&clientmanager.JWKSRefresherConfig{
+			Store:      store,
+			HTTPClient: http.DefaultClient,
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		refresher.Start(ctx, refreshInterval)
+		cancel()
+		refresher.Stop()
+	})
+}
+
+func newJWKSServer(t *testing.T, cacheControl string, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	jwk := newECDSAJWK(t, "sig")
+	jwk.KeyID = "key-1"
+
+	jwks, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{jwk}})
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+
+		if cacheControl != "" {
+			w.Header().Set("Cache-Control", cacheControl)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		_, _ = w.Write(jwks)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
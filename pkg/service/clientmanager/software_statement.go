@@ -0,0 +1,165 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clientmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	josejwt "github.com/go-jose/go-jose/v3/jwt"
+)
+
+// softwareStatementClaims are the client metadata claims of the software statement as defined in
+// https://datatracker.ietf.org/doc/html/rfc7591#section-2.3.
+type softwareStatementClaims struct {
+	josejwt.Claims
+
+	ClientName              string                 `json:"client_name,omitempty"`
+	ClientURI               string                 `json:"client_uri,omitempty"`
+	RedirectURIs            []string               `json:"redirect_uris,omitempty"`
+	GrantTypes              []string               `json:"grant_types,omitempty"`
+	ResponseTypes           []string               `json:"response_types,omitempty"`
+	Scope                   string                 `json:"scope,omitempty"`
+	LogoURI                 string                 `json:"logo_uri,omitempty"`
+	Contacts                []string               `json:"contacts,omitempty"`
+	TermsOfServiceURI       string                 `json:"tos_uri,omitempty"`
+	PolicyURI               string                 `json:"policy_uri,omitempty"`
+	JSONWebKeysURI          string                 `json:"jwks_uri,omitempty"`
+	JSONWebKeys             map[string]interface{} `json:"jwks,omitempty"`
+	SoftwareID              string                 `json:"software_id,omitempty"`
+	SoftwareVersion         string                 `json:"software_version,omitempty"`
+	TokenEndpointAuthMethod string                 `json:"token_endpoint_auth_method,omitempty"`
+}
+
+// applySoftwareStatement verifies the software statement against the key set from jwksURI and returns
+// a copy of the client metadata with the statement claims merged in. Statement claims take precedence
+// over the metadata sent in the registration request.
+func (m *Manager) applySoftwareStatement(
+	ctx context.Context,
+	jwksURI string,
+	data *ClientMetadata,
+) (*ClientMetadata, error) {
+	if jwksURI == "" {
+		return nil, &RegistrationError{
+			Code: ErrCodeUnapprovedSoftwareStatement,
+			Err:  errors.New("software statements are not accepted for the profile"),
+		}
+	}
+
+	jwks, _, err := fetchJWKS(ctx, m.httpClient, jwksURI)
+	if err != nil {
+		return nil, fmt.Errorf("fetch software statement jwks: %w", err)
+	}
+
+	claims, err := verifySoftwareStatement(*data.SoftwareStatement, jwks)
+	if err != nil {
+		return nil, &RegistrationError{
+			Code: ErrCodeInvalidSoftwareStatement,
+			Err:  err,
+		}
+	}
+
+	merged := *data
+	mergeSoftwareStatementClaims(&merged, claims)
+
+	return &merged, nil
+}
+
+func verifySoftwareStatement(statement string, jwks *jose.JSONWebKeySet) (*softwareStatementClaims, error) {
+	token, err := josejwt.ParseSigned(statement)
+	if err != nil {
+		return nil, fmt.Errorf("parse software statement: %w", err)
+	}
+
+	keys := jwks.Keys
+
+	if kid := token.Headers[0].KeyID; kid != "" {
+		keys = jwks.Key(kid)
+	}
+
+	for _, key := range keys {
+		var claims softwareStatementClaims
+
+		if err = token.Claims(key, &claims); err != nil {
+			continue
+		}
+
+		if err = claims.ValidateWithLeeway(josejwt.Expected{Time: time.Now()}, josejwt.DefaultLeeway); err != nil {
+			return nil, fmt.Errorf("validate software statement: %w", err)
+		}
+
+		return &claims, nil
+	}
+
+	return nil, errors.New("software statement signature is not verified by any of the trusted keys")
+}
+
+//nolint:gocyclo
+func mergeSoftwareStatementClaims(data *ClientMetadata, claims *softwareStatementClaims) {
+	if claims.ClientName != "" {
+		data.Name = claims.ClientName
+	}
+
+	if claims.ClientURI != "" {
+		data.URI = claims.ClientURI
+	}
+
+	if len(claims.RedirectURIs) > 0 {
+		data.RedirectURIs = claims.RedirectURIs
+	}
+
+	if len(claims.GrantTypes) > 0 {
+		data.GrantTypes = claims.GrantTypes
+	}
+
+	if len(claims.ResponseTypes) > 0 {
+		data.ResponseTypes = claims.ResponseTypes
+	}
+
+	if claims.Scope != "" {
+		data.Scope = claims.Scope
+	}
+
+	if claims.LogoURI != "" {
+		data.LogoURI = claims.LogoURI
+	}
+
+	if len(claims.Contacts) > 0 {
+		data.Contacts = claims.Contacts
+	}
+
+	if claims.TermsOfServiceURI != "" {
+		data.TermsOfServiceURI = claims.TermsOfServiceURI
+	}
+
+	if claims.PolicyURI != "" {
+		data.PolicyURI = claims.PolicyURI
+	}
+
+	if claims.JSONWebKeysURI != "" {
+		data.JSONWebKeysURI = claims.JSONWebKeysURI
+	}
+
+	if len(claims.JSONWebKeys) > 0 {
+		data.JSONWebKeys = claims.JSONWebKeys
+	}
+
+	if claims.SoftwareID != "" {
+		data.SoftwareID = claims.SoftwareID
+	}
+
+	if claims.SoftwareVersion != "" {
+		data.SoftwareVersion = claims.SoftwareVersion
+	}
+
+	if claims.TokenEndpointAuthMethod != "" {
+		data.TokenEndpointAuthMethod = claims.TokenEndpointAuthMethod
+	}
+}
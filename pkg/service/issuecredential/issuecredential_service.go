@@ -10,13 +10,16 @@ package issuecredential
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/samber/lo"
 	"github.com/trustbloc/vc-go/verifiable"
 
 	"github.com/trustbloc/vcs/pkg/doc/vc"
 	"github.com/trustbloc/vcs/pkg/doc/vc/crypto"
 	"github.com/trustbloc/vcs/pkg/doc/vc/vcutil"
+	vcsverifiable "github.com/trustbloc/vcs/pkg/doc/verifiable"
 	vcskms "github.com/trustbloc/vcs/pkg/kms"
 	profileapi "github.com/trustbloc/vcs/pkg/profile"
 	"github.com/trustbloc/vcs/pkg/service/credentialstatus"
@@ -26,6 +29,10 @@ const (
 	defaultCredentialPrefix = "urn:uuid:" //nolint:gosec
 )
 
+// ErrSigningAlgorithmNotAllowed is returned when the signing algorithm isn't allowed by the configuration
+// of the issued credential type.
+var ErrSigningAlgorithmNotAllowed = errors.New("signing algorithm not allowed")
+
 type vcCrypto interface {
 	SignCredential(signerData *vc.Signer, vc *verifiable.Credential,
 		opts ...crypto.SigningOpts) (*verifiable.Credential, error)
@@ -85,6 +92,12 @@ func (s *Service) IssueCredential(
 		DataIntegrityProof:      profile.VCConfig.DataIntegrityProof,
 	}
 
+	if conf := profile.GetCredentialConfiguration(credential.Types); conf != nil {
+		if err = applyCredentialConfiguration(signer, conf); err != nil {
+			return nil, err
+		}
+	}
+
 	var statusListEntry *credentialstatus.StatusListEntry
 
 	// update credential prefix.
@@ -101,7 +114,7 @@ func (s *Service) IssueCredential(
 	}
 
 	// update context
-	vcutil.UpdateSignatureTypeContext(credential, profile.VCConfig.SigningAlgorithm)
+	vcutil.UpdateSignatureTypeContext(credential, signer.SignatureType)
 
 	// update credential issuer
 	vcutil.UpdateIssuer(credential, profile.SigningDID.DID, profile.Name, true)
@@ -114,3 +127,34 @@ func (s *Service) IssueCredential(
 
 	return signedVC, nil
 }
+
+// applyCredentialConfiguration selects the signing key and algorithm of the credential type configuration.
+func applyCredentialConfiguration(signer *vc.Signer, conf *profileapi.CredentialConfiguration) error {
+	overridden := conf.SigningAlgorithm != ""
+
+	if conf.SigningKey != nil {
+		signer.Creator = conf.SigningKey.Creator
+		signer.KMSKeyID = conf.SigningKey.KMSKeyID
+
+		if conf.SigningKey.KeyType != "" {
+			signer.KeyType = conf.SigningKey.KeyType
+			overridden = true
+		}
+	}
+
+	if conf.SigningAlgorithm != "" {
+		signer.SignatureType = conf.SigningAlgorithm
+	}
+
+	if len(conf.AllowedAlgorithms) > 0 && !lo.Contains(conf.AllowedAlgorithms, signer.SignatureType) {
+		return fmt.Errorf("%w: %s", ErrSigningAlgorithmNotAllowed, signer.SignatureType)
+	}
+
+	if overridden {
+		if _, err := vcsverifiable.ValidateSignatureKeyType(signer.SignatureType, string(signer.KeyType)); err != nil {
+			return fmt.Errorf("credential configuration: %w", err)
+		}
+	}
+
+	return nil
+}
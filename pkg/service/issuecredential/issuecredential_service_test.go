@@ -211,6 +211,154 @@ func TestService_IssueCredential(t *testing.T) {
 		}
 	})
 
+	t.Run("Success credential configuration", func(t *testing.T) {
+		keyID, _, err := customKMS.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		didDoc := createDIDDoc("did:trustblock:abc", keyID)
+		crypto := vccrypto.New(
+			&vdrmock.VDRegistry{ResolveValue: didDoc}, testutil.DocumentLoader(t))
+
+		service := issuecredential.New(&issuecredential.Config{
+			VCStatusManager: mockVCStatusManager,
+			Crypto:          crypto,
+			KMSRegistry:     kmsRegistry,
+		})
+
+		credential := getVC()
+		credential.Types = append(credential.Types, "UniversityDegreeCredential")
+
+		verifiableCredentials, err := service.IssueCredential(
+			ctx,
+			credential,
+			nil,
+			&profileapi.Issuer{
+				VCConfig: &profileapi.VCConfig{
+					SigningAlgorithm: vcs.JSONWebSignature2020,
+					Format:           vcs.Jwt,
+					KeyType:          kms.ED25519Type,
+				},
+				SigningDID: &profileapi.SigningDID{
+					DID:      didDoc.ID,
+					Creator:  didDoc.VerificationMethod[0].ID,
+					KMSKeyID: keyID,
+				},
+				CredentialConfigurations: map[string]profileapi.CredentialConfiguration{
+					"UniversityDegreeCredential": {
+						AllowedAlgorithms: []vcs.SignatureType{vcs.JSONWebSignature2020},
+					},
+				}},
+		)
+		require.NoError(t, err)
+		validateVC(t, verifiableCredentials, didDoc, 0, vcs.Jwt)
+	})
+
+	t.Run("Success credential configuration signing key", func(t *testing.T) {
+		profileKeyID, _, err := customKMS.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		typeKeyID, _, err := customKMS.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		didDoc := createDIDDoc("did:trustblock:abc", typeKeyID)
+		crypto := vccrypto.New(
+			&vdrmock.VDRegistry{ResolveValue: didDoc}, testutil.DocumentLoader(t))
+
+		service := issuecredential.New(&issuecredential.Config{
+			VCStatusManager: mockVCStatusManager,
+			Crypto:          crypto,
+			KMSRegistry:     kmsRegistry,
+		})
+
+		verifiableCredentials, err := service.IssueCredential(
+			ctx,
+			getVC(),
+			nil,
+			&profileapi.Issuer{
+				VCConfig: &profileapi.VCConfig{
+					SigningAlgorithm:        vcs.EcdsaSecp256k1Signature2019,
+					SignatureRepresentation: verifiable.SignatureJWS,
+					Format:                  vcs.Ldp,
+					KeyType:                 kms.ECDSASecp256k1TypeIEEEP1363,
+				},
+				SigningDID: &profileapi.SigningDID{
+					DID:      didDoc.ID,
+					Creator:  didDoc.ID + "#" + profileKeyID,
+					KMSKeyID: profileKeyID,
+				},
+				CredentialConfigurations: map[string]profileapi.CredentialConfiguration{
+					verifiable.VCType: {
+						SigningKey: &profileapi.CredentialSigningKey{
+							Creator:  didDoc.VerificationMethod[0].ID,
+							KMSKeyID: typeKeyID,
+							KeyType:  kms.ED25519Type,
+						},
+						SigningAlgorithm:  vcs.JSONWebSignature2020,
+						AllowedAlgorithms: []vcs.SignatureType{vcs.JSONWebSignature2020},
+					},
+				}},
+		)
+		require.NoError(t, err)
+		validateVC(t, verifiableCredentials, didDoc, verifiable.SignatureJWS, vcs.Ldp)
+	})
+
+	t.Run("Error signing algorithm not supported by credential configuration key type", func(t *testing.T) {
+		service := issuecredential.New(&issuecredential.Config{
+			VCStatusManager: mockVCStatusManager,
+			KMSRegistry:     kmsRegistry,
+		})
+
+		verifiableCredentials, err := service.IssueCredential(
+			ctx,
+			getVC(),
+			nil,
+			&profileapi.Issuer{
+				VCConfig: &profileapi.VCConfig{
+					SigningAlgorithm: vcs.Ed25519Signature2018,
+					Format:           vcs.Ldp,
+					KeyType:          kms.ED25519Type,
+				},
+				SigningDID: &profileapi.SigningDID{},
+				CredentialConfigurations: map[string]profileapi.CredentialConfiguration{
+					verifiable.VCType: {
+						SigningKey: &profileapi.CredentialSigningKey{
+							Creator:  "did:trustblock:abc#key-2",
+							KMSKeyID: "key-2",
+							KeyType:  kms.ECDSAP256TypeIEEEP1363,
+						},
+					},
+				}},
+		)
+		require.ErrorContains(t, err, "credential configuration")
+		require.Nil(t, verifiableCredentials)
+	})
+
+	t.Run("Error signing algorithm not allowed by credential configuration", func(t *testing.T) {
+		service := issuecredential.New(&issuecredential.Config{
+			VCStatusManager: mockVCStatusManager,
+			KMSRegistry:     kmsRegistry,
+		})
+
+		verifiableCredentials, err := service.IssueCredential(
+			ctx,
+			getVC(),
+			nil,
+			&profileapi.Issuer{
+				VCConfig: &profileapi.VCConfig{
+					SigningAlgorithm: vcs.JSONWebSignature2020,
+					Format:           vcs.Ldp,
+				},
+				SigningDID: &profileapi.SigningDID{},
+				CredentialConfigurations: map[string]profileapi.CredentialConfiguration{
+					verifiable.VCType: {
+						AllowedAlgorithms: []vcs.SignatureType{vcs.Ed25519Signature2018},
+					},
+				}},
+		)
+		require.ErrorIs(t, err, issuecredential.ErrSigningAlgorithmNotAllowed)
+		require.Nil(t, verifiableCredentials)
+	})
+
 	t.Run("Error kmsRegistry", func(t *testing.T) {
 		registry := NewMockKMSRegistry(gomock.NewController(t))
 		registry.EXPECT().GetKeyManager(gomock.Any()).AnyTimes().Return(nil, errors.New("some error"))
@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/go-jose/go-jose/v3"
 	"github.com/labstack/echo/v4"
 	"github.com/trustbloc/vc-go/verifiable"
 
@@ -112,6 +113,21 @@ type OIDCConfiguration struct {
 	InitiateIssuanceEndpoint           string   `json:"initiate_issuance_endpoint"`
 }
 
+// WalletMetadata represents a wallet's OpenID configuration discovered via wallet_issuer
+// (<wallet_issuer>/.well-known/openid-configuration).
+type WalletMetadata struct {
+	Issuer                             string   `json:"issuer"`
+	AuthorizationEndpoint              string   `json:"authorization_endpoint"`
+	PushedAuthorizationRequestEndpoint string   `json:"pushed_authorization_request_endpoint"`
+	RequirePushedAuthorizationRequests bool     `json:"require_pushed_authorization_requests"`
+	ResponseTypesSupported             []string `json:"response_types_supported"`
+}
+
+// SupportsPushedAuthorizationRequests returns true if the wallet supports pushed authorization requests.
+func (m *WalletMetadata) SupportsPushedAuthorizationRequests() bool {
+	return m.PushedAuthorizationRequestEndpoint != "" || m.RequirePushedAuthorizationRequests
+}
+
 // InitiateIssuanceRequest is the request used by the Issuer to initiate the OIDC VC issuance interaction.
 type InitiateIssuanceRequest struct {
 	CredentialTemplateID      string
@@ -145,6 +161,7 @@ type PrepareClaimDataAuthorizationRequest struct {
 	Scope                []string
 	OpState              string
 	AuthorizationDetails *AuthorizationDetails
+	WalletIssuer         string
 }
 
 type PrepareClaimDataAuthorizationResponse struct {
@@ -156,14 +173,50 @@ type PrepareClaimDataAuthorizationResponse struct {
 	Scope                              []string
 	AuthorizationEndpoint              string
 	PushedAuthorizationRequestEndpoint string
+	WalletPushedAuthorizationSupported bool
+}
+
+// JWTProof is a proof of possession of key material in the form of a signed JWT.
+type JWTProof struct {
+	JWT       string
+	ProofType string
+}
+
+// OIDCDiscoveryDocument is the OpenID Provider metadata of an issuer profile.
+// Refer to https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata for more details.
+type OIDCDiscoveryDocument struct {
+	Issuer                                     string   `json:"issuer"`
+	AuthorizationEndpoint                      string   `json:"authorization_endpoint"`
+	TokenEndpoint                              string   `json:"token_endpoint"`
+	PushedAuthorizationRequestEndpoint         string   `json:"pushed_authorization_request_endpoint"`
+	RegistrationEndpoint                       string   `json:"registration_endpoint,omitempty"`
+	ScopesSupported                            []string `json:"scopes_supported,omitempty"`
+	ResponseTypesSupported                     []string `json:"response_types_supported"`
+	GrantTypesSupported                        []string `json:"grant_types_supported,omitempty"`
+	TokenEndpointAuthMethodsSupported          []string `json:"token_endpoint_auth_methods_supported,omitempty"`
+	PreAuthorizedGrantAnonymousAccessSupported bool     `json:"pre-authorized_grant_anonymous_access_supported"`
+	WalletInitiatedAuthFlowSupported           bool     `json:"wallet_initiated_auth_flow_supported"`
+}
+
+// CredentialIssuerMetadata is the metadata of the credential issuer of a profile.
+// Refer to https://openid.net/specs/openid-4-verifiable-credential-issuance-1_0.html#name-credential-issuer-metadata.
+type CredentialIssuerMetadata struct {
+	CredentialIssuer             string                   `json:"credential_issuer"`
+	AuthorizationEndpoint        string                   `json:"authorization_endpoint"`
+	CredentialEndpoint           string                   `json:"credential_endpoint"`
+	CredentialsSupported         []map[string]interface{} `json:"credentials_supported"`
+	CryptographicSuitesSupported []string                 `json:"cryptographic_suites_supported,omitempty"`
 }
 
 type PrepareCredential struct {
 	TxID             TxID
 	CredentialTypes  []string
 	CredentialFormat vcsverifiable.Format
-	DID              string
-	AudienceClaim    string
+	// CredentialIdentifier references an entry of the profile credentials_supported metadata. If set, the
+	// credential format and types are taken from that entry.
+	CredentialIdentifier string
+	DID                  string
+	AudienceClaim        string
 }
 
 type PrepareCredentialResult struct {
@@ -187,6 +240,8 @@ type AuthorizeState struct {
 	Header              map[string][]string             `json:"header"`
 	Parameters          map[string][]string             `json:"parameters"`
 	WalletInitiatedFlow *common.WalletInitiatedFlowData `json:"wallet_initiated_flow"`
+	// SessionBinding is the value of the session cookie set on the user agent that started the authorization.
+	SessionBinding string `json:"session_binding,omitempty"`
 }
 
 type eventPayload struct {
@@ -248,4 +303,16 @@ type ServiceInterface interface {
 		clientID string,
 	) (*Transaction, error)
 	PrepareCredential(ctx context.Context, req *PrepareCredential) (*PrepareCredentialResult, error)
+	VerifyProofOfPossession(
+		ctx context.Context,
+		proof *JWTProof,
+		clientID string,
+		txID TxID,
+		nonce string,
+	) (*jose.JSONWebKey, error)
+	GetDiscoveryDocument(ctx context.Context, profileID, profileVersion string) (*OIDCDiscoveryDocument, error)
+	BuildCredentialIssuerMetadata(
+		ctx context.Context,
+		profileID, profileVersion string,
+	) (*CredentialIssuerMetadata, error)
 }
@@ -20,6 +20,13 @@ var (
 	ErrInvalidScope                    = errors.New("invalid scope")
 	ErrCredentialTypeNotSupported      = errors.New("credential type not supported")
 	ErrCredentialFormatNotSupported    = errors.New("credential format not supported")
+	ErrCredentialIdentifierNotFound    = errors.New("credential identifier not found")
 	ErrVCOptionsNotConfigured          = errors.New("vc options not configured")
 	ErrInvalidIssuerURL                = errors.New("invalid issuer url")
+	ErrInvalidProof                    = errors.New("invalid proof")
+	ErrProofExpired                    = errors.New("proof expired")
+	ErrInvalidProofNonce               = errors.New("invalid proof nonce")
+	ErrInvalidProofAudience            = errors.New("invalid proof audience")
+	ErrProofKeyNotFound                = errors.New("proof key not found")
+	ErrOIDCNotConfigured               = errors.New("oidc not configured")
 )
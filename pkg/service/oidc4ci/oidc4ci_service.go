@@ -4,7 +4,7 @@ Copyright SecureKey Technologies Inc. All Rights Reserved.
 SPDX-License-Identifier: Apache-2.0
 */
 
-//go:generate mockgen -destination oidc4ci_service_mocks_test.go -self_package mocks -package oidc4ci_test -source=oidc4ci_service.go -mock_names transactionStore=MockTransactionStore,wellKnownService=MockWellKnownService,eventService=MockEventService,pinGenerator=MockPinGenerator,credentialOfferReferenceStore=MockCredentialOfferReferenceStore,claimDataStore=MockClaimDataStore,profileService=MockProfileService,dataProtector=MockDataProtector,kmsRegistry=MockKMSRegistry,cryptoJWTSigner=MockCryptoJWTSigner
+//go:generate mockgen -destination oidc4ci_service_mocks_test.go -self_package mocks -package oidc4ci_test -source=oidc4ci_service.go -mock_names transactionStore=MockTransactionStore,wellKnownService=MockWellKnownService,eventService=MockEventService,pinGenerator=MockPinGenerator,credentialOfferReferenceStore=MockCredentialOfferReferenceStore,claimDataStore=MockClaimDataStore,profileService=MockProfileService,dataProtector=MockDataProtector,kmsRegistry=MockKMSRegistry,cryptoJWTSigner=MockCryptoJWTSigner,clientManager=MockClientManager
 
 package oidc4ci
 
@@ -15,10 +15,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ory/fosite"
 	util "github.com/trustbloc/did-go/doc/util/time"
 	"github.com/trustbloc/logutil-go/pkg/log"
 	"github.com/trustbloc/vc-go/verifiable"
@@ -112,6 +114,15 @@ type cryptoJWTSigner interface {
 	NewJWTSigned(claims interface{}, signerData *vc.Signer) (string, error)
 }
 
+type clientManager interface {
+	Get(ctx context.Context, id string) (fosite.Client, error)
+}
+
+// WalletDiscoveryClient fetches wallet metadata from the wallet's OpenID configuration.
+type WalletDiscoveryClient interface {
+	FetchWalletMetadata(ctx context.Context, walletIssuerURL string) (*WalletMetadata, error)
+}
+
 // Config holds configuration options and dependencies for Service.
 type Config struct {
 	TransactionStore              transactionStore
@@ -128,6 +139,8 @@ type Config struct {
 	DataProtector                 dataProtector
 	KMSRegistry                   kmsRegistry
 	CryptoJWTSigner               cryptoJWTSigner
+	WalletDiscoveryClient         WalletDiscoveryClient // optional
+	ClientManager                 clientManager
 }
 
 // Service implements VCS credential interaction API for OIDC credential issuance.
@@ -146,6 +159,8 @@ type Service struct {
 	dataProtector                 dataProtector
 	kmsRegistry                   kmsRegistry
 	cryptoJWTSigner               cryptoJWTSigner
+	walletDiscoveryClient         WalletDiscoveryClient // optional
+	clientManager                 clientManager
 }
 
 // NewService returns a new Service instance.
@@ -165,6 +180,8 @@ func NewService(config *Config) (*Service, error) {
 		dataProtector:                 config.DataProtector,
 		kmsRegistry:                   config.KMSRegistry,
 		cryptoJWTSigner:               config.CryptoJWTSigner,
+		walletDiscoveryClient:         config.WalletDiscoveryClient,
+		clientManager:                 config.ClientManager,
 	}, nil
 }
 
@@ -210,6 +227,39 @@ func (s *Service) checkScopes(reqScopes []string, txScopes []string) error {
 func (s *Service) PrepareClaimDataAuthorizationRequest(
 	ctx context.Context,
 	req *PrepareClaimDataAuthorizationRequest,
+) (*PrepareClaimDataAuthorizationResponse, error) {
+	resp, err := s.prepareClaimDataAuthorizationRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.WalletIssuer != "" {
+		resp.WalletPushedAuthorizationSupported = s.walletSupportsPushedAuthorization(ctx, req.WalletIssuer)
+	}
+
+	return resp, nil
+}
+
+// walletSupportsPushedAuthorization discovers wallet capabilities using wallet_issuer. Discovery failure is not
+// fatal for the authorization flow, so the wallet is then considered as not supporting pushed authorization requests.
+func (s *Service) walletSupportsPushedAuthorization(ctx context.Context, walletIssuer string) bool {
+	if s.walletDiscoveryClient == nil {
+		return false
+	}
+
+	metadata, err := s.walletDiscoveryClient.FetchWalletMetadata(ctx, walletIssuer)
+	if err != nil {
+		logger.Warnc(ctx, "Failed to fetch wallet metadata", log.WithURL(walletIssuer), log.WithError(err))
+
+		return false
+	}
+
+	return metadata.SupportsPushedAuthorizationRequests()
+}
+
+func (s *Service) prepareClaimDataAuthorizationRequest(
+	ctx context.Context,
+	req *PrepareClaimDataAuthorizationRequest,
 ) (*PrepareClaimDataAuthorizationResponse, error) {
 	tx, err := s.store.FindByOpState(ctx, req.OpState)
 
@@ -381,9 +431,9 @@ func (s *Service) ValidatePreAuthorizedCodeRequest( //nolint:gocognit,nolintlint
 			fmt.Errorf("server expects user pin"))
 	}
 
-	if clientID == "" {
-		var profile *profileapi.Issuer
+	var profile *profileapi.Issuer
 
+	if clientID == "" {
 		profile, err = s.profileService.GetProfile(tx.ProfileID, tx.ProfileVersion)
 		if err != nil {
 			return nil, err
@@ -398,7 +448,9 @@ func (s *Service) ValidatePreAuthorizedCodeRequest( //nolint:gocognit,nolintlint
 
 	newState := TransactionStatePreAuthCodeValidated
 	if err = s.validateStateTransition(tx.State, newState); err != nil {
-		return nil, err
+		// the pre-authorized code is single use, any state other than the initial one means it was already redeemed
+		return nil, resterr.NewCustomError(resterr.OIDCTxNotFound,
+			fmt.Errorf("pre-authorization code already used: %w", err))
 	}
 	tx.State = newState
 
@@ -410,8 +462,21 @@ func (s *Service) ValidatePreAuthorizedCodeRequest( //nolint:gocognit,nolintlint
 		return nil, resterr.NewCustomError(resterr.OIDCTxNotFound, fmt.Errorf("invalid pre-authorization code"))
 	}
 
-	if len(tx.UserPin) > 0 && !s.pinGenerator.Validate(tx.UserPin, pin) {
-		return nil, resterr.NewCustomError(resterr.OIDCPreAuthorizeInvalidPin, fmt.Errorf("invalid pin"))
+	if len(tx.UserPin) > 0 {
+		if profile == nil {
+			profile, err = s.profileService.GetProfile(tx.ProfileID, tx.ProfileVersion)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if err = validateUserPin(pin, profile.OIDCConfig); err != nil {
+			return nil, err
+		}
+
+		if !s.pinGenerator.Validate(tx.UserPin, pin) {
+			return nil, resterr.NewCustomError(resterr.OIDCPreAuthorizeInvalidPin, fmt.Errorf("invalid pin"))
+		}
 	}
 
 	if err = s.store.Update(ctx, tx); err != nil {
@@ -425,6 +490,27 @@ func (s *Service) ValidatePreAuthorizedCodeRequest( //nolint:gocognit,nolintlint
 	return tx, nil
 }
 
+// validateUserPin checks that pin meets the length and pattern requirements of the profile.
+func validateUserPin(pin string, oidcConfig *profileapi.OIDCConfig) error {
+	pattern, err := regexp.Compile("^(?:" + oidcConfig.GetUserPinPattern() + ")$")
+	if err != nil {
+		return fmt.Errorf("compile user pin pattern: %w", err)
+	}
+
+	if len(pin) < oidcConfig.GetMinUserPinLength() || !pattern.MatchString(pin) {
+		return resterr.NewCustomError(resterr.OIDCPreAuthorizeWeakPin,
+			errors.New("user_pin does not meet security requirements"))
+	}
+
+	return nil
+}
+
+// issuerAudience returns the credential issuer identifier of the transaction profile, expected in "aud" of
+// the credential request proof.
+func (s *Service) issuerAudience(tx *Transaction) string {
+	return fmt.Sprintf("%v/issuer/%s/%s", s.issuerVCSPublicHost, tx.ProfileID, tx.ProfileVersion)
+}
+
 func (s *Service) PrepareCredential(
 	ctx context.Context,
 	req *PrepareCredential,
@@ -439,13 +525,28 @@ func (s *Service) PrepareCredential(
 		return nil, resterr.NewCustomError(resterr.OIDCCredentialTypeNotSupported, ErrCredentialTemplateNotConfigured)
 	}
 
-	expectedAudience := fmt.Sprintf("%v/issuer/%s/%s", s.issuerVCSPublicHost, tx.ProfileID, tx.ProfileVersion)
-
-	if req.AudienceClaim == "" || req.AudienceClaim != expectedAudience {
+	if req.AudienceClaim == "" || req.AudienceClaim != s.issuerAudience(tx) {
 		return nil, resterr.NewValidationError(resterr.InvalidOrMissingProofOIDCErr, req.AudienceClaim,
 			errors.New("invalid aud"))
 	}
 
+	credentialFormat := req.CredentialFormat
+
+	if req.CredentialIdentifier != "" {
+		if credentialFormat, err = s.credentialFormatByIdentifier(tx, req.CredentialIdentifier); err != nil {
+			s.sendFailedTransactionEvent(ctx, tx, err)
+			return nil, err
+		}
+	}
+
+	// The credential is signed in the format of the issuer profile, so a request for any other format can't be served.
+	if credentialFormat != "" && credentialFormat != tx.CredentialFormat {
+		s.sendFailedTransactionEvent(ctx, tx, ErrCredentialFormatNotSupported)
+		return nil, resterr.NewCustomError(resterr.OIDCCredentialFormatNotSupported,
+			fmt.Errorf("%w: requested %s, issuer profile supports %s",
+				ErrCredentialFormatNotSupported, credentialFormat, tx.CredentialFormat))
+	}
+
 	claimData, err := s.getClaimsData(ctx, tx)
 	if err != nil {
 		return nil, err
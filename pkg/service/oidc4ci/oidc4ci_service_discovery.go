@@ -0,0 +1,121 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4ci
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// defaultResponseTypesSupported are the response types advertised when the profile does not configure them.
+var defaultResponseTypesSupported = []string{"code"}
+
+// GetDiscoveryDocument returns the OpenID Provider metadata of the issuer profile. The document is built from
+// the OIDC config of the profile; endpoints are located on the public host of the issuer.
+func (s *Service) GetDiscoveryDocument(
+	_ context.Context,
+	profileID, profileVersion string,
+) (*OIDCDiscoveryDocument, error) {
+	profile, err := s.profileService.GetProfile(profileID, profileVersion)
+	if err != nil {
+		return nil, fmt.Errorf("get profile: %w", err)
+	}
+
+	if !profile.Active {
+		return nil, ErrProfileNotActive
+	}
+
+	if profile.OIDCConfig == nil {
+		return nil, fmt.Errorf("%w: profile %s", ErrOIDCNotConfigured, profileID)
+	}
+
+	issuer, err := url.JoinPath(s.issuerVCSPublicHost, "oidc", profileID, profileVersion)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIssuerURL, err)
+	}
+
+	oidcConfig := profile.OIDCConfig
+
+	doc := &OIDCDiscoveryDocument{
+		Issuer:                                     issuer,
+		AuthorizationEndpoint:                      s.issuerVCSPublicHost + "/oidc/authorize",
+		TokenEndpoint:                              s.issuerVCSPublicHost + "/oidc/token",
+		PushedAuthorizationRequestEndpoint:         s.issuerVCSPublicHost + "/oidc/par",
+		ScopesSupported:                            oidcConfig.ScopesSupported,
+		ResponseTypesSupported:                     oidcConfig.ResponseTypesSupported,
+		GrantTypesSupported:                        oidcConfig.GrantTypesSupported,
+		TokenEndpointAuthMethodsSupported:          oidcConfig.TokenEndpointAuthMethodsSupported,
+		PreAuthorizedGrantAnonymousAccessSupported: oidcConfig.PreAuthorizedGrantAnonymousAccessSupported,
+		WalletInitiatedAuthFlowSupported:           oidcConfig.WalletInitiatedAuthFlowSupported,
+	}
+
+	if len(doc.ResponseTypesSupported) == 0 {
+		doc.ResponseTypesSupported = defaultResponseTypesSupported
+	}
+
+	if oidcConfig.EnableDynamicClientRegistration {
+		doc.RegistrationEndpoint = issuer + "/register"
+	}
+
+	return doc, nil
+}
+
+// BuildCredentialIssuerMetadata returns the credential issuer metadata of the issuer profile. Credentials supported
+// by the profile are advertised with the DID method and the signing algorithm of its VC config.
+func (s *Service) BuildCredentialIssuerMetadata(
+	_ context.Context,
+	profileID, profileVersion string,
+) (*CredentialIssuerMetadata, error) {
+	profile, err := s.profileService.GetProfile(profileID, profileVersion)
+	if err != nil {
+		return nil, fmt.Errorf("get profile: %w", err)
+	}
+
+	if !profile.Active {
+		return nil, ErrProfileNotActive
+	}
+
+	if profile.VCConfig == nil {
+		return nil, ErrVCOptionsNotConfigured
+	}
+
+	issuerURL, err := url.JoinPath(s.issuerVCSPublicHost, "issuer", profileID, profileVersion)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIssuerURL, err)
+	}
+
+	suites := []string{string(profile.VCConfig.SigningAlgorithm)}
+
+	metadata := &CredentialIssuerMetadata{
+		CredentialIssuer:             issuerURL,
+		AuthorizationEndpoint:        s.issuerVCSPublicHost + "/oidc/authorize",
+		CredentialEndpoint:           s.issuerVCSPublicHost + "/oidc/credential",
+		CredentialsSupported:         []map[string]interface{}{},
+		CryptographicSuitesSupported: suites,
+	}
+
+	if profile.CredentialMetaData == nil {
+		return metadata, nil
+	}
+
+	for _, supported := range profile.CredentialMetaData.CredentialsSupported {
+		// copy, so that the profile is not modified
+		credential := make(map[string]interface{}, len(supported)+2)
+
+		for k, v := range supported {
+			credential[k] = v
+		}
+
+		credential["cryptographic_binding_methods_supported"] = []string{string(profile.VCConfig.DIDMethod)}
+		credential["cryptographic_suites_supported"] = suites
+
+		metadata.CredentialsSupported = append(metadata.CredentialsSupported, credential)
+	}
+
+	return metadata, nil
+}
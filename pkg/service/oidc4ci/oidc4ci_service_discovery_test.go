@@ -0,0 +1,177 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4ci_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	vcsverifiable "github.com/trustbloc/vcs/pkg/doc/verifiable"
+	profileapi "github.com/trustbloc/vcs/pkg/profile"
+	"github.com/trustbloc/vcs/pkg/service/oidc4ci"
+)
+
+func TestService_GetDiscoveryDocument(t *testing.T) {
+	const (
+		host           = "https://vcs.example.com"
+		profileID      = "test-profile"
+		profileVersion = "v1.0"
+	)
+
+	newService := func(t *testing.T, profile *profileapi.Issuer, err error) *oidc4ci.Service {
+		t.Helper()
+
+		profileService := NewMockProfileService(gomock.NewController(t))
+		profileService.EXPECT().GetProfile(profileID, profileVersion).Return(profile, err)
+
+		svc, svcErr := oidc4ci.NewService(&oidc4ci.Config{
+			ProfileService:      profileService,
+			IssuerVCSPublicHost: host,
+		})
+		require.NoError(t, svcErr)
+
+		return svc
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		svc := newService(t, &profileapi.Issuer{
+			ID:      profileID,
+			Version: profileVersion,
+			Active:  true,
+			OIDCConfig: &profileapi.OIDCConfig{
+				ScopesSupported:                            []string{"openid", "profile"},
+				GrantTypesSupported:                        []string{"authorization_code"},
+				TokenEndpointAuthMethodsSupported:          []string{"none"},
+				EnableDynamicClientRegistration:            true,
+				PreAuthorizedGrantAnonymousAccessSupported: true,
+			},
+		}, nil)
+
+		doc, err := svc.GetDiscoveryDocument(context.Background(), profileID, profileVersion)
+		require.NoError(t, err)
+		require.Equal(t, &oidc4ci.OIDCDiscoveryDocument{
+			Issuer:                                     host + "/oidc/" + profileID + "/" + profileVersion,
+			AuthorizationEndpoint:                      host + "/oidc/authorize",
+			TokenEndpoint:                              host + "/oidc/token",
+			PushedAuthorizationRequestEndpoint:         host + "/oidc/par",
+			RegistrationEndpoint:                       host + "/oidc/" + profileID + "/" + profileVersion + "/register",
+			ScopesSupported:                            []string{"openid", "profile"},
+			ResponseTypesSupported:                     []string{"code"},
+			GrantTypesSupported:                        []string{"authorization_code"},
+			TokenEndpointAuthMethodsSupported:          []string{"none"},
+			PreAuthorizedGrantAnonymousAccessSupported: true,
+		}, doc)
+	})
+
+	t.Run("Profile not found", func(t *testing.T) {
+		_, err := newService(t, nil, errors.New("not found")).
+			GetDiscoveryDocument(context.Background(), profileID, profileVersion)
+		require.ErrorContains(t, err, "get profile: not found")
+	})
+
+	t.Run("Profile not active", func(t *testing.T) {
+		_, err := newService(t, &profileapi.Issuer{OIDCConfig: &profileapi.OIDCConfig{}}, nil).
+			GetDiscoveryDocument(context.Background(), profileID, profileVersion)
+		require.ErrorIs(t, err, oidc4ci.ErrProfileNotActive)
+	})
+
+	t.Run("OIDC not configured", func(t *testing.T) {
+		_, err := newService(t, &profileapi.Issuer{Active: true}, nil).
+			GetDiscoveryDocument(context.Background(), profileID, profileVersion)
+		require.ErrorIs(t, err, oidc4ci.ErrOIDCNotConfigured)
+	})
+}
+
+func TestService_BuildCredentialIssuerMetadata(t *testing.T) {
+	const (
+		host           = "https://vcs.example.com"
+		profileID      = "test-profile"
+		profileVersion = "v1.0"
+	)
+
+	newService := func(t *testing.T, profile *profileapi.Issuer, err error) *oidc4ci.Service {
+		t.Helper()
+
+		profileService := NewMockProfileService(gomock.NewController(t))
+		profileService.EXPECT().GetProfile(profileID, profileVersion).Return(profile, err)
+
+		svc, svcErr := oidc4ci.NewService(&oidc4ci.Config{
+			ProfileService:      profileService,
+			IssuerVCSPublicHost: host,
+		})
+		require.NoError(t, svcErr)
+
+		return svc
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		profile := &profileapi.Issuer{
+			Active: true,
+			VCConfig: &profileapi.VCConfig{
+				DIDMethod:        profileapi.KeyDIDMethod,
+				SigningAlgorithm: vcsverifiable.EdDSA,
+			},
+			CredentialMetaData: &profileapi.CredentialMetaData{
+				CredentialsSupported: []map[string]interface{}{
+					{"format": "jwt_vc_json", "types": []string{"VerifiableCredential", "PermanentResidentCard"}},
+				},
+			},
+		}
+
+		metadata, err := newService(t, profile, nil).
+			BuildCredentialIssuerMetadata(context.Background(), profileID, profileVersion)
+		require.NoError(t, err)
+		require.Equal(t, &oidc4ci.CredentialIssuerMetadata{
+			CredentialIssuer:      host + "/issuer/" + profileID + "/" + profileVersion,
+			AuthorizationEndpoint: host + "/oidc/authorize",
+			CredentialEndpoint:    host + "/oidc/credential",
+			CredentialsSupported: []map[string]interface{}{
+				{
+					"format": "jwt_vc_json",
+					"types":  []string{"VerifiableCredential", "PermanentResidentCard"},
+					"cryptographic_binding_methods_supported": []string{"key"},
+					"cryptographic_suites_supported":          []string{"EdDSA"},
+				},
+			},
+			CryptographicSuitesSupported: []string{"EdDSA"},
+		}, metadata)
+
+		require.NotContains(t, profile.CredentialMetaData.CredentialsSupported[0], "cryptographic_suites_supported")
+	})
+
+	t.Run("No credential metadata", func(t *testing.T) {
+		metadata, err := newService(t, &profileapi.Issuer{
+			Active:   true,
+			VCConfig: &profileapi.VCConfig{SigningAlgorithm: vcsverifiable.EdDSA},
+		}, nil).BuildCredentialIssuerMetadata(context.Background(), profileID, profileVersion)
+		require.NoError(t, err)
+		require.Empty(t, metadata.CredentialsSupported)
+		require.NotNil(t, metadata.CredentialsSupported)
+	})
+
+	t.Run("Profile not found", func(t *testing.T) {
+		_, err := newService(t, nil, errors.New("not found")).
+			BuildCredentialIssuerMetadata(context.Background(), profileID, profileVersion)
+		require.ErrorContains(t, err, "get profile: not found")
+	})
+
+	t.Run("Profile not active", func(t *testing.T) {
+		_, err := newService(t, &profileapi.Issuer{VCConfig: &profileapi.VCConfig{}}, nil).
+			BuildCredentialIssuerMetadata(context.Background(), profileID, profileVersion)
+		require.ErrorIs(t, err, oidc4ci.ErrProfileNotActive)
+	})
+
+	t.Run("VC options not configured", func(t *testing.T) {
+		_, err := newService(t, &profileapi.Issuer{Active: true}, nil).
+			BuildCredentialIssuerMetadata(context.Background(), profileID, profileVersion)
+		require.ErrorIs(t, err, oidc4ci.ErrVCOptionsNotConfigured)
+	})
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/trustbloc/vcs/pkg/doc/verifiable"
 	"github.com/trustbloc/vcs/pkg/event/spi"
 	profileapi "github.com/trustbloc/vcs/pkg/profile"
+	"github.com/trustbloc/vcs/pkg/restapi/resterr"
 )
 
 // InitiateIssuance creates credential issuance transaction and builds initiate issuance URL.
@@ -196,6 +197,41 @@ func (s *Service) SelectProperOIDCFormat(
 	return verifiable.JwtVCJson
 }
 
+// credentialFormatByIdentifier returns the format of the credential referenced by the credential_identifier in the
+// credentials_supported metadata of the transaction profile.
+func (s *Service) credentialFormatByIdentifier(
+	tx *Transaction,
+	credentialIdentifier string,
+) (verifiable.Format, error) {
+	profile, err := s.profileService.GetProfile(tx.ProfileID, tx.ProfileVersion)
+	if err != nil {
+		return "", fmt.Errorf("get profile: %w", err)
+	}
+
+	if profile.CredentialMetaData != nil {
+		for _, supported := range profile.CredentialMetaData.CredentialsSupported {
+			if id, _ := supported["id"].(string); id != credentialIdentifier {
+				continue
+			}
+
+			format, _ := supported["format"].(string)
+
+			switch verifiable.OIDCFormat(format) {
+			case verifiable.JwtVCJson, verifiable.JwtVCJsonLD:
+				return verifiable.Jwt, nil
+			case verifiable.LdpVC:
+				return verifiable.Ldp, nil
+			default:
+				return "", resterr.NewCustomError(resterr.OIDCCredentialFormatNotSupported,
+					fmt.Errorf("%w: %s", ErrCredentialFormatNotSupported, format))
+			}
+		}
+	}
+
+	return "", resterr.NewCustomError(resterr.OIDCInvalidCredentialRequest,
+		fmt.Errorf("%w: %s", ErrCredentialIdentifierNotFound, credentialIdentifier))
+}
+
 func (s *Service) GetCredentialsExpirationTime(
 	req *InitiateIssuanceRequest,
 	template *profileapi.CredentialTemplate,
@@ -186,6 +186,7 @@ func TestService_PrepareClaimDataAuthorizationRequest(t *testing.T) {
 	var (
 		mockTransactionStore = NewMockTransactionStore(gomock.NewController(t))
 		eventMock            = NewMockEventService(gomock.NewController(t))
+		walletDiscoveryMock  = NewMockWalletDiscoveryClient(gomock.NewController(t))
 		req                  *oidc4ci.PrepareClaimDataAuthorizationRequest
 	)
 
@@ -239,6 +240,88 @@ func TestService_PrepareClaimDataAuthorizationRequest(t *testing.T) {
 				require.NotNil(t, resp)
 			},
 		},
+		{
+			name: "Success with wallet issuer supporting PAR",
+			setup: func() {
+				mockTransactionStore.EXPECT().FindByOpState(gomock.Any(), "opState").Return(&oidc4ci.Transaction{
+					ID: "txID",
+					TransactionData: oidc4ci.TransactionData{
+						CredentialTemplate: &profileapi.CredentialTemplate{
+							Type: "UniversityDegreeCredential",
+						},
+						CredentialFormat: vcsverifiable.Ldp,
+						ResponseType:     "code",
+						Scope:            []string{"openid", "profile", "address"},
+						State:            oidc4ci.TransactionStateIssuanceInitiated,
+					},
+				}, nil)
+
+				mockTransactionStore.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+				eventMock.EXPECT().Publish(gomock.Any(), spi.IssuerEventTopic, gomock.Any()).Return(nil)
+
+				walletDiscoveryMock.EXPECT().FetchWalletMetadata(gomock.Any(), "https://wallet.example.com").
+					Return(&oidc4ci.WalletMetadata{
+						PushedAuthorizationRequestEndpoint: "https://wallet.example.com/par",
+					}, nil)
+
+				req = &oidc4ci.PrepareClaimDataAuthorizationRequest{
+					OpState:      "opState",
+					ResponseType: "code",
+					Scope:        []string{"openid", "profile"},
+					AuthorizationDetails: &oidc4ci.AuthorizationDetails{
+						Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+						Format: vcsverifiable.Ldp,
+					},
+					WalletIssuer: "https://wallet.example.com",
+				}
+			},
+			check: func(t *testing.T, resp *oidc4ci.PrepareClaimDataAuthorizationResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+				require.True(t, resp.WalletPushedAuthorizationSupported)
+			},
+		},
+		{
+			name: "Success with wallet metadata fetch failure",
+			setup: func() {
+				mockTransactionStore.EXPECT().FindByOpState(gomock.Any(), "opState").Return(&oidc4ci.Transaction{
+					ID: "txID",
+					TransactionData: oidc4ci.TransactionData{
+						CredentialTemplate: &profileapi.CredentialTemplate{
+							Type: "UniversityDegreeCredential",
+						},
+						CredentialFormat: vcsverifiable.Ldp,
+						ResponseType:     "code",
+						Scope:            []string{"openid", "profile", "address"},
+						State:            oidc4ci.TransactionStateIssuanceInitiated,
+					},
+				}, nil)
+
+				mockTransactionStore.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+				eventMock.EXPECT().Publish(gomock.Any(), spi.IssuerEventTopic, gomock.Any()).Return(nil)
+
+				walletDiscoveryMock.EXPECT().FetchWalletMetadata(gomock.Any(), "https://wallet.example.com").
+					Return(nil, errors.New("fetch error"))
+
+				req = &oidc4ci.PrepareClaimDataAuthorizationRequest{
+					OpState:      "opState",
+					ResponseType: "code",
+					Scope:        []string{"openid", "profile"},
+					AuthorizationDetails: &oidc4ci.AuthorizationDetails{
+						Types:  []string{"VerifiableCredential", "UniversityDegreeCredential"},
+						Format: vcsverifiable.Ldp,
+					},
+					WalletIssuer: "https://wallet.example.com",
+				}
+			},
+			check: func(t *testing.T, resp *oidc4ci.PrepareClaimDataAuthorizationResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+				require.False(t, resp.WalletPushedAuthorizationSupported)
+			},
+		},
 		{
 			name: "Failed sending event",
 			setup: func() {
@@ -491,9 +574,10 @@ func TestService_PrepareClaimDataAuthorizationRequest(t *testing.T) {
 			tt.setup()
 
 			svc, err := oidc4ci.NewService(&oidc4ci.Config{
-				TransactionStore: mockTransactionStore,
-				EventService:     eventMock,
-				EventTopic:       spi.IssuerEventTopic,
+				TransactionStore:      mockTransactionStore,
+				EventService:          eventMock,
+				EventTopic:            spi.IssuerEventTopic,
+				WalletDiscoveryClient: walletDiscoveryMock,
 			})
 			require.NoError(t, err)
 
@@ -875,13 +959,13 @@ func TestValidatePreAuthCode(t *testing.T) {
 		})
 		assert.NoError(t, err)
 
-		pinGenerator.EXPECT().Validate("567", "567").Return(true)
+		pinGenerator.EXPECT().Validate("5678", "5678").Return(true)
 		storeMock.EXPECT().FindByOpState(gomock.Any(), "1234").Return(&oidc4ci.Transaction{
 			TransactionData: oidc4ci.TransactionData{
 				State:                oidc4ci.TransactionStateIssuanceInitiated,
 				PreAuthCode:          "1234",
 				PreAuthCodeExpiresAt: lo.ToPtr(time.Now().UTC().Add(10 * time.Second)),
-				UserPin:              "567",
+				UserPin:              "5678",
 			},
 		}, nil)
 
@@ -903,7 +987,7 @@ func TestValidatePreAuthCode(t *testing.T) {
 			}, nil)
 
 		storeMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
-		resp, err := srv.ValidatePreAuthorizedCodeRequest(context.TODO(), "1234", "567", "")
+		resp, err := srv.ValidatePreAuthorizedCodeRequest(context.TODO(), "1234", "5678", "")
 		assert.NoError(t, err)
 		assert.NotNil(t, resp)
 	})
@@ -981,29 +1065,116 @@ func TestValidatePreAuthCode(t *testing.T) {
 	t.Run("invalid pin", func(t *testing.T) {
 		storeMock := NewMockTransactionStore(gomock.NewController(t))
 		pinGenerator := NewMockPinGenerator(gomock.NewController(t))
+		profileService := NewMockProfileService(gomock.NewController(t))
 
 		srv, err := oidc4ci.NewService(&oidc4ci.Config{
 			TransactionStore: storeMock,
 			PinGenerator:     pinGenerator,
+			ProfileService:   profileService,
 		})
 		assert.NoError(t, err)
 
-		pinGenerator.EXPECT().Validate("567", "111").Return(false)
+		pinGenerator.EXPECT().Validate("5678", "1111").Return(false)
+		profileService.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Return(&profileapi.Issuer{}, nil)
 
 		storeMock.EXPECT().FindByOpState(gomock.Any(), "1234").Return(&oidc4ci.Transaction{
 			TransactionData: oidc4ci.TransactionData{
 				PreAuthCode:          "1234",
 				PreAuthCodeExpiresAt: lo.ToPtr(time.Now().UTC().Add(10 * time.Second)),
-				UserPin:              "567",
+				UserPin:              "5678",
 				State:                oidc4ci.TransactionStateIssuanceInitiated,
 			},
 		}, nil)
 
-		resp, err := srv.ValidatePreAuthorizedCodeRequest(context.TODO(), "1234", "111", "123abc")
+		resp, err := srv.ValidatePreAuthorizedCodeRequest(context.TODO(), "1234", "1111", "123abc")
 		assert.ErrorContains(t, err, "invalid pin")
 		assert.Nil(t, resp)
 	})
 
+	t.Run("pin does not meet security requirements", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			pin        string
+			oidcConfig *profileapi.OIDCConfig
+		}{
+			{
+				name: "default min length",
+				pin:  "123",
+			},
+			{
+				name: "default pattern",
+				pin:  "12ab",
+			},
+			{
+				name:       "profile min length",
+				pin:        "123456",
+				oidcConfig: &profileapi.OIDCConfig{MinUserPinLength: 8},
+			},
+			{
+				name:       "profile pattern",
+				pin:        "123456",
+				oidcConfig: &profileapi.OIDCConfig{UserPinPattern: `[a-z]+`},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				storeMock := NewMockTransactionStore(gomock.NewController(t))
+				profileService := NewMockProfileService(gomock.NewController(t))
+
+				srv, err := oidc4ci.NewService(&oidc4ci.Config{
+					TransactionStore: storeMock,
+					ProfileService:   profileService,
+				})
+				assert.NoError(t, err)
+
+				profileService.EXPECT().GetProfile(gomock.Any(), gomock.Any()).
+					Return(&profileapi.Issuer{OIDCConfig: tt.oidcConfig}, nil)
+
+				storeMock.EXPECT().FindByOpState(gomock.Any(), "1234").Return(&oidc4ci.Transaction{
+					TransactionData: oidc4ci.TransactionData{
+						PreAuthCode:          "1234",
+						PreAuthCodeExpiresAt: lo.ToPtr(time.Now().UTC().Add(10 * time.Second)),
+						UserPin:              "5678",
+						State:                oidc4ci.TransactionStateIssuanceInitiated,
+					},
+				}, nil)
+
+				resp, err := srv.ValidatePreAuthorizedCodeRequest(context.TODO(), "1234", tt.pin, "123abc")
+				assert.ErrorContains(t, err,
+					"oidc-pre-authorize-weak-pin[]: user_pin does not meet security requirements")
+				assert.Nil(t, resp)
+			})
+		}
+	})
+
+	t.Run("invalid user pin pattern", func(t *testing.T) {
+		storeMock := NewMockTransactionStore(gomock.NewController(t))
+		profileService := NewMockProfileService(gomock.NewController(t))
+
+		srv, err := oidc4ci.NewService(&oidc4ci.Config{
+			TransactionStore: storeMock,
+			ProfileService:   profileService,
+		})
+		assert.NoError(t, err)
+
+		profileService.EXPECT().GetProfile(gomock.Any(), gomock.Any()).
+			Return(&profileapi.Issuer{OIDCConfig: &profileapi.OIDCConfig{UserPinPattern: `[`}}, nil)
+
+		storeMock.EXPECT().FindByOpState(gomock.Any(), "1234").Return(&oidc4ci.Transaction{
+			TransactionData: oidc4ci.TransactionData{
+				PreAuthCode:          "1234",
+				PreAuthCodeExpiresAt: lo.ToPtr(time.Now().UTC().Add(10 * time.Second)),
+				UserPin:              "5678",
+				State:                oidc4ci.TransactionStateIssuanceInitiated,
+			},
+		}, nil)
+
+		resp, err := srv.ValidatePreAuthorizedCodeRequest(context.TODO(), "1234", "5678", "123abc")
+		assert.ErrorContains(t, err, "compile user pin pattern")
+		assert.Nil(t, resp)
+	})
+
 	t.Run("fail find tx", func(t *testing.T) {
 		storeMock := NewMockTransactionStore(gomock.NewController(t))
 		srv, err := oidc4ci.NewService(&oidc4ci.Config{
@@ -1035,7 +1206,8 @@ func TestValidatePreAuthCode(t *testing.T) {
 		}, nil)
 
 		resp, err := srv.ValidatePreAuthorizedCodeRequest(context.TODO(), "1234", "567", "123abc")
-		assert.ErrorContains(t, err, "unexpected transaction from 5 to 2")
+		assert.ErrorContains(t, err,
+			"oidc-tx-not-found[]: pre-authorization code already used: unexpected transaction from 5 to 2")
 		assert.Nil(t, resp)
 	})
 
@@ -1210,6 +1382,7 @@ func TestService_PrepareCredential(t *testing.T) {
 		mockClaimDataStore   = NewMockClaimDataStore(gomock.NewController(t))
 		eventMock            = NewMockEventService(gomock.NewController(t))
 		crypto               = NewMockDataProtector(gomock.NewController(t))
+		profileService       = NewMockProfileService(gomock.NewController(t))
 		httpClient           *http.Client
 		req                  *oidc4ci.PrepareCredential
 	)
@@ -1629,6 +1802,180 @@ func TestService_PrepareCredential(t *testing.T) {
 				require.Nil(t, resp)
 			},
 		},
+		{
+			name: "Requested credential format not supported",
+			setup: func() {
+				mockTransactionStore.EXPECT().Get(gomock.Any(), oidc4ci.TxID("txID")).Return(&oidc4ci.Transaction{
+					TransactionData: oidc4ci.TransactionData{
+						CredentialTemplate: &profileapi.CredentialTemplate{
+							Type: "VerifiedEmployee",
+						},
+						CredentialFormat: vcsverifiable.Jwt,
+					},
+				}, nil)
+
+				eventMock.EXPECT().Publish(gomock.Any(), spi.IssuerEventTopic, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, topic string, messages ...*spi.Event) error {
+						assert.Len(t, messages, 1)
+						assert.Equal(t, messages[0].Type, spi.IssuerOIDCInteractionFailed)
+
+						return nil
+					})
+
+				req = &oidc4ci.PrepareCredential{
+					TxID:             "txID",
+					CredentialFormat: vcsverifiable.Ldp,
+					AudienceClaim:    "/issuer//",
+				}
+			},
+			check: func(t *testing.T, resp *oidc4ci.PrepareCredentialResult, err error) {
+				require.ErrorContains(t, err,
+					"oidc-credential-format-not-supported[]: credential format not supported: requested ldp, "+
+						"issuer profile supports jwt")
+				require.Nil(t, resp)
+			},
+		},
+		{
+			name: "Success with credential identifier",
+			setup: func() {
+				mockTransactionStore.EXPECT().Get(gomock.Any(), oidc4ci.TxID("txID")).Return(&oidc4ci.Transaction{
+					ID: "txID",
+					TransactionData: oidc4ci.TransactionData{
+						ProfileID:      "test_issuer",
+						ProfileVersion: "1.1",
+						IssuerToken:    "issuer-access-token",
+						CredentialTemplate: &profileapi.CredentialTemplate{
+							Type: "VerifiedEmployee",
+						},
+						CredentialFormat: vcsverifiable.Ldp,
+					},
+				}, nil)
+
+				profileService.EXPECT().GetProfile(profileapi.ID("test_issuer"), profileapi.Version("1.1")).
+					Return(&profileapi.Issuer{
+						CredentialMetaData: &profileapi.CredentialMetaData{
+							CredentialsSupported: []map[string]interface{}{
+								{"id": "VerifiedEmployee_JWT", "format": "jwt_vc_json"},
+								{"id": "VerifiedEmployee_LDP", "format": "ldp_vc"},
+							},
+						},
+					}, nil)
+
+				claimData := `{"surname":"Smith","givenName":"Pat","jobTitle":"Worker"}`
+
+				httpClient = &http.Client{
+					Transport: &mockTransport{
+						func(req *http.Request) (*http.Response, error) {
+							return &http.Response{
+								StatusCode: http.StatusOK,
+								Body:       io.NopCloser(bytes.NewBuffer([]byte(claimData))),
+							}, nil
+						},
+					},
+				}
+
+				mockTransactionStore.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+				eventMock.EXPECT().Publish(gomock.Any(), spi.IssuerEventTopic, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, topic string, messages ...*spi.Event) error {
+						assert.Len(t, messages, 1)
+						assert.Equal(t, messages[0].Type, spi.IssuerOIDCInteractionSucceeded)
+
+						return nil
+					})
+
+				req = &oidc4ci.PrepareCredential{
+					TxID:                 "txID",
+					CredentialIdentifier: "VerifiedEmployee_LDP",
+					AudienceClaim:        "/issuer/test_issuer/1.1",
+				}
+			},
+			check: func(t *testing.T, resp *oidc4ci.PrepareCredentialResult, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+			},
+		},
+		{
+			name: "Credential identifier references other format",
+			setup: func() {
+				mockTransactionStore.EXPECT().Get(gomock.Any(), oidc4ci.TxID("txID")).Return(&oidc4ci.Transaction{
+					TransactionData: oidc4ci.TransactionData{
+						ProfileID:      "test_issuer",
+						ProfileVersion: "1.1",
+						CredentialTemplate: &profileapi.CredentialTemplate{
+							Type: "VerifiedEmployee",
+						},
+						CredentialFormat: vcsverifiable.Ldp,
+					},
+				}, nil)
+
+				profileService.EXPECT().GetProfile(profileapi.ID("test_issuer"), profileapi.Version("1.1")).
+					Return(&profileapi.Issuer{
+						CredentialMetaData: &profileapi.CredentialMetaData{
+							CredentialsSupported: []map[string]interface{}{
+								{"id": "VerifiedEmployee_JWT", "format": "jwt_vc_json"},
+							},
+						},
+					}, nil)
+
+				eventMock.EXPECT().Publish(gomock.Any(), spi.IssuerEventTopic, gomock.Any()).Return(nil)
+
+				req = &oidc4ci.PrepareCredential{
+					TxID:                 "txID",
+					CredentialIdentifier: "VerifiedEmployee_JWT",
+					AudienceClaim:        "/issuer/test_issuer/1.1",
+				}
+			},
+			check: func(t *testing.T, resp *oidc4ci.PrepareCredentialResult, err error) {
+				require.ErrorContains(t, err,
+					"oidc-credential-format-not-supported[]: credential format not supported: requested jwt, "+
+						"issuer profile supports ldp")
+				require.Nil(t, resp)
+			},
+		},
+		{
+			name: "Credential identifier not found",
+			setup: func() {
+				mockTransactionStore.EXPECT().Get(gomock.Any(), oidc4ci.TxID("txID")).Return(&oidc4ci.Transaction{
+					TransactionData: oidc4ci.TransactionData{
+						ProfileID:      "test_issuer",
+						ProfileVersion: "1.1",
+						CredentialTemplate: &profileapi.CredentialTemplate{
+							Type: "VerifiedEmployee",
+						},
+						CredentialFormat: vcsverifiable.Jwt,
+					},
+				}, nil)
+
+				profileService.EXPECT().GetProfile(profileapi.ID("test_issuer"), profileapi.Version("1.1")).
+					Return(&profileapi.Issuer{
+						CredentialMetaData: &profileapi.CredentialMetaData{
+							CredentialsSupported: []map[string]interface{}{
+								{"id": "VerifiedEmployee_JWT", "format": "jwt_vc_json"},
+							},
+						},
+					}, nil)
+
+				eventMock.EXPECT().Publish(gomock.Any(), spi.IssuerEventTopic, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, topic string, messages ...*spi.Event) error {
+						assert.Len(t, messages, 1)
+						assert.Equal(t, messages[0].Type, spi.IssuerOIDCInteractionFailed)
+
+						return nil
+					})
+
+				req = &oidc4ci.PrepareCredential{
+					TxID:                 "txID",
+					CredentialIdentifier: "UnknownCredential",
+					AudienceClaim:        "/issuer/test_issuer/1.1",
+				}
+			},
+			check: func(t *testing.T, resp *oidc4ci.PrepareCredentialResult, err error) {
+				require.ErrorContains(t, err,
+					"oidc-invalid-credential-request[]: credential identifier not found: UnknownCredential")
+				require.Nil(t, resp)
+			},
+		},
 		{
 			name: "Fail to make request to claim endpoint",
 			setup: func() {
@@ -1796,6 +2143,7 @@ func TestService_PrepareCredential(t *testing.T) {
 				EventService:     eventMock,
 				EventTopic:       spi.IssuerEventTopic,
 				DataProtector:    crypto,
+				ProfileService:   profileService,
 			})
 			require.NoError(t, err)
 
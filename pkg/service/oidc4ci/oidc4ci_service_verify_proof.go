@@ -0,0 +1,114 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4ci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	josejwt "github.com/go-jose/go-jose/v3/jwt"
+
+	"github.com/trustbloc/vcs/pkg/oauth2client"
+)
+
+const jwtProofType = "jwt"
+
+type jwtProofClaims struct {
+	Issuer   string               `json:"iss,omitempty"`
+	Audience josejwt.Audience     `json:"aud,omitempty"`
+	IssuedAt *josejwt.NumericDate `json:"iat,omitempty"`
+	Expiry   *josejwt.NumericDate `json:"exp,omitempty"`
+	Nonce    string               `json:"nonce,omitempty"`
+}
+
+// VerifyProofOfPossession verifies the signature of the JWT proof against the key material registered for the
+// authenticated client and checks "iss", "nonce" and "aud" claims. The audience must be the credential issuer
+// identifier of the transaction profile. It returns the key the proof was verified with.
+func (s *Service) VerifyProofOfPossession(
+	ctx context.Context,
+	proof *JWTProof,
+	clientID string,
+	txID TxID,
+	nonce string,
+) (*jose.JSONWebKey, error) {
+	if proof == nil || proof.ProofType != jwtProofType {
+		return nil, fmt.Errorf("%w: unsupported proof type", ErrInvalidProof)
+	}
+
+	jws, err := jose.ParseSigned(proof.JWT)
+	if err != nil {
+		return nil, fmt.Errorf("%w: parse jwt: %w", ErrInvalidProof, err)
+	}
+
+	if len(jws.Signatures) != 1 {
+		return nil, fmt.Errorf("%w: single signature expected", ErrInvalidProof)
+	}
+
+	var claims jwtProofClaims
+
+	if err = json.Unmarshal(jws.UnsafePayloadWithoutVerification(), &claims); err != nil {
+		return nil, fmt.Errorf("%w: decode claims: %w", ErrInvalidProof, err)
+	}
+
+	key, err := s.getClientKey(ctx, clientID, jws.Signatures[0].Header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = jws.Verify(key); err != nil {
+		return nil, fmt.Errorf("%w: verify signature: %w", ErrInvalidProof, err)
+	}
+
+	if claims.Issuer != clientID {
+		return nil, fmt.Errorf("%w: iss doesn't match client", ErrInvalidProof)
+	}
+
+	if claims.Expiry != nil && time.Now().After(claims.Expiry.Time()) {
+		return nil, ErrProofExpired
+	}
+
+	if claims.Nonce != nonce {
+		return nil, ErrInvalidProofNonce
+	}
+
+	tx, err := s.store.Get(ctx, txID)
+	if err != nil {
+		return nil, fmt.Errorf("get tx: %w", err)
+	}
+
+	if !claims.Audience.Contains(s.issuerAudience(tx)) {
+		return nil, ErrInvalidProofAudience
+	}
+
+	return key, nil
+}
+
+func (s *Service) getClientKey(ctx context.Context, clientID, keyID string) (*jose.JSONWebKey, error) {
+	if clientID == "" {
+		return nil, fmt.Errorf("%w: missing client id", ErrInvalidProof)
+	}
+
+	client, err := s.clientManager.Get(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("get client: %w", err)
+	}
+
+	c, ok := client.(*oauth2client.Client)
+	if !ok || c.JSONWebKeys == nil {
+		return nil, fmt.Errorf("%w: no keys registered for client %s", ErrProofKeyNotFound, clientID)
+	}
+
+	keys := c.JSONWebKeys.Key(keyID)
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: kid %s", ErrProofKeyNotFound, keyID)
+	}
+
+	return &keys[0], nil
+}
@@ -0,0 +1,266 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4ci_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	josejwt "github.com/go-jose/go-jose/v3/jwt"
+	"github.com/golang/mock/gomock"
+	"github.com/ory/fosite"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/vcs/pkg/oauth2client"
+	"github.com/trustbloc/vcs/pkg/service/oidc4ci"
+)
+
+const (
+	proofClientID = "client-id"
+	proofKeyID    = "key-1"
+	proofNonce    = "c_nonce"
+	proofTxID     = oidc4ci.TxID("tx-id")
+	proofHost     = "https://issuer.example.com"
+	proofAudience = proofHost + "/issuer/profile-id/v1.0"
+)
+
+type proofClaims struct {
+	josejwt.Claims
+	Nonce string `json:"nonce,omitempty"`
+}
+
+func TestService_VerifyProofOfPossession(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	client := &oauth2client.Client{
+		ID: proofClientID,
+		JSONWebKeys: &jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{{Key: publicKey, KeyID: proofKeyID, Algorithm: string(jose.EdDSA)}},
+		},
+	}
+
+	validClaims := func() *proofClaims {
+		return &proofClaims{
+			Claims: josejwt.Claims{
+				Issuer:   proofClientID,
+				Audience: josejwt.Audience{proofAudience},
+				IssuedAt: josejwt.NewNumericDate(time.Now()),
+				Expiry:   josejwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+			Nonce: proofNonce,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		claims func() *proofClaims
+		keyID  string
+		client fosite.Client
+		err    error
+		txErr  error
+		check  func(t *testing.T, err error)
+	}{
+		{
+			name:   "success",
+			claims: validClaims,
+			keyID:  proofKeyID,
+			client: client,
+			check: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "proof expired",
+			claims: func() *proofClaims {
+				c := validClaims()
+				c.Expiry = josejwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+				return c
+			},
+			keyID:  proofKeyID,
+			client: client,
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, oidc4ci.ErrProofExpired)
+			},
+		},
+		{
+			name: "invalid nonce",
+			claims: func() *proofClaims {
+				c := validClaims()
+				c.Nonce = "invalid"
+
+				return c
+			},
+			keyID:  proofKeyID,
+			client: client,
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, oidc4ci.ErrInvalidProofNonce)
+			},
+		},
+		{
+			name: "invalid audience",
+			claims: func() *proofClaims {
+				c := validClaims()
+				c.Audience = josejwt.Audience{"https://other.example.com"}
+
+				return c
+			},
+			keyID:  proofKeyID,
+			client: client,
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, oidc4ci.ErrInvalidProofAudience)
+			},
+		},
+		{
+			name:   "unknown key",
+			claims: validClaims,
+			keyID:  "unknown",
+			client: client,
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, oidc4ci.ErrProofKeyNotFound)
+			},
+		},
+		{
+			name:   "no keys registered for client",
+			claims: validClaims,
+			keyID:  proofKeyID,
+			client: &oauth2client.Client{ID: proofClientID},
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, oidc4ci.ErrProofKeyNotFound)
+			},
+		},
+		{
+			name: "iss of other client",
+			claims: func() *proofClaims {
+				c := validClaims()
+				c.Issuer = "other-client-id"
+
+				return c
+			},
+			keyID:  proofKeyID,
+			client: client,
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, oidc4ci.ErrInvalidProof)
+				require.ErrorContains(t, err, "iss doesn't match client")
+			},
+		},
+		{
+			name:   "get tx error",
+			claims: validClaims,
+			keyID:  proofKeyID,
+			client: client,
+			txErr:  errors.New("tx not found"),
+			check: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "get tx: tx not found")
+			},
+		},
+		{
+			name:   "get client error",
+			claims: validClaims,
+			keyID:  proofKeyID,
+			err:    errors.New("client not found"),
+			check: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "get client: client not found")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientManager := NewMockClientManager(gomock.NewController(t))
+
+			if tt.client != nil || tt.err != nil {
+				clientManager.EXPECT().Get(gomock.Any(), proofClientID).Return(tt.client, tt.err)
+			}
+
+			store := NewMockTransactionStore(gomock.NewController(t))
+			store.EXPECT().Get(gomock.Any(), proofTxID).Return(&oidc4ci.Transaction{
+				TransactionData: oidc4ci.TransactionData{ProfileID: "profile-id", ProfileVersion: "v1.0"},
+			}, tt.txErr).AnyTimes()
+
+			svc, err := oidc4ci.NewService(&oidc4ci.Config{
+				ClientManager:       clientManager,
+				TransactionStore:    store,
+				IssuerVCSPublicHost: proofHost,
+			})
+			require.NoError(t, err)
+
+			proof := &oidc4ci.JWTProof{
+				JWT:       signProof(t, privateKey, tt.keyID, tt.claims()),
+				ProofType: "jwt",
+			}
+
+			key, err := svc.VerifyProofOfPossession(context.Background(), proof, proofClientID, proofTxID, proofNonce)
+			tt.check(t, err)
+
+			if err == nil {
+				require.Equal(t, proofKeyID, key.KeyID)
+			}
+		})
+	}
+
+	t.Run("invalid signature", func(t *testing.T) {
+		_, otherPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		clientManager := NewMockClientManager(gomock.NewController(t))
+		clientManager.EXPECT().Get(gomock.Any(), proofClientID).Return(client, nil)
+
+		svc, err := oidc4ci.NewService(&oidc4ci.Config{
+			ClientManager: clientManager,
+		})
+		require.NoError(t, err)
+
+		proof := &oidc4ci.JWTProof{
+			JWT:       signProof(t, otherPrivateKey, proofKeyID, validClaims()),
+			ProofType: "jwt",
+		}
+
+		_, err = svc.VerifyProofOfPossession(context.Background(), proof, proofClientID, proofTxID, proofNonce)
+		require.ErrorIs(t, err, oidc4ci.ErrInvalidProof)
+		require.ErrorContains(t, err, "verify signature")
+	})
+
+	t.Run("unsupported proof type", func(t *testing.T) {
+		svc, err := oidc4ci.NewService(&oidc4ci.Config{})
+		require.NoError(t, err)
+
+		_, err = svc.VerifyProofOfPossession(context.Background(),
+			&oidc4ci.JWTProof{JWT: "jwt", ProofType: "ldp_vp"}, proofClientID, proofTxID, proofNonce)
+		require.ErrorIs(t, err, oidc4ci.ErrInvalidProof)
+	})
+
+	t.Run("malformed jwt", func(t *testing.T) {
+		svc, err := oidc4ci.NewService(&oidc4ci.Config{})
+		require.NoError(t, err)
+
+		_, err = svc.VerifyProofOfPossession(context.Background(),
+			&oidc4ci.JWTProof{JWT: "not a jwt", ProofType: "jwt"}, proofClientID, proofTxID, proofNonce)
+		require.ErrorIs(t, err, oidc4ci.ErrInvalidProof)
+		require.ErrorContains(t, err, "parse jwt")
+	})
+}
+
+func signProof(t *testing.T, privateKey ed25519.PrivateKey, keyID string, claims *proofClaims) string {
+	t.Helper()
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.EdDSA, Key: privateKey},
+		(&jose.SignerOptions{}).WithType("openid4vci-proof+jwt").WithHeader(jose.HeaderKey("kid"), keyID),
+	)
+	require.NoError(t, err)
+
+	jwt, err := josejwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(t, err)
+
+	return jwt
+}
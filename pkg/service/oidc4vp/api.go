@@ -8,6 +8,8 @@ package oidc4vp
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	util "github.com/trustbloc/did-go/doc/util/time"
 	"github.com/trustbloc/vc-go/presexch"
@@ -20,36 +22,108 @@ import (
 type InteractionInfo struct {
 	AuthorizationRequest string
 	TxID                 TxID
+	// QRCodeData is the authorization request encoded as a QR code PNG data URI.
+	// It's set only when QR code generation is enabled.
+	QRCodeData string
+	// DeepLinkURL invokes the wallet on the same device. It uses the wallet scheme of the profile
+	// and the percent-encoded request URI.
+	DeepLinkURL string
+}
+
+type initiateOidcInteractionOpts struct {
+	requestObjectTTL time.Duration
+	customData       map[string]interface{}
+}
+
+// InitiateOidcInteractionOpt configures InitiateOidcInteraction.
+type InitiateOidcInteractionOpt func(opts *initiateOidcInteractionOpts)
+
+// WithRequestObjectTTL overrides the lifetime of the request object and the one time token
+// created for the interaction. Defaults to Config.TokenLifetime.
+func WithRequestObjectTTL(ttl time.Duration) InitiateOidcInteractionOpt {
+	return func(opts *initiateOidcInteractionOpts) {
+		opts.requestObjectTTL = ttl
+	}
+}
+
+// WithCustomData attaches application-specific metadata to the transaction created for the interaction.
+// The data must be JSON-serializable.
+func WithCustomData(data map[string]interface{}) InitiateOidcInteractionOpt {
+	return func(opts *initiateOidcInteractionOpts) {
+		opts.customData = data
+	}
 }
 
 type ProcessedVPToken struct {
 	Nonce         string
 	ClientID      string
 	SignerDIDID   string
+	SignerKeyID   string
 	VpTokenFormat vcsverifiable.Format
 	Presentation  *verifiable.Presentation
+	// ReceivedAt is the time the token was received from the wallet. Zero value means now.
+	ReceivedAt time.Time
+}
+
+// ErrVerificationFailed is returned when the presentation verifier rejects a vp token. DescriptorID and
+// CredentialID are set only when the failure can be attributed to a single input descriptor and credential,
+// i.e. when the presentation contains exactly one credential.
+type ErrVerificationFailed struct {
+	DescriptorID string
+	CredentialID string
+	Reason       string
+	Err          error // the error returned by the verifier, nil if one of the verification checks failed
+}
+
+// Error returns a string representation of the error.
+func (e *ErrVerificationFailed) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("presentation verification failed: %s", e.Err)
+	}
+
+	return fmt.Sprintf("presentation verification checks failed: %s", e.Reason)
+}
+
+// Unwrap returns the error returned by the verifier.
+func (e *ErrVerificationFailed) Unwrap() error {
+	return e.Err
 }
 
 type CredentialMetadata struct {
-	Format         vcsverifiable.Format `json:"format"`
-	Type           []string             `json:"type"`
-	SubjectData    interface{}          `json:"subjectData"`
-	Issuer         verifiable.Issuer    `json:"issuer"`
-	IssuanceDate   *util.TimeWrapper    `json:"issuanceDate,omitempty"`
-	ExpirationDate *util.TimeWrapper    `json:"expirationDate,omitempty"`
+	CredentialID     string               `json:"credentialID"`
+	Format           vcsverifiable.Format `json:"format"`
+	Type             []string             `json:"type"`
+	SubjectData      interface{}          `json:"subjectData"`
+	Issuer           verifiable.Issuer    `json:"issuer"`
+	IssuanceDate     *util.TimeWrapper    `json:"issuanceDate,omitempty"`
+	ExpirationDate   *util.TimeWrapper    `json:"expirationDate,omitempty"`
+	ReceivedAt       *time.Time           `json:"receivedAt,omitempty"`
+	CredentialStatus *verifiable.TypedID  `json:"credentialStatus,omitempty"`
 }
 
 type ServiceInterface interface {
+	// InitiateOidcInteraction returns an error wrapping ErrMissingSigningDID if the profile has no signing DID.
 	InitiateOidcInteraction(
 		ctx context.Context,
 		presentationDefinition *presexch.PresentationDefinition,
 		purpose string,
 		profile *profileapi.Verifier,
+		opts ...InitiateOidcInteractionOpt,
+	) (*InteractionInfo, error)
+	InitiateOidcInteractionByTemplate(
+		ctx context.Context,
+		templateID, purpose string,
+		profile *profileapi.Verifier,
 	) (*InteractionInfo, error)
 	VerifyOIDCVerifiablePresentation(ctx context.Context, txID TxID, token []*ProcessedVPToken) error
 	GetTx(ctx context.Context, id TxID) (*Transaction, error)
+	GetTxByClaimsID(ctx context.Context, claimsID string) (*Transaction, error)
+	PollTransactionStatus(ctx context.Context, txID TxID) (TransactionStatus, error)
 	RetrieveClaims(ctx context.Context, tx *Transaction) map[string]CredentialMetadata
+	RetrieveRawTokens(ctx context.Context, tx *Transaction) ([]string, error)
 	DeleteClaims(ctx context.Context, receivedClaimsID string) error
+	DeleteClaimsForProfile(ctx context.Context, receivedClaimsID, profileID, profileVersion string) error
+	AmendTransaction(ctx context.Context, txID TxID, profileID, profileVersion string, update *TransactionUpdate) error
 }
 
 type TxNonceStore txNonceStore
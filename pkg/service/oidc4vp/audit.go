@@ -0,0 +1,60 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp
+
+import (
+	"context"
+	"time"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	"github.com/trustbloc/vcs/internal/logfields"
+)
+
+const (
+	// AuditOutcomeSuccess is the outcome of a presentation verification that stored the received claims.
+	AuditOutcomeSuccess = "success"
+	// AuditOutcomeFailure is the outcome of a presentation verification that failed.
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEntry is a record of a presentation verification attempt.
+type AuditEntry struct {
+	TxID          TxID
+	ProfileID     string
+	CredentialIDs []string
+	Outcome       string
+	ActorDID      string
+	Timestamp     time.Time
+}
+
+// AuditLogger appends audit entries to an event log. Implementations must not modify or drop entries that
+// have already been logged.
+type AuditLogger interface {
+	LogEvent(ctx context.Context, entry *AuditEntry) error
+}
+
+// NoopAuditLogger is an AuditLogger that discards all entries.
+type NoopAuditLogger struct{}
+
+// LogEvent discards the entry.
+func (NoopAuditLogger) LogEvent(context.Context, *AuditEntry) error {
+	return nil
+}
+
+func (s *Service) logAuditEvent(ctx context.Context, entry *AuditEntry, err error) {
+	entry.Outcome = AuditOutcomeSuccess
+	if err != nil {
+		entry.Outcome = AuditOutcomeFailure
+	}
+
+	entry.Timestamp = time.Now().UTC()
+
+	if logErr := s.auditLogger.LogEvent(ctx, entry); logErr != nil {
+		logger.Warnc(ctx, "Failed to log audit event", log.WithError(logErr), logfields.WithTransactionID(string(entry.TxID)))
+	}
+}
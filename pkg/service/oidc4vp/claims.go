@@ -45,6 +45,8 @@ func (tm *TxManager) ClaimsToClaimsRaw(data *ReceivedClaims) (*ReceivedClaimsRaw
 
 	raw := &ReceivedClaimsRaw{
 		Credentials: map[string][]byte{},
+		ReceivedAt:  data.ReceivedAt,
+		RawVPTokens: data.RawVPTokens,
 	}
 	for key, cred := range data.Credentials {
 		cl, err := json.Marshal(cred)
@@ -75,6 +77,8 @@ func (tm *TxManager) DecryptClaims(ctx context.Context, data *ClaimData) (*Recei
 
 	final := &ReceivedClaims{
 		Credentials: map[string]*verifiable.Credential{},
+		ReceivedAt:  raw.ReceivedAt,
+		RawVPTokens: raw.RawVPTokens,
 	}
 
 	for k, v := range raw.Credentials {
@@ -13,12 +13,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"runtime"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/piprate/json-gold/ld"
 	"github.com/samber/lo"
+	"github.com/trustbloc/did-go/doc/did"
 	vdrapi "github.com/trustbloc/did-go/vdr/api"
 	"github.com/trustbloc/kms-go/doc/jose"
 	kmsapi "github.com/trustbloc/kms-go/spi/kms"
@@ -29,6 +35,7 @@ import (
 	"github.com/trustbloc/vc-go/presexch"
 	"github.com/trustbloc/vc-go/verifiable"
 	"github.com/valyala/fastjson"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/trustbloc/vcs/internal/logfields"
 	"github.com/trustbloc/vcs/pkg/doc/vc"
@@ -45,22 +52,76 @@ var logger = log.New("oidc4vp-service")
 
 const vpSubmissionProperty = "presentation_submission"
 
-var ErrDataNotFound = errors.New("data not found")
+// defaultHTTPTimeout is the timeout of the HTTP client used for remote calls if Config.HTTPClient is not set.
+const defaultHTTPTimeout = 10 * time.Second
+
+// DefaultWalletScheme is the URL scheme of the deep link to the wallet if the profile has no wallet scheme.
+const DefaultWalletScheme = "openid-vc://"
+
+// Error codes of the oidc interaction initiation failed event.
+const (
+	ErrCodeInvalidProfile             = "invalid-profile"
+	ErrCodeCreateTxFailed             = "create-tx-failed"
+	ErrCodePublishEventFailed         = "publish-event-failed"
+	ErrCodeCreateRequestObjectFailed  = "create-request-object-failed"
+	ErrCodePublishRequestObjectFailed = "publish-request-object-failed"
+	ErrCodeGenerateQRCodeFailed       = "generate-qr-code-failed"
+
+	ErrCodeResolvePresentationDefinitionFailed = "resolve-presentation-definition-failed"
+)
+
+var (
+	ErrDataNotFound             = errors.New("data not found")
+	ErrClaimsOwnershipViolation = errors.New("received claims do not belong to the profile")
+	ErrTxOwnershipViolation     = errors.New("transaction does not belong to the profile")
+	ErrTxCompleted              = errors.New("transaction is already completed")
+	ErrTxExpired                = errors.New("transaction is expired")
+	ErrTooManyTokens            = errors.New("too many vp tokens")
+	ErrHolderBindingViolation   = errors.New("holder binding violation")
+	ErrUntrustedIssuer          = errors.New("untrusted issuer")
+	ErrCredentialTooOld         = errors.New("credential too old")
+	ErrPresentationTooOld       = errors.New("presentation too old")
+	ErrServiceShuttingDown      = errors.New("service is shutting down")
+	ErrMissingSigningDID        = errors.New("profile signing did can't be nil")
+	ErrInvalidTransactionState  = errors.New("invalid transaction state")
+	ErrTokenReceivedTooLate     = errors.New("vp token received outside of transaction validity window")
+	ErrRequestObjectTooLarge    = errors.New("request object too large")
+	ErrAlgorithmKeyMismatch     = errors.New("signing algorithm is incompatible with the key type")
+	ErrInvalidDIDSyntax         = errors.New("invalid did syntax")
+	ErrDisallowedDIDMethod      = errors.New("did method is not allowed")
+
+	ErrTemplateRegistryNotConfigured = errors.New("presentation definition template registry is not configured")
+)
 
 type eventService interface {
 	Publish(ctx context.Context, topic string, messages ...*spi.Event) error
 }
 
 type transactionManager interface {
-	CreateTx(pd *presexch.PresentationDefinition, profileID, profileVersion string) (*Transaction, string, error)
-	StoreReceivedClaims(txID TxID, claims *ReceivedClaims) error
-	DeleteReceivedClaims(claimsID string) error
-	GetByOneTimeToken(nonce string) (*Transaction, bool, error)
-	Get(txID TxID) (*Transaction, error)
+	CreateTx(
+		ctx context.Context,
+		pd *presexch.PresentationDefinition,
+		profileID, profileVersion, purpose string,
+		ttl time.Duration,
+		customData map[string]interface{},
+	) (*Transaction, string, error)
+	CreateTxNonce(ctx context.Context, txID TxID, ttl time.Duration) (string, error)
+	StoreReceivedClaims(ctx context.Context, txID TxID, claims *ReceivedClaims) error
+	DeleteReceivedClaims(ctx context.Context, claimsID string) error
+	GetByClaimsID(ctx context.Context, claimsID string) (*Transaction, error)
+	GetByOneTimeToken(ctx context.Context, nonce string) (*Transaction, bool, error)
+	Get(ctx context.Context, txID TxID) (*Transaction, error)
+	UpdateTx(ctx context.Context, txID TxID, update *TransactionUpdate) error
 }
 
 type requestObjectPublicStore interface {
-	Publish(ctx context.Context, requestObject string, accessRequestObjectEvent *spi.Event) (string, error)
+	Publish(
+		ctx context.Context,
+		requestObject string,
+		accessRequestObjectEvent *spi.Event,
+		ttl time.Duration,
+	) (string, error)
+	Retrieve(ctx context.Context, objectID string) (string, error)
 }
 
 type kmsRegistry interface {
@@ -97,7 +158,8 @@ type RequestObject struct {
 	Scope        string                    `json:"scope"`
 	Nonce        string                    `json:"nonce"`
 	ClientID     string                    `json:"client_id"`
-	RedirectURI  string                    `json:"redirect_uri"`
+	RedirectURI  string                    `json:"redirect_uri,omitempty"`
+	ResponseURI  string                    `json:"response_uri,omitempty"`
 	State        string                    `json:"state"`
 	Exp          int64                     `json:"exp"`
 	Registration RequestObjectRegistration `json:"registration"`
@@ -111,13 +173,54 @@ type Config struct {
 	DocumentLoader           ld.DocumentLoader
 	ProfileService           profileService
 	EventSvc                 eventService
-	EventTopic               string
+	EventTopics              []string
 	PresentationVerifier     presentationVerifier
 	VDR                      vdrapi.Registry
+	SchemaValidator          SchemaValidator
+	TemplateRegistry         TemplateRegistry
 
-	RedirectURL   string
+	RedirectURL string
+	// PublicURL is the public URL of the verifier REST API. It is used to build response_uri of the request
+	// objects of profiles with direct_post response mode.
+	PublicURL     string
 	TokenLifetime time.Duration
-	Metrics       metricsProvider
+	// MaxVPTokens limits the number of vp tokens accepted in a single authorization response. 0 means unlimited.
+	MaxVPTokens int
+	// MaxRequestObjectSize limits the size in bytes of the signed request object published for the wallet.
+	// 0 means unlimited.
+	MaxRequestObjectSize int64
+	// TemplateCacheTTL is how long templates fetched from TemplateRegistry are cached. 0 disables caching.
+	TemplateCacheTTL time.Duration
+	// GenerateQRCode enables QR code generation for the authorization request of initiated interactions.
+	GenerateQRCode bool
+	// QRCodeSize is the width and height of the generated QR code in pixels. 0 means the default size.
+	QRCodeSize int
+	Metrics    metricsProvider
+	// AuditLogger records the outcome of presentation verifications. Defaults to NoopAuditLogger.
+	AuditLogger AuditLogger
+	// VDRResolutionTimeout bounds each DID resolution made through VDR. 0 means no timeout.
+	VDRResolutionTimeout time.Duration
+	// AllowedVDRMethods is the whitelist of DID methods, e.g. "ion" or "web", VP signers may use.
+	// Empty means any method is allowed.
+	AllowedVDRMethods []string
+	// TrustRegistryClient checks issuers of received credentials. If not set and TrustRegistryURL is set,
+	// HTTPTrustRegistryClient for TrustRegistryURL is used with HTTPClient.
+	TrustRegistryClient TrustRegistryClient
+	TrustRegistryURL    string
+	// RequestObjectSigner signs request objects sent to the wallet. Defaults to KMSRequestObjectSigner,
+	// which signs with the profile signing key from KMSRegistry.
+	RequestObjectSigner RequestObjectSigner
+	// SigningAlgorithmOverride, if set, is the JWS algorithm the default KMSRequestObjectSigner signs request
+	// objects with instead of the algorithm derived from the profile key type. It must be compatible with the key type.
+	SigningAlgorithmOverride *verifiable.JWSAlgorithm
+	// PresentationDefinitionResolver resolves presentation definitions whose ID is an HTTP(S) URI. If not set and
+	// ResolvePresentationDefinitionURI is true, HTTPPresentationDefinitionResolver is used.
+	PresentationDefinitionResolver PresentationDefinitionResolver
+	// ResolvePresentationDefinitionURI enables fetching of presentation definitions whose ID is an HTTP(S) URI.
+	// It is off by default, as the URI comes from the caller of InitiateOidcInteraction.
+	ResolvePresentationDefinitionURI bool
+	// HTTPClient is used for remote calls of the service. Defaults to a client with a 10 seconds timeout.
+	HTTPClient *http.Client
 }
 
 type metricsProvider interface {
@@ -126,19 +229,38 @@ type metricsProvider interface {
 
 type Service struct {
 	eventSvc                 eventService
-	eventTopic               string
+	eventTopics              []string
 	transactionManager       transactionManager
 	requestObjectPublicStore requestObjectPublicStore
 	kmsRegistry              kmsRegistry
+	requestObjectSigner      RequestObjectSigner
 	documentLoader           ld.DocumentLoader
 	profileService           profileService
 	presentationVerifier     presentationVerifier
 	vdr                      vdrapi.Registry
+	schemaValidator          SchemaValidator
+	trustRegistry            TrustRegistryClient
+	templates                *templateCache
+	pdResolver               PresentationDefinitionResolver
 
 	redirectURL   string
+	publicURL     string
 	tokenLifetime time.Duration
+	maxVPTokens   int
 
-	metrics metricsProvider
+	maxRequestObjectSize int64
+
+	allowedVDRMethods []string
+
+	generateQRCode bool
+	qrCodeSize     int
+
+	metrics     metricsProvider
+	auditLogger AuditLogger
+
+	shutdownMu   sync.RWMutex
+	shuttingDown bool
+	inFlight     sync.WaitGroup
 }
 
 type RequestObjectRegistration struct {
@@ -150,43 +272,126 @@ type RequestObjectRegistration struct {
 }
 
 type eventPayload struct {
+	TxID           string `json:"txID,omitempty"`
 	WebHook        string `json:"webHook,omitempty"`
 	ProfileID      string `json:"profileID,omitempty"`
 	ProfileVersion string `json:"profileVersion,omitempty"`
 	OrgID          string `json:"orgID,omitempty"`
 	Error          string `json:"error,omitempty"`
+	ErrorCode      string `json:"errorCode,omitempty"`
 }
 
-func NewService(cfg *Config) *Service {
+// NewService creates a new Service from cfg. Options, if any, are applied on top of cfg,
+// which may be nil when the service is configured through options only.
+func NewService(cfg *Config, opts ...Option) *Service {
+	c := &Config{}
+	if cfg != nil {
+		*c = *cfg
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	cfg = c
+
 	metrics := cfg.Metrics
 
 	if metrics == nil {
 		metrics = &noopMetricsProvider.NoMetrics{}
 	}
 
+	auditLogger := cfg.AuditLogger
+
+	if auditLogger == nil {
+		auditLogger = NoopAuditLogger{}
+	}
+
+	qrCodeSize := cfg.QRCodeSize
+
+	if qrCodeSize <= 0 {
+		qrCodeSize = defaultQRCodeSize
+	}
+
+	httpClient := cfg.HTTPClient
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+
+	trustRegistry := cfg.TrustRegistryClient
+
+	if trustRegistry == nil && cfg.TrustRegistryURL != "" {
+		trustRegistry = NewHTTPTrustRegistryClient(cfg.TrustRegistryURL, httpClient)
+	}
+
+	requestObjectSigner := cfg.RequestObjectSigner
+
+	if requestObjectSigner == nil {
+		requestObjectSigner = NewKMSRequestObjectSigner(cfg.KMSRegistry, cfg.SigningAlgorithmOverride)
+	}
+
+	pdResolver := cfg.PresentationDefinitionResolver
+
+	if pdResolver == nil && cfg.ResolvePresentationDefinitionURI {
+		pdResolver = NewHTTPPresentationDefinitionResolver(httpClient)
+	}
+
+	vdr := cfg.VDR
+
+	if vdr != nil && cfg.VDRResolutionTimeout > 0 {
+		vdr = &timeoutVDR{Registry: vdr, timeout: cfg.VDRResolutionTimeout}
+	}
+
 	return &Service{
 		eventSvc:                 cfg.EventSvc,
-		eventTopic:               cfg.EventTopic,
+		eventTopics:              cfg.EventTopics,
 		transactionManager:       cfg.TransactionManager,
 		requestObjectPublicStore: cfg.RequestObjectPublicStore,
 		kmsRegistry:              cfg.KMSRegistry,
+		requestObjectSigner:      requestObjectSigner,
 		documentLoader:           cfg.DocumentLoader,
 		profileService:           cfg.ProfileService,
 		presentationVerifier:     cfg.PresentationVerifier,
 		redirectURL:              cfg.RedirectURL,
+		publicURL:                cfg.PublicURL,
 		tokenLifetime:            cfg.TokenLifetime,
-		vdr:                      cfg.VDR,
+		maxVPTokens:              cfg.MaxVPTokens,
+		allowedVDRMethods:        cfg.AllowedVDRMethods,
+		maxRequestObjectSize:     cfg.MaxRequestObjectSize,
+		vdr:                      vdr,
+		schemaValidator:          cfg.SchemaValidator,
+		trustRegistry:            trustRegistry,
+		templates:                newTemplateCache(cfg.TemplateRegistry, cfg.TemplateCacheTTL),
+		pdResolver:               pdResolver,
+		generateQRCode:           cfg.GenerateQRCode,
+		qrCodeSize:               qrCodeSize,
 		metrics:                  metrics,
+		auditLogger:              auditLogger,
 	}
 }
 
 func (s *Service) createEvent(tx *Transaction, profile *profileapi.Verifier,
 	eventType spi.EventType, e error) (*spi.Event, error) {
+	return s.createEventWithErrorCode(tx, profile, eventType, e, "")
+}
+
+func (s *Service) createEventWithErrorCode(tx *Transaction, profile *profileapi.Verifier,
+	eventType spi.EventType, e error, errCode string) (*spi.Event, error) {
+	var txID string
+
+	// Transaction is not available when the failure occurred before it was created.
+	if tx != nil {
+		txID = string(tx.ID)
+	}
+
 	ep := eventPayload{
+		TxID:           txID,
 		WebHook:        profile.WebHook,
 		ProfileID:      profile.ID,
 		ProfileVersion: profile.Version,
 		OrgID:          profile.OrganizationID,
+		ErrorCode:      errCode,
 	}
 
 	if e != nil {
@@ -199,7 +404,7 @@ func (s *Service) createEvent(tx *Transaction, profile *profileapi.Verifier,
 	}
 
 	event := spi.NewEventWithPayload(uuid.NewString(), "source://vcs/verifier", eventType, payload)
-	event.TransactionID = string(tx.ID)
+	event.TransactionID = txID
 
 	return event, nil
 }
@@ -216,7 +421,21 @@ func (s *Service) sendEventWithError(ctx context.Context, tx *Transaction, profi
 		return err
 	}
 
-	return s.eventSvc.Publish(ctx, s.eventTopic, event)
+	return s.publishEvent(ctx, event)
+}
+
+// publishEvent publishes the event to each of the configured topics in order. A failure to publish to one topic
+// does not prevent publishing to the subsequent topics; all errors are returned joined.
+func (s *Service) publishEvent(ctx context.Context, event *spi.Event) error {
+	var errs []error
+
+	for _, topic := range s.eventTopics {
+		if err := s.eventSvc.Publish(ctx, topic, event); err != nil {
+			errs = append(errs, fmt.Errorf("publish event to topic %s: %w", topic, err))
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
 func (s *Service) sendFailedEvent(ctx context.Context, tx *Transaction, profile *profileapi.Verifier, err error) {
@@ -224,31 +443,82 @@ func (s *Service) sendFailedEvent(ctx context.Context, tx *Transaction, profile
 	logger.Debugc(ctx, "sending Failed OIDC verifier event error, ignoring..", log.WithError(e))
 }
 
+func (s *Service) sendInitiationFailedEvent(ctx context.Context, tx *Transaction, profile *profileapi.Verifier,
+	errCode string, err error) {
+	event, e := s.createEventWithErrorCode(tx, profile, spi.VerifierOIDCInteractionInitiationFailed, err, errCode)
+	if e == nil {
+		e = s.publishEvent(ctx, event)
+	}
+
+	logger.Debugc(ctx, "sending Initiation Failed OIDC verifier event error, ignoring..", log.WithError(e))
+}
+
+// sendVerificationFailedEvent notifies downstream consumers that the claims of an otherwise successfully verified
+// presentation were not stored, so that side effects of the earlier verification steps can be compensated.
+func (s *Service) sendVerificationFailedEvent(ctx context.Context, tx *Transaction, profile *profileapi.Verifier,
+	err error) {
+	e := s.sendEventWithError(ctx, tx, profile, spi.VerifierOIDCVerificationFailed, err)
+	logger.Debugc(ctx, "sending Verification Failed OIDC verifier event error, ignoring..", log.WithError(e))
+}
+
 func (s *Service) InitiateOidcInteraction(
 	ctx context.Context,
 	presentationDefinition *presexch.PresentationDefinition,
 	purpose string,
 	profile *profileapi.Verifier,
+	opts ...InitiateOidcInteractionOpt,
 ) (*InteractionInfo, error) {
 	logger.Debugc(ctx, "InitiateOidcInteraction begin")
 
+	if err := s.beginCall(); err != nil {
+		return nil, err
+	}
+	defer s.inFlight.Done()
+
+	options := &initiateOidcInteractionOpts{
+		requestObjectTTL: s.tokenLifetime,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	if profile.SigningDID == nil {
-		return nil, errors.New("profile signing did can't be nil")
+		err := fmt.Errorf("%w: profile %s", ErrMissingSigningDID, profile.ID)
+		s.sendInitiationFailedEvent(ctx, nil, profile, ErrCodeInvalidProfile, err)
+
+		return nil, err
 	}
 
-	tx, nonce, err := s.transactionManager.CreateTx(presentationDefinition, profile.ID, profile.Version)
+	presentationDefinition, err := s.resolvePresentationDefinition(ctx, presentationDefinition)
 	if err != nil {
-		return nil, fmt.Errorf("fail to create oidc tx: %w", err)
+		s.sendInitiationFailedEvent(ctx, nil, profile, ErrCodeResolvePresentationDefinitionFailed, err)
+
+		return nil, err
+	}
+
+	tx, nonce, err := s.transactionManager.CreateTx(
+		ctx, presentationDefinition, profile.ID, profile.Version, purpose, options.requestObjectTTL, options.customData)
+	if err != nil {
+		err = fmt.Errorf("fail to create oidc tx: %w", err)
+		s.sendInitiationFailedEvent(ctx, nil, profile, ErrCodeCreateTxFailed, err)
+
+		return nil, err
 	}
 
 	logger.Debugc(ctx, "InitiateOidcInteraction tx created", log.WithTxID(string(tx.ID)))
 
 	if errSendEvent := s.sendEvent(ctx, tx, profile, spi.VerifierOIDCInteractionInitiated); errSendEvent != nil {
+		s.sendInitiationFailedEvent(ctx, tx, profile, ErrCodePublishEventFailed, errSendEvent)
+
 		return nil, errSendEvent
 	}
 
-	token, err := s.createRequestObjectJWT(presentationDefinition, tx, nonce, purpose, profile)
+	token, err := s.createRequestObjectJWT(ctx,
+		presentationDefinition, tx, nonce, purpose, profile, options.requestObjectTTL)
 	if err != nil {
+		s.sendInitiationFailedEvent(ctx, tx, profile, ErrCodeCreateRequestObjectFailed, err)
+
 		return nil, err
 	}
 
@@ -256,22 +526,51 @@ func (s *Service) InitiateOidcInteraction(
 
 	accessRequestObjectEvent, err := s.createEvent(tx, profile, spi.VerifierOIDCInteractionQRScanned, nil)
 	if err != nil {
+		s.sendInitiationFailedEvent(ctx, tx, profile, ErrCodePublishRequestObjectFailed, err)
+
 		return nil, err
 	}
 
-	requestURI, err := s.requestObjectPublicStore.Publish(ctx, token, accessRequestObjectEvent)
+	requestURI, err := s.requestObjectPublicStore.Publish(
+		ctx, token, accessRequestObjectEvent, options.requestObjectTTL)
 	if err != nil {
-		return nil, fmt.Errorf("fail publish request object: %w", err)
+		err = fmt.Errorf("fail publish request object: %w", err)
+		s.sendInitiationFailedEvent(ctx, tx, profile, ErrCodePublishRequestObjectFailed, err)
+
+		return nil, err
 	}
 
 	logger.Debugc(ctx, "InitiateOidcInteraction request object published")
 
-	logger.Debugc(ctx, "InitiateOidcInteraction succeed")
-
-	return &InteractionInfo{
+	info := &InteractionInfo{
 		AuthorizationRequest: "openid-vc://?request_uri=" + requestURI,
 		TxID:                 tx.ID,
-	}, nil
+		DeepLinkURL:          deepLinkURL(profile, requestURI),
+	}
+
+	if s.generateQRCode {
+		info.QRCodeData, err = encodeQRCode(info.AuthorizationRequest, s.qrCodeSize)
+		if err != nil {
+			s.sendInitiationFailedEvent(ctx, tx, profile, ErrCodeGenerateQRCodeFailed, err)
+
+			return nil, err
+		}
+	}
+
+	logger.Debugc(ctx, "InitiateOidcInteraction succeed")
+
+	return info, nil
+}
+
+// deepLinkURL returns the URL that invokes the wallet of the profile on the same device with the request URI.
+func deepLinkURL(profile *profileapi.Verifier, requestURI string) string {
+	scheme := DefaultWalletScheme
+
+	if profile.OIDCConfig != nil && profile.OIDCConfig.WalletScheme != "" {
+		scheme = profile.OIDCConfig.WalletScheme
+	}
+
+	return scheme + "?" + url.Values{"request_uri": {requestURI}}.Encode()
 }
 
 func (s *Service) verifyTokens(
@@ -281,74 +580,100 @@ func (s *Service) verifyTokens(
 	tokens []*ProcessedVPToken,
 ) (map[string]*ProcessedVPToken, error) {
 	verifiedPresentations := make(map[string]*ProcessedVPToken)
-
-	var validationErrors []error
 	mut := sync.Mutex{}
-	wg := sync.WaitGroup{}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(runtime.GOMAXPROCS(0))
+
 	for _, token2 := range tokens {
 		token := token2
-		wg.Add(1)
-
-		go func() {
-			defer wg.Done()
-			if !lo.Contains(profile.Checks.Presentation.Format, token.VpTokenFormat) {
-				e := fmt.Errorf("profile does not support %s vp_token format", token.VpTokenFormat)
-				s.sendFailedEvent(ctx, tx, profile, e)
-
-				mut.Lock()
-				validationErrors = append(validationErrors, e)
-				mut.Unlock()
-				return
-			}
 
-			vr, innerErr := s.presentationVerifier.VerifyPresentation(ctx, token.Presentation, &verifypresentation.Options{
-				Domain:    token.ClientID,
-				Challenge: token.Nonce,
-			}, profile)
-			if innerErr != nil {
-				e := fmt.Errorf("presentation verification failed: %w", innerErr)
-				s.sendFailedEvent(ctx, tx, profile, e)
-
-				mut.Lock()
-				validationErrors = append(validationErrors, e)
-				mut.Unlock()
-				return
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil { // another token already failed
+				return err
 			}
 
-			if len(vr) > 0 {
-				e := fmt.Errorf("presentation verification checks failed: %s", vr[0].Error)
-				s.sendFailedEvent(ctx, tx, profile, e)
-
-				mut.Lock()
-				validationErrors = append(validationErrors, e)
-				mut.Unlock()
-				return
+			if err := s.verifyToken(gctx, profile, token); err != nil {
+				return err
 			}
 
 			mut.Lock()
 			defer mut.Unlock()
-			if _, ok := verifiedPresentations[token.Presentation.ID]; !ok {
-				verifiedPresentations[token.Presentation.ID] = token
-			} else {
-				e := fmt.Errorf("duplicate presentation ID: %s", token.Presentation.ID)
-				s.sendFailedEvent(ctx, tx, profile, e)
-
-				validationErrors = append(validationErrors, e)
-				return
+
+			if _, ok := verifiedPresentations[token.Presentation.ID]; ok {
+				return fmt.Errorf("duplicate presentation ID: %s", token.Presentation.ID)
 			}
-		}()
-		logger.Debugc(ctx, "VerifyOIDCVerifiablePresentation verified")
+
+			verifiedPresentations[token.Presentation.ID] = token
+
+			return nil
+		})
 	}
-	wg.Wait()
 
-	if len(validationErrors) > 0 {
-		return nil, validationErrors[0]
+	if err := g.Wait(); err != nil {
+		s.sendFailedEvent(ctx, tx, profile, err)
+
+		return nil, err
 	}
 
+	logger.Debugc(ctx, "VerifyOIDCVerifiablePresentation verified")
+
 	return verifiedPresentations, nil
 }
 
-func (s *Service) VerifyOIDCVerifiablePresentation(ctx context.Context, txID TxID, tokens []*ProcessedVPToken) error {
+func (s *Service) verifyToken(ctx context.Context, profile *profileapi.Verifier, token *ProcessedVPToken) error {
+	if !lo.Contains(profile.Checks.Presentation.Format, token.VpTokenFormat) {
+		return fmt.Errorf("profile does not support %s vp_token format", token.VpTokenFormat)
+	}
+
+	if err := checkPresentationAge(token, profile.Checks.Presentation.MaxPresentationAge); err != nil {
+		return err
+	}
+
+	vr, err := s.presentationVerifier.VerifyPresentation(ctx, token.Presentation, &verifypresentation.Options{
+		Domain:    token.ClientID,
+		Challenge: token.Nonce,
+	}, profile)
+	if err != nil {
+		return newVerificationFailedError(token.Presentation, err.Error(), err)
+	}
+
+	if len(vr) > 0 {
+		return newVerificationFailedError(token.Presentation, vr[0].Error, nil)
+	}
+
+	return nil
+}
+
+// newVerificationFailedError creates ErrVerificationFailed for the presentation. The verifier doesn't report
+// which of the credentials failed, so the descriptor and credential are resolved for single credential
+// presentations only.
+func newVerificationFailedError(vp *verifiable.Presentation, reason string, err error) *ErrVerificationFailed {
+	verificationErr := &ErrVerificationFailed{
+		Reason: reason,
+		Err:    err,
+	}
+
+	if vp == nil || len(vp.Credentials()) != 1 {
+		return verificationErr
+	}
+
+	if cred, ok := vp.Credentials()[0].(*verifiable.Credential); ok {
+		verificationErr.CredentialID = cred.ID
+	}
+
+	if submission, e := parsePresentationSubmission(vp); e == nil && len(submission.DescriptorMap) == 1 {
+		verificationErr.DescriptorID = submission.DescriptorMap[0].ID
+	}
+
+	return verificationErr
+}
+
+func (s *Service) VerifyOIDCVerifiablePresentation(
+	ctx context.Context,
+	txID TxID,
+	tokens []*ProcessedVPToken,
+) (err error) {
 	logger.Debugc(ctx, "VerifyOIDCVerifiablePresentation begin")
 	startTime := time.Now()
 
@@ -356,13 +681,36 @@ func (s *Service) VerifyOIDCVerifiablePresentation(ctx context.Context, txID TxI
 		logger.Debugc(ctx, "VerifyOIDCVerifiablePresentation", log.WithDuration(time.Since(startTime)))
 	}()
 
+	if err = s.beginCall(); err != nil {
+		return err
+	}
+	defer s.inFlight.Done()
+
+	auditEntry := &AuditEntry{TxID: txID}
+
+	defer func() {
+		s.logAuditEvent(ctx, auditEntry, err)
+	}()
+
 	if len(tokens) == 0 {
 		// this should never happen
 		return fmt.Errorf("must have at least one token")
 	}
 
+	auditEntry.ActorDID = tokens[0].SignerDIDID
+
+	if s.maxVPTokens > 0 && len(tokens) > s.maxVPTokens {
+		return fmt.Errorf("%w: got %d, max %d", ErrTooManyTokens, len(tokens), s.maxVPTokens)
+	}
+
+	for _, token := range tokens {
+		if err = s.checkSignerDID(token.SignerDIDID); err != nil {
+			return fmt.Errorf("vp signer: %w", err)
+		}
+	}
+
 	// All tokens have same nonce
-	tx, validNonce, err := s.transactionManager.GetByOneTimeToken(tokens[0].Nonce)
+	tx, validNonce, err := s.transactionManager.GetByOneTimeToken(ctx, tokens[0].Nonce)
 	if err != nil {
 		return fmt.Errorf("get tx by nonce failed: %w", err)
 	}
@@ -373,6 +721,27 @@ func (s *Service) VerifyOIDCVerifiablePresentation(ctx context.Context, txID TxI
 
 	logger.Debugc(ctx, "VerifyOIDCVerifiablePresentation nonce verified")
 
+	auditEntry.ProfileID = tx.ProfileID
+
+	// The one time token is consumed at this point, so the transaction can't be completed after a failure.
+	// Successfully received claims mark the transaction as completed when they are stored.
+	claimsStored := false
+
+	defer func() {
+		if err != nil && !claimsStored {
+			s.setTxStatus(ctx, tx.ID, TransactionStatusFailed)
+		}
+	}()
+
+	if err = checkTokensReceivedInTime(tx, tokens); err != nil {
+		return err
+	}
+
+	// An empty version would silently resolve to whatever profile the service returns for it.
+	if tx.ProfileVersion == "" {
+		return fmt.Errorf("%w: profile version is not set for transaction %s", ErrInvalidTransactionState, tx.ID)
+	}
+
 	profile, err := s.profileService.GetProfile(tx.ProfileID, tx.ProfileVersion)
 	if err != nil {
 		return fmt.Errorf("inconsistent transaction state %w", err)
@@ -387,13 +756,61 @@ func (s *Service) VerifyOIDCVerifiablePresentation(ctx context.Context, txID TxI
 		return err
 	}
 
-	err = s.extractClaimData(ctx, tx, tokens, profile, verifiedPresentations)
+	// Raw tokens are captured before extracting claim data, which clears JWT of the presentations.
+	rawTokens := rawVPTokens(tokens)
+
+	storeCredentials, err := s.extractClaimData(ctx, tx, tokens, profile, verifiedPresentations)
+	if err != nil {
+		s.sendFailedEvent(ctx, tx, profile, err)
+
+		return err
+	}
+
+	auditEntry.CredentialIDs = credentialIDs(storeCredentials)
+
+	if err = checkAllowedIssuers(profile, storeCredentials); err != nil {
+		s.sendFailedEvent(ctx, tx, profile, err)
+
+		return err
+	}
+
+	if err = s.checkTrustRegistry(ctx, storeCredentials); err != nil {
+		s.sendFailedEvent(ctx, tx, profile, err)
+
+		return err
+	}
+
+	if profile.Checks != nil {
+		err = checkCredentialAge(storeCredentials, profile.Checks.Credential.MaxCredentialAge, time.Now())
+		if err != nil {
+			s.sendFailedEvent(ctx, tx, profile, err)
+
+			return err
+		}
+	}
+
+	if err = s.validateCredentialSchemas(ctx, profile, storeCredentials); err != nil {
+		s.sendFailedEvent(ctx, tx, profile, err)
+
+		return err
+	}
+
+	err = s.transactionManager.StoreReceivedClaims(ctx, tx.ID, &ReceivedClaims{
+		Credentials: storeCredentials,
+		ReceivedAt:  time.Now().UTC(),
+		RawVPTokens: rawTokens,
+	})
 	if err != nil {
+		err = fmt.Errorf("store received claims: %w", err)
+
 		s.sendFailedEvent(ctx, tx, profile, err)
+		s.sendVerificationFailedEvent(ctx, tx, profile, err)
 
 		return err
 	}
 
+	claimsStored = true
+
 	logger.Debugc(ctx, "extractClaimData claims stored")
 
 	if err = s.sendEvent(ctx, tx, profile, spi.VerifierOIDCInteractionSucceeded); err != nil {
@@ -404,14 +821,51 @@ func (s *Service) VerifyOIDCVerifiablePresentation(ctx context.Context, txID TxI
 	return nil
 }
 
-func (s *Service) GetTx(_ context.Context, id TxID) (*Transaction, error) {
-	return s.transactionManager.Get(id)
+func (s *Service) GetTx(ctx context.Context, id TxID) (*Transaction, error) {
+	return s.transactionManager.Get(ctx, id)
+}
+
+// PollTransactionStatus returns the status of the transaction for the relying party polling for completion of
+// the cross-device flow. Transactions are removed from the store once they expire, so a transaction that is not
+// found is reported as expired.
+func (s *Service) PollTransactionStatus(ctx context.Context, txID TxID) (TransactionStatus, error) {
+	tx, err := s.transactionManager.Get(ctx, txID)
+	if err != nil {
+		if errors.Is(err, ErrDataNotFound) {
+			return TransactionStatusExpired, nil
+		}
+
+		return TransactionStatusPending, fmt.Errorf("get tx: %w", err)
+	}
+
+	// Transactions completed before the status was tracked only have received claims set.
+	if tx.Status == TransactionStatusPending && tx.ReceivedClaimsID != "" {
+		return TransactionStatusCompleted, nil
+	}
+
+	return tx.Status, nil
+}
+
+func (s *Service) setTxStatus(ctx context.Context, txID TxID, status TransactionStatus) {
+	if err := s.transactionManager.UpdateTx(ctx, txID, &TransactionUpdate{Status: &status}); err != nil {
+		logger.Warnc(ctx, "Failed to update transaction status", log.WithTxID(string(txID)), log.WithError(err))
+	}
+}
+
+// GetTxByClaimsID returns transaction the received claims with the given ID were stored for.
+func (s *Service) GetTxByClaimsID(ctx context.Context, claimsID string) (*Transaction, error) {
+	return s.transactionManager.GetByClaimsID(ctx, claimsID)
 }
 
 func (s *Service) RetrieveClaims(ctx context.Context, tx *Transaction) map[string]CredentialMetadata {
 	logger.Debugc(ctx, "RetrieveClaims begin")
 	result := map[string]CredentialMetadata{}
 
+	var receivedAt *time.Time
+	if !tx.ReceivedClaims.ReceivedAt.IsZero() {
+		receivedAt = &tx.ReceivedClaims.ReceivedAt
+	}
+
 	for _, cred := range tx.ReceivedClaims.Credentials {
 		credType := vcsverifiable.Ldp
 		if cred.JWT != "" {
@@ -429,12 +883,15 @@ func (s *Service) RetrieveClaims(ctx context.Context, tx *Transaction) map[strin
 		}
 
 		result[cred.ID] = CredentialMetadata{
-			Format:         credType,
-			Type:           cred.Types,
-			SubjectData:    cred.Subject,
-			Issuer:         cred.Issuer,
-			IssuanceDate:   cred.Issued,
-			ExpirationDate: cred.Expired,
+			CredentialID:     cred.ID,
+			Format:           credType,
+			Type:             cred.Types,
+			SubjectData:      cred.Subject,
+			Issuer:           cred.Issuer,
+			IssuanceDate:     cred.Issued,
+			ExpirationDate:   cred.Expired,
+			ReceivedAt:       receivedAt,
+			CredentialStatus: cred.Status,
 		}
 	}
 	logger.Debugc(ctx, "RetrieveClaims succeed")
@@ -442,8 +899,121 @@ func (s *Service) RetrieveClaims(ctx context.Context, tx *Transaction) map[strin
 	return result
 }
 
-func (s *Service) DeleteClaims(_ context.Context, claimsID string) error {
-	return s.transactionManager.DeleteReceivedClaims(claimsID)
+// RetrieveRawTokens returns original JWT VP tokens received for the given transaction.
+func (s *Service) RetrieveRawTokens(_ context.Context, tx *Transaction) ([]string, error) {
+	if tx.ReceivedClaims == nil {
+		return nil, fmt.Errorf("received claims for tx %s: %w", tx.ID, ErrDataNotFound)
+	}
+
+	return tx.ReceivedClaims.RawVPTokens, nil
+}
+
+func (s *Service) DeleteClaims(ctx context.Context, claimsID string) error {
+	return s.transactionManager.DeleteReceivedClaims(ctx, claimsID)
+}
+
+// DeleteClaimsForProfile deletes received claims only if they were received by the given profile.
+func (s *Service) DeleteClaimsForProfile(
+	ctx context.Context,
+	receivedClaimsID, profileID, profileVersion string,
+) error {
+	tx, err := s.transactionManager.GetByClaimsID(ctx, receivedClaimsID)
+	if err != nil {
+		return fmt.Errorf("get tx by claims id: %w", err)
+	}
+
+	if tx.ProfileID != profileID || tx.ProfileVersion != profileVersion {
+		return ErrClaimsOwnershipViolation
+	}
+
+	return s.transactionManager.DeleteReceivedClaims(ctx, receivedClaimsID)
+}
+
+// AmendTransaction updates transaction of the given profile before the wallet responds to it.
+func (s *Service) AmendTransaction(
+	ctx context.Context,
+	txID TxID,
+	profileID, profileVersion string,
+	update *TransactionUpdate,
+) error {
+	tx, err := s.transactionManager.Get(ctx, txID)
+	if err != nil {
+		return fmt.Errorf("get tx: %w", err)
+	}
+
+	if tx.ProfileID != profileID || tx.ProfileVersion != profileVersion {
+		return ErrTxOwnershipViolation
+	}
+
+	if tx.ReceivedClaimsID != "" {
+		return ErrTxCompleted
+	}
+
+	return s.transactionManager.UpdateTx(ctx, txID, update)
+}
+
+// ReissueRequestObject signs a new request object for the pending transaction and publishes it again, so that
+// the transaction can be presented to another wallet, e.g. after the wallet crashed mid-flow. The new request
+// object carries a new nonce and is valid for the remaining lifetime of the transaction. Returns the URI the
+// request object is published at.
+func (s *Service) ReissueRequestObject(
+	ctx context.Context,
+	txID TxID,
+	profile *profileapi.Verifier,
+) (string, error) {
+	if err := s.beginCall(); err != nil {
+		return "", err
+	}
+	defer s.inFlight.Done()
+
+	tx, err := s.transactionManager.Get(ctx, txID)
+	if err != nil {
+		return "", fmt.Errorf("get tx: %w", err)
+	}
+
+	if tx.ProfileID != profile.ID || tx.ProfileVersion != profile.Version {
+		return "", ErrTxOwnershipViolation
+	}
+
+	// Failed transactions have their one time token consumed by the verification attempt as well.
+	if tx.Status != TransactionStatusPending || tx.ReceivedClaimsID != "" {
+		return "", ErrTxCompleted
+	}
+
+	if profile.SigningDID == nil {
+		return "", fmt.Errorf("%w: profile %s", ErrMissingSigningDID, profile.ID)
+	}
+
+	ttl := s.tokenLifetime
+	if !tx.ExpiresAt.IsZero() {
+		if ttl = time.Until(tx.ExpiresAt); ttl <= 0 {
+			return "", ErrTxExpired
+		}
+	}
+
+	nonce, err := s.transactionManager.CreateTxNonce(ctx, tx.ID, ttl)
+	if err != nil {
+		return "", fmt.Errorf("create tx nonce: %w", err)
+	}
+
+	token, err := s.createRequestObjectJWT(ctx, tx.PresentationDefinition, tx, nonce, tx.Purpose, profile, ttl)
+	if err != nil {
+		return "", err
+	}
+
+	accessRequestObjectEvent, err := s.createEvent(tx, profile, spi.VerifierOIDCInteractionQRScanned, nil)
+	if err != nil {
+		return "", err
+	}
+
+	requestURI, err := s.requestObjectPublicStore.Publish(ctx, token, accessRequestObjectEvent, ttl)
+	if err != nil {
+		return "", fmt.Errorf("fail publish request object: %w", err)
+	}
+
+	logger.Debugc(ctx, "ReissueRequestObject request object published", log.WithTxID(string(tx.ID)))
+
+	return requestURI, nil
 }
 
 func (s *Service) getDataIntegrityVerifier() (*dataintegrity.Verifier, error) {
@@ -467,7 +1037,7 @@ func (s *Service) extractClaimData(
 	tokens []*ProcessedVPToken,
 	profile *profileapi.Verifier,
 	verifiedPresentations map[string]*ProcessedVPToken,
-) error {
+) (map[string]*verifiable.Credential, error) {
 	var presentations []*verifiable.Presentation
 
 	for _, token := range tokens {
@@ -477,7 +1047,7 @@ func (s *Service) extractClaimData(
 	}
 	diVerifier, err := s.getDataIntegrityVerifier()
 	if err != nil {
-		return fmt.Errorf("get data integrity verifier: %w", err)
+		return nil, fmt.Errorf("get data integrity verifier: %w", err)
 	}
 
 	opts := []presexch.MatchOption{
@@ -489,45 +1059,344 @@ func (s *Service) extractClaimData(
 		presexch.WithDisableSchemaValidation(),
 	}
 
-	if len(presentations) > 1 {
-		opts = append(opts,
-			presexch.WithMergedSubmissionMap(presentations[0].CustomFields[vpSubmissionProperty].(map[string]interface{})))
-	}
+	var matchedCredentials map[string]presexch.MatchValue
 
-	matchedCredentials, err := tx.PresentationDefinition.Match(presentations, s.documentLoader, opts...)
-	if err != nil {
-		return fmt.Errorf("presentation definition match: %w", err)
+	if hasPerPresentationSubmissions(presentations) {
+		matchedCredentials, err = s.matchPerPresentationSubmissions(tx.PresentationDefinition, presentations, opts)
+		if err != nil {
+			return nil, fmt.Errorf("presentation definition match: %w", err)
+		}
+	} else {
+		if len(presentations) > 1 {
+			opts = append(opts,
+				presexch.WithMergedSubmissionMap(presentations[0].CustomFields[vpSubmissionProperty].(map[string]interface{})))
+		}
+
+		matchedCredentials, err = tx.PresentationDefinition.Match(presentations, s.documentLoader, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("presentation definition match: %w", err)
+		}
 	}
 
 	storeCredentials := make(map[string]*verifiable.Credential)
 
+	var presentationChecks profileapi.PresentationChecks
+
+	if profile.Checks != nil && profile.Checks.Presentation != nil {
+		presentationChecks = *profile.Checks.Presentation
+	}
+
 	for inputDescID, mc := range matchedCredentials {
-		if profile.Checks != nil && profile.Checks.Presentation != nil && profile.Checks.Presentation.VCSubject {
+		if presentationChecks.VCSubject || presentationChecks.RequireHolderBinding {
 			token, ok := verifiedPresentations[mc.PresentationID]
 			if !ok {
 				// this should never happen
-				return fmt.Errorf("missing verified presentation ID: %s", mc.PresentationID)
+				return nil, fmt.Errorf("missing verified presentation ID: %s", mc.PresentationID)
 			}
 
-			err = checkVCSubject(mc.Credential, token)
-			if err != nil {
-				return fmt.Errorf("extractClaimData vc subject: %w", err)
+			if presentationChecks.VCSubject {
+				err = checkVCSubject(mc.Credential, token)
+				if err != nil {
+					return nil, fmt.Errorf("extractClaimData vc subject: %w", err)
+				}
+
+				logger.Debugc(ctx, "vc subject verified")
 			}
 
-			logger.Debugc(ctx, "vc subject verified")
+			if presentationChecks.RequireHolderBinding {
+				err = s.checkHolderBinding(mc.Credential, token)
+				if err != nil {
+					return nil, fmt.Errorf("extractClaimData holder binding: %w", err)
+				}
+
+				logger.Debugc(ctx, "holder binding verified")
+			}
 		}
 
 		storeCredentials[inputDescID] = mc.Credential
 	}
 
-	err = s.transactionManager.StoreReceivedClaims(tx.ID, &ReceivedClaims{Credentials: storeCredentials})
+	return storeCredentials, nil
+}
+
+// hasPerPresentationSubmissions reports whether each of the presentations carries its own presentation
+// submission, as opposed to sharing the one merged submission from the id_token.
+func hasPerPresentationSubmissions(presentations []*verifiable.Presentation) bool {
+	if len(presentations) < 2 {
+		return false
+	}
+
+	submissionIDs := make(map[string]struct{}, len(presentations))
+
+	for _, vp := range presentations {
+		submission, ok := vp.CustomFields[vpSubmissionProperty].(map[string]interface{})
+		if !ok {
+			return false
+		}
+
+		id, _ := submission["id"].(string)
+		if id == "" {
+			return false
+		}
+
+		if _, ok = submissionIDs[id]; ok {
+			return false
+		}
+
+		submissionIDs[id] = struct{}{}
+	}
+
+	return true
+}
+
+// matchPerPresentationSubmissions matches each presentation independently against the input descriptors
+// referenced by its own presentation submission and aggregates the results.
+func (s *Service) matchPerPresentationSubmissions(
+	pd *presexch.PresentationDefinition,
+	presentations []*verifiable.Presentation,
+	opts []presexch.MatchOption,
+) (map[string]presexch.MatchValue, error) {
+	inputDescriptors := make(map[string]*presexch.InputDescriptor, len(pd.InputDescriptors))
+
+	for _, desc := range pd.InputDescriptors {
+		inputDescriptors[desc.ID] = desc
+	}
+
+	result := make(map[string]presexch.MatchValue, len(pd.InputDescriptors))
+
+	for _, vp := range presentations {
+		submission, err := parsePresentationSubmission(vp)
+		if err != nil {
+			return nil, err
+		}
+
+		vpDefinition := &presexch.PresentationDefinition{
+			ID:      pd.ID,
+			Name:    pd.Name,
+			Purpose: pd.Purpose,
+			Locale:  pd.Locale,
+			Format:  pd.Format,
+			Frame:   pd.Frame,
+		}
+
+		for _, mapping := range submission.DescriptorMap {
+			desc, ok := inputDescriptors[mapping.ID]
+			if !ok {
+				return nil, fmt.Errorf("submission %s: unknown input descriptor %s", submission.ID, mapping.ID)
+			}
+
+			if _, ok = result[mapping.ID]; ok {
+				return nil, fmt.Errorf("submission %s: input descriptor %s already matched", submission.ID, mapping.ID)
+			}
+
+			vpDefinition.InputDescriptors = append(vpDefinition.InputDescriptors, desc)
+		}
+
+		matched, err := vpDefinition.Match([]*verifiable.Presentation{vp}, s.documentLoader, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("submission %s: %w", submission.ID, err)
+		}
+
+		for descID, mv := range matched {
+			result[descID] = mv
+		}
+	}
+
+	for descID := range inputDescriptors {
+		if _, ok := result[descID]; !ok {
+			return nil, fmt.Errorf("no submission for input descriptor %s", descID)
+		}
+	}
+
+	return result, nil
+}
+
+func parsePresentationSubmission(vp *verifiable.Presentation) (*presexch.PresentationSubmission, error) {
+	raw, err := json.Marshal(vp.CustomFields[vpSubmissionProperty])
+	if err != nil {
+		return nil, fmt.Errorf("marshal presentation submission: %w", err)
+	}
+
+	var submission presexch.PresentationSubmission
+
+	if err = json.Unmarshal(raw, &submission); err != nil {
+		return nil, fmt.Errorf("unmarshal presentation submission: %w", err)
+	}
+
+	return &submission, nil
+}
+
+func (s *Service) validateCredentialSchemas(
+	ctx context.Context,
+	profile *profileapi.Verifier,
+	credentials map[string]*verifiable.Credential,
+) error {
+	if profile.Checks == nil || len(profile.Checks.CredentialSchemaURL) == 0 {
+		return nil
+	}
+
+	for inputDescID, cred := range credentials {
+		schemaURL, ok := profile.Checks.CredentialSchemaURL[inputDescID]
+		if !ok {
+			continue
+		}
+
+		if s.schemaValidator == nil {
+			return fmt.Errorf("schema validator is not configured")
+		}
+
+		if err := s.schemaValidator.Validate(ctx, schemaURL, cred); err != nil {
+			return fmt.Errorf("credential schema validation for input descriptor %s: %w", inputDescID, err)
+		}
+
+		logger.Debugc(ctx, "credential schema validated")
+	}
+
+	return nil
+}
+
+// checkTokensReceivedInTime checks that the tokens were received within the validity window of tx.
+// Tokens with zero ReceivedAt are considered received now.
+func checkTokensReceivedInTime(tx *Transaction, tokens []*ProcessedVPToken) error {
+	for _, token := range tokens {
+		if token.ReceivedAt.IsZero() {
+			token.ReceivedAt = time.Now()
+		}
+
+		if !tx.CreatedAt.IsZero() && token.ReceivedAt.Before(tx.CreatedAt) {
+			return fmt.Errorf("%w: received at %s, transaction created at %s", ErrTokenReceivedTooLate,
+				token.ReceivedAt.Format(time.RFC3339), tx.CreatedAt.Format(time.RFC3339))
+		}
+
+		if !tx.ExpiresAt.IsZero() && token.ReceivedAt.After(tx.ExpiresAt) {
+			return fmt.Errorf("%w: received at %s, transaction expired at %s", ErrTokenReceivedTooLate,
+				token.ReceivedAt.Format(time.RFC3339), tx.ExpiresAt.Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+func rawVPTokens(tokens []*ProcessedVPToken) []string {
+	var raw []string
+
+	for _, token := range tokens {
+		// Only JWT presentations keep the original signed token.
+		if token.Presentation != nil && token.Presentation.JWT != "" {
+			raw = append(raw, token.Presentation.JWT)
+		}
+	}
+
+	return raw
+}
+
+func credentialIDs(credentials map[string]*verifiable.Credential) []string {
+	ids := make([]string, 0, len(credentials))
+
+	for _, cred := range credentials {
+		ids = append(ids, cred.ID)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+func checkAllowedIssuers(profile *profileapi.Verifier, credentials map[string]*verifiable.Credential) error {
+	if profile.Checks == nil || len(profile.Checks.Credential.AllowedIssuers) == 0 {
+		return nil
+	}
+
+	for _, cred := range credentials {
+		if !lo.Contains(profile.Checks.Credential.AllowedIssuers, cred.Issuer.ID) {
+			return fmt.Errorf("%w: %s", ErrUntrustedIssuer, cred.Issuer.ID)
+		}
+	}
+
+	return nil
+}
+
+func checkCredentialAge(credentials map[string]*verifiable.Credential, maxAge time.Duration, now time.Time) error {
+	if maxAge == 0 {
+		return nil
+	}
+
+	for _, cred := range credentials {
+		if cred.Issued == nil {
+			return fmt.Errorf("%w: credential %s has no issuance date", ErrCredentialTooOld, cred.ID)
+		}
+
+		if now.Sub(cred.Issued.Time) > maxAge {
+			return fmt.Errorf("%w: credential %s issued at %s",
+				ErrCredentialTooOld, cred.ID, cred.Issued.Time.Format(time.RFC3339Nano))
+		}
+	}
+
+	return nil
+}
+
+// checkPresentationAge checks that the VP was created by the holder no earlier than maxAge before it was received.
+func checkPresentationAge(token *ProcessedVPToken, maxAge time.Duration) error {
+	if maxAge == 0 {
+		return nil
+	}
+
+	createdAt, err := presentationCreatedAt(token.Presentation)
 	if err != nil {
-		return fmt.Errorf("store received claims: %w", err)
+		return fmt.Errorf("%w: %w", ErrPresentationTooOld, err)
+	}
+
+	receivedAt := token.ReceivedAt
+	if receivedAt.IsZero() {
+		receivedAt = time.Now()
+	}
+
+	if receivedAt.Sub(createdAt) > maxAge {
+		return fmt.Errorf("%w: presentation %s created at %s",
+			ErrPresentationTooOld, token.Presentation.ID, createdAt.Format(time.RFC3339))
 	}
 
 	return nil
 }
 
+// presentationCreatedAt returns the "nbf" claim (or "iat" if "nbf" is not set) of the JWT VP and the creation
+// time of the proof of the LDP VP.
+func presentationCreatedAt(vp *verifiable.Presentation) (time.Time, error) {
+	if vp.JWT != "" {
+		_, rawClaims, err := jwt.Parse(
+			vp.JWT,
+			jwt.WithSignatureVerifier(&noVerifier{}),
+			jwt.WithIgnoreClaimsMapDecoding(true),
+		)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("parse presentation jwt: %w", err)
+		}
+
+		if nbf := fastjson.GetInt(rawClaims, "nbf"); nbf != 0 {
+			return time.Unix(int64(nbf), 0), nil
+		}
+
+		if iat := fastjson.GetInt(rawClaims, "iat"); iat != 0 {
+			return time.Unix(int64(iat), 0), nil
+		}
+
+		return time.Time{}, fmt.Errorf("presentation %s has no nbf or iat claim", vp.ID)
+	}
+
+	if len(vp.Proofs) > 0 {
+		if created, ok := vp.Proofs[0]["created"].(string); ok {
+			createdAt, err := time.Parse(time.RFC3339, created)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("parse presentation proof created: %w", err)
+			}
+
+			return createdAt, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("presentation %s has no creation time", vp.ID)
+}
+
 func checkVCSubject(cred *verifiable.Credential, token *ProcessedVPToken) error {
 	subjectID, err := verifiable.SubjectID(cred.Subject)
 	if err != nil {
@@ -556,48 +1425,123 @@ func checkVCSubject(cred *verifiable.Credential, token *ProcessedVPToken) error
 	return nil
 }
 
-func (s *Service) createRequestObjectJWT(presentationDefinition *presexch.PresentationDefinition,
+// checkHolderBinding checks that the VP is signed by the credential subject with a key from the authentication
+// verification relationship of the subject DID document.
+func (s *Service) checkHolderBinding(cred *verifiable.Credential, token *ProcessedVPToken) error {
+	if err := checkVCSubject(cred, token); err != nil {
+		return fmt.Errorf("%w: %w", ErrHolderBindingViolation, err)
+	}
+
+	docResolution, err := s.vdr.Resolve(token.SignerDIDID)
+	if err != nil {
+		return fmt.Errorf("resolve signer did %s: %w", token.SignerDIDID, err)
+	}
+
+	signerKeyID := absoluteKeyID(token.SignerDIDID, token.SignerKeyID)
+
+	authMethods := docResolution.DIDDocument.VerificationMethods(did.Authentication)[did.Authentication]
+
+	for _, vm := range authMethods {
+		if absoluteKeyID(token.SignerDIDID, vm.VerificationMethod.ID) == signerKeyID {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: vp signing key(%s) is not an authentication key of %s",
+		ErrHolderBindingViolation, token.SignerKeyID, token.SignerDIDID)
+}
+
+// validateDIDSyntax checks that didID has the did:<method>:<method-specific-id> form, so that a malformed signer
+// is reported as such instead of as a DID resolution failure.
+func validateDIDSyntax(didID string) error {
+	parts := strings.SplitN(didID, ":", 3) //nolint:gomnd
+
+	if len(parts) < 3 || parts[0] != "did" || parts[1] == "" || parts[2] == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidDIDSyntax, didID)
+	}
+
+	return nil
+}
+
+// checkSignerDID checks the syntax of the VP signer DID and that its method is allowed to be resolved.
+func (s *Service) checkSignerDID(didID string) error {
+	if err := validateDIDSyntax(didID); err != nil {
+		return err
+	}
+
+	if len(s.allowedVDRMethods) == 0 {
+		return nil
+	}
+
+	method := strings.Split(didID, ":")[1]
+
+	if !lo.Contains(s.allowedVDRMethods, method) {
+		return fmt.Errorf("%w: %s", ErrDisallowedDIDMethod, method)
+	}
+
+	return nil
+}
+
+func absoluteKeyID(didID, keyID string) string {
+	if strings.HasPrefix(keyID, "#") {
+		return didID + keyID
+	}
+
+	return keyID
+}
+
+func (s *Service) createRequestObjectJWT(
+	ctx context.Context,
+	presentationDefinition *presexch.PresentationDefinition,
 	tx *Transaction,
 	nonce string,
 	purpose string,
-	profile *profileapi.Verifier) (string, error) {
-	kms, err := s.kmsRegistry.GetKeyManager(profile.KMSConfig)
+	profile *profileapi.Verifier,
+	tokenLifetime time.Duration) (string, error) {
+	keyTypes, err := s.supportedKeyTypes(profile)
 	if err != nil {
 		return "", fmt.Errorf("initiate oidc interaction: get key manager failed: %w", err)
 	}
 
 	vpFormats := GetSupportedVPFormats(
-		kms.SupportedKeyTypes(), profile.Checks.Presentation.Format, profile.Checks.Credential.Format)
+		keyTypes, profile.Checks.Presentation.Format, profile.Checks.Credential.Format)
 
-	ro := s.createRequestObject(presentationDefinition, vpFormats, tx, nonce, purpose, profile)
+	ro := s.createRequestObject(presentationDefinition, vpFormats, tx, nonce, purpose, profile, tokenLifetime)
 
-	signatureTypes := vcsverifiable.GetSignatureTypesByKeyTypeFormat(profile.OIDCConfig.KeyType, vcsverifiable.Jwt)
-	if len(signatureTypes) < 1 {
-		return "", fmt.Errorf("unsupported jwt key type %s", profile.OIDCConfig.KeyType)
+	claims, err := jwt.PayloadToMap(ro)
+	if err != nil {
+		return "", fmt.Errorf("initiate oidc interaction: %w", err)
 	}
 
-	vcsSigner, err := kms.NewVCSigner(profile.SigningDID.KMSKeyID, signatureTypes[0])
+	token, err := s.requestObjectSigner.Sign(ctx, claims, profile)
 	if err != nil {
-		return "", fmt.Errorf("initiate oidc interaction: get create signer failed: %w", err)
+		return "", fmt.Errorf("initiate oidc interaction: %w", err)
 	}
 
-	return singRequestObject(ro, profile, vcsSigner)
+	if s.maxRequestObjectSize > 0 && int64(len(token)) > s.maxRequestObjectSize {
+		return "", fmt.Errorf("%w: got %d bytes, max %d", ErrRequestObjectTooLarge, len(token), s.maxRequestObjectSize)
+	}
+
+	return token, nil
 }
 
-func singRequestObject(ro *RequestObject, profile *profileapi.Verifier, vcsSigner vc.SignerAlgorithm) (string, error) {
-	signer := NewJWSSigner(profile.SigningDID.Creator, vcsSigner)
+// supportedKeyTypes returns the key types of the request object vp formats. An external request object signer
+// doesn't use VCS KMS, so only the profile key type is returned for it.
+func (s *Service) supportedKeyTypes(profile *profileapi.Verifier) ([]kmsapi.KeyType, error) {
+	if _, ok := s.requestObjectSigner.(*KMSRequestObjectSigner); !ok {
+		if profile.OIDCConfig == nil || profile.OIDCConfig.KeyType == "" {
+			return nil, nil
+		}
 
-	token, err := jwt.NewSigned(ro, nil, signer)
-	if err != nil {
-		return "", fmt.Errorf("initiate oidc interaction: sign token failed: %w", err)
+		return []kmsapi.KeyType{profile.OIDCConfig.KeyType}, nil
 	}
 
-	tokenBytes, err := token.Serialize(false)
+	kms, err := s.kmsRegistry.GetKeyManager(profile.KMSConfig)
 	if err != nil {
-		return "", fmt.Errorf("initiate oidc interaction: serialize token failed: %w", err)
+		return nil, err
 	}
 
-	return tokenBytes, nil
+	return kms.SupportedKeyTypes(), nil
 }
 
 func GetSupportedVPFormats(
@@ -657,15 +1601,16 @@ func (s *Service) createRequestObject(
 	tx *Transaction,
 	nonce string,
 	purpose string,
-	profile *profileapi.Verifier) *RequestObject {
-	tokenLifetime := s.tokenLifetime
+	profile *profileapi.Verifier,
+	tokenLifetime time.Duration) *RequestObject {
 	now := time.Now()
-	return &RequestObject{
+
+	ro := &RequestObject{
 		JTI:          uuid.New().String(),
 		IAT:          now.Unix(),
 		ISS:          profile.SigningDID.DID,
 		ResponseType: "id_token",
-		ResponseMode: "post",
+		ResponseMode: profile.OIDCConfig.GetResponseMode(),
 		Scope:        "openid",
 		Nonce:        nonce,
 		ClientID:     profile.SigningDID.DID,
@@ -683,6 +1628,15 @@ func (s *Service) createRequestObject(
 			presentationDefinition,
 		}},
 	}
+
+	// redirect_uri must not be present with direct_post (OpenID4VP, section 6.2)
+	if ro.ResponseMode == profileapi.ResponseModeDirectPost {
+		ro.RedirectURI = ""
+		ro.ResponseURI = fmt.Sprintf("%s/verifier/%s/%s/interactions/authorization-response",
+			strings.TrimSuffix(s.publicURL, "/"), url.PathEscape(profile.ID), url.PathEscape(profile.Version))
+	}
+
+	return ro
 }
 
 type JWSSigner struct {
@@ -0,0 +1,54 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	utiltime "github.com/trustbloc/did-go/doc/util/time"
+	"github.com/trustbloc/vc-go/verifiable"
+)
+
+func TestCheckCredentialAge(t *testing.T) {
+	const maxAge = 24 * time.Hour
+
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	newCredentials := func(issued *utiltime.TimeWrapper) map[string]*verifiable.Credential {
+		return map[string]*verifiable.Credential{
+			"input-descriptor-1": {
+				ID:     "http://example.edu/credentials/1872",
+				Issued: issued,
+			},
+		}
+	}
+
+	t.Run("At boundary", func(t *testing.T) {
+		err := checkCredentialAge(newCredentials(utiltime.NewTime(now.Add(-maxAge))), maxAge, now)
+		require.NoError(t, err)
+	})
+
+	t.Run("One nanosecond over boundary", func(t *testing.T) {
+		err := checkCredentialAge(newCredentials(utiltime.NewTime(now.Add(-maxAge-time.Nanosecond))), maxAge, now)
+		require.ErrorIs(t, err, ErrCredentialTooOld)
+		require.ErrorContains(t, err, "http://example.edu/credentials/1872")
+		require.ErrorContains(t, err, "2023-05-31T11:59:59.999999999Z")
+	})
+
+	t.Run("Check disabled", func(t *testing.T) {
+		err := checkCredentialAge(newCredentials(utiltime.NewTime(now.Add(-10*maxAge))), 0, now)
+		require.NoError(t, err)
+	})
+
+	t.Run("No issuance date", func(t *testing.T) {
+		err := checkCredentialAge(newCredentials(nil), maxAge, now)
+		require.ErrorIs(t, err, ErrCredentialTooOld)
+		require.ErrorContains(t, err, "has no issuance date")
+	})
+}
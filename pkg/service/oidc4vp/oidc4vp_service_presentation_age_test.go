@@ -0,0 +1,101 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/vc-go/jwt"
+	"github.com/trustbloc/vc-go/verifiable"
+)
+
+func TestCheckPresentationAge(t *testing.T) {
+	const maxAge = 10 * time.Minute
+
+	receivedAt := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	newJWTToken := func(t *testing.T, claims map[string]interface{}) *ProcessedVPToken {
+		t.Helper()
+
+		token, err := jwt.NewUnsecured(claims, nil)
+		require.NoError(t, err)
+
+		vpJWT, err := token.Serialize(false)
+		require.NoError(t, err)
+
+		return &ProcessedVPToken{
+			Presentation: &verifiable.Presentation{ID: "urn:uuid:vp-1", JWT: vpJWT},
+			ReceivedAt:   receivedAt,
+		}
+	}
+
+	newLDPToken := func(created string) *ProcessedVPToken {
+		return &ProcessedVPToken{
+			Presentation: &verifiable.Presentation{
+				ID:     "urn:uuid:vp-1",
+				Proofs: []verifiable.Proof{{"created": created}},
+			},
+			ReceivedAt: receivedAt,
+		}
+	}
+
+	t.Run("JWT at boundary", func(t *testing.T) {
+		token := newJWTToken(t, map[string]interface{}{"nbf": receivedAt.Add(-maxAge).Unix()})
+
+		require.NoError(t, checkPresentationAge(token, maxAge))
+	})
+
+	t.Run("JWT backdated by max age plus one second", func(t *testing.T) {
+		token := newJWTToken(t, map[string]interface{}{"nbf": receivedAt.Add(-maxAge - time.Second).Unix()})
+
+		err := checkPresentationAge(token, maxAge)
+		require.ErrorIs(t, err, ErrPresentationTooOld)
+		require.ErrorContains(t, err, "presentation urn:uuid:vp-1 created at 2023-06-01T11:49:59Z")
+	})
+
+	t.Run("JWT iat used when nbf is not set", func(t *testing.T) {
+		token := newJWTToken(t, map[string]interface{}{"iat": receivedAt.Add(-maxAge - time.Second).Unix()})
+
+		require.ErrorIs(t, checkPresentationAge(token, maxAge), ErrPresentationTooOld)
+	})
+
+	t.Run("JWT without nbf and iat", func(t *testing.T) {
+		token := newJWTToken(t, map[string]interface{}{"nonce": "nonce"})
+
+		err := checkPresentationAge(token, maxAge)
+		require.ErrorIs(t, err, ErrPresentationTooOld)
+		require.ErrorContains(t, err, "has no nbf or iat claim")
+	})
+
+	t.Run("LDP proof created", func(t *testing.T) {
+		require.NoError(t, checkPresentationAge(
+			newLDPToken(receivedAt.Add(-time.Minute).Format(time.RFC3339)), maxAge))
+
+		require.ErrorIs(t, checkPresentationAge(
+			newLDPToken(receivedAt.Add(-maxAge-time.Second).Format(time.RFC3339)), maxAge), ErrPresentationTooOld)
+	})
+
+	t.Run("LDP invalid proof created", func(t *testing.T) {
+		err := checkPresentationAge(newLDPToken("yesterday"), maxAge)
+		require.ErrorIs(t, err, ErrPresentationTooOld)
+		require.ErrorContains(t, err, "parse presentation proof created")
+	})
+
+	t.Run("No creation time", func(t *testing.T) {
+		err := checkPresentationAge(&ProcessedVPToken{Presentation: &verifiable.Presentation{}}, maxAge)
+		require.ErrorIs(t, err, ErrPresentationTooOld)
+		require.ErrorContains(t, err, "has no creation time")
+	})
+
+	t.Run("Check disabled", func(t *testing.T) {
+		token := newJWTToken(t, map[string]interface{}{"nbf": receivedAt.Add(-10 * maxAge).Unix()})
+
+		require.NoError(t, checkPresentationAge(token, 0))
+	})
+}
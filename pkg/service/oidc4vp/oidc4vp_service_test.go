@@ -7,17 +7,27 @@ SPDX-License-Identifier: Apache-2.0
 package oidc4vp_test
 
 import (
+	"bytes"
 	"context"
 	_ "embed"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/jinzhu/copier"
+	"github.com/makiuchi-d/gozxing"
+	gozxingqr "github.com/makiuchi-d/gozxing/qrcode"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -29,6 +39,7 @@ import (
 	vdrapi "github.com/trustbloc/did-go/vdr/api"
 	vdrmock "github.com/trustbloc/did-go/vdr/mock"
 	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/kms-go/doc/jose"
 	"github.com/trustbloc/kms-go/doc/jose/jwk"
 	"github.com/trustbloc/kms-go/doc/util/fingerprint"
 	"github.com/trustbloc/kms-go/doc/util/jwkkid"
@@ -37,6 +48,7 @@ import (
 	"github.com/trustbloc/kms-go/secretlock/noop"
 	ariescrypto "github.com/trustbloc/kms-go/spi/crypto"
 	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/vc-go/jwt"
 	"github.com/trustbloc/vc-go/presexch"
 	"github.com/trustbloc/vc-go/signature/suite"
 	"github.com/trustbloc/vc-go/verifiable"
@@ -48,6 +60,8 @@ import (
 	"github.com/trustbloc/vcs/pkg/kms/signer"
 	profileapi "github.com/trustbloc/vcs/pkg/profile"
 	"github.com/trustbloc/vcs/pkg/service/oidc4vp"
+	"github.com/trustbloc/vcs/pkg/service/oidc4vp/oidc4vptest"
+	"github.com/trustbloc/vcs/pkg/service/verifypresentation"
 )
 
 var (
@@ -73,20 +87,23 @@ func TestService_InitiateOidcInteraction(t *testing.T) {
 		&mockVCSKeyManager{crypto: customCrypto, kms: customKMS}, nil)
 
 	txManager := NewMockTransactionManager(gomock.NewController(t))
-	txManager.EXPECT().CreateTx(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(&oidc4vp.Transaction{
-		ID:                     "TxID1",
-		ProfileID:              "test4",
-		PresentationDefinition: &presexch.PresentationDefinition{},
-	}, "nonce1", nil)
+	txManager.EXPECT().CreateTx(
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		Return(&oidc4vp.Transaction{
+			ID:                     "TxID1",
+			ProfileID:              "test4",
+			PresentationDefinition: &presexch.PresentationDefinition{},
+		}, "nonce1", nil)
 	requestObjectPublicStore := NewMockRequestObjectPublicStore(gomock.NewController(t))
-	requestObjectPublicStore.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).
-		AnyTimes().DoAndReturn(func(ctx context.Context, token string, event *spi.Event) (string, error) {
+	requestObjectPublicStore.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		AnyTimes().DoAndReturn(func(ctx context.Context, token string, event *spi.Event,
+		ttl time.Duration) (string, error) {
 		return "someurl/abc", nil
 	})
 
 	s := oidc4vp.NewService(&oidc4vp.Config{
 		EventSvc:                 &mockEvent{},
-		EventTopic:               spi.VerifierEventTopic,
+		EventTopics:              []string{spi.VerifierEventTopic},
 		TransactionManager:       txManager,
 		RequestObjectPublicStore: requestObjectPublicStore,
 		KMSRegistry:              kmsRegistry,
@@ -131,6 +148,392 @@ func TestService_InitiateOidcInteraction(t *testing.T) {
 			ID: "test",
 		}, "test", correctProfile)
 
+		require.NoError(t, err)
+		require.NotNil(t, info)
+		require.Empty(t, info.QRCodeData)
+	})
+
+	t.Run("Success - deep link URL", func(t *testing.T) {
+		const requestURI = "https://verifier.example.com/request-object/abc?tenant=a b&lang=en"
+
+		store := NewMockRequestObjectPublicStore(gomock.NewController(t))
+		store.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			AnyTimes().Return(requestURI, nil)
+
+		svc := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:                 &mockEvent{},
+			EventTopics:              []string{spi.VerifierEventTopic},
+			TransactionManager:       txManager,
+			RequestObjectPublicStore: store,
+			KMSRegistry:              kmsRegistry,
+			TokenLifetime:            time.Second * 100,
+		})
+
+		info, err := svc.InitiateOidcInteraction(context.TODO(), &presexch.PresentationDefinition{
+			ID: "test",
+		}, "test", correctProfile)
+		require.NoError(t, err)
+		require.Equal(t, "openid-vc://?request_uri="+
+			"https%3A%2F%2Fverifier.example.com%2Frequest-object%2Fabc%3Ftenant%3Da+b%26lang%3Den",
+			info.DeepLinkURL)
+
+		walletSchemeProfile := &profileapi.Verifier{}
+		require.NoError(t, copier.Copy(walletSchemeProfile, correctProfile))
+		walletSchemeProfile.OIDCConfig = &profileapi.OIDC4VPConfig{
+			KeyType:      correctProfile.OIDCConfig.KeyType,
+			WalletScheme: "examplewallet://",
+		}
+
+		info, err = svc.InitiateOidcInteraction(context.TODO(), &presexch.PresentationDefinition{
+			ID: "test",
+		}, "test", walletSchemeProfile)
+		require.NoError(t, err)
+
+		deepLink, err := url.Parse(info.DeepLinkURL)
+		require.NoError(t, err)
+		require.Equal(t, "examplewallet", deepLink.Scheme)
+		require.Equal(t, requestURI, deepLink.Query().Get("request_uri"))
+	})
+
+	t.Run("Success - presentation definition by reference", func(t *testing.T) {
+		const pdURI = "https://verifier.example.com/pd/driver-license"
+
+		resolved := &presexch.PresentationDefinition{ID: "driver-license"}
+
+		pdTxManager := NewMockTransactionManager(gomock.NewController(t))
+		pdTxManager.EXPECT().CreateTx(
+			gomock.Any(), resolved, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&oidc4vp.Transaction{
+				ID:                     "TxID1",
+				ProfileID:              "test4",
+				PresentationDefinition: resolved,
+			}, "nonce1", nil)
+
+		resolver := &mockPresentationDefinitionResolver{pd: resolved}
+
+		svc := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:                 &mockEvent{},
+			EventTopics:              []string{spi.VerifierEventTopic},
+			TransactionManager:       pdTxManager,
+			RequestObjectPublicStore: requestObjectPublicStore,
+			KMSRegistry:              kmsRegistry,
+			RedirectURL:              "test://redirect",
+			TokenLifetime:            time.Second * 100,
+		}, oidc4vp.WithPresentationDefinitionResolver(resolver))
+
+		info, err := svc.InitiateOidcInteraction(context.TODO(), &presexch.PresentationDefinition{
+			ID: pdURI,
+		}, "test", correctProfile)
+		require.NoError(t, err)
+		require.NotNil(t, info)
+		require.Equal(t, pdURI, resolver.uri)
+	})
+
+	t.Run("Success - presentation definition by reference is not resolved by default", func(t *testing.T) {
+		pd := &presexch.PresentationDefinition{ID: "https://verifier.example.com/pd/driver-license"}
+
+		pdTxManager := NewMockTransactionManager(gomock.NewController(t))
+		pdTxManager.EXPECT().CreateTx(
+			gomock.Any(), pd, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&oidc4vp.Transaction{
+				ID:                     "TxID1",
+				ProfileID:              "test4",
+				PresentationDefinition: pd,
+			}, "nonce1", nil)
+
+		svc := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:                 &mockEvent{},
+			EventTopics:              []string{spi.VerifierEventTopic},
+			TransactionManager:       pdTxManager,
+			RequestObjectPublicStore: requestObjectPublicStore,
+			KMSRegistry:              kmsRegistry,
+			RedirectURL:              "test://redirect",
+			TokenLifetime:            time.Second * 100,
+		})
+
+		info, err := svc.InitiateOidcInteraction(context.TODO(), pd, "test", correctProfile)
+		require.NoError(t, err)
+		require.NotNil(t, info)
+	})
+
+	t.Run("Success - presentation definition resolved with HTTP client", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"id":"driver-license"}`))
+		}))
+		defer srv.Close()
+
+		pdTxManager := NewMockTransactionManager(gomock.NewController(t))
+		pdTxManager.EXPECT().CreateTx(gomock.Any(), &presexch.PresentationDefinition{ID: "driver-license"},
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&oidc4vp.Transaction{
+				ID:                     "TxID1",
+				ProfileID:              "test4",
+				PresentationDefinition: &presexch.PresentationDefinition{ID: "driver-license"},
+			}, "nonce1", nil)
+
+		svc := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:                         &mockEvent{},
+			EventTopics:                      []string{spi.VerifierEventTopic},
+			TransactionManager:               pdTxManager,
+			RequestObjectPublicStore:         requestObjectPublicStore,
+			KMSRegistry:                      kmsRegistry,
+			RedirectURL:                      "test://redirect",
+			TokenLifetime:                    time.Second * 100,
+			ResolvePresentationDefinitionURI: true,
+			HTTPClient:                       srv.Client(),
+		})
+
+		info, err := svc.InitiateOidcInteraction(context.TODO(), &presexch.PresentationDefinition{
+			ID: srv.URL + "/pd/driver-license",
+		}, "test", correctProfile)
+		require.NoError(t, err)
+		require.NotNil(t, info)
+	})
+
+	t.Run("Error - resolve presentation definition", func(t *testing.T) {
+		eventSvc := &mockEvent{}
+
+		svc := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:                       eventSvc,
+			EventTopics:                    []string{spi.VerifierEventTopic},
+			TransactionManager:             txManager,
+			RequestObjectPublicStore:       requestObjectPublicStore,
+			KMSRegistry:                    kmsRegistry,
+			PresentationDefinitionResolver: &mockPresentationDefinitionResolver{err: errors.New("not found")},
+		})
+
+		info, err := svc.InitiateOidcInteraction(context.TODO(), &presexch.PresentationDefinition{
+			ID: "https://verifier.example.com/pd/unknown",
+		}, "test", correctProfile)
+		require.ErrorContains(t, err,
+			"resolve presentation definition https://verifier.example.com/pd/unknown: not found")
+		require.Nil(t, info)
+
+		failed := findEvent(eventSvc.published[spi.VerifierEventTopic], spi.VerifierOIDCInteractionInitiationFailed)
+		require.NotNil(t, failed)
+
+		var payload map[string]interface{}
+		require.NoError(t, json.Unmarshal(failed.Data, &payload))
+		require.Equal(t, oidc4vp.ErrCodeResolvePresentationDefinitionFailed, payload["errorCode"])
+	})
+
+	t.Run("Signing algorithm override", func(t *testing.T) {
+		newService := func(algorithm verifiable.JWSAlgorithm, store *MockRequestObjectPublicStore) *oidc4vp.Service {
+			return oidc4vp.NewService(&oidc4vp.Config{
+				EventSvc:                 &mockEvent{},
+				EventTopics:              []string{spi.VerifierEventTopic},
+				TransactionManager:       txManager,
+				RequestObjectPublicStore: store,
+				KMSRegistry:              kmsRegistry,
+			}, oidc4vp.WithSigningAlgorithmOverride(algorithm))
+		}
+
+		t.Run("Compatible with key type", func(t *testing.T) {
+			var requestObject string
+
+			store := NewMockRequestObjectPublicStore(gomock.NewController(t))
+			store.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(1).DoAndReturn(func(_ context.Context, token string, _ *spi.Event,
+				_ time.Duration) (string, error) {
+				requestObject = token
+
+				return "someurl/abc", nil
+			})
+
+			info, err := newService(verifiable.EdDSA, store).InitiateOidcInteraction(context.TODO(),
+				&presexch.PresentationDefinition{ID: "test"}, "test", correctProfile)
+			require.NoError(t, err)
+			require.NotNil(t, info)
+
+			token, _, err := jwt.Parse(requestObject, jwt.WithSignatureVerifier(jose.SignatureVerifierFunc(
+				func(_ jose.Headers, _, _, _ []byte) error { return nil })))
+			require.NoError(t, err)
+
+			alg, ok := token.Headers.Algorithm()
+			require.True(t, ok)
+			require.Equal(t, "EdDSA", alg)
+		})
+
+		t.Run("Incompatible with key type", func(t *testing.T) {
+			info, err := newService(verifiable.ECDSASecp256r1, NewMockRequestObjectPublicStore(gomock.NewController(t))).
+				InitiateOidcInteraction(context.TODO(), &presexch.PresentationDefinition{ID: "test"}, "test",
+					correctProfile)
+			require.ErrorIs(t, err, oidc4vp.ErrAlgorithmKeyMismatch)
+			require.ErrorContains(t, err, "algorithm ES256, key type ED25519")
+			require.Nil(t, info)
+		})
+	})
+
+	t.Run("Error - request object too large", func(t *testing.T) {
+		pd := &presexch.PresentationDefinition{ID: "large"}
+
+		for i := 0; i < 1000; i++ {
+			pd.InputDescriptors = append(pd.InputDescriptors, &presexch.InputDescriptor{
+				ID: fmt.Sprintf("descriptor-%d", i),
+				Constraints: &presexch.Constraints{
+					Fields: []*presexch.Field{{Path: []string{"$.credentialSubject.id"}}},
+				},
+			})
+		}
+
+		newService := func(maxSize int64, store *MockRequestObjectPublicStore) *oidc4vp.Service {
+			return oidc4vp.NewService(&oidc4vp.Config{
+				EventSvc:                 &mockEvent{},
+				EventTopics:              []string{spi.VerifierEventTopic},
+				TransactionManager:       txManager,
+				RequestObjectPublicStore: store,
+				KMSRegistry:              kmsRegistry,
+				MaxRequestObjectSize:     maxSize,
+			})
+		}
+
+		// Publish is not expected to be called.
+		info, err := newService(64*1024, NewMockRequestObjectPublicStore(gomock.NewController(t))).
+			InitiateOidcInteraction(context.TODO(), pd, "test", correctProfile)
+		require.ErrorIs(t, err, oidc4vp.ErrRequestObjectTooLarge)
+		require.Nil(t, info)
+
+		info, err = newService(0, requestObjectPublicStore).
+			InitiateOidcInteraction(context.TODO(), pd, "test", correctProfile)
+		require.NoError(t, err)
+		require.NotNil(t, info)
+	})
+
+	t.Run("Success - QR code", func(t *testing.T) {
+		const qrCodeSize = 200
+
+		svc := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:                 &mockEvent{},
+			EventTopics:              []string{spi.VerifierEventTopic},
+			TransactionManager:       txManager,
+			RequestObjectPublicStore: requestObjectPublicStore,
+			KMSRegistry:              kmsRegistry,
+			RedirectURL:              "test://redirect",
+			TokenLifetime:            time.Second * 100,
+			GenerateQRCode:           true,
+			QRCodeSize:               qrCodeSize,
+		})
+
+		info, err := svc.InitiateOidcInteraction(context.TODO(), &presexch.PresentationDefinition{
+			ID: "test",
+		}, "test", correctProfile)
+		require.NoError(t, err)
+
+		require.True(t, strings.HasPrefix(info.QRCodeData, "data:image/png;base64,"))
+
+		b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(info.QRCodeData, "data:image/png;base64,"))
+		require.NoError(t, err)
+
+		img, err := png.Decode(bytes.NewReader(b))
+		require.NoError(t, err)
+		require.Equal(t, qrCodeSize, img.Bounds().Dx())
+
+		bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+		require.NoError(t, err)
+
+		result, err := gozxingqr.NewQRCodeReader().Decode(bmp, nil)
+		require.NoError(t, err)
+		require.Equal(t, info.AuthorizationRequest, result.GetText())
+	})
+
+	t.Run("Success - QR code with default size", func(t *testing.T) {
+		svc := oidc4vp.NewService(nil,
+			oidc4vp.WithEventService(&mockEvent{}, spi.VerifierEventTopic),
+			oidc4vp.WithTransactionManager(txManager),
+			oidc4vp.WithRequestObjectPublicStore(requestObjectPublicStore),
+			oidc4vp.WithKMSRegistry(kmsRegistry),
+			oidc4vp.WithTokenLifetime(time.Second*100),
+			oidc4vp.WithQRCode(0),
+		)
+
+		info, err := svc.InitiateOidcInteraction(context.TODO(), &presexch.PresentationDefinition{
+			ID: "test",
+		}, "test", correctProfile)
+		require.NoError(t, err)
+
+		b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(info.QRCodeData, "data:image/png;base64,"))
+		require.NoError(t, err)
+
+		img, err := png.Decode(bytes.NewReader(b))
+		require.NoError(t, err)
+		require.Equal(t, 256, img.Bounds().Dx())
+	})
+
+	t.Run("Success - request object ttl override", func(t *testing.T) {
+		ttl := 30 * time.Second
+
+		txManagerTTL := NewMockTransactionManager(gomock.NewController(t))
+		txManagerTTL.EXPECT().CreateTx(
+			gomock.Any(), gomock.Any(), correctProfile.ID, correctProfile.Version, "test", ttl, nil).
+			Times(1).Return(&oidc4vp.Transaction{
+			ID:                     "TxID1",
+			ProfileID:              "test4",
+			PresentationDefinition: &presexch.PresentationDefinition{},
+		}, "nonce1", nil)
+
+		var requestObject string
+
+		requestObjectPublicStoreTTL := NewMockRequestObjectPublicStore(gomock.NewController(t))
+		requestObjectPublicStoreTTL.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any(), ttl).
+			Times(1).DoAndReturn(func(ctx context.Context, token string, event *spi.Event,
+			ttl time.Duration) (string, error) {
+			requestObject = token
+
+			return "someurl/abc", nil
+		})
+
+		withTTL := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:                 &mockEvent{},
+			EventTopics:              []string{spi.VerifierEventTopic},
+			TransactionManager:       txManagerTTL,
+			RequestObjectPublicStore: requestObjectPublicStoreTTL,
+			KMSRegistry:              kmsRegistry,
+			RedirectURL:              "test://redirect",
+			TokenLifetime:            time.Second * 100,
+		})
+
+		info, err := withTTL.InitiateOidcInteraction(context.TODO(), &presexch.PresentationDefinition{
+			ID: "test",
+		}, "test", correctProfile, oidc4vp.WithRequestObjectTTL(ttl))
+
+		require.NoError(t, err)
+		require.NotNil(t, info)
+
+		token, _, err := jwt.Parse(requestObject, jwt.WithSignatureVerifier(jose.SignatureVerifierFunc(
+			func(_ jose.Headers, _, _, _ []byte) error { return nil })))
+		require.NoError(t, err)
+
+		ro := &oidc4vp.RequestObject{}
+		require.NoError(t, token.DecodeClaims(ro))
+		require.Equal(t, ttl, time.Duration(ro.Exp-ro.IAT)*time.Second)
+	})
+
+	t.Run("Success - custom data", func(t *testing.T) {
+		customData := map[string]interface{}{"sessionID": "session-1"}
+
+		txManagerCustomData := NewMockTransactionManager(gomock.NewController(t))
+		txManagerCustomData.EXPECT().CreateTx(
+			gomock.Any(), gomock.Any(), correctProfile.ID, correctProfile.Version, gomock.Any(), gomock.Any(), customData).
+			Times(1).Return(&oidc4vp.Transaction{
+			ID:                     "TxID1",
+			ProfileID:              "test4",
+			PresentationDefinition: &presexch.PresentationDefinition{},
+			CustomData:             customData,
+		}, "nonce1", nil)
+
+		withCustomData := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:                 &mockEvent{},
+			EventTopics:              []string{spi.VerifierEventTopic},
+			TransactionManager:       txManagerCustomData,
+			RequestObjectPublicStore: requestObjectPublicStore,
+			KMSRegistry:              kmsRegistry,
+			RedirectURL:              "test://redirect",
+		})
+
+		info, err := withCustomData.InitiateOidcInteraction(context.TODO(), &presexch.PresentationDefinition{
+			ID: "test",
+		}, "test", correctProfile, oidc4vp.WithCustomData(customData))
+
 		require.NoError(t, err)
 		require.NotNil(t, info)
 	})
@@ -142,18 +545,20 @@ func TestService_InitiateOidcInteraction(t *testing.T) {
 
 		info, err := s.InitiateOidcInteraction(context.TODO(), &presexch.PresentationDefinition{}, "test", incorrectProfile)
 
-		require.Error(t, err)
+		require.ErrorIs(t, err, oidc4vp.ErrMissingSigningDID)
+		require.ErrorContains(t, err, incorrectProfile.ID)
 		require.Nil(t, info)
 	})
 
 	t.Run("Tx create failed", func(t *testing.T) {
 		txManagerErr := NewMockTransactionManager(gomock.NewController(t))
 		txManagerErr.EXPECT().CreateTx(
-			gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil, "", errors.New("fail"))
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			AnyTimes().Return(nil, "", errors.New("fail"))
 
 		withError := oidc4vp.NewService(&oidc4vp.Config{
 			EventSvc:                 &mockEvent{},
-			EventTopic:               spi.VerifierEventTopic,
+			EventTopics:              []string{spi.VerifierEventTopic},
 			TransactionManager:       txManagerErr,
 			RequestObjectPublicStore: requestObjectPublicStore,
 			KMSRegistry:              kmsRegistry,
@@ -173,12 +578,12 @@ func TestService_InitiateOidcInteraction(t *testing.T) {
 
 	t.Run("publish request object failed", func(t *testing.T) {
 		requestObjectPublicStoreErr := NewMockRequestObjectPublicStore(gomock.NewController(t))
-		requestObjectPublicStoreErr.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).
+		requestObjectPublicStoreErr.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			AnyTimes().Return("", errors.New("fail"))
 
 		withError := oidc4vp.NewService(&oidc4vp.Config{
 			EventSvc:                 &mockEvent{},
-			EventTopic:               spi.VerifierEventTopic,
+			EventTopics:              []string{spi.VerifierEventTopic},
 			TransactionManager:       txManager,
 			RequestObjectPublicStore: requestObjectPublicStoreErr,
 			KMSRegistry:              kmsRegistry,
@@ -196,13 +601,72 @@ func TestService_InitiateOidcInteraction(t *testing.T) {
 		require.Nil(t, info)
 	})
 
+	t.Run("Success - custom request object signer", func(t *testing.T) {
+		var requestObject string
+
+		publicStore := NewMockRequestObjectPublicStore(gomock.NewController(t))
+		publicStore.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).DoAndReturn(func(ctx context.Context, token string, event *spi.Event,
+			ttl time.Duration) (string, error) {
+			requestObject = token
+
+			return "someurl/abc", nil
+		})
+
+		signer := &mockRequestObjectSigner{token: "hsm-signed-request-object"}
+
+		svc := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:                 &mockEvent{},
+			EventTopics:              []string{spi.VerifierEventTopic},
+			TransactionManager:       txManager,
+			RequestObjectPublicStore: publicStore,
+			KMSRegistry:              NewMockKMSRegistry(gomock.NewController(t)), // not used with external signer
+			RedirectURL:              "test://redirect",
+			TokenLifetime:            time.Second * 100,
+		}, oidc4vp.WithRequestObjectSigner(signer))
+
+		info, err := svc.InitiateOidcInteraction(context.TODO(), &presexch.PresentationDefinition{
+			ID: "test",
+		}, "test", correctProfile)
+
+		require.NoError(t, err)
+		require.NotNil(t, info)
+		require.Equal(t, "hsm-signed-request-object", requestObject)
+		require.Equal(t, correctProfile, signer.profile)
+		require.Equal(t, "TxID1", signer.claims["state"])
+		require.Equal(t, correctProfile.SigningDID.DID, signer.claims["client_id"])
+		require.Equal(t, "test://redirect", signer.claims["redirect_uri"])
+
+		registration, ok := signer.claims["registration"].(map[string]interface{})
+		require.True(t, ok)
+		require.Contains(t, fmt.Sprint(registration["vp_formats"]), "EdDSA")
+	})
+
+	t.Run("Fail - request object signer error", func(t *testing.T) {
+		svc := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:                 &mockEvent{},
+			EventTopics:              []string{spi.VerifierEventTopic},
+			TransactionManager:       txManager,
+			RequestObjectPublicStore: requestObjectPublicStore,
+			KMSRegistry:              kmsRegistry,
+			RequestObjectSigner:      &mockRequestObjectSigner{err: errors.New("hsm unavailable")},
+			RedirectURL:              "test://redirect",
+		})
+
+		info, err := svc.InitiateOidcInteraction(context.TODO(), &presexch.PresentationDefinition{}, "test",
+			correctProfile)
+
+		require.ErrorContains(t, err, "initiate oidc interaction: hsm unavailable")
+		require.Nil(t, info)
+	})
+
 	t.Run("fail to get kms form registry", func(t *testing.T) {
 		kmsRegistry := NewMockKMSRegistry(gomock.NewController(t))
 		kmsRegistry.EXPECT().GetKeyManager(gomock.Any()).AnyTimes().Return(nil, errors.New("fail"))
 
 		withError := oidc4vp.NewService(&oidc4vp.Config{
 			EventSvc:                 &mockEvent{},
-			EventTopic:               spi.VerifierEventTopic,
+			EventTopics:              []string{spi.VerifierEventTopic},
 			TransactionManager:       txManager,
 			RequestObjectPublicStore: requestObjectPublicStore,
 			KMSRegistry:              kmsRegistry,
@@ -241,6 +705,204 @@ func TestService_InitiateOidcInteraction(t *testing.T) {
 		require.Error(t, err)
 		require.Nil(t, info)
 	})
+
+	t.Run("Events", func(t *testing.T) {
+		newService := func(eventSvc *mockEvent, txManager *MockTransactionManager,
+			requestObjectStore *MockRequestObjectPublicStore) *oidc4vp.Service {
+			return oidc4vp.NewService(&oidc4vp.Config{
+				EventSvc:                 eventSvc,
+				EventTopics:              []string{spi.VerifierEventTopic},
+				TransactionManager:       txManager,
+				RequestObjectPublicStore: requestObjectStore,
+				KMSRegistry:              kmsRegistry,
+				RedirectURL:              "test://redirect",
+			})
+		}
+
+		t.Run("Initiated", func(t *testing.T) {
+			eventSvc := &mockEvent{}
+
+			_, err := newService(eventSvc, txManager, requestObjectPublicStore).InitiateOidcInteraction(
+				context.TODO(), &presexch.PresentationDefinition{}, "test", correctProfile)
+			require.NoError(t, err)
+
+			initiated := findEvent(eventSvc.published[spi.VerifierEventTopic], spi.VerifierOIDCInteractionInitiated)
+			require.NotNil(t, initiated)
+			require.Equal(t, "TxID1", initiated.TransactionID)
+
+			var payload map[string]interface{}
+			require.NoError(t, json.Unmarshal(initiated.Data, &payload))
+			require.Equal(t, "TxID1", payload["txID"])
+			require.Equal(t, correctProfile.ID, payload["profileID"])
+
+			require.Nil(t, findEvent(eventSvc.published[spi.VerifierEventTopic],
+				spi.VerifierOIDCInteractionInitiationFailed))
+		})
+
+		t.Run("Multiple topics", func(t *testing.T) {
+			const auditTopic = "vcs-audit"
+
+			eventSvc := &mockEvent{topicErrs: map[string]error{spi.VerifierEventTopic: errors.New("publish failed")}}
+
+			svc := oidc4vp.NewService(&oidc4vp.Config{
+				EventSvc:                 eventSvc,
+				EventTopics:              []string{spi.VerifierEventTopic, auditTopic},
+				TransactionManager:       txManager,
+				RequestObjectPublicStore: requestObjectPublicStore,
+				KMSRegistry:              kmsRegistry,
+				RedirectURL:              "test://redirect",
+			})
+
+			_, err := svc.InitiateOidcInteraction(
+				context.TODO(), &presexch.PresentationDefinition{}, "test", correctProfile)
+			require.ErrorContains(t, err, "publish event to topic "+spi.VerifierEventTopic+": publish failed")
+			require.NotContains(t, err.Error(), auditTopic)
+
+			require.NotNil(t, findEvent(eventSvc.published[auditTopic], spi.VerifierOIDCInteractionInitiated))
+			require.NotNil(t, findEvent(eventSvc.published[auditTopic], spi.VerifierOIDCInteractionInitiationFailed))
+			require.Empty(t, eventSvc.published[spi.VerifierEventTopic])
+		})
+
+		txManagerErr := NewMockTransactionManager(gomock.NewController(t))
+		txManagerErr.EXPECT().CreateTx(
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+			Return(nil, "", errors.New("fail"))
+
+		requestObjectPublicStoreErr := NewMockRequestObjectPublicStore(gomock.NewController(t))
+		requestObjectPublicStoreErr.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			AnyTimes().Return("", errors.New("fail"))
+
+		noSigningDIDProfile := &profileapi.Verifier{}
+		require.NoError(t, copier.Copy(noSigningDIDProfile, correctProfile))
+		noSigningDIDProfile.SigningDID = nil
+
+		tests := []struct {
+			name               string
+			profile            *profileapi.Verifier
+			txManager          *MockTransactionManager
+			requestObjectStore *MockRequestObjectPublicStore
+			expectedTxID       string
+			expectedCode       string
+		}{
+			{
+				name:               "Signing did missing",
+				profile:            noSigningDIDProfile,
+				txManager:          txManager,
+				requestObjectStore: requestObjectPublicStore,
+				expectedCode:       oidc4vp.ErrCodeInvalidProfile,
+			},
+			{
+				name:               "Tx create failed",
+				profile:            correctProfile,
+				txManager:          txManagerErr,
+				requestObjectStore: requestObjectPublicStore,
+				expectedCode:       oidc4vp.ErrCodeCreateTxFailed,
+			},
+			{
+				name:               "Publish request object failed",
+				profile:            correctProfile,
+				txManager:          txManager,
+				requestObjectStore: requestObjectPublicStoreErr,
+				expectedTxID:       "TxID1",
+				expectedCode:       oidc4vp.ErrCodePublishRequestObjectFailed,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				eventSvc := &mockEvent{}
+
+				_, err := newService(eventSvc, tt.txManager, tt.requestObjectStore).InitiateOidcInteraction(
+					context.TODO(), &presexch.PresentationDefinition{}, "test", tt.profile)
+				require.Error(t, err)
+
+				failed := findEvent(eventSvc.published[spi.VerifierEventTopic],
+					spi.VerifierOIDCInteractionInitiationFailed)
+				require.NotNil(t, failed)
+				require.Equal(t, tt.expectedTxID, failed.TransactionID)
+
+				var payload map[string]interface{}
+				require.NoError(t, json.Unmarshal(failed.Data, &payload))
+				require.Equal(t, tt.expectedCode, payload["errorCode"])
+				require.Equal(t, correctProfile.ID, payload["profileID"])
+				require.Equal(t, err.Error(), payload["error"])
+			})
+		}
+	})
+
+	t.Run("By template", func(t *testing.T) {
+		newTemplateService := func(registry oidc4vp.TemplateRegistry, cacheTTL time.Duration) *oidc4vp.Service {
+			return oidc4vp.NewService(&oidc4vp.Config{
+				EventSvc:                 &mockEvent{},
+				EventTopics:              []string{spi.VerifierEventTopic},
+				TransactionManager:       txManager,
+				RequestObjectPublicStore: requestObjectPublicStore,
+				KMSRegistry:              kmsRegistry,
+				RedirectURL:              "test://redirect",
+				TokenLifetime:            time.Second * 100,
+				TemplateRegistry:         registry,
+				TemplateCacheTTL:         cacheTTL,
+			})
+		}
+
+		t.Run("Success with cache", func(t *testing.T) {
+			registry := &mockTemplateRegistry{pd: &presexch.PresentationDefinition{ID: "test"}}
+			svc := newTemplateService(registry, time.Minute)
+
+			for i := 0; i < 2; i++ {
+				info, err := svc.InitiateOidcInteractionByTemplate(context.TODO(), "templateID", "test", correctProfile)
+				require.NoError(t, err)
+				require.NotNil(t, info)
+			}
+
+			require.Equal(t, []string{"templateID"}, registry.requested)
+		})
+
+		t.Run("Cache expired", func(t *testing.T) {
+			registry := &mockTemplateRegistry{pd: &presexch.PresentationDefinition{ID: "test"}}
+			svc := newTemplateService(registry, time.Millisecond)
+
+			_, err := svc.InitiateOidcInteractionByTemplate(context.TODO(), "templateID", "test", correctProfile)
+			require.NoError(t, err)
+
+			time.Sleep(5 * time.Millisecond)
+
+			_, err = svc.InitiateOidcInteractionByTemplate(context.TODO(), "templateID", "test", correctProfile)
+			require.NoError(t, err)
+
+			require.Equal(t, []string{"templateID", "templateID"}, registry.requested)
+		})
+
+		t.Run("Cache disabled", func(t *testing.T) {
+			registry := &mockTemplateRegistry{pd: &presexch.PresentationDefinition{ID: "test"}}
+			svc := newTemplateService(registry, 0)
+
+			for i := 0; i < 2; i++ {
+				_, err := svc.InitiateOidcInteractionByTemplate(context.TODO(), "templateID", "test", correctProfile)
+				require.NoError(t, err)
+			}
+
+			require.Len(t, registry.requested, 2)
+		})
+
+		t.Run("Registry error", func(t *testing.T) {
+			registry := &mockTemplateRegistry{err: errors.New("template not found")}
+			svc := newTemplateService(registry, time.Minute)
+
+			_, err := svc.InitiateOidcInteractionByTemplate(context.TODO(), "templateID", "test", correctProfile)
+			require.ErrorContains(t, err, "get presentation definition template templateID: template not found")
+
+			_, err = svc.InitiateOidcInteractionByTemplate(context.TODO(), "templateID", "test", correctProfile)
+			require.Error(t, err)
+			require.Len(t, registry.requested, 2)
+		})
+
+		t.Run("Registry not configured", func(t *testing.T) {
+			_, err := newTemplateService(nil, time.Minute).InitiateOidcInteractionByTemplate(
+				context.TODO(), "templateID", "test", correctProfile)
+			require.ErrorIs(t, err, oidc4vp.ErrTemplateRegistryNotConfigured)
+		})
+	})
 }
 
 func TestService_VerifyOIDCVerifiablePresentation(t *testing.T) {
@@ -256,7 +918,7 @@ func TestService_VerifyOIDCVerifiablePresentation(t *testing.T) {
 
 	s := oidc4vp.NewService(&oidc4vp.Config{
 		EventSvc:             &mockEvent{},
-		EventTopic:           spi.VerifierEventTopic,
+		EventTopics:          []string{spi.VerifierEventTopic},
 		TransactionManager:   txManager,
 		PresentationVerifier: presentationVerifier,
 		ProfileService:       profileService,
@@ -264,14 +926,15 @@ func TestService_VerifyOIDCVerifiablePresentation(t *testing.T) {
 		VDR:                  vdr,
 	})
 
-	txManager.EXPECT().GetByOneTimeToken("nonce1").AnyTimes().Return(&oidc4vp.Transaction{
+	txManager.EXPECT().UpdateTx(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).AnyTimes().Return(nil)
+	txManager.EXPECT().GetByOneTimeToken(gomock.Any(), "nonce1").AnyTimes().Return(&oidc4vp.Transaction{
 		ID:                     "txID1",
 		ProfileID:              profileID,
 		ProfileVersion:         profileVersion,
 		PresentationDefinition: pd,
 	}, true, nil)
 
-	txManager.EXPECT().StoreReceivedClaims(oidc4vp.TxID("txID1"), gomock.Any()).AnyTimes().Return(nil)
+	txManager.EXPECT().StoreReceivedClaims(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).AnyTimes().Return(nil)
 
 	profileService.EXPECT().GetProfile(profileID, profileVersion).AnyTimes().Return(&profileapi.Verifier{
 		ID:      profileID,
@@ -287,7 +950,7 @@ func TestService_VerifyOIDCVerifiablePresentation(t *testing.T) {
 		},
 	}, nil)
 
-	presentationVerifier.EXPECT().VerifyPresentation(context.Background(), gomock.Any(), gomock.Any(), gomock.Any()).
+	presentationVerifier.EXPECT().VerifyPresentation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		AnyTimes().Return(nil, nil)
 
 	t.Run("Success", func(t *testing.T) {
@@ -302,9 +965,410 @@ func TestService_VerifyOIDCVerifiablePresentation(t *testing.T) {
 		require.NoError(t, err)
 	})
 
-	t.Run("Unsupported vp token format", func(t *testing.T) {
-		err := s.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
-			[]*oidc4vp.ProcessedVPToken{{
+	t.Run("Too many vp tokens", func(t *testing.T) {
+		withMaxTokens := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:             &mockEvent{},
+			EventTopics:          []string{spi.VerifierEventTopic},
+			TransactionManager:   txManager,
+			PresentationVerifier: presentationVerifier,
+			ProfileService:       profileService,
+			DocumentLoader:       loader,
+			VDR:                  vdr,
+			MaxVPTokens:          2,
+		})
+
+		token := &oidc4vp.ProcessedVPToken{
+			Nonce:         "nonce1",
+			Presentation:  vp,
+			SignerDIDID:   issuer,
+			VpTokenFormat: vcsverifiable.Jwt,
+		}
+
+		err := withMaxTokens.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{token, token, token})
+
+		require.ErrorIs(t, err, oidc4vp.ErrTooManyTokens)
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		withOptions := oidc4vp.NewService(nil,
+			oidc4vp.WithEventService(&mockEvent{}, spi.VerifierEventTopic),
+			oidc4vp.WithTransactionManager(txManager),
+			oidc4vp.WithPresentationVerifier(presentationVerifier),
+			oidc4vp.WithProfileService(profileService),
+			oidc4vp.WithDocumentLoader(loader),
+			oidc4vp.WithVDR(vdr),
+			oidc4vp.WithMaxVPTokens(2),
+		)
+
+		token := &oidc4vp.ProcessedVPToken{
+			Nonce:         "nonce1",
+			Presentation:  vp,
+			SignerDIDID:   issuer,
+			VpTokenFormat: vcsverifiable.Jwt,
+		}
+
+		err := withOptions.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{token})
+		require.NoError(t, err)
+
+		err = withOptions.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{token, token, token})
+		require.ErrorIs(t, err, oidc4vp.ErrTooManyTokens)
+	})
+
+	t.Run("Options override config", func(t *testing.T) {
+		cfg := &oidc4vp.Config{
+			EventSvc:             &mockEvent{},
+			EventTopics:          []string{spi.VerifierEventTopic},
+			TransactionManager:   txManager,
+			PresentationVerifier: presentationVerifier,
+			ProfileService:       profileService,
+			DocumentLoader:       loader,
+			VDR:                  vdr,
+		}
+
+		withOverride := oidc4vp.NewService(cfg, oidc4vp.WithMaxVPTokens(2))
+
+		token := &oidc4vp.ProcessedVPToken{
+			Nonce:         "nonce1",
+			Presentation:  vp,
+			SignerDIDID:   issuer,
+			VpTokenFormat: vcsverifiable.Jwt,
+		}
+
+		err := withOverride.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{token, token, token})
+		require.ErrorIs(t, err, oidc4vp.ErrTooManyTokens)
+		require.Zero(t, cfg.MaxVPTokens)
+	})
+
+	t.Run("Audit log", func(t *testing.T) {
+		auditLogger := &mockAuditLogger{}
+
+		withAudit := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:             &mockEvent{},
+			EventTopics:          []string{spi.VerifierEventTopic},
+			TransactionManager:   txManager,
+			PresentationVerifier: presentationVerifier,
+			ProfileService:       profileService,
+			DocumentLoader:       loader,
+			VDR:                  vdr,
+			MaxVPTokens:          1,
+		}, oidc4vp.WithAuditLogger(auditLogger))
+
+		token := &oidc4vp.ProcessedVPToken{
+			Nonce:         "nonce1",
+			Presentation:  vp,
+			SignerDIDID:   issuer,
+			VpTokenFormat: vcsverifiable.Jwt,
+		}
+
+		err := withAudit.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{token})
+		require.NoError(t, err)
+
+		err = withAudit.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{token, token})
+		require.ErrorIs(t, err, oidc4vp.ErrTooManyTokens)
+
+		require.Len(t, auditLogger.entries, 2)
+
+		succeeded := auditLogger.entries[0]
+		require.Equal(t, oidc4vp.TxID("txID1"), succeeded.TxID)
+		require.Equal(t, profileID, succeeded.ProfileID)
+		require.Equal(t, oidc4vp.AuditOutcomeSuccess, succeeded.Outcome)
+		require.Equal(t, issuer, succeeded.ActorDID)
+		require.Len(t, succeeded.CredentialIDs, 1)
+		require.False(t, succeeded.Timestamp.IsZero())
+
+		failed := auditLogger.entries[1]
+		require.Equal(t, oidc4vp.TxID("txID1"), failed.TxID)
+		require.Equal(t, oidc4vp.AuditOutcomeFailure, failed.Outcome)
+		require.Equal(t, issuer, failed.ActorDID)
+		require.Empty(t, failed.ProfileID)
+		require.Empty(t, failed.CredentialIDs)
+
+		auditLogger.err = errors.New("audit log error")
+
+		err = withAudit.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{token})
+		require.NoError(t, err)
+	})
+
+	t.Run("Holder binding", func(t *testing.T) {
+		holderBindingProfileService := NewMockProfileService(gomock.NewController(t))
+		holderBindingProfileService.EXPECT().GetProfile(profileID, profileVersion).AnyTimes().Return(
+			&profileapi.Verifier{
+				ID:      profileID,
+				Version: profileVersion,
+				Active:  true,
+				Checks: &profileapi.VerificationChecks{
+					Presentation: &profileapi.PresentationChecks{
+						RequireHolderBinding: true,
+						Format: []vcsverifiable.Format{
+							vcsverifiable.Jwt,
+						},
+					},
+				},
+			}, nil)
+
+		withHolderBinding := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:             &mockEvent{},
+			EventTopics:          []string{spi.VerifierEventTopic},
+			TransactionManager:   txManager,
+			PresentationVerifier: presentationVerifier,
+			ProfileService:       holderBindingProfileService,
+			DocumentLoader:       loader,
+			VDR:                  vdr,
+		})
+
+		fragment := "#" + strings.TrimPrefix(issuer, "did:key:")
+
+		for _, keyID := range []string{issuer + fragment, fragment} {
+			err := withHolderBinding.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+				[]*oidc4vp.ProcessedVPToken{{
+					Nonce:         "nonce1",
+					Presentation:  vp,
+					SignerDIDID:   issuer,
+					SignerKeyID:   keyID,
+					VpTokenFormat: vcsverifiable.Jwt,
+				}})
+			require.NoError(t, err)
+		}
+
+		err := withHolderBinding.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{{
+				Nonce:         "nonce1",
+				Presentation:  vp,
+				SignerDIDID:   issuer,
+				SignerKeyID:   issuer + "#other-key",
+				VpTokenFormat: vcsverifiable.Jwt,
+			}})
+		require.ErrorIs(t, err, oidc4vp.ErrHolderBindingViolation)
+
+		err = withHolderBinding.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{{
+				Nonce:         "nonce1",
+				Presentation:  vp,
+				SignerDIDID:   "did:example:other",
+				SignerKeyID:   "did:example:other" + fragment,
+				VpTokenFormat: vcsverifiable.Jwt,
+			}})
+		require.ErrorIs(t, err, oidc4vp.ErrHolderBindingViolation)
+	})
+
+	t.Run("Raw VP tokens", func(t *testing.T) {
+		rawTokensTxManager := NewMockTransactionManager(gomock.NewController(t))
+		rawTokensTxManager.EXPECT().GetByOneTimeToken(gomock.Any(), "nonce1").AnyTimes().Return(&oidc4vp.Transaction{
+			ID:                     "txID1",
+			ProfileID:              profileID,
+			ProfileVersion:         profileVersion,
+			PresentationDefinition: pd,
+		}, true, nil)
+
+		var storedClaims *oidc4vp.ReceivedClaims
+
+		rawTokensTxManager.EXPECT().StoreReceivedClaims(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ oidc4vp.TxID, claims *oidc4vp.ReceivedClaims) error {
+				storedClaims = claims
+
+				return nil
+			})
+
+		withRawTokens := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:             &mockEvent{},
+			EventTopics:          []string{spi.VerifierEventTopic},
+			TransactionManager:   rawTokensTxManager,
+			PresentationVerifier: presentationVerifier,
+			ProfileService:       profileService,
+			DocumentLoader:       loader,
+			VDR:                  vdr,
+		})
+
+		jwtVP := *vp
+		jwtVP.JWT = "signed.vp.jwt"
+
+		err := withRawTokens.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{{
+				Nonce:         "nonce1",
+				Presentation:  &jwtVP,
+				SignerDIDID:   issuer,
+				VpTokenFormat: vcsverifiable.Jwt,
+			}})
+		require.NoError(t, err)
+
+		require.NotNil(t, storedClaims)
+		require.Equal(t, []string{"signed.vp.jwt"}, storedClaims.RawVPTokens)
+	})
+
+	t.Run("Allowed issuers", func(t *testing.T) {
+		newServiceWithAllowedIssuers := func(allowedIssuers []string) *oidc4vp.Service {
+			allowedIssuersProfileService := NewMockProfileService(gomock.NewController(t))
+			allowedIssuersProfileService.EXPECT().GetProfile(profileID, profileVersion).AnyTimes().Return(
+				&profileapi.Verifier{
+					ID:      profileID,
+					Version: profileVersion,
+					Active:  true,
+					Checks: &profileapi.VerificationChecks{
+						Presentation: &profileapi.PresentationChecks{
+							Format: []vcsverifiable.Format{
+								vcsverifiable.Jwt,
+							},
+						},
+						Credential: profileapi.CredentialChecks{
+							AllowedIssuers: allowedIssuers,
+						},
+					},
+				}, nil)
+
+			return oidc4vp.NewService(&oidc4vp.Config{
+				EventSvc:             &mockEvent{},
+				EventTopics:          []string{spi.VerifierEventTopic},
+				TransactionManager:   txManager,
+				PresentationVerifier: presentationVerifier,
+				ProfileService:       allowedIssuersProfileService,
+				DocumentLoader:       loader,
+				VDR:                  vdr,
+			})
+		}
+
+		tokens := []*oidc4vp.ProcessedVPToken{{
+			Nonce:         "nonce1",
+			Presentation:  vp,
+			SignerDIDID:   issuer,
+			VpTokenFormat: vcsverifiable.Jwt,
+		}}
+
+		err := newServiceWithAllowedIssuers([]string{"did:example:other", issuer}).
+			VerifyOIDCVerifiablePresentation(context.Background(), "txID1", tokens)
+		require.NoError(t, err)
+
+		err = newServiceWithAllowedIssuers([]string{"did:example:other"}).
+			VerifyOIDCVerifiablePresentation(context.Background(), "txID1", tokens)
+		require.ErrorIs(t, err, oidc4vp.ErrUntrustedIssuer)
+		require.ErrorContains(t, err, issuer)
+	})
+
+	t.Run("Trust registry", func(t *testing.T) {
+		tokens := []*oidc4vp.ProcessedVPToken{{
+			Nonce:         "nonce1",
+			Presentation:  vp,
+			SignerDIDID:   issuer,
+			VpTokenFormat: vcsverifiable.Jwt,
+		}}
+
+		newServiceWithTrustRegistry := func(registry oidc4vp.TrustRegistryClient) *oidc4vp.Service {
+			return oidc4vp.NewService(&oidc4vp.Config{
+				EventSvc:             &mockEvent{},
+				EventTopics:          []string{spi.VerifierEventTopic},
+				TransactionManager:   txManager,
+				PresentationVerifier: presentationVerifier,
+				ProfileService:       profileService,
+				DocumentLoader:       loader,
+				VDR:                  vdr,
+			}, oidc4vp.WithTrustRegistryClient(registry))
+		}
+
+		t.Run("Trusted", func(t *testing.T) {
+			registry := &mockTrustRegistry{trusted: true}
+
+			err := newServiceWithTrustRegistry(registry).
+				VerifyOIDCVerifiablePresentation(context.Background(), "txID1", tokens)
+			require.NoError(t, err)
+			require.Equal(t, []string{issuer + " " + verifiable.VCType}, registry.requested)
+		})
+
+		t.Run("Untrusted", func(t *testing.T) {
+			err := newServiceWithTrustRegistry(&mockTrustRegistry{trusted: false}).
+				VerifyOIDCVerifiablePresentation(context.Background(), "txID1", tokens)
+			require.ErrorIs(t, err, oidc4vp.ErrUntrustedIssuer)
+			require.ErrorContains(t, err, issuer)
+		})
+
+		t.Run("Registry error", func(t *testing.T) {
+			err := newServiceWithTrustRegistry(&mockTrustRegistry{err: errors.New("registry unavailable")}).
+				VerifyOIDCVerifiablePresentation(context.Background(), "txID1", tokens)
+			require.ErrorContains(t, err, "registry unavailable")
+			require.NotErrorIs(t, err, oidc4vp.ErrUntrustedIssuer)
+		})
+
+		t.Run("Registry timeout", func(t *testing.T) {
+			release := make(chan struct{})
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-release
+			}))
+			defer srv.Close()
+			defer close(release)
+
+			svc := oidc4vp.NewService(&oidc4vp.Config{
+				EventSvc:             &mockEvent{},
+				EventTopics:          []string{spi.VerifierEventTopic},
+				TransactionManager:   txManager,
+				PresentationVerifier: presentationVerifier,
+				ProfileService:       profileService,
+				DocumentLoader:       loader,
+				VDR:                  vdr,
+				TrustRegistryURL:     srv.URL,
+			}, oidc4vp.WithHTTPClient(&http.Client{Timeout: 50 * time.Millisecond}))
+
+			err := svc.VerifyOIDCVerifiablePresentation(context.Background(), "txID1", tokens)
+			require.ErrorContains(t, err, "Client.Timeout exceeded")
+			require.NotErrorIs(t, err, oidc4vp.ErrUntrustedIssuer)
+		})
+	})
+
+	t.Run("Max credential age", func(t *testing.T) {
+		newServiceWithMaxCredentialAge := func(maxCredentialAge time.Duration) *oidc4vp.Service {
+			maxAgeProfileService := NewMockProfileService(gomock.NewController(t))
+			maxAgeProfileService.EXPECT().GetProfile(profileID, profileVersion).AnyTimes().Return(
+				&profileapi.Verifier{
+					ID:      profileID,
+					Version: profileVersion,
+					Active:  true,
+					Checks: &profileapi.VerificationChecks{
+						Presentation: &profileapi.PresentationChecks{
+							Format: []vcsverifiable.Format{
+								vcsverifiable.Jwt,
+							},
+						},
+						Credential: profileapi.CredentialChecks{
+							MaxCredentialAge: maxCredentialAge,
+						},
+					},
+				}, nil)
+
+			return oidc4vp.NewService(&oidc4vp.Config{
+				EventSvc:             &mockEvent{},
+				EventTopics:          []string{spi.VerifierEventTopic},
+				TransactionManager:   txManager,
+				PresentationVerifier: presentationVerifier,
+				ProfileService:       maxAgeProfileService,
+				DocumentLoader:       loader,
+				VDR:                  vdr,
+			})
+		}
+
+		tokens := []*oidc4vp.ProcessedVPToken{{
+			Nonce:         "nonce1",
+			Presentation:  vp,
+			SignerDIDID:   issuer,
+			VpTokenFormat: vcsverifiable.Jwt,
+		}}
+
+		err := newServiceWithMaxCredentialAge(time.Hour).
+			VerifyOIDCVerifiablePresentation(context.Background(), "txID1", tokens)
+		require.NoError(t, err)
+
+		err = newServiceWithMaxCredentialAge(time.Nanosecond).
+			VerifyOIDCVerifiablePresentation(context.Background(), "txID1", tokens)
+		require.ErrorIs(t, err, oidc4vp.ErrCredentialTooOld)
+	})
+
+	t.Run("Unsupported vp token format", func(t *testing.T) {
+		err := s.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{{
 				Nonce:         "nonce1",
 				Presentation:  vp,
 				SignerDIDID:   issuer,
@@ -314,6 +1378,38 @@ func TestService_VerifyOIDCVerifiablePresentation(t *testing.T) {
 		require.ErrorContains(t, err, "profile does not support ldp vp_token format")
 	})
 
+	t.Run("VDR resolution timeout", func(t *testing.T) {
+		slowVDR := &vdrmock.VDRegistry{
+			ResolveFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				time.Sleep(time.Second)
+
+				return vdr.Resolve(didID, opts...)
+			}}
+
+		withTimeout := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:             &mockEvent{},
+			EventTopics:          []string{spi.VerifierEventTopic},
+			TransactionManager:   txManager,
+			PresentationVerifier: presentationVerifier,
+			ProfileService:       profileService,
+			DocumentLoader:       loader,
+			VDR:                  slowVDR,
+		}, oidc4vp.WithVDRResolutionTimeout(50*time.Millisecond))
+
+		start := time.Now()
+
+		err := withTimeout.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{{
+				Nonce:         "nonce1",
+				Presentation:  vp,
+				SignerDIDID:   issuer,
+				VpTokenFormat: vcsverifiable.Jwt,
+			}})
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), time.Second)
+	})
+
 	t.Run("Success - two VP tokens (merged)", func(t *testing.T) {
 		var descriptors []*presexch.InputDescriptor
 		err := json.Unmarshal([]byte(twoInputDescriptors), &descriptors)
@@ -365,7 +1461,7 @@ func TestService_VerifyOIDCVerifiablePresentation(t *testing.T) {
 
 		s2 := oidc4vp.NewService(&oidc4vp.Config{
 			EventSvc:             &mockEvent{},
-			EventTopic:           spi.VerifierEventTopic,
+			EventTopics:          []string{spi.VerifierEventTopic},
 			TransactionManager:   txManager2,
 			PresentationVerifier: presentationVerifier,
 			ProfileService:       profileService,
@@ -373,14 +1469,15 @@ func TestService_VerifyOIDCVerifiablePresentation(t *testing.T) {
 			VDR:                  combinedDIDResolver,
 		})
 
-		txManager2.EXPECT().GetByOneTimeToken("nonce1").AnyTimes().Return(&oidc4vp.Transaction{
+		txManager2.EXPECT().GetByOneTimeToken(gomock.Any(), "nonce1").AnyTimes().Return(&oidc4vp.Transaction{
 			ID:                     "txID1",
 			ProfileID:              profileID,
 			ProfileVersion:         profileVersion,
 			PresentationDefinition: defs,
 		}, true, nil)
 
-		txManager2.EXPECT().StoreReceivedClaims(oidc4vp.TxID("txID1"), gomock.Any()).AnyTimes().Return(nil)
+		txManager2.EXPECT().StoreReceivedClaims(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).AnyTimes().Return(nil)
+		txManager2.EXPECT().UpdateTx(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).AnyTimes().Return(nil)
 
 		err = s2.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
 			[]*oidc4vp.ProcessedVPToken{
@@ -401,6 +1498,104 @@ func TestService_VerifyOIDCVerifiablePresentation(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("Two VP tokens (per presentation submission)", func(t *testing.T) {
+		var descriptors []*presexch.InputDescriptor
+		err := json.Unmarshal([]byte(twoInputDescriptors), &descriptors)
+		require.NoError(t, err)
+
+		defs := &presexch.PresentationDefinition{
+			InputDescriptors: descriptors,
+		}
+
+		newSubmission := func(descID string) *presexch.PresentationSubmission {
+			return &presexch.PresentationSubmission{
+				ID: uuid.New().String(),
+				DescriptorMap: []*presexch.InputDescriptorMapping{{
+					ID:   descID,
+					Path: "$.verifiableCredential[0]",
+				}},
+			}
+		}
+
+		testLoader := testutil.DocumentLoader(t)
+
+		vp1, issuer1, vdr1 := newVPWithPS(t, keyManager, crypto, newSubmission(descriptors[0].ID), "PhDDegree")
+		vp2, issuer2, vdr2 := newVPWithPS(t, keyManager, crypto, newSubmission(descriptors[1].ID), "BachelorDegree")
+
+		combinedDIDResolver := &vdrmock.VDRegistry{
+			ResolveFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				switch didID {
+				case issuer1:
+					return vdr1.Resolve(didID, opts...)
+				case issuer2:
+					return vdr2.Resolve(didID, opts...)
+				}
+
+				return nil, fmt.Errorf("unexpected issuer")
+			}}
+
+		txManager2 := NewMockTransactionManager(gomock.NewController(t))
+
+		s2 := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:             &mockEvent{},
+			EventTopics:          []string{spi.VerifierEventTopic},
+			TransactionManager:   txManager2,
+			PresentationVerifier: presentationVerifier,
+			ProfileService:       profileService,
+			DocumentLoader:       testLoader,
+			VDR:                  combinedDIDResolver,
+		})
+
+		txManager2.EXPECT().GetByOneTimeToken(gomock.Any(), "nonce1").AnyTimes().Return(&oidc4vp.Transaction{
+			ID:                     "txID1",
+			ProfileID:              profileID,
+			ProfileVersion:         profileVersion,
+			PresentationDefinition: defs,
+		}, true, nil)
+
+		txManager2.EXPECT().UpdateTx(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).AnyTimes().Return(nil)
+
+		tokens := func(vps ...*verifiable.Presentation) []*oidc4vp.ProcessedVPToken {
+			signers := map[*verifiable.Presentation]string{vp1: issuer1, vp2: issuer2}
+
+			var result []*oidc4vp.ProcessedVPToken
+
+			for _, vp := range vps {
+				result = append(result, &oidc4vp.ProcessedVPToken{
+					Nonce:         "nonce1",
+					Presentation:  vp,
+					SignerDIDID:   signers[vp],
+					VpTokenFormat: vcsverifiable.Jwt,
+				})
+			}
+
+			return result
+		}
+
+		t.Run("Success", func(t *testing.T) {
+			txManager2.EXPECT().StoreReceivedClaims(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ oidc4vp.TxID, claims *oidc4vp.ReceivedClaims) error {
+					require.Len(t, claims.Credentials, 2)
+					require.Contains(t, claims.Credentials, descriptors[0].ID)
+					require.Contains(t, claims.Credentials, descriptors[1].ID)
+
+					return nil
+				})
+
+			require.NoError(t, s2.VerifyOIDCVerifiablePresentation(context.Background(), "txID1", tokens(vp1, vp2)))
+		})
+
+		t.Run("Input descriptor submitted twice", func(t *testing.T) {
+			vp3, issuer3, _ := newVPWithPS(t, keyManager, crypto, newSubmission(descriptors[0].ID), "PhDDegree")
+
+			submitted := tokens(vp1, vp3)
+			submitted[1].SignerDIDID = issuer3
+
+			err := s2.VerifyOIDCVerifiablePresentation(context.Background(), "txID1", submitted)
+			require.ErrorContains(t, err, "already matched")
+		})
+	})
+
 	t.Run("Error - Two VP tokens without presentation ID", func(t *testing.T) {
 		var descriptors []*presexch.InputDescriptor
 		err := json.Unmarshal([]byte(twoInputDescriptors), &descriptors)
@@ -452,7 +1647,7 @@ func TestService_VerifyOIDCVerifiablePresentation(t *testing.T) {
 
 		s2 := oidc4vp.NewService(&oidc4vp.Config{
 			EventSvc:             &mockEvent{},
-			EventTopic:           spi.VerifierEventTopic,
+			EventTopics:          []string{spi.VerifierEventTopic},
 			TransactionManager:   txManager2,
 			PresentationVerifier: presentationVerifier,
 			ProfileService:       profileService,
@@ -460,14 +1655,15 @@ func TestService_VerifyOIDCVerifiablePresentation(t *testing.T) {
 			VDR:                  combinedDIDResolver,
 		})
 
-		txManager2.EXPECT().GetByOneTimeToken("nonce1").AnyTimes().Return(&oidc4vp.Transaction{
+		txManager2.EXPECT().GetByOneTimeToken(gomock.Any(), "nonce1").AnyTimes().Return(&oidc4vp.Transaction{
 			ID:                     "txID1",
 			ProfileID:              profileID,
 			ProfileVersion:         profileVersion,
 			PresentationDefinition: defs,
 		}, true, nil)
 
-		txManager2.EXPECT().StoreReceivedClaims(oidc4vp.TxID("txID1"), gomock.Any()).AnyTimes().Return(nil)
+		txManager2.EXPECT().StoreReceivedClaims(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).AnyTimes().Return(nil)
+		txManager2.EXPECT().UpdateTx(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).AnyTimes().Return(nil)
 
 		vp1.ID = ""
 		vp2.ID = ""
@@ -514,12 +1710,14 @@ func TestService_VerifyOIDCVerifiablePresentation(t *testing.T) {
 
 	t.Run("Invalid Nonce", func(t *testing.T) {
 		errTxManager := NewMockTransactionManager(gomock.NewController(t))
-		errTxManager.EXPECT().GetByOneTimeToken("nonce1").AnyTimes().
+		errTxManager.EXPECT().GetByOneTimeToken(gomock.Any(), "nonce1").AnyTimes().
 			Return(nil, false, errors.New("invalid nonce1"))
 
+		eventSvc := &mockEvent{}
+
 		withError := oidc4vp.NewService(&oidc4vp.Config{
-			EventSvc:             &mockEvent{},
-			EventTopic:           spi.VerifierEventTopic,
+			EventSvc:             eventSvc,
+			EventTopics:          []string{spi.VerifierEventTopic},
 			TransactionManager:   errTxManager,
 			PresentationVerifier: presentationVerifier,
 			ProfileService:       profileService,
@@ -533,156 +1731,968 @@ func TestService_VerifyOIDCVerifiablePresentation(t *testing.T) {
 				SignerDIDID:  "did:example123:ebfeb1f712ebc6f1c276e12ec21",
 			}})
 
-		require.Contains(t, err.Error(), "invalid nonce1")
-	})
+		require.Contains(t, err.Error(), "invalid nonce1")
+	})
+
+	t.Run("Invalid Nonce 2", func(t *testing.T) {
+		err := s.VerifyOIDCVerifiablePresentation(context.Background(), "txID2",
+			[]*oidc4vp.ProcessedVPToken{{
+				Nonce:        "nonce1",
+				Presentation: vp,
+				SignerDIDID:  "did:example123:ebfeb1f712ebc6f1c276e12ec21",
+			}})
+
+		require.Contains(t, err.Error(), "invalid nonce")
+	})
+
+	t.Run("Invalid Nonce", func(t *testing.T) {
+		errProfileService := NewMockProfileService(gomock.NewController(t))
+		errProfileService.EXPECT().GetProfile(profileID, profileVersion).Times(1).Return(nil,
+			errors.New("get profile error"))
+
+		withError := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:             &mockEvent{},
+			EventTopics:          []string{spi.VerifierEventTopic},
+			TransactionManager:   txManager,
+			PresentationVerifier: presentationVerifier,
+			ProfileService:       errProfileService,
+			DocumentLoader:       loader,
+		})
+
+		err := withError.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{{
+				Nonce:        "nonce1",
+				Presentation: vp,
+				SignerDIDID:  "did:example123:ebfeb1f712ebc6f1c276e12ec21",
+			}})
+
+		require.Contains(t, err.Error(), "get profile error")
+	})
+
+	t.Run("Empty profile version", func(t *testing.T) {
+		noVersionTxManager := NewMockTransactionManager(gomock.NewController(t))
+		noVersionTxManager.EXPECT().UpdateTx(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).AnyTimes().Return(nil)
+		noVersionTxManager.EXPECT().GetByOneTimeToken(gomock.Any(), "nonce1").Times(1).Return(&oidc4vp.Transaction{
+			ID:                     "txID1",
+			ProfileID:              profileID,
+			PresentationDefinition: pd,
+		}, true, nil)
+
+		noCallsProfileService := NewMockProfileService(gomock.NewController(t))
+		noCallsProfileService.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Times(0)
+
+		withError := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:             &mockEvent{},
+			EventTopics:          []string{spi.VerifierEventTopic},
+			TransactionManager:   noVersionTxManager,
+			PresentationVerifier: presentationVerifier,
+			ProfileService:       noCallsProfileService,
+			DocumentLoader:       loader,
+		})
+
+		err := withError.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{{
+				Nonce:        "nonce1",
+				Presentation: vp,
+				SignerDIDID:  "did:example123:ebfeb1f712ebc6f1c276e12ec21",
+			}})
+
+		require.ErrorIs(t, err, oidc4vp.ErrInvalidTransactionState)
+		require.ErrorContains(t, err, "profile version is not set for transaction txID1")
+	})
+
+	t.Run("Token received outside of transaction validity window", func(t *testing.T) {
+		createdAt := time.Now().Add(-time.Hour)
+		expiresAt := time.Now().Add(-time.Minute)
+
+		windowTxManager := NewMockTransactionManager(gomock.NewController(t))
+		windowTxManager.EXPECT().UpdateTx(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).AnyTimes().Return(nil)
+		windowTxManager.EXPECT().GetByOneTimeToken(gomock.Any(), "nonce1").AnyTimes().Return(&oidc4vp.Transaction{
+			ID:                     "txID1",
+			ProfileID:              profileID,
+			ProfileVersion:         profileVersion,
+			PresentationDefinition: pd,
+			CreatedAt:              createdAt,
+			ExpiresAt:              expiresAt,
+		}, true, nil)
+
+		withWindow := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:             &mockEvent{},
+			EventTopics:          []string{spi.VerifierEventTopic},
+			TransactionManager:   windowTxManager,
+			PresentationVerifier: presentationVerifier,
+			ProfileService:       profileService,
+			DocumentLoader:       loader,
+		})
+
+		for name, receivedAt := range map[string]time.Time{
+			"after expiration":    time.Time{},
+			"before creation":     createdAt.Add(-time.Second),
+			"explicitly too late": expiresAt.Add(time.Second),
+		} {
+			t.Run(name, func(t *testing.T) {
+				err := withWindow.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+					[]*oidc4vp.ProcessedVPToken{{
+						Nonce:        "nonce1",
+						Presentation: vp,
+						SignerDIDID:  issuer,
+						ReceivedAt:   receivedAt,
+					}})
+
+				require.ErrorIs(t, err, oidc4vp.ErrTokenReceivedTooLate)
+			})
+		}
+	})
+
+	t.Run("verification failed", func(t *testing.T) {
+		errPresentationVerifier := NewMockPresentationVerifier(gomock.NewController(t))
+		errPresentationVerifier.EXPECT().VerifyPresentation(
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			Return(nil, errors.New("verification failed"))
+		withError := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:             &mockEvent{},
+			EventTopics:          []string{spi.VerifierEventTopic},
+			TransactionManager:   txManager,
+			PresentationVerifier: errPresentationVerifier,
+			ProfileService:       profileService,
+			DocumentLoader:       loader,
+			VDR:                  vdr,
+		})
+
+		err := withError.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{{
+				Nonce:         "nonce1",
+				Presentation:  vp,
+				SignerDIDID:   "did:example123:ebfeb1f712ebc6f1c276e12ec21",
+				VpTokenFormat: vcsverifiable.Jwt,
+			}})
+
+		require.Contains(t, err.Error(), "verification failed")
+
+		var verificationErr *oidc4vp.ErrVerificationFailed
+		require.ErrorAs(t, err, &verificationErr)
+		require.Equal(t, pd.InputDescriptors[0].ID, verificationErr.DescriptorID)
+		require.Equal(t, vp.Credentials()[0].(*verifiable.Credential).ID, verificationErr.CredentialID)
+		require.Equal(t, "verification failed", verificationErr.Reason)
+	})
+
+	t.Run("verification failed for several tokens", func(t *testing.T) {
+		errPresentationVerifier := NewMockPresentationVerifier(gomock.NewController(t))
+		errPresentationVerifier.EXPECT().VerifyPresentation(
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).MinTimes(1).
+			Return(nil, errors.New("verification failed"))
+
+		eventSvc := &mockEvent{}
+
+		withError := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:             eventSvc,
+			EventTopics:          []string{spi.VerifierEventTopic},
+			TransactionManager:   txManager,
+			PresentationVerifier: errPresentationVerifier,
+			ProfileService:       profileService,
+			DocumentLoader:       loader,
+			VDR:                  vdr,
+		})
+
+		var tokens []*oidc4vp.ProcessedVPToken
+
+		for i := 0; i < 5; i++ {
+			tokens = append(tokens, &oidc4vp.ProcessedVPToken{
+				Nonce:         "nonce1",
+				Presentation:  vp,
+				SignerDIDID:   "did:example123:ebfeb1f712ebc6f1c276e12ec21",
+				VpTokenFormat: vcsverifiable.Jwt,
+			})
+		}
+
+		err := withError.VerifyOIDCVerifiablePresentation(context.Background(), "txID1", tokens)
+		require.ErrorContains(t, err, "verification failed")
+
+		var failedEvents int
+
+		for _, e := range eventSvc.published[spi.VerifierEventTopic] {
+			if e.Type == spi.VerifierOIDCInteractionFailed {
+				failedEvents++
+			}
+		}
+
+		require.Equal(t, 1, failedEvents)
+	})
+
+	t.Run("verification checks failed", func(t *testing.T) {
+		checksPresentationVerifier := NewMockPresentationVerifier(gomock.NewController(t))
+		checksPresentationVerifier.EXPECT().VerifyPresentation(
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			Return([]verifypresentation.PresentationVerificationCheckResult{{
+				Check: "credentialStatus",
+				Error: "credential is revoked",
+			}}, nil)
+		withChecksFailed := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:             &mockEvent{},
+			EventTopics:          []string{spi.VerifierEventTopic},
+			TransactionManager:   txManager,
+			PresentationVerifier: checksPresentationVerifier,
+			ProfileService:       profileService,
+			DocumentLoader:       loader,
+			VDR:                  vdr,
+		})
+
+		err := withChecksFailed.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{{
+				Nonce:         "nonce1",
+				Presentation:  vp,
+				SignerDIDID:   "did:example123:ebfeb1f712ebc6f1c276e12ec21",
+				VpTokenFormat: vcsverifiable.Jwt,
+			}})
+		require.ErrorContains(t, err, "presentation verification checks failed: credential is revoked")
+
+		var verificationErr *oidc4vp.ErrVerificationFailed
+		require.ErrorAs(t, err, &verificationErr)
+		require.Equal(t, pd.InputDescriptors[0].ID, verificationErr.DescriptorID)
+		require.Equal(t, "credential is revoked", verificationErr.Reason)
+		require.NoError(t, verificationErr.Unwrap())
+	})
+
+	t.Run("Allowed VDR methods", func(t *testing.T) {
+		token := &oidc4vp.ProcessedVPToken{
+			Nonce:         "nonce1",
+			Presentation:  vp,
+			SignerDIDID:   issuer,
+			VpTokenFormat: vcsverifiable.Jwt,
+		}
+
+		newService := func(methods ...string) *oidc4vp.Service {
+			return oidc4vp.NewService(nil,
+				oidc4vp.WithEventService(&mockEvent{}, spi.VerifierEventTopic),
+				oidc4vp.WithTransactionManager(txManager),
+				oidc4vp.WithPresentationVerifier(presentationVerifier),
+				oidc4vp.WithProfileService(profileService),
+				oidc4vp.WithDocumentLoader(loader),
+				oidc4vp.WithVDR(vdr),
+				oidc4vp.WithAllowedVDRMethods(methods...),
+			)
+		}
+
+		err := newService("web", strings.Split(issuer, ":")[1]).VerifyOIDCVerifiablePresentation(
+			context.Background(), "txID1", []*oidc4vp.ProcessedVPToken{token})
+		require.NoError(t, err)
+
+		err = newService("web", "ion").VerifyOIDCVerifiablePresentation(
+			context.Background(), "txID1", []*oidc4vp.ProcessedVPToken{token})
+		require.ErrorIs(t, err, oidc4vp.ErrDisallowedDIDMethod)
+		require.ErrorContains(t, err, strings.Split(issuer, ":")[1])
+	})
+
+	t.Run("Invalid signer DID syntax", func(t *testing.T) {
+		for _, signer := range []string{"", "example:123", "did:example", "did::123", "did:example:"} {
+			err := s.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+				[]*oidc4vp.ProcessedVPToken{{
+					Nonce:         "nonce1",
+					Presentation:  vp,
+					SignerDIDID:   signer,
+					VpTokenFormat: vcsverifiable.Jwt,
+				}})
+			require.ErrorIs(t, err, oidc4vp.ErrInvalidDIDSyntax, signer)
+		}
+	})
+
+	t.Run("Match failed", func(t *testing.T) {
+		err := s.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{{
+				Nonce:         "nonce1",
+				Presentation:  &verifiable.Presentation{},
+				SignerDIDID:   issuer,
+				VpTokenFormat: vcsverifiable.Jwt,
+			}})
+		require.Contains(t, err.Error(), "match:")
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		errTxManager := NewMockTransactionManager(gomock.NewController(t))
+		errTxManager.EXPECT().GetByOneTimeToken(gomock.Any(), "nonce1").AnyTimes().Return(&oidc4vp.Transaction{
+			ID:                     "txID1",
+			ProfileID:              profileID,
+			ProfileVersion:         profileVersion,
+			PresentationDefinition: pd,
+		}, true, nil)
+
+		errTxManager.EXPECT().StoreReceivedClaims(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).
+			Return(errors.New("store error"))
+		errTxManager.EXPECT().UpdateTx(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ oidc4vp.TxID, update *oidc4vp.TransactionUpdate) error {
+				require.Equal(t, oidc4vp.TransactionStatusFailed, *update.Status)
+
+				return nil
+			})
+
+		eventSvc := &mockEvent{}
+
+		withError := oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:             eventSvc,
+			EventTopics:          []string{spi.VerifierEventTopic},
+			TransactionManager:   errTxManager,
+			PresentationVerifier: presentationVerifier,
+			ProfileService:       profileService,
+			DocumentLoader:       loader,
+			VDR:                  vdr,
+		})
+
+		err := withError.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+			[]*oidc4vp.ProcessedVPToken{{
+				Nonce:         "nonce1",
+				Presentation:  vp,
+				SignerDIDID:   issuer,
+				VpTokenFormat: vcsverifiable.Jwt,
+			}})
+
+		require.Contains(t, err.Error(), "store error")
+
+		var verificationFailed *spi.Event
+		for _, e := range eventSvc.published[spi.VerifierEventTopic] {
+			if e.Type == spi.VerifierOIDCVerificationFailed {
+				verificationFailed = e
+			}
+		}
+
+		require.NotNil(t, verificationFailed)
+		require.Equal(t, "txID1", verificationFailed.TransactionID)
+
+		var payload map[string]interface{}
+		require.NoError(t, json.Unmarshal(verificationFailed.Data, &payload))
+		require.Equal(t, "txID1", payload["txID"])
+		require.Contains(t, payload["error"], "store error")
+	})
+	t.Run("Credential schema validation", func(t *testing.T) {
+		schemaProfileService := NewMockProfileService(gomock.NewController(t))
+		schemaProfileService.EXPECT().GetProfile(profileID, profileVersion).AnyTimes().Return(&profileapi.Verifier{
+			ID:      profileID,
+			Version: profileVersion,
+			Active:  true,
+			Checks: &profileapi.VerificationChecks{
+				Presentation: &profileapi.PresentationChecks{
+					Format: []vcsverifiable.Format{
+						vcsverifiable.Jwt,
+					},
+				},
+				CredentialSchemaURL: map[string]string{
+					pd.InputDescriptors[0].ID: "https://example.com/schema.json",
+				},
+			},
+		}, nil)
+
+		tokens := []*oidc4vp.ProcessedVPToken{{
+			Nonce:         "nonce1",
+			Presentation:  vp,
+			SignerDIDID:   issuer,
+			VpTokenFormat: vcsverifiable.Jwt,
+		}}
+
+		newService := func(validator oidc4vp.SchemaValidator) *oidc4vp.Service {
+			return oidc4vp.NewService(&oidc4vp.Config{
+				EventSvc:             &mockEvent{},
+				EventTopics:          []string{spi.VerifierEventTopic},
+				TransactionManager:   txManager,
+				PresentationVerifier: presentationVerifier,
+				ProfileService:       schemaProfileService,
+				DocumentLoader:       loader,
+				VDR:                  vdr,
+				SchemaValidator:      validator,
+			})
+		}
+
+		t.Run("Success", func(t *testing.T) {
+			validator := &mockSchemaValidator{}
+
+			err := newService(validator).VerifyOIDCVerifiablePresentation(context.Background(), "txID1", tokens)
+
+			require.NoError(t, err)
+			require.Equal(t, []string{"https://example.com/schema.json"}, validator.validated)
+		})
+
+		t.Run("Validation failed", func(t *testing.T) {
+			err := newService(&mockSchemaValidator{err: errors.New("missing property")}).
+				VerifyOIDCVerifiablePresentation(context.Background(), "txID1", tokens)
+
+			require.ErrorContains(t, err, "credential schema validation for input descriptor")
+			require.ErrorContains(t, err, "missing property")
+		})
+
+		t.Run("Validator not configured", func(t *testing.T) {
+			err := newService(nil).VerifyOIDCVerifiablePresentation(context.Background(), "txID1", tokens)
+
+			require.ErrorContains(t, err, "schema validator is not configured")
+		})
+	})
+
+	t.Run("Polling client", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			format vcsverifiable.Format
+			status oidc4vp.TransactionStatus
+		}{
+			{
+				name:   "Completed",
+				format: vcsverifiable.Jwt,
+				status: oidc4vp.TransactionStatusCompleted,
+			},
+			{
+				name:   "Failed",
+				format: vcsverifiable.Ldp,
+				status: oidc4vp.TransactionStatusFailed,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var (
+					mu     sync.Mutex
+					status oidc4vp.TransactionStatus
+				)
+
+				pollTxManager := NewMockTransactionManager(gomock.NewController(t))
+				pollTxManager.EXPECT().GetByOneTimeToken(gomock.Any(), "nonce1").Return(&oidc4vp.Transaction{
+					ID:                     "txID1",
+					ProfileID:              profileID,
+					ProfileVersion:         profileVersion,
+					PresentationDefinition: pd,
+				}, true, nil)
+				pollTxManager.EXPECT().StoreReceivedClaims(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).AnyTimes().
+					DoAndReturn(func(context.Context, oidc4vp.TxID, *oidc4vp.ReceivedClaims) error {
+						mu.Lock()
+						defer mu.Unlock()
+
+						status = oidc4vp.TransactionStatusCompleted
+
+						return nil
+					})
+				pollTxManager.EXPECT().UpdateTx(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).AnyTimes().
+					DoAndReturn(func(_ context.Context, _ oidc4vp.TxID, update *oidc4vp.TransactionUpdate) error {
+						mu.Lock()
+						defer mu.Unlock()
+
+						status = *update.Status
+
+						return nil
+					})
+				pollTxManager.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID1")).AnyTimes().
+					DoAndReturn(func(_ context.Context, txID oidc4vp.TxID) (*oidc4vp.Transaction, error) {
+						mu.Lock()
+						defer mu.Unlock()
+
+						return &oidc4vp.Transaction{ID: txID, Status: status}, nil
+					})
+
+				svc := oidc4vp.NewService(&oidc4vp.Config{
+					EventSvc:             &mockEvent{},
+					EventTopics:          []string{spi.VerifierEventTopic},
+					TransactionManager:   pollTxManager,
+					PresentationVerifier: presentationVerifier,
+					ProfileService:       profileService,
+					DocumentLoader:       loader,
+					VDR:                  vdr,
+				})
+
+				polled := make(chan oidc4vp.TransactionStatus)
+
+				go func() {
+					defer close(polled)
+
+					for {
+						st, pollErr := svc.PollTransactionStatus(context.Background(), "txID1")
+						if pollErr != nil || st != oidc4vp.TransactionStatusPending {
+							polled <- st
+
+							return
+						}
+
+						time.Sleep(time.Millisecond)
+					}
+				}()
+
+				_ = svc.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+					[]*oidc4vp.ProcessedVPToken{{
+						Nonce:         "nonce1",
+						Presentation:  vp,
+						SignerDIDID:   issuer,
+						VpTokenFormat: tt.format,
+					}})
+
+				select {
+				case st := <-polled:
+					require.Equal(t, tt.status, st)
+				case <-time.After(5 * time.Second):
+					require.Fail(t, "polling client didn't observe transaction completion")
+				}
+			})
+		}
+	})
+}
+
+func TestService_PollTransactionStatus(t *testing.T) {
+	t.Run("Pending", func(t *testing.T) {
+		txManager := oidc4vptest.NewInMemoryTransactionManager()
+
+		tx, _, err := txManager.CreateTx(context.Background(), nil, profileID, profileVersion, "", 0, nil)
+		require.NoError(t, err)
+
+		svc := oidc4vptest.NewTestService(t, oidc4vp.WithTransactionManager(txManager))
+
+		status, err := svc.PollTransactionStatus(context.Background(), tx.ID)
+		require.NoError(t, err)
+		require.Equal(t, oidc4vp.TransactionStatusPending, status)
+	})
+
+	t.Run("Completed", func(t *testing.T) {
+		txManager := oidc4vptest.NewInMemoryTransactionManager()
+
+		tx, _, err := txManager.CreateTx(context.Background(), nil, profileID, profileVersion, "", 0, nil)
+		require.NoError(t, err)
+		require.NoError(t, txManager.StoreReceivedClaims(context.Background(), tx.ID, &oidc4vp.ReceivedClaims{}))
+
+		svc := oidc4vptest.NewTestService(t, oidc4vp.WithTransactionManager(txManager))
+
+		status, err := svc.PollTransactionStatus(context.Background(), tx.ID)
+		require.NoError(t, err)
+		require.Equal(t, oidc4vp.TransactionStatusCompleted, status)
+	})
+
+	t.Run("Completed without status", func(t *testing.T) {
+		txManager := NewMockTransactionManager(gomock.NewController(t))
+		txManager.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID1")).Return(&oidc4vp.Transaction{
+			ID:               "txID1",
+			ReceivedClaimsID: "claimsID",
+		}, nil)
+
+		svc := oidc4vptest.NewTestService(t, oidc4vp.WithTransactionManager(txManager))
+
+		status, err := svc.PollTransactionStatus(context.Background(), "txID1")
+		require.NoError(t, err)
+		require.Equal(t, oidc4vp.TransactionStatusCompleted, status)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		svc := oidc4vptest.NewTestService(t)
+
+		status, err := svc.PollTransactionStatus(context.Background(), "txID1")
+		require.NoError(t, err)
+		require.Equal(t, oidc4vp.TransactionStatusExpired, status)
+	})
+
+	t.Run("Get tx error", func(t *testing.T) {
+		txManager := NewMockTransactionManager(gomock.NewController(t))
+		txManager.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID1")).Return(nil, errors.New("get error"))
+
+		svc := oidc4vptest.NewTestService(t, oidc4vp.WithTransactionManager(txManager))
+
+		_, err := svc.PollTransactionStatus(context.Background(), "txID1")
+		require.ErrorContains(t, err, "get tx: get error")
+	})
+}
+
+func TestService_GetTx(t *testing.T) {
+	txManager := oidc4vptest.NewInMemoryTransactionManager()
+
+	created, _, err := txManager.CreateTx(context.Background(), nil, "testP1", profileVersion, "", 0, nil)
+	require.NoError(t, err)
+
+	svc := oidc4vptest.NewTestService(t, oidc4vp.WithTransactionManager(txManager))
+
+	t.Run("Success", func(t *testing.T) {
+		tx, err := svc.GetTx(context.Background(), created.ID)
+		require.NoError(t, err)
+		require.NotNil(t, tx)
+		require.Equal(t, "testP1", tx.ProfileID)
+	})
+}
+
+func TestService_DeleteClaims(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		txManager := oidc4vptest.NewInMemoryTransactionManager()
+
+		tx, _, err := txManager.CreateTx(context.Background(), nil, profileID, profileVersion, "", 0, nil)
+		require.NoError(t, err)
+		require.NoError(t, txManager.StoreReceivedClaims(context.Background(), tx.ID, &oidc4vp.ReceivedClaims{}))
+
+		tx, err = txManager.Get(context.Background(), tx.ID)
+		require.NoError(t, err)
+
+		svc := oidc4vptest.NewTestService(t, oidc4vp.WithTransactionManager(txManager))
+
+		err = svc.DeleteClaims(context.Background(), tx.ReceivedClaimsID)
+		require.NoError(t, err)
+
+		_, err = txManager.GetByClaimsID(context.Background(), tx.ReceivedClaimsID)
+		require.ErrorIs(t, err, oidc4vp.ErrDataNotFound)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		txManager := NewMockTransactionManager(gomock.NewController(t))
+		txManager.EXPECT().DeleteReceivedClaims(gomock.Any(), "claimsID").Times(1).Return(fmt.Errorf("delete error"))
+
+		svc := oidc4vptest.NewTestService(t, oidc4vp.WithTransactionManager(txManager))
+
+		err := svc.DeleteClaims(context.Background(), "claimsID")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "delete error")
+	})
+}
+
+func TestService_GetTxByClaimsID(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		txManager := oidc4vptest.NewInMemoryTransactionManager()
+
+		created, _, err := txManager.CreateTx(context.Background(), nil, profileID, profileVersion, "", 0, nil)
+		require.NoError(t, err)
+		require.NoError(t, txManager.StoreReceivedClaims(context.Background(), created.ID, &oidc4vp.ReceivedClaims{}))
+
+		created, err = txManager.Get(context.Background(), created.ID)
+		require.NoError(t, err)
+
+		svc := oidc4vptest.NewTestService(t, oidc4vp.WithTransactionManager(txManager))
+
+		tx, err := svc.GetTxByClaimsID(context.Background(), created.ReceivedClaimsID)
+		require.NoError(t, err)
+		require.Equal(t, created.ID, tx.ID)
+		require.Equal(t, created.ReceivedClaimsID, tx.ReceivedClaimsID)
+	})
+
+	t.Run("Tx not found", func(t *testing.T) {
+		svc := oidc4vptest.NewTestService(t)
+
+		tx, err := svc.GetTxByClaimsID(context.Background(), "claimsID")
+		require.ErrorIs(t, err, oidc4vp.ErrDataNotFound)
+		require.Nil(t, tx)
+	})
+}
+
+func TestService_DeleteClaimsForProfile(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		txManager := NewMockTransactionManager(gomock.NewController(t))
+		txManager.EXPECT().GetByClaimsID(gomock.Any(), "claimsID").Times(1).Return(&oidc4vp.Transaction{
+			ID:             "txID",
+			ProfileID:      profileID,
+			ProfileVersion: profileVersion,
+		}, nil)
+		txManager.EXPECT().DeleteReceivedClaims(gomock.Any(), "claimsID").Times(1).Return(nil)
+
+		svc := oidc4vptest.NewTestService(t, oidc4vp.WithTransactionManager(txManager))
+
+		err := svc.DeleteClaimsForProfile(context.Background(), "claimsID", profileID, profileVersion)
+		require.NoError(t, err)
+	})
+
+	t.Run("Ownership violation", func(t *testing.T) {
+		txManager := NewMockTransactionManager(gomock.NewController(t))
+		txManager.EXPECT().GetByClaimsID(gomock.Any(), "claimsID").Times(1).Return(&oidc4vp.Transaction{
+			ID:             "txID",
+			ProfileID:      "otherProfileID",
+			ProfileVersion: profileVersion,
+		}, nil)
+		txManager.EXPECT().DeleteReceivedClaims(gomock.Any(), gomock.Any()).Times(0)
+
+		svc := oidc4vptest.NewTestService(t, oidc4vp.WithTransactionManager(txManager))
+
+		err := svc.DeleteClaimsForProfile(context.Background(), "claimsID", profileID, profileVersion)
+		require.ErrorIs(t, err, oidc4vp.ErrClaimsOwnershipViolation)
+	})
+
+	t.Run("Tx not found", func(t *testing.T) {
+		svc := oidc4vptest.NewTestService(t)
+
+		err := svc.DeleteClaimsForProfile(context.Background(), "claimsID", profileID, profileVersion)
+		require.ErrorIs(t, err, oidc4vp.ErrDataNotFound)
+	})
+}
+
+func TestService_AmendTransaction(t *testing.T) {
+	purpose := "amended purpose"
+	update := &oidc4vp.TransactionUpdate{
+		PresentationDefinition: &presexch.PresentationDefinition{ID: "amended"},
+		Purpose:                &purpose,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		txManager := oidc4vptest.NewInMemoryTransactionManager()
+
+		tx, _, err := txManager.CreateTx(context.Background(), nil, profileID, profileVersion, "", 0, nil)
+		require.NoError(t, err)
+
+		svc := oidc4vptest.NewTestService(t, oidc4vp.WithTransactionManager(txManager))
+
+		err = svc.AmendTransaction(context.Background(), tx.ID, profileID, profileVersion, update)
+		require.NoError(t, err)
+
+		tx, err = txManager.Get(context.Background(), tx.ID)
+		require.NoError(t, err)
+		require.Equal(t, "amended", tx.PresentationDefinition.ID)
+		require.Equal(t, purpose, tx.Purpose)
+	})
+
+	t.Run("Ownership violation", func(t *testing.T) {
+		txManager := oidc4vptest.NewInMemoryTransactionManager()
+
+		tx, _, err := txManager.CreateTx(context.Background(), nil, profileID, "otherProfileVersion", "", 0, nil)
+		require.NoError(t, err)
+
+		svc := oidc4vptest.NewTestService(t, oidc4vp.WithTransactionManager(txManager))
+
+		err = svc.AmendTransaction(context.Background(), tx.ID, profileID, profileVersion, update)
+		require.ErrorIs(t, err, oidc4vp.ErrTxOwnershipViolation)
+	})
+
+	t.Run("Tx completed", func(t *testing.T) {
+		txManager := oidc4vptest.NewInMemoryTransactionManager()
+
+		tx, _, err := txManager.CreateTx(context.Background(), nil, profileID, profileVersion, "", 0, nil)
+		require.NoError(t, err)
+		require.NoError(t, txManager.StoreReceivedClaims(context.Background(), tx.ID, &oidc4vp.ReceivedClaims{}))
+
+		svc := oidc4vptest.NewTestService(t, oidc4vp.WithTransactionManager(txManager))
+
+		err = svc.AmendTransaction(context.Background(), tx.ID, profileID, profileVersion, update)
+		require.ErrorIs(t, err, oidc4vp.ErrTxCompleted)
+	})
+
+	t.Run("Tx not found", func(t *testing.T) {
+		svc := oidc4vptest.NewTestService(t)
+
+		err := svc.AmendTransaction(context.Background(), "txID", profileID, profileVersion, update)
+		require.ErrorIs(t, err, oidc4vp.ErrDataNotFound)
+	})
+}
+
+func TestService_ReissueRequestObject(t *testing.T) {
+	customKMS := createKMS(t)
+
+	customCrypto, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	kmsRegistry := NewMockKMSRegistry(gomock.NewController(t))
+	kmsRegistry.EXPECT().GetKeyManager(gomock.Any()).AnyTimes().Return(
+		&mockVCSKeyManager{crypto: customCrypto, kms: customKMS}, nil)
+
+	profile := &profileapi.Verifier{
+		ID:      profileID,
+		Version: profileVersion,
+		OIDCConfig: &profileapi.OIDC4VPConfig{
+			KeyType: kms.ED25519Type,
+		},
+		Checks: &profileapi.VerificationChecks{
+			Presentation: &profileapi.PresentationChecks{
+				Format: []vcsverifiable.Format{vcsverifiable.Jwt},
+			},
+		},
+		SigningDID: &profileapi.SigningDID{
+			DID: "did:test:acde",
+		},
+	}
+
+	pendingTx := func() *oidc4vp.Transaction {
+		return &oidc4vp.Transaction{
+			ID:                     "txID",
+			ProfileID:              profileID,
+			ProfileVersion:         profileVersion,
+			PresentationDefinition: &presexch.PresentationDefinition{ID: "pd"},
+			Purpose:                "purpose",
+			ExpiresAt:              time.Now().Add(time.Minute),
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		txManager := NewMockTransactionManager(gomock.NewController(t))
+		txManager.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).Times(1).Return(pendingTx(), nil)
+		txManager.EXPECT().CreateTxNonce(gomock.Any(), oidc4vp.TxID("txID"), gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, _ oidc4vp.TxID, ttl time.Duration) (string, error) {
+				require.True(t, ttl > 0 && ttl <= time.Minute)
+
+				return "nonce2", nil
+			})
+
+		requestObjectPublicStore := NewMockRequestObjectPublicStore(gomock.NewController(t))
+		requestObjectPublicStore.EXPECT().Publish(gomock.Any(), "signed-request-object", gomock.Any(), gomock.Any()).
+			Times(1).DoAndReturn(func(_ context.Context, _ string, event *spi.Event, _ time.Duration) (string, error) {
+			require.EqualValues(t, spi.VerifierOIDCInteractionQRScanned, event.Type)
+			require.Equal(t, "txID", event.TransactionID)
+
+			return "someurl/def", nil
+		})
+
+		signer := &mockRequestObjectSigner{token: "signed-request-object"}
+
+		svc := oidc4vp.NewService(&oidc4vp.Config{
+			TransactionManager:       txManager,
+			RequestObjectPublicStore: requestObjectPublicStore,
+			KMSRegistry:              kmsRegistry,
+			RequestObjectSigner:      signer,
+			RedirectURL:              "https://verifier.example.com/oidc/present",
+			PublicURL:                "https://verifier.example.com",
+		})
+
+		requestURI, err := svc.ReissueRequestObject(context.Background(), "txID", profile)
+		require.NoError(t, err)
+		require.Equal(t, "someurl/def", requestURI)
+		require.Equal(t, "nonce2", signer.claims["nonce"])
+		require.Equal(t, "purpose", signer.claims["registration"].(map[string]interface{})["client_purpose"])
+		require.Equal(t, profileapi.ResponseModePost, signer.claims["response_mode"])
+		require.Equal(t, "https://verifier.example.com/oidc/present", signer.claims["redirect_uri"])
+		require.NotContains(t, signer.claims, "response_uri")
+	})
+
+	t.Run("Success - direct_post", func(t *testing.T) {
+		directPostProfile := *profile
+		directPostProfile.OIDCConfig = &profileapi.OIDC4VPConfig{
+			KeyType:      kms.ED25519Type,
+			ResponseMode: profileapi.ResponseModeDirectPost,
+		}
+
+		txManager := NewMockTransactionManager(gomock.NewController(t))
+		txManager.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).Times(1).Return(pendingTx(), nil)
+		txManager.EXPECT().CreateTxNonce(gomock.Any(), oidc4vp.TxID("txID"), gomock.Any()).Times(1).
+			Return("nonce2", nil)
+
+		requestObjectPublicStore := NewMockRequestObjectPublicStore(gomock.NewController(t))
+		requestObjectPublicStore.EXPECT().Publish(gomock.Any(), "signed-request-object", gomock.Any(), gomock.Any()).
+			Times(1).Return("someurl/def", nil)
 
-	t.Run("Invalid Nonce 2", func(t *testing.T) {
-		err := s.VerifyOIDCVerifiablePresentation(context.Background(), "txID2",
-			[]*oidc4vp.ProcessedVPToken{{
-				Nonce:        "nonce1",
-				Presentation: vp,
-				SignerDIDID:  "did:example123:ebfeb1f712ebc6f1c276e12ec21",
-			}})
+		signer := &mockRequestObjectSigner{token: "signed-request-object"}
 
-		require.Contains(t, err.Error(), "invalid nonce")
+		svc := oidc4vp.NewService(&oidc4vp.Config{
+			TransactionManager:       txManager,
+			RequestObjectPublicStore: requestObjectPublicStore,
+			KMSRegistry:              kmsRegistry,
+			RequestObjectSigner:      signer,
+			RedirectURL:              "https://verifier.example.com/oidc/present",
+			PublicURL:                "https://verifier.example.com/",
+		})
+
+		_, err := svc.ReissueRequestObject(context.Background(), "txID", &directPostProfile)
+		require.NoError(t, err)
+		require.Equal(t, profileapi.ResponseModeDirectPost, signer.claims["response_mode"])
+		require.Equal(t,
+			"https://verifier.example.com/verifier/"+profileID+"/"+profileVersion+"/interactions/authorization-response",
+			signer.claims["response_uri"])
+		require.NotContains(t, signer.claims, "redirect_uri")
 	})
 
-	t.Run("Invalid Nonce", func(t *testing.T) {
-		errProfileService := NewMockProfileService(gomock.NewController(t))
-		errProfileService.EXPECT().GetProfile(profileID, profileVersion).Times(1).Return(nil,
-			errors.New("get profile error"))
+	t.Run("Ownership violation", func(t *testing.T) {
+		tx := pendingTx()
+		tx.ProfileVersion = "otherProfileVersion"
 
-		withError := oidc4vp.NewService(&oidc4vp.Config{
-			EventSvc:             &mockEvent{},
-			EventTopic:           spi.VerifierEventTopic,
-			TransactionManager:   txManager,
-			PresentationVerifier: presentationVerifier,
-			ProfileService:       errProfileService,
-			DocumentLoader:       loader,
-		})
+		txManager := NewMockTransactionManager(gomock.NewController(t))
+		txManager.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).Times(1).Return(tx, nil)
+		txManager.EXPECT().CreateTxNonce(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-		err := withError.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
-			[]*oidc4vp.ProcessedVPToken{{
-				Nonce:        "nonce1",
-				Presentation: vp,
-				SignerDIDID:  "did:example123:ebfeb1f712ebc6f1c276e12ec21",
-			}})
+		svc := oidc4vp.NewService(&oidc4vp.Config{
+			TransactionManager: txManager,
+		})
 
-		require.Contains(t, err.Error(), "get profile error")
+		_, err := svc.ReissueRequestObject(context.Background(), "txID", profile)
+		require.ErrorIs(t, err, oidc4vp.ErrTxOwnershipViolation)
 	})
 
-	t.Run("verification failed", func(t *testing.T) {
-		errPresentationVerifier := NewMockPresentationVerifier(gomock.NewController(t))
-		errPresentationVerifier.EXPECT().VerifyPresentation(
-			context.Background(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
-			Return(nil, errors.New("verification failed"))
-		withError := oidc4vp.NewService(&oidc4vp.Config{
-			EventSvc:             &mockEvent{},
-			EventTopic:           spi.VerifierEventTopic,
-			TransactionManager:   txManager,
-			PresentationVerifier: errPresentationVerifier,
-			ProfileService:       profileService,
-			DocumentLoader:       loader,
-			VDR:                  vdr,
-		})
+	t.Run("Tx expired", func(t *testing.T) {
+		tx := pendingTx()
+		tx.ExpiresAt = time.Now().Add(-time.Second)
 
-		err := withError.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
-			[]*oidc4vp.ProcessedVPToken{{
-				Nonce:         "nonce1",
-				Presentation:  vp,
-				SignerDIDID:   "did:example123:ebfeb1f712ebc6f1c276e12ec21",
-				VpTokenFormat: vcsverifiable.Jwt,
-			}})
+		txManager := NewMockTransactionManager(gomock.NewController(t))
+		txManager.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).Times(1).Return(tx, nil)
+		txManager.EXPECT().CreateTxNonce(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-		require.Contains(t, err.Error(), "verification failed")
-	})
+		svc := oidc4vp.NewService(&oidc4vp.Config{
+			TransactionManager: txManager,
+		})
 
-	t.Run("Match failed", func(t *testing.T) {
-		err := s.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
-			[]*oidc4vp.ProcessedVPToken{{
-				Nonce:         "nonce1",
-				Presentation:  &verifiable.Presentation{},
-				VpTokenFormat: vcsverifiable.Jwt,
-			}})
-		require.Contains(t, err.Error(), "match:")
+		_, err := svc.ReissueRequestObject(context.Background(), "txID", profile)
+		require.ErrorIs(t, err, oidc4vp.ErrTxExpired)
 	})
 
-	t.Run("Store error", func(t *testing.T) {
-		errTxManager := NewMockTransactionManager(gomock.NewController(t))
-		errTxManager.EXPECT().GetByOneTimeToken("nonce1").AnyTimes().Return(&oidc4vp.Transaction{
-			ID:                     "txID1",
-			ProfileID:              profileID,
-			ProfileVersion:         profileVersion,
-			PresentationDefinition: pd,
-		}, true, nil)
+	t.Run("Tx already verified", func(t *testing.T) {
+		completed := pendingTx()
+		completed.ReceivedClaimsID = "claimsID"
 
-		errTxManager.EXPECT().StoreReceivedClaims(oidc4vp.TxID("txID1"), gomock.Any()).
-			Return(errors.New("store error"))
+		failed := pendingTx()
+		failed.Status = oidc4vp.TransactionStatusFailed
 
-		withError := oidc4vp.NewService(&oidc4vp.Config{
-			EventSvc:             &mockEvent{},
-			EventTopic:           spi.VerifierEventTopic,
-			TransactionManager:   errTxManager,
-			PresentationVerifier: presentationVerifier,
-			ProfileService:       profileService,
-			DocumentLoader:       loader,
-			VDR:                  vdr,
-		})
+		for _, tx := range []*oidc4vp.Transaction{completed, failed} {
+			txManager := NewMockTransactionManager(gomock.NewController(t))
+			txManager.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).Times(1).Return(tx, nil)
+			txManager.EXPECT().CreateTxNonce(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-		err := withError.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
-			[]*oidc4vp.ProcessedVPToken{{
-				Nonce:         "nonce1",
-				Presentation:  vp,
-				SignerDIDID:   issuer,
-				VpTokenFormat: vcsverifiable.Jwt,
-			}})
+			svc := oidc4vp.NewService(&oidc4vp.Config{
+				TransactionManager: txManager,
+			})
 
-		require.Contains(t, err.Error(), "store error")
+			_, err := svc.ReissueRequestObject(context.Background(), "txID", profile)
+			require.ErrorIs(t, err, oidc4vp.ErrTxCompleted)
+		}
 	})
-}
 
-func TestService_GetTx(t *testing.T) {
-	txManager := NewMockTransactionManager(gomock.NewController(t))
-	txManager.EXPECT().Get(oidc4vp.TxID("test")).Times(1).Return(&oidc4vp.Transaction{
-		ProfileID: "testP1",
-	}, nil)
+	t.Run("Tx not found", func(t *testing.T) {
+		txManager := NewMockTransactionManager(gomock.NewController(t))
+		txManager.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).Times(1).Return(nil, oidc4vp.ErrDataNotFound)
 
-	svc := oidc4vp.NewService(&oidc4vp.Config{
-		TransactionManager: txManager,
-	})
+		svc := oidc4vp.NewService(&oidc4vp.Config{
+			TransactionManager: txManager,
+		})
 
-	t.Run("Success", func(t *testing.T) {
-		tx, err := svc.GetTx(context.Background(), "test")
-		require.NoError(t, err)
-		require.NotNil(t, tx)
-		require.Equal(t, "testP1", tx.ProfileID)
+		_, err := svc.ReissueRequestObject(context.Background(), "txID", profile)
+		require.ErrorIs(t, err, oidc4vp.ErrDataNotFound)
 	})
-}
 
-func TestService_DeleteClaims(t *testing.T) {
-	t.Run("Success", func(t *testing.T) {
+	t.Run("Create nonce error", func(t *testing.T) {
 		txManager := NewMockTransactionManager(gomock.NewController(t))
-		txManager.EXPECT().DeleteReceivedClaims("claimsID").Times(1).Return(nil)
+		txManager.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).Times(1).Return(pendingTx(), nil)
+		txManager.EXPECT().CreateTxNonce(gomock.Any(), oidc4vp.TxID("txID"), gomock.Any()).Times(1).
+			Return("", errors.New("nonce store error"))
 
 		svc := oidc4vp.NewService(&oidc4vp.Config{
 			TransactionManager: txManager,
 		})
 
-		err := svc.DeleteClaims(context.Background(), "claimsID")
-		require.NoError(t, err)
+		_, err := svc.ReissueRequestObject(context.Background(), "txID", profile)
+		require.ErrorContains(t, err, "create tx nonce: nonce store error")
 	})
 
-	t.Run("Error", func(t *testing.T) {
+	t.Run("Publish error", func(t *testing.T) {
 		txManager := NewMockTransactionManager(gomock.NewController(t))
-		txManager.EXPECT().DeleteReceivedClaims("claimsID").Times(1).Return(fmt.Errorf("delete error"))
+		txManager.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).Times(1).Return(pendingTx(), nil)
+		txManager.EXPECT().CreateTxNonce(gomock.Any(), oidc4vp.TxID("txID"), gomock.Any()).Times(1).
+			Return("nonce2", nil)
+
+		requestObjectPublicStore := NewMockRequestObjectPublicStore(gomock.NewController(t))
+		requestObjectPublicStore.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return("", errors.New("publish error"))
 
 		svc := oidc4vp.NewService(&oidc4vp.Config{
-			TransactionManager: txManager,
+			TransactionManager:       txManager,
+			RequestObjectPublicStore: requestObjectPublicStore,
+			KMSRegistry:              kmsRegistry,
+			RequestObjectSigner:      &mockRequestObjectSigner{token: "signed-request-object"},
 		})
 
-		err := svc.DeleteClaims(context.Background(), "claimsID")
-		require.Error(t, err)
-		require.Contains(t, err.Error(), "delete error")
+		_, err := svc.ReissueRequestObject(context.Background(), "txID", profile)
+		require.ErrorContains(t, err, "fail publish request object: publish error")
+	})
+}
+
+func TestService_RetrieveRawTokens(t *testing.T) {
+	svc := oidc4vp.NewService(&oidc4vp.Config{})
+
+	t.Run("Success", func(t *testing.T) {
+		tokens, err := svc.RetrieveRawTokens(context.Background(), &oidc4vp.Transaction{
+			ID: "txID",
+			ReceivedClaims: &oidc4vp.ReceivedClaims{
+				RawVPTokens: []string{"vp.token"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"vp.token"}, tokens)
+	})
+
+	t.Run("No received claims", func(t *testing.T) {
+		tokens, err := svc.RetrieveRawTokens(context.Background(), &oidc4vp.Transaction{ID: "txID"})
+		require.ErrorIs(t, err, oidc4vp.ErrDataNotFound)
+		require.Nil(t, tokens)
 	})
 }
 
@@ -697,10 +2707,12 @@ func TestService_RetrieveClaims(t *testing.T) {
 
 		require.NoError(t, err)
 
+		receivedAt := time.Now().UTC()
+
 		claims := svc.RetrieveClaims(context.Background(), &oidc4vp.Transaction{
 			ReceivedClaims: &oidc4vp.ReceivedClaims{Credentials: map[string]*verifiable.Credential{
 				"id": jwtvc,
-			}}})
+			}, ReceivedAt: receivedAt}})
 
 		require.NotNil(t, claims)
 		subjects, ok := claims["http://example.gov/credentials/3732"].SubjectData.([]verifiable.Subject)
@@ -708,9 +2720,17 @@ func TestService_RetrieveClaims(t *testing.T) {
 		require.True(t, ok)
 		require.Equal(t, "did:example:ebfeb1f712ebc6f1c276e12ec21", subjects[0].ID)
 
+		require.Equal(t, "http://example.gov/credentials/3732", claims["http://example.gov/credentials/3732"].CredentialID)
 		require.NotEmpty(t, claims["http://example.gov/credentials/3732"].Issuer)
 		require.NotEmpty(t, claims["http://example.gov/credentials/3732"].IssuanceDate)
 		require.Empty(t, claims["http://example.gov/credentials/3732"].ExpirationDate)
+		require.Equal(t, &receivedAt, claims["http://example.gov/credentials/3732"].ReceivedAt)
+
+		status := claims["http://example.gov/credentials/3732"].CredentialStatus
+		require.NotNil(t, status)
+		require.Equal(t, "https://issuer-vcs.sandbox.trustbloc.dev/vc-issuer-test-2/status/1#0", status.ID)
+		require.Equal(t, "StatusList2021Entry", status.Type)
+		require.Equal(t, "1", status.CustomFields["statusListIndex"])
 	})
 
 	t.Run("Success JsonLD", func(t *testing.T) {
@@ -731,9 +2751,30 @@ func TestService_RetrieveClaims(t *testing.T) {
 		require.True(t, ok)
 		require.Equal(t, "did:example:ebfeb1f712ebc6f1c276e12ec21", subjects[0].ID)
 
+		require.Equal(t, "http://example.gov/credentials/3732", claims["http://example.gov/credentials/3732"].CredentialID)
 		require.NotEmpty(t, claims["http://example.gov/credentials/3732"].Issuer)
 		require.NotEmpty(t, claims["http://example.gov/credentials/3732"].IssuanceDate)
 		require.NotEmpty(t, claims["http://example.gov/credentials/3732"].ExpirationDate)
+		require.Nil(t, claims["http://example.gov/credentials/3732"].ReceivedAt)
+
+		status := claims["http://example.gov/credentials/3732"].CredentialStatus
+		require.NotNil(t, status)
+		require.Equal(t, "https://issuer-vcs.sandbox.trustbloc.dev/vc-issuer-test-2/status/1#0", status.ID)
+		require.Equal(t, "StatusList2021Entry", status.Type)
+		require.Equal(t, "1", status.CustomFields["statusListIndex"])
+	})
+
+	t.Run("Success without credential status", func(t *testing.T) {
+		claims := svc.RetrieveClaims(context.Background(), &oidc4vp.Transaction{
+			ReceivedClaims: &oidc4vp.ReceivedClaims{Credentials: map[string]*verifiable.Credential{
+				"id": {
+					ID:    "http://example.gov/credentials/3732",
+					Types: []string{"VerifiableCredential"},
+				},
+			}}})
+
+		require.Contains(t, claims, "http://example.gov/credentials/3732")
+		require.Nil(t, claims["http://example.gov/credentials/3732"].CredentialStatus)
 	})
 
 	t.Run("Error", func(t *testing.T) {
@@ -783,18 +2824,118 @@ func (m *mockVCSKeyManager) CreateCryptoKey(_ kms.KeyType) (string, interface{},
 	return "", nil, nil
 }
 
-type mockEvent struct {
+type mockRequestObjectSigner struct {
+	token   string
+	err     error
+	claims  map[string]interface{}
+	profile *profileapi.Verifier
+}
+
+func (m *mockRequestObjectSigner) Sign(
+	_ context.Context,
+	claims map[string]interface{},
+	profile *profileapi.Verifier,
+) (string, error) {
+	m.claims = claims
+	m.profile = profile
+
+	return m.token, m.err
+}
+
+type mockAuditLogger struct {
+	err     error
+	entries []*oidc4vp.AuditEntry
+}
+
+func (m *mockAuditLogger) LogEvent(_ context.Context, entry *oidc4vp.AuditEntry) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.entries = append(m.entries, entry)
+
+	return nil
+}
+
+type mockPresentationDefinitionResolver struct {
+	pd  *presexch.PresentationDefinition
 	err error
+	uri string
+}
+
+func (m *mockPresentationDefinitionResolver) Resolve(
+	_ context.Context,
+	uri string,
+) (*presexch.PresentationDefinition, error) {
+	m.uri = uri
+
+	return m.pd, m.err
+}
+
+type mockEvent struct {
+	err       error
+	topicErrs map[string]error
+	mu        sync.Mutex
+	published map[string][]*spi.Event
 }
 
-func (m *mockEvent) Publish(_ context.Context, _ string, _ ...*spi.Event) error {
+func (m *mockEvent) Publish(_ context.Context, topic string, messages ...*spi.Event) error {
 	if m.err != nil {
 		return m.err
 	}
 
+	if err := m.topicErrs[topic]; err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.published == nil {
+		m.published = map[string][]*spi.Event{}
+	}
+
+	m.published[topic] = append(m.published[topic], messages...)
+
 	return nil
 }
 
+type mockSchemaValidator struct {
+	err       error
+	validated []string
+}
+
+func (m *mockSchemaValidator) Validate(_ context.Context, schema string, _ *verifiable.Credential) error {
+	m.validated = append(m.validated, schema)
+
+	return m.err
+}
+
+type mockTemplateRegistry struct {
+	pd        *presexch.PresentationDefinition
+	err       error
+	requested []string
+}
+
+func (m *mockTemplateRegistry) GetTemplate(_ context.Context, templateID string) (
+	*presexch.PresentationDefinition, error) {
+	m.requested = append(m.requested, templateID)
+
+	return m.pd, m.err
+}
+
+type mockTrustRegistry struct {
+	trusted   bool
+	err       error
+	requested []string
+}
+
+func (m *mockTrustRegistry) IsIssuerTrusted(_ context.Context, issuerDID, credentialType string) (bool, error) {
+	m.requested = append(m.requested, issuerDID+" "+credentialType)
+
+	return m.trusted, m.err
+}
+
 func newVPWithPD(t *testing.T, keyManager kms.KeyManager, crypto ariescrypto.Crypto) (
 	*verifiable.Presentation, *presexch.PresentationDefinition, string,
 	vdrapi.Registry, *lddocloader.DocumentLoader) {
@@ -1146,6 +3287,73 @@ func Test_GetSupportedVPFormats(t *testing.T) {
 				LdpVP: nil,
 			},
 		},
+		{
+			name: "OK with RSA",
+			args: args{
+				kmsSupportedKeyTypes: []kms.KeyType{
+					kms.RSAPS256Type,
+					kms.RSARS256Type,
+				},
+				supportedVPFormats: []vcsverifiable.Format{
+					vcsverifiable.Jwt,
+				},
+				supportedVCFormats: []vcsverifiable.Format{
+					vcsverifiable.Jwt,
+					vcsverifiable.Ldp,
+				},
+			},
+			want: &presexch.Format{
+				JwtVC: &presexch.JwtType{Alg: []string{
+					"PS256",
+					"RS256",
+				}},
+				JwtVP: &presexch.JwtType{Alg: []string{
+					"PS256",
+					"RS256",
+				}},
+				LdpVC: &presexch.LdpType{ProofType: []string{
+					"JsonWebSignature2020",
+				}},
+				LdpVP: nil,
+			},
+		},
+		{
+			name: "OK with BBS+",
+			args: args{
+				kmsSupportedKeyTypes: []kms.KeyType{
+					kms.ED25519Type,
+					kms.BLS12381G2Type,
+				},
+				supportedVPFormats: []vcsverifiable.Format{
+					vcsverifiable.Jwt,
+					vcsverifiable.Ldp,
+				},
+				supportedVCFormats: []vcsverifiable.Format{
+					vcsverifiable.Jwt,
+					vcsverifiable.Ldp,
+				},
+			},
+			want: &presexch.Format{
+				JwtVC: &presexch.JwtType{Alg: []string{
+					"EdDSA",
+				}},
+				JwtVP: &presexch.JwtType{Alg: []string{
+					"EdDSA",
+				}},
+				LdpVC: &presexch.LdpType{ProofType: []string{
+					"Ed25519Signature2018",
+					"Ed25519Signature2020",
+					"JsonWebSignature2020",
+					"BbsBlsSignature2020",
+				}},
+				LdpVP: &presexch.LdpType{ProofType: []string{
+					"Ed25519Signature2018",
+					"Ed25519Signature2020",
+					"JsonWebSignature2020",
+					"BbsBlsSignature2020",
+				}},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1159,19 +3367,29 @@ func Test_GetSupportedVPFormats(t *testing.T) {
 			}
 
 			assert.Equal(t, tt.want.JwtVP == nil, got.JwtVP == nil)
-			if got.JwtVC != nil {
+			if got.JwtVP != nil {
 				assert.ElementsMatch(t, tt.want.JwtVP.Alg, got.JwtVP.Alg)
 			}
 
 			assert.Equal(t, tt.want.LdpVC == nil, got.LdpVC == nil)
-			if got.JwtVC != nil {
+			if got.LdpVC != nil {
 				assert.ElementsMatch(t, tt.want.LdpVC.ProofType, got.LdpVC.ProofType)
 			}
 
 			assert.Equal(t, tt.want.LdpVP == nil, got.LdpVP == nil)
-			if got.JwtVC != nil {
+			if got.LdpVP != nil {
 				assert.ElementsMatch(t, tt.want.LdpVP.ProofType, got.LdpVP.ProofType)
 			}
 		})
 	}
 }
+
+func findEvent(events []*spi.Event, eventType spi.EventType) *spi.Event {
+	for _, e := range events {
+		if e.Type == eventType {
+			return e
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/trustbloc/vc-go/verifiable"
+
+	vcsverifiable "github.com/trustbloc/vcs/pkg/doc/verifiable"
+	profileapi "github.com/trustbloc/vcs/pkg/profile"
+	"github.com/trustbloc/vcs/pkg/service/verifypresentation"
+)
+
+type slowPresentationVerifier struct {
+	delay time.Duration
+}
+
+func (v *slowPresentationVerifier) VerifyPresentation(
+	_ context.Context,
+	_ *verifiable.Presentation,
+	_ *verifypresentation.Options,
+	_ *profileapi.Verifier,
+) ([]verifypresentation.PresentationVerificationCheckResult, error) {
+	time.Sleep(v.delay)
+
+	return nil, nil
+}
+
+func BenchmarkVerifyTokens(b *testing.B) {
+	s := &Service{presentationVerifier: &slowPresentationVerifier{delay: time.Millisecond}}
+
+	profile := &profileapi.Verifier{
+		Checks: &profileapi.VerificationChecks{
+			Presentation: &profileapi.PresentationChecks{
+				Format: []vcsverifiable.Format{vcsverifiable.Jwt},
+			},
+		},
+	}
+
+	for _, n := range []int{5, 10} {
+		tokens := make([]*ProcessedVPToken, n)
+		for i := range tokens {
+			tokens[i] = &ProcessedVPToken{
+				VpTokenFormat: vcsverifiable.Jwt,
+				Presentation:  &verifiable.Presentation{ID: fmt.Sprintf("presentation-%d", i)},
+			}
+		}
+
+		b.Run(fmt.Sprintf("Sequential/%d tokens", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, token := range tokens {
+					if err := s.verifyToken(context.Background(), profile, token); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("Parallel/%d tokens", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := s.verifyTokens(context.Background(), &Transaction{}, profile, tokens); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
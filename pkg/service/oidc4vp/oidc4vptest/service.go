@@ -0,0 +1,55 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package oidc4vptest provides utilities for testing code that uses the oidc4vp service.
+package oidc4vptest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/trustbloc/vcs/pkg/event/spi"
+	"github.com/trustbloc/vcs/pkg/internal/testutil"
+	vcskms "github.com/trustbloc/vcs/pkg/kms"
+	"github.com/trustbloc/vcs/pkg/service/oidc4vp"
+)
+
+// ErrKMSNotConfigured is returned by NoopKMSRegistry.
+var ErrKMSNotConfigured = errors.New("kms is not configured in the test service")
+
+// TestOption overrides the defaults of the test service. Any oidc4vp.Option can be used.
+type TestOption = oidc4vp.Option
+
+// NewTestService creates oidc4vp.Service with defaults that are safe to use in tests: NoopEventService,
+// InMemoryTransactionManager, NoopKMSRegistry and the test document loader. Options override the defaults.
+func NewTestService(t *testing.T, opts ...TestOption) *oidc4vp.Service {
+	t.Helper()
+
+	return oidc4vp.NewService(&oidc4vp.Config{
+		EventSvc:           NoopEventService{},
+		EventTopics:        []string{spi.VerifierEventTopic},
+		TransactionManager: NewInMemoryTransactionManager(),
+		KMSRegistry:        NoopKMSRegistry{},
+		DocumentLoader:     testutil.DocumentLoader(t),
+	}, opts...)
+}
+
+// NoopEventService discards published events.
+type NoopEventService struct{}
+
+// Publish does nothing.
+func (NoopEventService) Publish(context.Context, string, ...*spi.Event) error {
+	return nil
+}
+
+// NoopKMSRegistry has no key managers. Tests that sign request objects have to override it.
+type NoopKMSRegistry struct{}
+
+// GetKeyManager returns ErrKMSNotConfigured.
+func (NoopKMSRegistry) GetKeyManager(*vcskms.Config) (vcskms.VCSKeyManager, error) {
+	return nil, ErrKMSNotConfigured
+}
@@ -0,0 +1,245 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vptest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trustbloc/vc-go/presexch"
+
+	"github.com/trustbloc/vcs/pkg/service/oidc4vp"
+)
+
+const nonceSize = 10
+
+type receivedClaimsEntry struct {
+	txID   oidc4vp.TxID
+	claims *oidc4vp.ReceivedClaims
+}
+
+// InMemoryTransactionManager is a transaction manager that keeps transactions, one time tokens and received claims
+// in memory. One time tokens never expire.
+type InMemoryTransactionManager struct {
+	mu             sync.Mutex
+	txs            map[oidc4vp.TxID]oidc4vp.Transaction
+	nonces         map[string]oidc4vp.TxID
+	receivedClaims map[string]receivedClaimsEntry
+}
+
+// NewInMemoryTransactionManager creates InMemoryTransactionManager.
+func NewInMemoryTransactionManager() *InMemoryTransactionManager {
+	return &InMemoryTransactionManager{
+		txs:            map[oidc4vp.TxID]oidc4vp.Transaction{},
+		nonces:         map[string]oidc4vp.TxID{},
+		receivedClaims: map[string]receivedClaimsEntry{},
+	}
+}
+
+// CreateTx creates transaction and one time token for it.
+func (m *InMemoryTransactionManager) CreateTx(
+	_ context.Context,
+	pd *presexch.PresentationDefinition,
+	profileID, profileVersion, purpose string,
+	ttl time.Duration,
+	customData map[string]interface{},
+) (*oidc4vp.Transaction, string, error) {
+	nonce, err := genNonce()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now().UTC()
+
+	tx := oidc4vp.Transaction{
+		ID:                     oidc4vp.TxID(uuid.NewString()),
+		ProfileID:              profileID,
+		ProfileVersion:         profileVersion,
+		Purpose:                purpose,
+		PresentationDefinition: pd,
+		CustomData:             customData,
+		Status:                 oidc4vp.TransactionStatusPending,
+		CreatedAt:              now,
+	}
+
+	if ttl > 0 {
+		tx.ExpiresAt = now.Add(ttl)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.txs[tx.ID] = tx
+	m.nonces[nonce] = tx.ID
+
+	return &tx, nonce, nil
+}
+
+// CreateTxNonce creates one more one time token for the existing transaction.
+func (m *InMemoryTransactionManager) CreateTxNonce(
+	_ context.Context,
+	txID oidc4vp.TxID,
+	_ time.Duration,
+) (string, error) {
+	nonce, err := genNonce()
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.txs[txID]; !ok {
+		return "", oidc4vp.ErrDataNotFound
+	}
+
+	m.nonces[nonce] = txID
+
+	return nonce, nil
+}
+
+// StoreReceivedClaims stores claims received for the transaction and marks it completed.
+func (m *InMemoryTransactionManager) StoreReceivedClaims(
+	_ context.Context,
+	txID oidc4vp.TxID,
+	claims *oidc4vp.ReceivedClaims,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tx, ok := m.txs[txID]
+	if !ok {
+		return oidc4vp.ErrDataNotFound
+	}
+
+	claimsID := uuid.NewString()
+
+	m.receivedClaims[claimsID] = receivedClaimsEntry{txID: txID, claims: claims}
+
+	tx.ReceivedClaimsID = claimsID
+	tx.Status = oidc4vp.TransactionStatusCompleted
+	m.txs[txID] = tx
+
+	return nil
+}
+
+// DeleteReceivedClaims deletes received claims by id.
+func (m *InMemoryTransactionManager) DeleteReceivedClaims(_ context.Context, claimsID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.receivedClaims, claimsID)
+
+	return nil
+}
+
+// GetByClaimsID returns transaction the received claims with the given id were stored for.
+func (m *InMemoryTransactionManager) GetByClaimsID(_ context.Context, claimsID string) (*oidc4vp.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.receivedClaims[claimsID]
+	if !ok {
+		return nil, oidc4vp.ErrDataNotFound
+	}
+
+	return m.get(entry.txID)
+}
+
+// GetByOneTimeToken returns transaction by one time token and deletes the token.
+func (m *InMemoryTransactionManager) GetByOneTimeToken(
+	_ context.Context,
+	nonce string,
+) (*oidc4vp.Transaction, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	txID, ok := m.nonces[nonce]
+	if !ok {
+		return nil, false, nil
+	}
+
+	delete(m.nonces, nonce)
+
+	tx, err := m.get(txID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return tx, true, nil
+}
+
+// Get returns transaction by id.
+func (m *InMemoryTransactionManager) Get(_ context.Context, txID oidc4vp.TxID) (*oidc4vp.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.get(txID)
+}
+
+// UpdateTx amends transaction with the given id. Received claims can't be changed with the update.
+func (m *InMemoryTransactionManager) UpdateTx(
+	_ context.Context,
+	txID oidc4vp.TxID,
+	update *oidc4vp.TransactionUpdate,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tx, ok := m.txs[txID]
+	if !ok {
+		return oidc4vp.ErrDataNotFound
+	}
+
+	if update.ExpiresAt != nil {
+		tx.ExpiresAt = *update.ExpiresAt
+	}
+
+	if update.PresentationDefinition != nil {
+		tx.PresentationDefinition = update.PresentationDefinition
+	}
+
+	if update.Purpose != nil {
+		tx.Purpose = *update.Purpose
+	}
+
+	if update.Status != nil {
+		tx.Status = *update.Status
+	}
+
+	m.txs[txID] = tx
+
+	return nil
+}
+
+func (m *InMemoryTransactionManager) get(txID oidc4vp.TxID) (*oidc4vp.Transaction, error) {
+	tx, ok := m.txs[txID]
+	if !ok {
+		return nil, oidc4vp.ErrDataNotFound
+	}
+
+	if entry, found := m.receivedClaims[tx.ReceivedClaimsID]; found {
+		tx.ReceivedClaims = entry.claims
+	}
+
+	return &tx, nil
+}
+
+func genNonce() (string, error) {
+	nonceBytes := make([]byte, nonceSize)
+
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return "", fmt.Errorf("nonce generating random failed: %w", err)
+	}
+
+	return base64.URLEncoding.EncodeToString(nonceBytes), nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vptest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/vcs/pkg/service/oidc4vp"
+	"github.com/trustbloc/vcs/pkg/service/oidc4vp/oidc4vptest"
+)
+
+func TestInMemoryTransactionManager(t *testing.T) {
+	ctx := context.Background()
+	txManager := oidc4vptest.NewInMemoryTransactionManager()
+
+	tx, nonce, err := txManager.CreateTx(ctx, nil, "profileID", "v1.0", "", time.Minute, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, nonce)
+	require.Equal(t, oidc4vp.TransactionStatusPending, tx.Status)
+	require.False(t, tx.ExpiresAt.IsZero())
+
+	t.Run("one time token", func(t *testing.T) {
+		otherNonce, err := txManager.CreateTxNonce(ctx, tx.ID, time.Minute)
+		require.NoError(t, err)
+
+		for _, token := range []string{nonce, otherNonce} {
+			found, valid, err := txManager.GetByOneTimeToken(ctx, token)
+			require.NoError(t, err)
+			require.True(t, valid)
+			require.Equal(t, tx.ID, found.ID)
+
+			_, valid, err = txManager.GetByOneTimeToken(ctx, token)
+			require.NoError(t, err)
+			require.False(t, valid)
+		}
+
+		_, err = txManager.CreateTxNonce(ctx, "unknown", time.Minute)
+		require.ErrorIs(t, err, oidc4vp.ErrDataNotFound)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		status := oidc4vp.TransactionStatusFailed
+
+		require.NoError(t, txManager.UpdateTx(ctx, tx.ID, &oidc4vp.TransactionUpdate{Status: &status}))
+
+		updated, err := txManager.Get(ctx, tx.ID)
+		require.NoError(t, err)
+		require.Equal(t, oidc4vp.TransactionStatusFailed, updated.Status)
+
+		require.ErrorIs(t, txManager.UpdateTx(ctx, "unknown", &oidc4vp.TransactionUpdate{}), oidc4vp.ErrDataNotFound)
+	})
+
+	t.Run("received claims", func(t *testing.T) {
+		claims := &oidc4vp.ReceivedClaims{RawVPTokens: []string{"token"}}
+
+		require.NoError(t, txManager.StoreReceivedClaims(ctx, tx.ID, claims))
+
+		completed, err := txManager.Get(ctx, tx.ID)
+		require.NoError(t, err)
+		require.Equal(t, oidc4vp.TransactionStatusCompleted, completed.Status)
+		require.Equal(t, claims, completed.ReceivedClaims)
+
+		byClaims, err := txManager.GetByClaimsID(ctx, completed.ReceivedClaimsID)
+		require.NoError(t, err)
+		require.Equal(t, tx.ID, byClaims.ID)
+
+		require.NoError(t, txManager.DeleteReceivedClaims(ctx, completed.ReceivedClaimsID))
+
+		_, err = txManager.GetByClaimsID(ctx, completed.ReceivedClaimsID)
+		require.ErrorIs(t, err, oidc4vp.ErrDataNotFound)
+
+		require.ErrorIs(t, txManager.StoreReceivedClaims(ctx, "unknown", claims), oidc4vp.ErrDataNotFound)
+	})
+}
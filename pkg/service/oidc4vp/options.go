@@ -0,0 +1,191 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/piprate/json-gold/ld"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+	"github.com/trustbloc/vc-go/verifiable"
+)
+
+// Option configures the Service. Options are applied on top of the Config passed to NewService.
+type Option func(cfg *Config)
+
+// WithTransactionManager sets the transaction manager.
+func WithTransactionManager(tm transactionManager) Option {
+	return func(cfg *Config) {
+		cfg.TransactionManager = tm
+	}
+}
+
+// WithRequestObjectPublicStore sets the store used to publish request objects.
+func WithRequestObjectPublicStore(store requestObjectPublicStore) Option {
+	return func(cfg *Config) {
+		cfg.RequestObjectPublicStore = store
+	}
+}
+
+// WithKMSRegistry sets the KMS registry.
+func WithKMSRegistry(registry kmsRegistry) Option {
+	return func(cfg *Config) {
+		cfg.KMSRegistry = registry
+	}
+}
+
+// WithRequestObjectSigner sets the signer of request objects.
+func WithRequestObjectSigner(signer RequestObjectSigner) Option {
+	return func(cfg *Config) {
+		cfg.RequestObjectSigner = signer
+	}
+}
+
+// WithSigningAlgorithmOverride sets the JWS algorithm the default request object signer signs with.
+func WithSigningAlgorithmOverride(algorithm verifiable.JWSAlgorithm) Option {
+	return func(cfg *Config) {
+		cfg.SigningAlgorithmOverride = &algorithm
+	}
+}
+
+// WithDocumentLoader sets the JSON-LD document loader.
+func WithDocumentLoader(loader ld.DocumentLoader) Option {
+	return func(cfg *Config) {
+		cfg.DocumentLoader = loader
+	}
+}
+
+// WithProfileService sets the verifier profile service.
+func WithProfileService(svc profileService) Option {
+	return func(cfg *Config) {
+		cfg.ProfileService = svc
+	}
+}
+
+// WithEventService sets the event service and the topics events are published to.
+func WithEventService(svc eventService, topics ...string) Option {
+	return func(cfg *Config) {
+		cfg.EventSvc = svc
+		cfg.EventTopics = topics
+	}
+}
+
+// WithPresentationVerifier sets the presentation verifier.
+func WithPresentationVerifier(verifier presentationVerifier) Option {
+	return func(cfg *Config) {
+		cfg.PresentationVerifier = verifier
+	}
+}
+
+// WithVDR sets the VDR registry.
+func WithVDR(vdr vdrapi.Registry) Option {
+	return func(cfg *Config) {
+		cfg.VDR = vdr
+	}
+}
+
+// WithVDRResolutionTimeout bounds each DID resolution made through the VDR registry.
+func WithVDRResolutionTimeout(timeout time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.VDRResolutionTimeout = timeout
+	}
+}
+
+// WithAllowedVDRMethods restricts the DID methods VP signers may use.
+func WithAllowedVDRMethods(methods ...string) Option {
+	return func(cfg *Config) {
+		cfg.AllowedVDRMethods = methods
+	}
+}
+
+// WithTrustRegistryClient sets the client used to check issuers of received credentials in a trust registry.
+func WithTrustRegistryClient(client TrustRegistryClient) Option {
+	return func(cfg *Config) {
+		cfg.TrustRegistryClient = client
+	}
+}
+
+// WithTrustRegistryURL sets the URL of the trust registry used to check issuers of received credentials.
+func WithTrustRegistryURL(url string) Option {
+	return func(cfg *Config) {
+		cfg.TrustRegistryURL = url
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for remote calls of the service.
+func WithHTTPClient(client *http.Client) Option {
+	return func(cfg *Config) {
+		cfg.HTTPClient = client
+	}
+}
+
+// WithPresentationDefinitionResolver sets the resolver of presentation definitions passed by URI.
+func WithPresentationDefinitionResolver(resolver PresentationDefinitionResolver) Option {
+	return func(cfg *Config) {
+		cfg.PresentationDefinitionResolver = resolver
+	}
+}
+
+// WithSchemaValidator sets the credential JSON schema validator.
+func WithSchemaValidator(validator SchemaValidator) Option {
+	return func(cfg *Config) {
+		cfg.SchemaValidator = validator
+	}
+}
+
+// WithTemplateRegistry sets the presentation definition template registry and how long fetched templates are cached.
+func WithTemplateRegistry(registry TemplateRegistry, cacheTTL time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.TemplateRegistry = registry
+		cfg.TemplateCacheTTL = cacheTTL
+	}
+}
+
+// WithRedirectURL sets the redirect URL used in request objects.
+func WithRedirectURL(url string) Option {
+	return func(cfg *Config) {
+		cfg.RedirectURL = url
+	}
+}
+
+// WithTokenLifetime sets the lifetime of published request objects.
+func WithTokenLifetime(lifetime time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.TokenLifetime = lifetime
+	}
+}
+
+// WithMaxVPTokens limits the number of vp tokens accepted in a single authorization response.
+func WithMaxVPTokens(max int) Option {
+	return func(cfg *Config) {
+		cfg.MaxVPTokens = max
+	}
+}
+
+// WithQRCode enables QR code generation for the authorization request with the given size in pixels.
+// Size 0 means the default size.
+func WithQRCode(size int) Option {
+	return func(cfg *Config) {
+		cfg.GenerateQRCode = true
+		cfg.QRCodeSize = size
+	}
+}
+
+// WithMetrics sets the metrics provider.
+func WithMetrics(metrics metricsProvider) Option {
+	return func(cfg *Config) {
+		cfg.Metrics = metrics
+	}
+}
+
+// WithAuditLogger sets the logger that records the outcome of presentation verifications.
+func WithAuditLogger(auditLogger AuditLogger) Option {
+	return func(cfg *Config) {
+		cfg.AuditLogger = auditLogger
+	}
+}
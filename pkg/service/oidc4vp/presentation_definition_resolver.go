@@ -0,0 +1,110 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/trustbloc/vc-go/presexch"
+)
+
+// maxPresentationDefinitionSize is the maximum size in bytes of the presentation definition fetched by
+// HTTPPresentationDefinitionResolver.
+const maxPresentationDefinitionSize = 1 << 20
+
+// PresentationDefinitionResolver resolves a presentation definition passed by reference, i.e. by its URI.
+type PresentationDefinitionResolver interface {
+	Resolve(ctx context.Context, uri string) (*presexch.PresentationDefinition, error)
+}
+
+// HTTPPresentationDefinitionResolver is a PresentationDefinitionResolver that fetches the presentation
+// definition with an HTTP GET request to its URI.
+type HTTPPresentationDefinitionResolver struct {
+	httpClient *http.Client
+}
+
+// NewHTTPPresentationDefinitionResolver returns a new HTTPPresentationDefinitionResolver.
+func NewHTTPPresentationDefinitionResolver(httpClient *http.Client) *HTTPPresentationDefinitionResolver {
+	return &HTTPPresentationDefinitionResolver{
+		httpClient: httpClient,
+	}
+}
+
+// Resolve fetches the presentation definition from uri.
+func (r *HTTPPresentationDefinitionResolver) Resolve(
+	ctx context.Context,
+	uri string,
+) (*presexch.PresentationDefinition, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPresentationDefinitionSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if len(body) > maxPresentationDefinitionSize {
+		return nil, fmt.Errorf("response exceeds %d bytes", maxPresentationDefinitionSize)
+	}
+
+	var pd presexch.PresentationDefinition
+
+	if err = json.Unmarshal(body, &pd); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &pd, nil
+}
+
+// isPresentationDefinitionURI reports whether the presentation definition ID is an HTTP(S) URI the definition
+// can be resolved from.
+func isPresentationDefinitionURI(id string) bool {
+	u, err := url.Parse(id)
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "https" || u.Scheme == "http") && u.Host != ""
+}
+
+// resolvePresentationDefinition returns the presentation definition resolved from its ID if the ID is a URI and
+// resolution is enabled. Otherwise, pd is returned as is.
+func (s *Service) resolvePresentationDefinition(
+	ctx context.Context,
+	pd *presexch.PresentationDefinition,
+) (*presexch.PresentationDefinition, error) {
+	if s.pdResolver == nil || pd == nil || !isPresentationDefinitionURI(pd.ID) {
+		return pd, nil
+	}
+
+	resolved, err := s.pdResolver.Resolve(ctx, pd.ID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve presentation definition %s: %w", pd.ID, err)
+	}
+
+	return resolved, nil
+}
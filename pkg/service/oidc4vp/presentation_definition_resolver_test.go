@@ -0,0 +1,62 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/vcs/pkg/service/oidc4vp"
+)
+
+func TestHTTPPresentationDefinitionResolver_Resolve(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+
+		switch r.URL.Path {
+		case "/pd/driver-license":
+			_, _ = w.Write([]byte(`{"id":"driver-license","input_descriptors":[{"id":"dl"}]}`))
+		case "/pd/invalid":
+			_, _ = w.Write([]byte(`invalid`))
+		case "/pd/large":
+			_, _ = w.Write([]byte(`{"id":"` + strings.Repeat("a", 1<<20) + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	resolver := oidc4vp.NewHTTPPresentationDefinitionResolver(srv.Client())
+
+	t.Run("Success", func(t *testing.T) {
+		pd, err := resolver.Resolve(context.Background(), srv.URL+"/pd/driver-license")
+		require.NoError(t, err)
+		require.Equal(t, "driver-license", pd.ID)
+		require.Len(t, pd.InputDescriptors, 1)
+		require.Equal(t, "dl", pd.InputDescriptors[0].ID)
+	})
+
+	t.Run("Invalid response", func(t *testing.T) {
+		_, err := resolver.Resolve(context.Background(), srv.URL+"/pd/invalid")
+		require.ErrorContains(t, err, "decode response")
+	})
+
+	t.Run("Response too large", func(t *testing.T) {
+		_, err := resolver.Resolve(context.Background(), srv.URL+"/pd/large")
+		require.ErrorContains(t, err, "response exceeds 1048576 bytes")
+	})
+
+	t.Run("Unexpected status code", func(t *testing.T) {
+		_, err := resolver.Resolve(context.Background(), srv.URL+"/pd/unknown")
+		require.ErrorContains(t, err, "unexpected status code: 404")
+	})
+}
@@ -0,0 +1,38 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+const (
+	defaultQRCodeSize   = 256
+	qrCodeDataURIPrefix = "data:image/png;base64,"
+)
+
+// encodeQRCode encodes content as a size x size QR code and returns it as a PNG data URI.
+func encodeQRCode(content string, size int) (string, error) {
+	bm, err := qrcode.NewQRCodeWriter().Encode(content, gozxing.BarcodeFormat_QR_CODE, size, size, nil)
+	if err != nil {
+		return "", fmt.Errorf("encode qr code: %w", err)
+	}
+
+	var buf bytes.Buffer
+
+	if err = png.Encode(&buf, bm); err != nil {
+		return "", fmt.Errorf("encode qr code png: %w", err)
+	}
+
+	return qrCodeDataURIPrefix + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
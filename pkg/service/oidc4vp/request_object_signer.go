@@ -0,0 +1,100 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/samber/lo"
+	"github.com/trustbloc/kms-go/spi/kms"
+	"github.com/trustbloc/vc-go/jwt"
+	"github.com/trustbloc/vc-go/verifiable"
+
+	vcsverifiable "github.com/trustbloc/vcs/pkg/doc/verifiable"
+	profileapi "github.com/trustbloc/vcs/pkg/profile"
+)
+
+// RequestObjectSigner signs the claims of the request object sent to the wallet and returns the compact JWS.
+// Implementations may keep the signing key outside of VCS KMS, e.g. in an external HSM.
+type RequestObjectSigner interface {
+	Sign(ctx context.Context, claims map[string]interface{}, profile *profileapi.Verifier) (string, error)
+}
+
+// KMSRequestObjectSigner signs request objects with the signing key of the verifier profile from the KMS
+// configured for the profile.
+type KMSRequestObjectSigner struct {
+	kmsRegistry kmsRegistry
+	algorithm   *verifiable.JWSAlgorithm
+}
+
+// NewKMSRequestObjectSigner creates a new KMSRequestObjectSigner. If algorithm is not nil, request objects are
+// signed with it instead of the algorithm derived from the profile key type.
+func NewKMSRequestObjectSigner(kmsRegistry kmsRegistry, algorithm *verifiable.JWSAlgorithm) *KMSRequestObjectSigner {
+	return &KMSRequestObjectSigner{
+		kmsRegistry: kmsRegistry,
+		algorithm:   algorithm,
+	}
+}
+
+// Sign signs claims with the key of profile.SigningDID using the signature type for profile.OIDCConfig.KeyType.
+func (s *KMSRequestObjectSigner) Sign(
+	_ context.Context,
+	claims map[string]interface{},
+	profile *profileapi.Verifier,
+) (string, error) {
+	keyManager, err := s.kmsRegistry.GetKeyManager(profile.KMSConfig)
+	if err != nil {
+		return "", fmt.Errorf("get key manager failed: %w", err)
+	}
+
+	signatureType, err := jwtSignatureType(profile.OIDCConfig.KeyType, s.algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	vcsSigner, err := keyManager.NewVCSigner(profile.SigningDID.KMSKeyID, signatureType)
+	if err != nil {
+		return "", fmt.Errorf("get create signer failed: %w", err)
+	}
+
+	token, err := jwt.NewSigned(claims, nil, NewJWSSigner(profile.SigningDID.Creator, vcsSigner))
+	if err != nil {
+		return "", fmt.Errorf("sign token failed: %w", err)
+	}
+
+	tokenBytes, err := token.Serialize(false)
+	if err != nil {
+		return "", fmt.Errorf("serialize token failed: %w", err)
+	}
+
+	return tokenBytes, nil
+}
+
+// jwtSignatureType returns the JWT signature type for keyType. If algorithm is not nil, it must be supported by
+// keyType, otherwise ErrAlgorithmKeyMismatch is returned.
+func jwtSignatureType(keyType kms.KeyType, algorithm *verifiable.JWSAlgorithm) (vcsverifiable.SignatureType, error) {
+	signatureTypes := vcsverifiable.GetSignatureTypesByKeyTypeFormat(keyType, vcsverifiable.Jwt)
+	if len(signatureTypes) < 1 {
+		return "", fmt.Errorf("unsupported jwt key type %s", keyType)
+	}
+
+	if algorithm == nil {
+		return signatureTypes[0], nil
+	}
+
+	name, err := algorithm.Name()
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrAlgorithmKeyMismatch, err)
+	}
+
+	if !lo.Contains(signatureTypes, vcsverifiable.SignatureType(name)) {
+		return "", fmt.Errorf("%w: algorithm %s, key type %s", ErrAlgorithmKeyMismatch, name, keyType)
+	}
+
+	return vcsverifiable.SignatureType(name), nil
+}
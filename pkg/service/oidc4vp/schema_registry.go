@@ -0,0 +1,92 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/trustbloc/vc-go/verifiable"
+
+	"github.com/trustbloc/vcs/pkg/internal/common/jsonschema"
+)
+
+// SchemaValidator validates received credentials against a JSON schema.
+type SchemaValidator interface {
+	Validate(ctx context.Context, schema string, credential *verifiable.Credential) error
+}
+
+// SchemaRegistry is a SchemaValidator that validates credentials against JSON schemas registered by their URL.
+// The "$id" of a registered schema must match its URL.
+type SchemaRegistry struct {
+	schemas   map[string][]byte
+	validator *jsonschema.CachingValidator
+	mutex     sync.RWMutex
+}
+
+// NewSchemaRegistry returns a new empty SchemaRegistry.
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		schemas:   make(map[string][]byte),
+		validator: jsonschema.NewCachingValidator(),
+	}
+}
+
+// Register registers JSON schema under the given URL.
+func (r *SchemaRegistry) Register(schemaURL string, schema []byte) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.schemas[schemaURL] = schema
+}
+
+// Validate validates credential against JSON schema registered under the given URL.
+func (r *SchemaRegistry) Validate(_ context.Context, schemaURL string, credential *verifiable.Credential) error {
+	r.mutex.RLock()
+	schema, ok := r.schemas[schemaURL]
+	r.mutex.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("schema %s is not registered", schemaURL)
+	}
+
+	data, err := credentialToMap(credential)
+	if err != nil {
+		return err
+	}
+
+	return r.validator.Validate(data, schemaURL, schema)
+}
+
+func credentialToMap(credential *verifiable.Credential) (map[string]interface{}, error) {
+	// For SD-JWT credentials this returns a credential with all disclosed subject claims.
+	displayCredential, err := credential.CreateDisplayCredential(verifiable.DisplayAllDisclosures())
+	if err != nil {
+		return nil, fmt.Errorf("create display credential: %w", err)
+	}
+
+	// JWT credentials are marshalled as a JWT string, so validate the decoded credential instead.
+	decoded := *displayCredential
+	decoded.JWT = ""
+	decoded.SDJWTHashAlg = ""
+	decoded.SDJWTDisclosures = nil
+
+	credentialBytes, err := decoded.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("marshal credential: %w", err)
+	}
+
+	var data map[string]interface{}
+
+	if err = json.Unmarshal(credentialBytes, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal credential: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/vc-go/verifiable"
+
+	"github.com/trustbloc/vcs/pkg/internal/testutil"
+	"github.com/trustbloc/vcs/pkg/service/oidc4vp"
+)
+
+const (
+	degreeSchemaURL = "https://example.com/schema/degree.json"
+	degreeSchema    = `{
+  "$id": "https://example.com/schema/degree.json",
+  "type": "object",
+  "required": ["credentialSubject"],
+  "properties": {
+    "credentialSubject": {
+      "type": "object",
+      "required": ["degree", "name"]
+    }
+  }
+}`
+
+	gpaSchemaURL = "https://example.com/schema/gpa.json"
+	gpaSchema    = `{
+  "$id": "https://example.com/schema/gpa.json",
+  "type": "object",
+  "properties": {
+    "credentialSubject": {
+      "type": "object",
+      "required": ["gpa"]
+    }
+  }
+}`
+)
+
+func TestSchemaRegistry_Validate(t *testing.T) {
+	loader := testutil.DocumentLoader(t)
+
+	registry := oidc4vp.NewSchemaRegistry()
+	registry.Register(degreeSchemaURL, []byte(degreeSchema))
+	registry.Register(gpaSchemaURL, []byte(gpaSchema))
+
+	ldvc, err := verifiable.ParseCredential([]byte(sampleVCJsonLD),
+		verifiable.WithJSONLDDocumentLoader(loader),
+		verifiable.WithDisabledProofCheck())
+	require.NoError(t, err)
+
+	jwtvc, err := verifiable.ParseCredential([]byte(sampleVCJWT),
+		verifiable.WithJSONLDDocumentLoader(loader),
+		verifiable.WithDisabledProofCheck())
+	require.NoError(t, err)
+
+	t.Run("Success JsonLD", func(t *testing.T) {
+		require.NoError(t, registry.Validate(context.Background(), degreeSchemaURL, ldvc))
+	})
+
+	t.Run("Success JWT", func(t *testing.T) {
+		require.NoError(t, registry.Validate(context.Background(), degreeSchemaURL, jwtvc))
+	})
+
+	t.Run("Validation failed", func(t *testing.T) {
+		err := registry.Validate(context.Background(), gpaSchemaURL, ldvc)
+		require.ErrorContains(t, err, "validation error")
+		require.ErrorContains(t, err, "gpa")
+	})
+
+	t.Run("Schema not registered", func(t *testing.T) {
+		err := registry.Validate(context.Background(), "https://example.com/schema/unknown.json", ldvc)
+		require.ErrorContains(t, err, "schema https://example.com/schema/unknown.json is not registered")
+	})
+
+	t.Run("Schema ID mismatch", func(t *testing.T) {
+		r := oidc4vp.NewSchemaRegistry()
+		r.Register("https://example.com/schema/other.json", []byte(degreeSchema))
+
+		err := r.Validate(context.Background(), "https://example.com/schema/other.json", ldvc)
+		require.ErrorContains(t, err, "does not match schema ID")
+	})
+}
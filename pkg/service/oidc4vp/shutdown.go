@@ -0,0 +1,50 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp
+
+import (
+	"context"
+	"fmt"
+)
+
+// Shutdown stops the service from accepting new interactions and verifications and waits for the in-flight ones
+// to complete. If ctx is done before that, Shutdown returns the context error; in-flight calls keep running.
+// Calls made after Shutdown fail with ErrServiceShuttingDown.
+func (s *Service) Shutdown(ctx context.Context) error {
+	s.shutdownMu.Lock()
+	s.shuttingDown = true
+	s.shutdownMu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("wait for in-flight calls: %w", ctx.Err())
+	}
+}
+
+// beginCall registers an in-flight call unless the service is shutting down.
+// The caller must call s.inFlight.Done() when the call completes.
+func (s *Service) beginCall() error {
+	s.shutdownMu.RLock()
+	defer s.shutdownMu.RUnlock()
+
+	if s.shuttingDown {
+		return ErrServiceShuttingDown
+	}
+
+	s.inFlight.Add(1)
+
+	return nil
+}
@@ -0,0 +1,159 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/kms-go/crypto/tinkcrypto"
+	"github.com/trustbloc/vc-go/presexch"
+	"github.com/trustbloc/vc-go/verifiable"
+
+	vcsverifiable "github.com/trustbloc/vcs/pkg/doc/verifiable"
+	"github.com/trustbloc/vcs/pkg/event/spi"
+	profileapi "github.com/trustbloc/vcs/pkg/profile"
+	"github.com/trustbloc/vcs/pkg/service/oidc4vp"
+	"github.com/trustbloc/vcs/pkg/service/verifypresentation"
+)
+
+func TestService_Shutdown(t *testing.T) {
+	keyManager := createKMS(t)
+
+	crypto, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	vp, pd, issuer, vdr, loader := newVPWithPD(t, keyManager, crypto)
+
+	profile := &profileapi.Verifier{
+		ID:      profileID,
+		Version: profileVersion,
+		Active:  true,
+		Checks: &profileapi.VerificationChecks{
+			Presentation: &profileapi.PresentationChecks{
+				Format: []vcsverifiable.Format{vcsverifiable.Jwt},
+			},
+		},
+	}
+
+	token := &oidc4vp.ProcessedVPToken{
+		Nonce:         "nonce1",
+		Presentation:  vp,
+		SignerDIDID:   issuer,
+		VpTokenFormat: vcsverifiable.Jwt,
+	}
+
+	// newService returns a service whose presentation verification blocks until release is closed.
+	newService := func(t *testing.T) (svc *oidc4vp.Service, verifying <-chan struct{}, release chan struct{}) {
+		t.Helper()
+
+		txManager := NewMockTransactionManager(gomock.NewController(t))
+		txManager.EXPECT().GetByOneTimeToken(gomock.Any(), "nonce1").AnyTimes().Return(&oidc4vp.Transaction{
+			ID:                     "txID1",
+			ProfileID:              profileID,
+			ProfileVersion:         profileVersion,
+			PresentationDefinition: pd,
+		}, true, nil)
+		txManager.EXPECT().StoreReceivedClaims(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).AnyTimes().Return(nil)
+		txManager.EXPECT().UpdateTx(gomock.Any(), oidc4vp.TxID("txID1"), gomock.Any()).AnyTimes().Return(nil)
+
+		profileService := NewMockProfileService(gomock.NewController(t))
+		profileService.EXPECT().GetProfile(profileID, profileVersion).AnyTimes().Return(profile, nil)
+
+		verifyingCh := make(chan struct{}, 1)
+		release = make(chan struct{})
+
+		presentationVerifier := NewMockPresentationVerifier(gomock.NewController(t))
+		presentationVerifier.EXPECT().VerifyPresentation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			AnyTimes().DoAndReturn(func(context.Context, *verifiable.Presentation, *verifypresentation.Options,
+			*profileapi.Verifier) ([]verifypresentation.PresentationVerificationCheckResult, error) {
+			verifyingCh <- struct{}{}
+			<-release
+
+			return nil, nil
+		})
+
+		svc = oidc4vp.NewService(&oidc4vp.Config{
+			EventSvc:             &mockEvent{},
+			EventTopics:          []string{spi.VerifierEventTopic},
+			TransactionManager:   txManager,
+			PresentationVerifier: presentationVerifier,
+			ProfileService:       profileService,
+			DocumentLoader:       loader,
+			VDR:                  vdr,
+		})
+
+		return svc, verifyingCh, release
+	}
+
+	t.Run("Reject calls after shutdown", func(t *testing.T) {
+		svc, _, _ := newService(t)
+
+		require.NoError(t, svc.Shutdown(context.Background()))
+
+		_, err := svc.InitiateOidcInteraction(context.Background(), &presexch.PresentationDefinition{}, "", profile)
+		require.ErrorIs(t, err, oidc4vp.ErrServiceShuttingDown)
+
+		err = svc.VerifyOIDCVerifiablePresentation(context.Background(), "txID1", []*oidc4vp.ProcessedVPToken{token})
+		require.ErrorIs(t, err, oidc4vp.ErrServiceShuttingDown)
+	})
+
+	t.Run("Wait for in-flight verification", func(t *testing.T) {
+		svc, verifying, release := newService(t)
+
+		verified := make(chan error, 1)
+
+		go func() {
+			verified <- svc.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+				[]*oidc4vp.ProcessedVPToken{token})
+		}()
+
+		<-verifying
+
+		shutdown := make(chan error, 1)
+
+		go func() {
+			shutdown <- svc.Shutdown(context.Background())
+		}()
+
+		select {
+		case <-shutdown:
+			require.Fail(t, "shutdown returned before in-flight verification completed")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+
+		require.NoError(t, <-verified)
+		require.NoError(t, <-shutdown)
+	})
+
+	t.Run("Context done before in-flight verification completed", func(t *testing.T) {
+		svc, verifying, release := newService(t)
+
+		verified := make(chan error, 1)
+
+		go func() {
+			verified <- svc.VerifyOIDCVerifiablePresentation(context.Background(), "txID1",
+				[]*oidc4vp.ProcessedVPToken{token})
+		}()
+
+		<-verifying
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		require.ErrorIs(t, svc.Shutdown(ctx), context.DeadlineExceeded)
+
+		close(release)
+
+		require.NoError(t, <-verified)
+	})
+}
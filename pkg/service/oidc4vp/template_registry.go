@@ -0,0 +1,88 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/vc-go/presexch"
+
+	profileapi "github.com/trustbloc/vcs/pkg/profile"
+)
+
+// TemplateRegistry provides presentation definitions that are reused across interactions.
+type TemplateRegistry interface {
+	GetTemplate(ctx context.Context, templateID string) (*presexch.PresentationDefinition, error)
+}
+
+type cachedTemplate struct {
+	pd        *presexch.PresentationDefinition
+	expiresAt time.Time
+}
+
+// templateCache caches presentation definitions fetched from the TemplateRegistry. Zero TTL disables caching.
+type templateCache struct {
+	registry TemplateRegistry
+	ttl      time.Duration
+	items    map[string]cachedTemplate
+	mutex    sync.Mutex
+}
+
+func newTemplateCache(registry TemplateRegistry, ttl time.Duration) *templateCache {
+	return &templateCache{
+		registry: registry,
+		ttl:      ttl,
+		items:    make(map[string]cachedTemplate),
+	}
+}
+
+func (c *templateCache) get(ctx context.Context, templateID string) (*presexch.PresentationDefinition, error) {
+	if c.registry == nil {
+		return nil, ErrTemplateRegistryNotConfigured
+	}
+
+	now := time.Now()
+
+	c.mutex.Lock()
+	item, ok := c.items[templateID]
+	c.mutex.Unlock()
+
+	if ok && now.Before(item.expiresAt) {
+		return item.pd, nil
+	}
+
+	pd, err := c.registry.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("get presentation definition template %s: %w", templateID, err)
+	}
+
+	if c.ttl > 0 {
+		c.mutex.Lock()
+		c.items[templateID] = cachedTemplate{pd: pd, expiresAt: now.Add(c.ttl)}
+		c.mutex.Unlock()
+	}
+
+	return pd, nil
+}
+
+// InitiateOidcInteractionByTemplate initiates OIDC interaction using the presentation definition template
+// with the given ID.
+func (s *Service) InitiateOidcInteractionByTemplate(
+	ctx context.Context,
+	templateID, purpose string,
+	profile *profileapi.Verifier,
+) (*InteractionInfo, error) {
+	pd, err := s.templates.get(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.InitiateOidcInteraction(ctx, pd, purpose, profile)
+}
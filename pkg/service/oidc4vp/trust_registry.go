@@ -0,0 +1,116 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/trustbloc/vc-go/verifiable"
+)
+
+// TrustRegistryClient checks whether an issuer is trusted to issue credentials of the given type.
+type TrustRegistryClient interface {
+	IsIssuerTrusted(ctx context.Context, issuerDID, credentialType string) (bool, error)
+}
+
+type trustRegistryRequest struct {
+	IssuerDID      string `json:"issuer_did"`
+	CredentialType string `json:"credential_type"`
+}
+
+type trustRegistryResponse struct {
+	Trusted bool `json:"trusted"`
+}
+
+// HTTPTrustRegistryClient is a TrustRegistryClient that queries a trust registry over HTTP. The issuer DID and
+// credential type are POSTed as JSON to the registry URL, which responds with {"trusted": true|false}.
+type HTTPTrustRegistryClient struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewHTTPTrustRegistryClient returns a new HTTPTrustRegistryClient for the registry at url.
+func NewHTTPTrustRegistryClient(url string, httpClient *http.Client) *HTTPTrustRegistryClient {
+	return &HTTPTrustRegistryClient{
+		url:        url,
+		httpClient: httpClient,
+	}
+}
+
+// IsIssuerTrusted asks the trust registry whether the issuer is trusted to issue credentials of the given type.
+func (c *HTTPTrustRegistryClient) IsIssuerTrusted(ctx context.Context, issuerDID, credentialType string) (bool, error) {
+	body, err := json.Marshal(&trustRegistryRequest{
+		IssuerDID:      issuerDID,
+		CredentialType: credentialType,
+	})
+	if err != nil {
+		return false, fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("new request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("send request: %w", err)
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result trustRegistryResponse
+
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("decode response: %w", err)
+	}
+
+	return result.Trusted, nil
+}
+
+func (s *Service) checkTrustRegistry(ctx context.Context, credentials map[string]*verifiable.Credential) error {
+	if s.trustRegistry == nil {
+		return nil
+	}
+
+	for _, cred := range credentials {
+		credentialType := credentialTypeForTrustRegistry(cred)
+
+		trusted, err := s.trustRegistry.IsIssuerTrusted(ctx, cred.Issuer.ID, credentialType)
+		if err != nil {
+			return fmt.Errorf("check issuer %s in trust registry: %w", cred.Issuer.ID, err)
+		}
+
+		if !trusted {
+			return fmt.Errorf("%w: %s is not trusted to issue %s", ErrUntrustedIssuer, cred.Issuer.ID, credentialType)
+		}
+	}
+
+	return nil
+}
+
+// credentialTypeForTrustRegistry returns the most specific type of the credential, i.e. the last one other than
+// the base VerifiableCredential type.
+func credentialTypeForTrustRegistry(cred *verifiable.Credential) string {
+	for i := len(cred.Types) - 1; i >= 0; i-- {
+		if cred.Types[i] != verifiable.VCType {
+			return cred.Types[i]
+		}
+	}
+
+	return verifiable.VCType
+}
@@ -0,0 +1,67 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/vcs/pkg/service/oidc4vp"
+)
+
+func TestHTTPTrustRegistryClient_IsIssuerTrusted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			IssuerDID      string `json:"issuer_did"`
+			CredentialType string `json:"credential_type"`
+		}
+
+		require.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		switch req.IssuerDID {
+		case "did:example:trusted":
+			_, _ = w.Write([]byte(`{"trusted":true}`))
+		case "did:example:untrusted":
+			_, _ = w.Write([]byte(`{"trusted":false}`))
+		case "did:example:invalid":
+			_, _ = w.Write([]byte(`invalid`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	client := oidc4vp.NewHTTPTrustRegistryClient(srv.URL, srv.Client())
+
+	t.Run("Trusted", func(t *testing.T) {
+		trusted, err := client.IsIssuerTrusted(context.Background(), "did:example:trusted", "DegreeCredential")
+		require.NoError(t, err)
+		require.True(t, trusted)
+	})
+
+	t.Run("Untrusted", func(t *testing.T) {
+		trusted, err := client.IsIssuerTrusted(context.Background(), "did:example:untrusted", "DegreeCredential")
+		require.NoError(t, err)
+		require.False(t, trusted)
+	})
+
+	t.Run("Invalid response", func(t *testing.T) {
+		_, err := client.IsIssuerTrusted(context.Background(), "did:example:invalid", "DegreeCredential")
+		require.ErrorContains(t, err, "decode response")
+	})
+
+	t.Run("Unexpected status code", func(t *testing.T) {
+		_, err := client.IsIssuerTrusted(context.Background(), "did:example:error", "DegreeCredential")
+		require.ErrorContains(t, err, "unexpected status code: 500")
+	})
+}
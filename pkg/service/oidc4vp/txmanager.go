@@ -12,8 +12,10 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/piprate/json-gold/ld"
 	"github.com/trustbloc/vc-go/presexch"
@@ -29,6 +31,16 @@ const (
 
 type TxID string
 
+// TransactionStatus is the status of the transaction as seen by the relying party polling for completion.
+type TransactionStatus int16
+
+const (
+	TransactionStatusPending   = TransactionStatus(0)
+	TransactionStatusCompleted = TransactionStatus(1)
+	TransactionStatusFailed    = TransactionStatus(2)
+	TransactionStatusExpired   = TransactionStatus(3)
+)
+
 type Transaction struct {
 	ID                     TxID
 	ProfileID              string
@@ -36,41 +48,65 @@ type Transaction struct {
 	PresentationDefinition *presexch.PresentationDefinition
 	ReceivedClaims         *ReceivedClaims
 	ReceivedClaimsID       string
+	Purpose                string
+	Status                 TransactionStatus
+	// CustomData is application-specific metadata attached to the transaction on creation.
+	CustomData map[string]interface{}
+	// CreatedAt and ExpiresAt bound the validity window of the transaction. Zero values mean unknown.
+	CreatedAt time.Time
+	ExpiresAt time.Time
 }
 
 type ReceivedClaims struct {
 	Credentials map[string]*verifiable.Credential `json:"credentials"`
+	ReceivedAt  time.Time                         `json:"receivedAt"`
+	// RawVPTokens are original signed JWT VP tokens the credentials were received in.
+	RawVPTokens []string `json:"rawVPTokens,omitempty"`
 }
 
 // ReceivedClaimsRaw is temporary struct for parsing to ReceivedClaims, as we need to unmarshal credentials separately.
 type ReceivedClaimsRaw struct {
 	Credentials map[string][]byte `json:"credentials"`
+	ReceivedAt  time.Time         `json:"receivedAt"`
+	RawVPTokens []string          `json:"rawVPTokens,omitempty"`
 }
 
 type ClaimData struct {
 	EncryptedData *dataprotect.EncryptedData `json:"encrypted_data"`
+	TxID          TxID                       `json:"tx_id,omitempty"`
 }
 
+// TransactionUpdate holds transaction fields to update. Empty fields are left unchanged.
 type TransactionUpdate struct {
-	ID               TxID
-	ReceivedClaimsID string
+	ID                     TxID
+	ReceivedClaimsID       string
+	ExpiresAt              *time.Time
+	PresentationDefinition *presexch.PresentationDefinition
+	Purpose                *string
+	Status                 *TransactionStatus
 }
 
 type txStore interface {
-	Create(pd *presexch.PresentationDefinition, profileID, profileVersion string) (TxID, *Transaction, error)
-	Update(update TransactionUpdate) error
-	Get(txID TxID) (*Transaction, error)
+	Create(
+		ctx context.Context,
+		pd *presexch.PresentationDefinition,
+		profileID, profileVersion, purpose string,
+		ttl time.Duration,
+		customData map[string]interface{},
+	) (TxID, *Transaction, error)
+	Update(ctx context.Context, update TransactionUpdate) error
+	Get(ctx context.Context, txID TxID) (*Transaction, error)
 }
 
 type txClaimsStore interface {
-	Create(claims *ClaimData) (string, error)
-	Get(claimsID string) (*ClaimData, error)
-	Delete(claimsID string) error
+	Create(ctx context.Context, claims *ClaimData) (string, error)
+	Get(ctx context.Context, claimsID string) (*ClaimData, error)
+	Delete(ctx context.Context, claimsID string) error
 }
 
 type txNonceStore interface {
-	SetIfNotExist(nonce string, txID TxID) (bool, error)
-	GetAndDelete(nonce string) (TxID, bool, error)
+	SetIfNotExist(ctx context.Context, nonce string, txID TxID, ttl time.Duration) (bool, error)
+	GetAndDelete(ctx context.Context, nonce string) (TxID, bool, error)
 }
 
 type dataProtector interface {
@@ -104,15 +140,28 @@ func NewTxManager(
 	}
 }
 
-// CreateTx creates transaction and generate one time access token.
+// CreateTx creates transaction and generate one time access token, both valid for the given ttl.
+// If ttl is zero, the default ttl of the stores is used. Optional customData is stored with the transaction
+// and must be JSON-serializable.
 func (tm *TxManager) CreateTx(
-	pd *presexch.PresentationDefinition, profileID, profileVersion string) (*Transaction, string, error) {
-	txID, tx, err := tm.txStore.Create(pd, profileID, profileVersion)
+	ctx context.Context,
+	pd *presexch.PresentationDefinition,
+	profileID, profileVersion, purpose string,
+	ttl time.Duration,
+	customData map[string]interface{},
+) (*Transaction, string, error) {
+	if customData != nil {
+		if _, err := json.Marshal(customData); err != nil {
+			return nil, "", fmt.Errorf("oidc tx custom data is not json-serializable: %w", err)
+		}
+	}
+
+	txID, tx, err := tm.txStore.Create(ctx, pd, profileID, profileVersion, purpose, ttl, customData)
 	if err != nil {
 		return nil, "", fmt.Errorf("oidc tx create failed: %w", err)
 	}
 
-	nonce, err := tm.tryCreateTxNonce(txID)
+	nonce, err := tm.tryCreateTxNonce(ctx, txID, ttl)
 	if err != nil {
 		return nil, "", fmt.Errorf("oidc tx nonce create failed: %w", err)
 	}
@@ -120,27 +169,62 @@ func (tm *TxManager) CreateTx(
 	return tx, nonce, nil
 }
 
-func (tm *TxManager) DeleteReceivedClaims(claimsID string) error {
-	return tm.txClaimsStore.Delete(claimsID)
+// CreateTxNonce generates a new one time access token for the existing transaction valid for the given ttl.
+// Tokens issued earlier for the transaction stay valid until they are used or expire.
+func (tm *TxManager) CreateTxNonce(ctx context.Context, txID TxID, ttl time.Duration) (string, error) {
+	nonce, err := tm.tryCreateTxNonce(ctx, txID, ttl)
+	if err != nil {
+		return "", fmt.Errorf("oidc tx nonce create failed: %w", err)
+	}
+
+	return nonce, nil
 }
 
-func (tm *TxManager) StoreReceivedClaims(txID TxID, claims *ReceivedClaims) error {
-	encrypted, err := tm.EncryptClaims(context.TODO(), claims)
+func (tm *TxManager) DeleteReceivedClaims(ctx context.Context, claimsID string) error {
+	return tm.txClaimsStore.Delete(ctx, claimsID)
+}
+
+func (tm *TxManager) StoreReceivedClaims(ctx context.Context, txID TxID, claims *ReceivedClaims) error {
+	encrypted, err := tm.EncryptClaims(ctx, claims)
 	if err != nil {
 		return err
 	}
 
-	receivedClaimsID, err := tm.txClaimsStore.Create(encrypted)
+	if encrypted != nil {
+		encrypted.TxID = txID
+	}
+
+	receivedClaimsID, err := tm.txClaimsStore.Create(ctx, encrypted)
 	if err != nil {
 		return err
 	}
 
-	return tm.txStore.Update(TransactionUpdate{ID: txID, ReceivedClaimsID: receivedClaimsID})
+	status := TransactionStatusCompleted
+
+	return tm.txStore.Update(ctx, TransactionUpdate{ID: txID, ReceivedClaimsID: receivedClaimsID, Status: &status})
+}
+
+// UpdateTx amends transaction with the given ID. Received claims can't be changed with the update.
+func (tm *TxManager) UpdateTx(ctx context.Context, txID TxID, update *TransactionUpdate) error {
+	if update.ExpiresAt != nil && !update.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("oidc tx update failed: expiration time %s is in the past",
+			update.ExpiresAt.Format(time.RFC3339))
+	}
+
+	txUpdate := *update
+	txUpdate.ID = txID
+	txUpdate.ReceivedClaimsID = ""
+
+	if err := tm.txStore.Update(ctx, txUpdate); err != nil {
+		return fmt.Errorf("oidc tx update failed: %w", err)
+	}
+
+	return nil
 }
 
 // Get transaction id.
-func (tm *TxManager) Get(txID TxID) (*Transaction, error) {
-	tx, err := tm.txStore.Get(txID)
+func (tm *TxManager) Get(ctx context.Context, txID TxID) (*Transaction, error) {
+	tx, err := tm.txStore.Get(ctx, txID)
 	if errors.Is(err, ErrDataNotFound) {
 		return nil, err
 	}
@@ -153,11 +237,11 @@ func (tm *TxManager) Get(txID TxID) (*Transaction, error) {
 		return tx, nil
 	}
 
-	receivedClaims, err := tm.txClaimsStore.Get(tx.ReceivedClaimsID)
+	receivedClaims, err := tm.txClaimsStore.Get(ctx, tx.ReceivedClaimsID)
 	if err != nil && !errors.Is(err, ErrDataNotFound) {
 		return nil, fmt.Errorf("find received claims: %w", err)
 	}
-	decrypted, err := tm.DecryptClaims(context.TODO(), receivedClaims)
+	decrypted, err := tm.DecryptClaims(ctx, receivedClaims)
 	if err != nil {
 		return nil, err
 	}
@@ -167,16 +251,43 @@ func (tm *TxManager) Get(txID TxID) (*Transaction, error) {
 	return tx, nil
 }
 
+// GetByClaimsID returns transaction the received claims with the given ID were stored for.
+func (tm *TxManager) GetByClaimsID(ctx context.Context, claimsID string) (*Transaction, error) {
+	claimData, err := tm.txClaimsStore.Get(ctx, claimsID)
+	if err != nil {
+		if errors.Is(err, ErrDataNotFound) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("find received claims: %w", err)
+	}
+
+	if claimData.TxID == "" {
+		return nil, fmt.Errorf("received claims %s are not bound to a transaction", claimsID)
+	}
+
+	tx, err := tm.Get(ctx, claimData.TxID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx.ReceivedClaimsID != claimsID {
+		return nil, fmt.Errorf("transaction %s does not reference received claims %s", tx.ID, claimsID)
+	}
+
+	return tx, nil
+}
+
 // GetByOneTimeToken get transaction by nonce and then delete nonce.
-func (tm *TxManager) GetByOneTimeToken(nonce string) (*Transaction, bool, error) {
-	txID, valid, err := tm.nonceStore.GetAndDelete(nonce)
+func (tm *TxManager) GetByOneTimeToken(ctx context.Context, nonce string) (*Transaction, bool, error) {
+	txID, valid, err := tm.nonceStore.GetAndDelete(ctx, nonce)
 	if err != nil {
 		return nil, false, fmt.Errorf("oidc tx nonceStore get failed: %w", err)
 	}
 
 	var tx *Transaction
 	if valid {
-		tx, err = tm.txStore.Get(txID)
+		tx, err = tm.txStore.Get(ctx, txID)
 		if err != nil {
 			return nil, false, fmt.Errorf("oidc get tx by id failed: %w", err)
 		}
@@ -185,14 +296,14 @@ func (tm *TxManager) GetByOneTimeToken(nonce string) (*Transaction, bool, error)
 	return tx, valid, nil
 }
 
-func (tm *TxManager) tryCreateTxNonce(txID TxID) (string, error) {
+func (tm *TxManager) tryCreateTxNonce(ctx context.Context, txID TxID, ttl time.Duration) (string, error) {
 	for i := 1; i <= maxRetries; i++ {
 		nonce, err := genNonce()
 		if err != nil {
 			return "", err
 		}
 
-		isSet, err := tm.nonceStore.SetIfNotExist(nonce, txID)
+		isSet, err := tm.nonceStore.SetIfNotExist(ctx, nonce, txID, ttl)
 		if err != nil {
 			return "", fmt.Errorf("oidc tx nonceStore set failed: %w", err)
 		}
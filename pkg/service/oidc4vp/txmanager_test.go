@@ -12,6 +12,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -27,13 +28,14 @@ import (
 func TestTxManager_CreateTx(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		store := NewMockTxStore(gomock.NewController(t))
-		store.EXPECT().Create(gomock.Any(), profileID, profileVersion).Return(
-			oidc4vp.TxID("txID"), &oidc4vp.Transaction{ID: "txID", ProfileID: profileID, ProfileVersion: profileVersion}, nil)
+		store.EXPECT().Create(gomock.Any(), gomock.Any(), profileID, profileVersion, "purpose", time.Minute, gomock.Any()).
+			Return(oidc4vp.TxID("txID"),
+				&oidc4vp.Transaction{ID: "txID", ProfileID: profileID, ProfileVersion: profileVersion}, nil)
 
 		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
 
 		nonceStore := NewMockTxNonceStore(gomock.NewController(t))
-		nonceStore.EXPECT().SetIfNotExist(gomock.Any(), oidc4vp.TxID("txID")).
+		nonceStore.EXPECT().SetIfNotExist(gomock.Any(), gomock.Any(), oidc4vp.TxID("txID"), time.Minute).
 			Times(1).Return(true, nil)
 
 		crypto := NewMockDataProtector(gomock.NewController(t))
@@ -41,7 +43,8 @@ func TestTxManager_CreateTx(t *testing.T) {
 		manager := oidc4vp.NewTxManager(nonceStore, store, claimsStore, crypto,
 			testutil.DocumentLoader(t))
 
-		tx, nonce, err := manager.CreateTx(&presexch.PresentationDefinition{}, profileID, profileVersion)
+		tx, nonce, err := manager.CreateTx(context.Background(),
+			&presexch.PresentationDefinition{}, profileID, profileVersion, "purpose", time.Minute, nil)
 
 		require.NoError(t, err)
 		require.NotEmpty(t, nonce)
@@ -52,7 +55,8 @@ func TestTxManager_CreateTx(t *testing.T) {
 
 	t.Run("Fail", func(t *testing.T) {
 		store := NewMockTxStore(gomock.NewController(t))
-		store.EXPECT().Create(gomock.Any(), profileID, profileVersion).Return(oidc4vp.TxID(""), nil, errors.New("test error"))
+		store.EXPECT().Create(gomock.Any(), gomock.Any(), profileID, profileVersion, "", time.Duration(0), gomock.Any()).
+			Return(oidc4vp.TxID(""), nil, errors.New("test error"))
 
 		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
 
@@ -62,46 +66,116 @@ func TestTxManager_CreateTx(t *testing.T) {
 		manager := oidc4vp.NewTxManager(nonceStore, store, claimsStore, crypto,
 			testutil.DocumentLoader(t))
 
-		_, _, err := manager.CreateTx(&presexch.PresentationDefinition{}, profileID, profileVersion)
+		_, _, err := manager.CreateTx(context.Background(),
+			&presexch.PresentationDefinition{}, profileID, profileVersion, "", 0, nil)
 
 		require.Contains(t, err.Error(), "test error")
 	})
 
 	t.Run("Fail", func(t *testing.T) {
 		store := NewMockTxStore(gomock.NewController(t))
-		store.EXPECT().Create(gomock.Any(), profileID, profileVersion).Return(oidc4vp.TxID("txID"), nil, nil)
+		store.EXPECT().Create(gomock.Any(), gomock.Any(), profileID, profileVersion, "", time.Duration(0), gomock.Any()).
+			Return(oidc4vp.TxID("txID"), nil, nil)
 
 		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
 
 		nonceStore := NewMockTxNonceStore(gomock.NewController(t))
-		nonceStore.EXPECT().SetIfNotExist(gomock.Any(), oidc4vp.TxID("txID")).
+		nonceStore.EXPECT().SetIfNotExist(gomock.Any(), gomock.Any(), oidc4vp.TxID("txID"), time.Duration(0)).
 			Times(1).Return(false, errors.New("test error"))
 		crypto := NewMockDataProtector(gomock.NewController(t))
 
 		manager := oidc4vp.NewTxManager(nonceStore, store, claimsStore, crypto,
 			testutil.DocumentLoader(t))
 
-		_, _, err := manager.CreateTx(&presexch.PresentationDefinition{}, profileID, profileVersion)
+		_, _, err := manager.CreateTx(context.Background(),
+			&presexch.PresentationDefinition{}, profileID, profileVersion, "", 0, nil)
 
 		require.Contains(t, err.Error(), "test error")
 	})
+
+	t.Run("Custom data", func(t *testing.T) {
+		customData := map[string]interface{}{"sessionID": "session-1"}
+
+		store := NewMockTxStore(gomock.NewController(t))
+		store.EXPECT().Create(gomock.Any(), gomock.Any(), profileID, profileVersion, "", time.Minute, customData).Return(
+			oidc4vp.TxID("txID"), &oidc4vp.Transaction{ID: "txID", CustomData: customData}, nil)
+
+		nonceStore := NewMockTxNonceStore(gomock.NewController(t))
+		nonceStore.EXPECT().SetIfNotExist(gomock.Any(), gomock.Any(), oidc4vp.TxID("txID"), time.Minute).
+			Times(1).Return(true, nil)
+
+		manager := oidc4vp.NewTxManager(nonceStore, store, NewMockTxClaimsStore(gomock.NewController(t)),
+			NewMockDataProtector(gomock.NewController(t)), testutil.DocumentLoader(t))
+
+		tx, _, err := manager.CreateTx(context.Background(),
+			&presexch.PresentationDefinition{}, profileID, profileVersion, "", time.Minute, customData)
+
+		require.NoError(t, err)
+		require.Equal(t, customData, tx.CustomData)
+	})
+
+	t.Run("Custom data is not json-serializable", func(t *testing.T) {
+		store := NewMockTxStore(gomock.NewController(t))
+		store.EXPECT().Create(
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		manager := oidc4vp.NewTxManager(NewMockTxNonceStore(gomock.NewController(t)), store,
+			NewMockTxClaimsStore(gomock.NewController(t)), NewMockDataProtector(gomock.NewController(t)),
+			testutil.DocumentLoader(t))
+
+		_, _, err := manager.CreateTx(context.Background(), &presexch.PresentationDefinition{},
+			profileID, profileVersion, "", 0, map[string]interface{}{"callback": func() {}})
+
+		require.ErrorContains(t, err, "oidc tx custom data is not json-serializable")
+	})
+}
+
+func TestTxManager_CreateTxNonce(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		nonceStore := NewMockTxNonceStore(gomock.NewController(t))
+		nonceStore.EXPECT().SetIfNotExist(gomock.Any(), gomock.Any(), oidc4vp.TxID("txID"), time.Minute).
+			Times(1).Return(true, nil)
+
+		manager := oidc4vp.NewTxManager(nonceStore, NewMockTxStore(gomock.NewController(t)),
+			NewMockTxClaimsStore(gomock.NewController(t)), NewMockDataProtector(gomock.NewController(t)),
+			testutil.DocumentLoader(t))
+
+		nonce, err := manager.CreateTxNonce(context.Background(), "txID", time.Minute)
+		require.NoError(t, err)
+		require.NotEmpty(t, nonce)
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		nonceStore := NewMockTxNonceStore(gomock.NewController(t))
+		nonceStore.EXPECT().SetIfNotExist(gomock.Any(), gomock.Any(), oidc4vp.TxID("txID"), time.Minute).
+			Times(1).Return(false, errors.New("test error"))
+
+		manager := oidc4vp.NewTxManager(nonceStore, NewMockTxStore(gomock.NewController(t)),
+			NewMockTxClaimsStore(gomock.NewController(t)), NewMockDataProtector(gomock.NewController(t)),
+			testutil.DocumentLoader(t))
+
+		_, err := manager.CreateTxNonce(context.Background(), "txID", time.Minute)
+		require.ErrorContains(t, err, "oidc tx nonce create failed")
+		require.ErrorContains(t, err, "test error")
+	})
 }
 
 func TestTxManager_GetByOneTimeToken(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		store := NewMockTxStore(gomock.NewController(t))
-		store.EXPECT().Get(oidc4vp.TxID("txID")).Return(&oidc4vp.Transaction{ID: "txID", ProfileID: "org_id"}, nil)
+		store.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).
+			Return(&oidc4vp.Transaction{ID: "txID", ProfileID: "org_id"}, nil)
 
 		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
 
 		nonceStore := NewMockTxNonceStore(gomock.NewController(t))
-		nonceStore.EXPECT().GetAndDelete("nonce").Times(1).Return(oidc4vp.TxID("txID"), true, nil)
+		nonceStore.EXPECT().GetAndDelete(gomock.Any(), "nonce").Times(1).Return(oidc4vp.TxID("txID"), true, nil)
 		crypto := NewMockDataProtector(gomock.NewController(t))
 
 		manager := oidc4vp.NewTxManager(nonceStore, store, claimsStore, crypto,
 			testutil.DocumentLoader(t))
 
-		tx, exists, err := manager.GetByOneTimeToken("nonce")
+		tx, exists, err := manager.GetByOneTimeToken(context.Background(), "nonce")
 
 		require.NoError(t, err)
 		require.True(t, exists)
@@ -115,14 +189,14 @@ func TestTxManager_GetByOneTimeToken(t *testing.T) {
 		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
 
 		nonceStore := NewMockTxNonceStore(gomock.NewController(t))
-		nonceStore.EXPECT().GetAndDelete(gomock.Any()).Times(1).Return(oidc4vp.TxID(""), true,
+		nonceStore.EXPECT().GetAndDelete(gomock.Any(), gomock.Any()).Times(1).Return(oidc4vp.TxID(""), true,
 			errors.New("test error 123"))
 		crypto := NewMockDataProtector(gomock.NewController(t))
 
 		manager := oidc4vp.NewTxManager(nonceStore, store, claimsStore, crypto,
 			testutil.DocumentLoader(t))
 
-		_, exists, err := manager.GetByOneTimeToken("nonce")
+		_, exists, err := manager.GetByOneTimeToken(context.Background(), "nonce")
 
 		require.False(t, exists)
 		require.Contains(t, err.Error(), "test error 123")
@@ -130,18 +204,18 @@ func TestTxManager_GetByOneTimeToken(t *testing.T) {
 
 	t.Run("Fail Get", func(t *testing.T) {
 		store := NewMockTxStore(gomock.NewController(t))
-		store.EXPECT().Get(oidc4vp.TxID("txID")).Return(nil, errors.New("test error 333"))
+		store.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).Return(nil, errors.New("test error 333"))
 
 		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
 
 		nonceStore := NewMockTxNonceStore(gomock.NewController(t))
-		nonceStore.EXPECT().GetAndDelete("nonce").Times(1).Return(oidc4vp.TxID("txID"), true, nil)
+		nonceStore.EXPECT().GetAndDelete(gomock.Any(), "nonce").Times(1).Return(oidc4vp.TxID("txID"), true, nil)
 		crypto := NewMockDataProtector(gomock.NewController(t))
 
 		manager := oidc4vp.NewTxManager(nonceStore, store, claimsStore, crypto,
 			testutil.DocumentLoader(t))
 
-		_, exists, err := manager.GetByOneTimeToken("nonce")
+		_, exists, err := manager.GetByOneTimeToken(context.Background(), "nonce")
 
 		require.False(t, exists)
 		require.Contains(t, err.Error(), "test error 333")
@@ -151,7 +225,8 @@ func TestTxManager_GetByOneTimeToken(t *testing.T) {
 func TestTxManager_Get(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		store := NewMockTxStore(gomock.NewController(t))
-		store.EXPECT().Get(oidc4vp.TxID("txID")).Return(&oidc4vp.Transaction{ID: "txID", ProfileID: "org_id"}, nil)
+		store.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).
+			Return(&oidc4vp.Transaction{ID: "txID", ProfileID: "org_id"}, nil)
 
 		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
 
@@ -161,7 +236,7 @@ func TestTxManager_Get(t *testing.T) {
 		manager := oidc4vp.NewTxManager(nonceStore, store, claimsStore, crypto,
 			testutil.DocumentLoader(t))
 
-		tx, err := manager.Get("txID")
+		tx, err := manager.Get(context.Background(), "txID")
 
 		require.NoError(t, err)
 		require.NotNil(t, tx)
@@ -172,7 +247,7 @@ func TestTxManager_Get(t *testing.T) {
 
 	t.Run("Success - with claims ID", func(t *testing.T) {
 		store := NewMockTxStore(gomock.NewController(t))
-		store.EXPECT().Get(oidc4vp.TxID("txID")).Return(
+		store.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).Return(
 			&oidc4vp.Transaction{
 				ID:               "txID",
 				ProfileID:        "org_id",
@@ -181,6 +256,7 @@ func TestTxManager_Get(t *testing.T) {
 
 		encryptedClaims := []byte{0x0, 0x1, 0x2}
 		nonce := []byte{0x3, 0x4}
+		receivedAt := time.Now().UTC().Truncate(time.Second)
 
 		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
 		crypto := NewMockDataProtector(gomock.NewController(t))
@@ -189,7 +265,7 @@ func TestTxManager_Get(t *testing.T) {
 			Encrypted:      encryptedClaims,
 			EncryptedNonce: nonce,
 		}
-		claimsStore.EXPECT().Get(gomock.Any()).Return(&oidc4vp.ClaimData{
+		claimsStore.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&oidc4vp.ClaimData{
 			EncryptedData: chunks,
 		}, nil)
 
@@ -221,6 +297,7 @@ func TestTxManager_Get(t *testing.T) {
 						"sd":  vcSD,
 						"ldp": ld,
 					},
+					ReceivedAt: receivedAt,
 				}
 				raw, err := manager.ClaimsToClaimsRaw(rs)
 				assert.NoError(t, err)
@@ -231,18 +308,19 @@ func TestTxManager_Get(t *testing.T) {
 				return b, nil
 			})
 
-		tx, err := manager.Get("txID")
+		tx, err := manager.Get(context.Background(), "txID")
 
 		require.NoError(t, err)
 		require.NotNil(t, tx)
 		require.Equal(t, "org_id", tx.ProfileID)
 		require.Equal(t, tx.ReceivedClaimsID, "claims_id")
 		require.NotNil(t, tx.ReceivedClaims)
+		require.Equal(t, receivedAt, tx.ReceivedClaims.ReceivedAt)
 	})
 
 	t.Run("Success - claims not found", func(t *testing.T) {
 		store := NewMockTxStore(gomock.NewController(t))
-		store.EXPECT().Get(oidc4vp.TxID("txID")).Return(
+		store.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).Return(
 			&oidc4vp.Transaction{
 				ID:               "txID",
 				ProfileID:        "org_id",
@@ -250,7 +328,7 @@ func TestTxManager_Get(t *testing.T) {
 			nil)
 
 		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
-		claimsStore.EXPECT().Get(gomock.Any()).Return(nil, oidc4vp.ErrDataNotFound)
+		claimsStore.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, oidc4vp.ErrDataNotFound)
 
 		nonceStore := NewMockTxNonceStore(gomock.NewController(t))
 		crypto := NewMockDataProtector(gomock.NewController(t))
@@ -258,7 +336,7 @@ func TestTxManager_Get(t *testing.T) {
 		manager := oidc4vp.NewTxManager(nonceStore, store, claimsStore, crypto,
 			testutil.DocumentLoader(t))
 
-		tx, err := manager.Get("txID")
+		tx, err := manager.Get(context.Background(), "txID")
 
 		require.NoError(t, err)
 		require.NotNil(t, tx)
@@ -269,7 +347,7 @@ func TestTxManager_Get(t *testing.T) {
 
 	t.Run("Error - claims store error", func(t *testing.T) {
 		store := NewMockTxStore(gomock.NewController(t))
-		store.EXPECT().Get(oidc4vp.TxID("txID")).Return(
+		store.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).Return(
 			&oidc4vp.Transaction{
 				ID:               "txID",
 				ProfileID:        "org_id",
@@ -277,7 +355,7 @@ func TestTxManager_Get(t *testing.T) {
 			nil)
 
 		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
-		claimsStore.EXPECT().Get(gomock.Any()).Return(nil, fmt.Errorf("store error"))
+		claimsStore.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("store error"))
 
 		nonceStore := NewMockTxNonceStore(gomock.NewController(t))
 		crypto := NewMockDataProtector(gomock.NewController(t))
@@ -285,7 +363,7 @@ func TestTxManager_Get(t *testing.T) {
 		manager := oidc4vp.NewTxManager(nonceStore, store, claimsStore, crypto,
 			testutil.DocumentLoader(t))
 
-		tx, err := manager.Get("txID")
+		tx, err := manager.Get(context.Background(), "txID")
 
 		require.Error(t, err)
 		require.Nil(t, tx)
@@ -294,7 +372,7 @@ func TestTxManager_Get(t *testing.T) {
 
 	t.Run("Fail Get", func(t *testing.T) {
 		store := NewMockTxStore(gomock.NewController(t))
-		store.EXPECT().Get(oidc4vp.TxID("txID")).Return(nil, errors.New("test error 333"))
+		store.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).Return(nil, errors.New("test error 333"))
 
 		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
 
@@ -304,14 +382,14 @@ func TestTxManager_Get(t *testing.T) {
 		manager := oidc4vp.NewTxManager(nonceStore, store, claimsStore, crypto,
 			testutil.DocumentLoader(t))
 
-		_, err := manager.Get("txID")
+		_, err := manager.Get(context.Background(), "txID")
 
 		require.Contains(t, err.Error(), "test error 333")
 	})
 
 	t.Run("Fail Get 2", func(t *testing.T) {
 		store := NewMockTxStore(gomock.NewController(t))
-		store.EXPECT().Get(oidc4vp.TxID("txID")).Return(nil, oidc4vp.ErrDataNotFound)
+		store.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).Return(nil, oidc4vp.ErrDataNotFound)
 
 		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
 
@@ -321,7 +399,7 @@ func TestTxManager_Get(t *testing.T) {
 		manager := oidc4vp.NewTxManager(nonceStore, store, claimsStore, crypto,
 			testutil.DocumentLoader(t))
 
-		_, err := manager.Get("txID")
+		_, err := manager.Get(context.Background(), "txID")
 
 		require.Contains(t, err.Error(), "data not found")
 	})
@@ -330,7 +408,12 @@ func TestTxManager_Get(t *testing.T) {
 func TestTxManagerStoreReceivedClaims(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		store := NewMockTxStore(gomock.NewController(t))
-		store.EXPECT().Update(gomock.Any()).Return(nil)
+		store.EXPECT().Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, update oidc4vp.TransactionUpdate) error {
+				require.Equal(t, oidc4vp.TransactionStatusCompleted, *update.Status)
+
+				return nil
+			})
 
 		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
 
@@ -348,10 +431,11 @@ func TestTxManagerStoreReceivedClaims(t *testing.T) {
 				return chunks, nil
 			})
 
-		claimsStore.EXPECT().Create(gomock.Any()).
-			DoAndReturn(func(data *oidc4vp.ClaimData) (string, error) {
+		claimsStore.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, data *oidc4vp.ClaimData) (string, error) {
 				assert.Equal(t, oidc4vp.ClaimData{
 					EncryptedData: chunks,
+					TxID:          "txID",
 				}, *data)
 				return "claimsID", nil
 			})
@@ -372,7 +456,7 @@ func TestTxManagerStoreReceivedClaims(t *testing.T) {
 			verifiable.WithDisabledProofCheck())
 		assert.NoError(t, err)
 
-		err = manager.StoreReceivedClaims("txID", &oidc4vp.ReceivedClaims{
+		err = manager.StoreReceivedClaims(context.Background(), "txID", &oidc4vp.ReceivedClaims{
 			Credentials: map[string]*verifiable.Credential{
 				"jwt": vc,
 				"sd":  vcSD,
@@ -396,7 +480,7 @@ func TestTxManagerStoreReceivedClaims(t *testing.T) {
 		manager := oidc4vp.NewTxManager(nonceStore, store, claimsStore, crypto,
 			testutil.DocumentLoader(t))
 
-		err := manager.StoreReceivedClaims("txID", &oidc4vp.ReceivedClaims{
+		err := manager.StoreReceivedClaims(context.Background(), "txID", &oidc4vp.ReceivedClaims{
 			Credentials: map[string]*verifiable.Credential{},
 		})
 
@@ -412,12 +496,12 @@ func TestTxManagerStoreReceivedClaims(t *testing.T) {
 
 		crypto.EXPECT().Encrypt(gomock.Any(), gomock.Any()).
 			Return(nil, nil)
-		claimsStore.EXPECT().Create(gomock.Any()).Return("", errors.New("can not store claims"))
+		claimsStore.EXPECT().Create(gomock.Any(), gomock.Any()).Return("", errors.New("can not store claims"))
 
 		manager := oidc4vp.NewTxManager(nonceStore, store, claimsStore, crypto,
 			testutil.DocumentLoader(t))
 
-		err := manager.StoreReceivedClaims("txID", &oidc4vp.ReceivedClaims{
+		err := manager.StoreReceivedClaims(context.Background(), "txID", &oidc4vp.ReceivedClaims{
 			Credentials: map[string]*verifiable.Credential{},
 		})
 
@@ -425,11 +509,144 @@ func TestTxManagerStoreReceivedClaims(t *testing.T) {
 	})
 }
 
+func TestTxManager_GetByClaimsID(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		store := NewMockTxStore(gomock.NewController(t))
+		store.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).Return(
+			&oidc4vp.Transaction{
+				ID:               "txID",
+				ProfileID:        profileID,
+				ProfileVersion:   profileVersion,
+				ReceivedClaimsID: "claimsID",
+			}, nil)
+
+		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
+		claimsStore.EXPECT().Get(gomock.Any(), "claimsID").Times(2).Return(&oidc4vp.ClaimData{
+			EncryptedData: &dataprotect.EncryptedData{},
+			TxID:          "txID",
+		}, nil)
+
+		crypto := NewMockDataProtector(gomock.NewController(t))
+		crypto.EXPECT().Decrypt(gomock.Any(), gomock.Any()).Return([]byte(`{"credentials":{}}`), nil)
+
+		manager := oidc4vp.NewTxManager(NewMockTxNonceStore(gomock.NewController(t)), store, claimsStore, crypto,
+			testutil.DocumentLoader(t))
+
+		tx, err := manager.GetByClaimsID(context.Background(), "claimsID")
+		require.NoError(t, err)
+		require.Equal(t, oidc4vp.TxID("txID"), tx.ID)
+		require.Equal(t, profileID, tx.ProfileID)
+		require.NotNil(t, tx.ReceivedClaims)
+	})
+
+	t.Run("Claims not found", func(t *testing.T) {
+		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
+		claimsStore.EXPECT().Get(gomock.Any(), "claimsID").Return(nil, oidc4vp.ErrDataNotFound)
+
+		manager := oidc4vp.NewTxManager(NewMockTxNonceStore(gomock.NewController(t)),
+			NewMockTxStore(gomock.NewController(t)), claimsStore, NewMockDataProtector(gomock.NewController(t)),
+			testutil.DocumentLoader(t))
+
+		_, err := manager.GetByClaimsID(context.Background(), "claimsID")
+		require.ErrorIs(t, err, oidc4vp.ErrDataNotFound)
+	})
+
+	t.Run("Claims store error", func(t *testing.T) {
+		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
+		claimsStore.EXPECT().Get(gomock.Any(), "claimsID").Return(nil, errors.New("store error"))
+
+		manager := oidc4vp.NewTxManager(NewMockTxNonceStore(gomock.NewController(t)),
+			NewMockTxStore(gomock.NewController(t)), claimsStore, NewMockDataProtector(gomock.NewController(t)),
+			testutil.DocumentLoader(t))
+
+		_, err := manager.GetByClaimsID(context.Background(), "claimsID")
+		require.ErrorContains(t, err, "find received claims: store error")
+	})
+
+	t.Run("Claims not bound to tx", func(t *testing.T) {
+		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
+		claimsStore.EXPECT().Get(gomock.Any(), "claimsID").Return(&oidc4vp.ClaimData{}, nil)
+
+		manager := oidc4vp.NewTxManager(NewMockTxNonceStore(gomock.NewController(t)),
+			NewMockTxStore(gomock.NewController(t)), claimsStore, NewMockDataProtector(gomock.NewController(t)),
+			testutil.DocumentLoader(t))
+
+		_, err := manager.GetByClaimsID(context.Background(), "claimsID")
+		require.ErrorContains(t, err, "are not bound to a transaction")
+	})
+
+	t.Run("Tx references other claims", func(t *testing.T) {
+		store := NewMockTxStore(gomock.NewController(t))
+		store.EXPECT().Get(gomock.Any(), oidc4vp.TxID("txID")).Return(&oidc4vp.Transaction{ID: "txID"}, nil)
+
+		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
+		claimsStore.EXPECT().Get(gomock.Any(), "claimsID").Return(&oidc4vp.ClaimData{TxID: "txID"}, nil)
+
+		manager := oidc4vp.NewTxManager(NewMockTxNonceStore(gomock.NewController(t)), store, claimsStore,
+			NewMockDataProtector(gomock.NewController(t)), testutil.DocumentLoader(t))
+
+		_, err := manager.GetByClaimsID(context.Background(), "claimsID")
+		require.ErrorContains(t, err, "does not reference received claims")
+	})
+}
+
+func TestTxManager_UpdateTx(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Hour)
+		purpose := "new purpose"
+		pd := &presexch.PresentationDefinition{ID: "amended"}
+
+		store := NewMockTxStore(gomock.NewController(t))
+		store.EXPECT().Update(gomock.Any(), oidc4vp.TransactionUpdate{
+			ID:                     "txID",
+			ExpiresAt:              &expiresAt,
+			PresentationDefinition: pd,
+			Purpose:                &purpose,
+		}).Return(nil)
+
+		manager := oidc4vp.NewTxManager(NewMockTxNonceStore(gomock.NewController(t)), store,
+			NewMockTxClaimsStore(gomock.NewController(t)), NewMockDataProtector(gomock.NewController(t)),
+			testutil.DocumentLoader(t))
+
+		err := manager.UpdateTx(context.Background(), "txID", &oidc4vp.TransactionUpdate{
+			ID:                     "otherTxID",
+			ReceivedClaimsID:       "claimsID",
+			ExpiresAt:              &expiresAt,
+			PresentationDefinition: pd,
+			Purpose:                &purpose,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Expiration in the past", func(t *testing.T) {
+		expiresAt := time.Now().Add(-time.Minute)
+
+		manager := oidc4vp.NewTxManager(NewMockTxNonceStore(gomock.NewController(t)),
+			NewMockTxStore(gomock.NewController(t)), NewMockTxClaimsStore(gomock.NewController(t)),
+			NewMockDataProtector(gomock.NewController(t)), testutil.DocumentLoader(t))
+
+		err := manager.UpdateTx(context.Background(), "txID", &oidc4vp.TransactionUpdate{ExpiresAt: &expiresAt})
+		require.ErrorContains(t, err, "is in the past")
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		store := NewMockTxStore(gomock.NewController(t))
+		store.EXPECT().Update(gomock.Any(), gomock.Any()).Return(oidc4vp.ErrDataNotFound)
+
+		manager := oidc4vp.NewTxManager(NewMockTxNonceStore(gomock.NewController(t)), store,
+			NewMockTxClaimsStore(gomock.NewController(t)), NewMockDataProtector(gomock.NewController(t)),
+			testutil.DocumentLoader(t))
+
+		err := manager.UpdateTx(context.Background(), "txID", &oidc4vp.TransactionUpdate{})
+		require.ErrorIs(t, err, oidc4vp.ErrDataNotFound)
+	})
+}
+
 func TestTxManagerDeleteReceivedClaims(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		store := NewMockTxStore(gomock.NewController(t))
 		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
-		claimsStore.EXPECT().Delete(gomock.Any()).Return(nil)
+		claimsStore.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
 
 		nonceStore := NewMockTxNonceStore(gomock.NewController(t))
 		crypto := NewMockDataProtector(gomock.NewController(t))
@@ -437,14 +654,14 @@ func TestTxManagerDeleteReceivedClaims(t *testing.T) {
 		manager := oidc4vp.NewTxManager(nonceStore, store, claimsStore, crypto,
 			testutil.DocumentLoader(t))
 
-		err := manager.DeleteReceivedClaims("claimsID")
+		err := manager.DeleteReceivedClaims(context.Background(), "claimsID")
 		require.NoError(t, err)
 	})
 
 	t.Run("Error", func(t *testing.T) {
 		store := NewMockTxStore(gomock.NewController(t))
 		claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
-		claimsStore.EXPECT().Delete(gomock.Any()).Return(fmt.Errorf("delete error"))
+		claimsStore.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(fmt.Errorf("delete error"))
 
 		nonceStore := NewMockTxNonceStore(gomock.NewController(t))
 		crypto := NewMockDataProtector(gomock.NewController(t))
@@ -452,12 +669,65 @@ func TestTxManagerDeleteReceivedClaims(t *testing.T) {
 		manager := oidc4vp.NewTxManager(nonceStore, store, claimsStore, crypto,
 			testutil.DocumentLoader(t))
 
-		err := manager.DeleteReceivedClaims("claimsID")
+		err := manager.DeleteReceivedClaims(context.Background(), "claimsID")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "delete error")
 	})
 }
 
+func TestTxManager_ContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	<-ctx.Done()
+
+	store := NewMockTxStore(gomock.NewController(t))
+	store.EXPECT().Create(ctx, gomock.Any(), profileID, profileVersion, "", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ *presexch.PresentationDefinition, _, _, _ string, _ time.Duration,
+			_ map[string]interface{}) (oidc4vp.TxID, *oidc4vp.Transaction, error) {
+			return "", nil, ctx.Err()
+		})
+	store.EXPECT().Get(ctx, oidc4vp.TxID("txID")).
+		DoAndReturn(func(ctx context.Context, _ oidc4vp.TxID) (*oidc4vp.Transaction, error) {
+			return nil, ctx.Err()
+		})
+
+	nonceStore := NewMockTxNonceStore(gomock.NewController(t))
+	nonceStore.EXPECT().GetAndDelete(ctx, "nonce").
+		DoAndReturn(func(ctx context.Context, _ string) (oidc4vp.TxID, bool, error) {
+			return "", false, ctx.Err()
+		})
+
+	claimsStore := NewMockTxClaimsStore(gomock.NewController(t))
+	claimsStore.EXPECT().Delete(ctx, "claimsID").
+		DoAndReturn(func(ctx context.Context, _ string) error {
+			return ctx.Err()
+		})
+
+	crypto := NewMockDataProtector(gomock.NewController(t))
+	crypto.EXPECT().Encrypt(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ []byte) (*dataprotect.EncryptedData, error) {
+			return nil, ctx.Err()
+		})
+
+	manager := oidc4vp.NewTxManager(nonceStore, store, claimsStore, crypto, testutil.DocumentLoader(t))
+
+	_, _, err := manager.CreateTx(ctx, &presexch.PresentationDefinition{}, profileID, profileVersion, "", 0, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = manager.Get(ctx, "txID")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, _, err = manager.GetByOneTimeToken(ctx, "nonce")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	err = manager.StoreReceivedClaims(ctx, "txID", &oidc4vp.ReceivedClaims{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	err = manager.DeleteReceivedClaims(ctx, "claimsID")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
 func TestClaimsToRaw(t *testing.T) {
 	t.Run("data nil", func(t *testing.T) {
 		manager := oidc4vp.NewTxManager(nil, nil, nil, nil,
@@ -492,6 +762,29 @@ func TestEncrypt(t *testing.T) {
 	})
 }
 
+func TestEncryptDecryptRawVPTokens(t *testing.T) {
+	crypto := NewMockDataProtector(gomock.NewController(t))
+	crypto.EXPECT().Encrypt(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, msg []byte) (*dataprotect.EncryptedData, error) {
+			return &dataprotect.EncryptedData{Encrypted: msg}, nil
+		})
+	crypto.EXPECT().Decrypt(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, data *dataprotect.EncryptedData) ([]byte, error) {
+			return data.Encrypted, nil
+		})
+
+	manager := oidc4vp.NewTxManager(nil, nil, nil, crypto, testutil.DocumentLoader(t))
+
+	encrypted, err := manager.EncryptClaims(context.TODO(), &oidc4vp.ReceivedClaims{
+		RawVPTokens: []string{"vp.token.1", "vp.token.2"},
+	})
+	require.NoError(t, err)
+
+	decrypted, err := manager.DecryptClaims(context.TODO(), encrypted)
+	require.NoError(t, err)
+	require.Equal(t, []string{"vp.token.1", "vp.token.2"}, decrypted.RawVPTokens)
+}
+
 func TestDecrypt(t *testing.T) {
 	t.Run("decrypt err", func(t *testing.T) {
 		crypto := NewMockDataProtector(gomock.NewController(t))
@@ -0,0 +1,47 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+// timeoutVDR bounds DID resolution of the wrapped registry. The registry API has no context, so resolution
+// keeps running in the background after the timeout; only the caller stops waiting for it.
+type timeoutVDR struct {
+	vdrapi.Registry
+	timeout time.Duration
+}
+
+func (r *timeoutVDR) Resolve(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	type result struct {
+		docRes *did.DocResolution
+		err    error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		docRes, err := r.Registry.Resolve(didID, opts...)
+		done <- result{docRes: docRes, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.docRes, res.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("resolve %s: %w", didID, ctx.Err())
+	}
+}
@@ -8,6 +8,8 @@ package requestobject
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/trustbloc/vcs/pkg/event/spi"
 )
@@ -16,6 +18,11 @@ type RequestObject struct {
 	ID                       string     `json:"id"`
 	Content                  string     `json:"content"`
 	AccessRequestObjectEvent *spi.Event `json:"accessRequestObjectEvent"`
+	ExpireAt                 time.Time  `json:"expireAt,omitempty"`
 }
 
-var ErrDataNotFound = errors.New("data not found")
+var (
+	ErrDataNotFound = errors.New("data not found")
+	// ErrObjectExpiredOrNotFound is returned when the request object doesn't exist or has expired.
+	ErrObjectExpiredOrNotFound = fmt.Errorf("request object expired or not found: %w", ErrDataNotFound)
+)
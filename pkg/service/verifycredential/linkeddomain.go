@@ -10,6 +10,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	didconfig "github.com/trustbloc/vc-go/didconfig/client"
@@ -75,7 +76,32 @@ func getServiceType(serviceType interface{}) string {
 				val = str
 			}
 		}
+	case map[string]interface{}:
+		val = getNodeType(t)
 	}
 
 	return val
 }
+
+// getNodeType returns the type of a JSON-LD typed node, i.e. its @id, falling back to the first string value
+// of the node in key order.
+func getNodeType(node map[string]interface{}) string {
+	if id, ok := node["@id"].(string); ok {
+		return id
+	}
+
+	keys := make([]string, 0, len(node))
+	for k := range node {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if str, ok := node[k].(string); ok {
+			return str
+		}
+	}
+
+	return ""
+}
@@ -310,3 +310,35 @@ func TestService_ValidateLinkedDomain(t *testing.T) {
 		})
 	}
 }
+
+func Test_getServiceType(t *testing.T) {
+	tests := []struct {
+		name        string
+		serviceType interface{}
+		want        string
+	}{
+		{name: "String", serviceType: "LinkedDomains", want: "LinkedDomains"},
+		{name: "String array", serviceType: []string{"LinkedDomains"}, want: "LinkedDomains"},
+		{name: "Interface array", serviceType: []interface{}{"LinkedDomains"}, want: "LinkedDomains"},
+		{
+			name: "Typed node",
+			serviceType: map[string]interface{}{
+				"@id":   "LinkedDomains",
+				"@type": "https://identity.foundation/.well-known/resources/did-configuration/#LinkedDomains",
+			},
+			want: "LinkedDomains",
+		},
+		{
+			name:        "Typed node without @id",
+			serviceType: map[string]interface{}{"value": "LinkedDomains", "count": 1},
+			want:        "LinkedDomains",
+		},
+		{name: "Empty typed node", serviceType: map[string]interface{}{}, want: ""},
+		{name: "Unsupported", serviceType: 1, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, getServiceType(tt.serviceType))
+		})
+	}
+}
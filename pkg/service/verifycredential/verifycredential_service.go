@@ -33,6 +33,9 @@ const (
 	revokedMsg = "revoked"
 )
 
+// ErrRevoked is returned by ValidateVCStatus when the credential is revoked.
+var ErrRevoked = errors.New(revokedMsg)
+
 type statusListVCURIResolver interface {
 	Resolve(ctx context.Context, statusListVCURL string) (*verifiable.Credential, error)
 }
@@ -243,7 +246,7 @@ func (s *Service) ValidateVCStatus(ctx context.Context, vcStatus *verifiable.Typ
 	}
 
 	if bitSet {
-		return errors.New(revokedMsg)
+		return ErrRevoked
 	}
 
 	return nil
@@ -14,10 +14,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/trustbloc/vcs/pkg/service/oidc4ci"
 )
 
+const openIDConfigurationPath = "/.well-known/openid-configuration"
+
 type httpClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
@@ -33,12 +36,33 @@ func NewService(client httpClient) *Service {
 }
 
 func (s *Service) GetOIDCConfiguration(ctx context.Context, url string) (*oidc4ci.OIDCConfiguration, error) {
+	var conf oidc4ci.OIDCConfiguration
+
+	if err := s.fetchJSON(ctx, url, &conf); err != nil {
+		return nil, err
+	}
+
+	return &conf, nil
+}
+
+// FetchWalletMetadata fetches wallet metadata from <walletIssuerURL>/.well-known/openid-configuration.
+func (s *Service) FetchWalletMetadata(ctx context.Context, walletIssuerURL string) (*oidc4ci.WalletMetadata, error) {
+	var metadata oidc4ci.WalletMetadata
+
+	if err := s.fetchJSON(ctx, strings.TrimSuffix(walletIssuerURL, "/")+openIDConfigurationPath, &metadata); err != nil {
+		return nil, fmt.Errorf("fetch wallet metadata: %w", err)
+	}
+
+	return &metadata, nil
+}
+
+func (s *Service) fetchJSON(ctx context.Context, url string, v interface{}) error {
 	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 
 	resp, err := s.client.Do(req)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	defer func() {
@@ -48,19 +72,13 @@ func (s *Service) GetOIDCConfiguration(ctx context.Context, url string) (*oidc4c
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("got unexpected status code: %v", resp.StatusCode)
+		return fmt.Errorf("got unexpected status code: %v", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var conf oidc4ci.OIDCConfiguration
-
-	if err = json.Unmarshal(body, &conf); err != nil {
-		return nil, err
-	}
-
-	return &conf, nil
+	return json.Unmarshal(body, v)
 }
@@ -95,3 +95,38 @@ func TestClientBodyErr(t *testing.T) {
 	assert.Nil(t, resp)
 	assert.ErrorContains(t, err, text)
 }
+
+func TestFetchWalletMetadata(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		httpClient := NewMockHTTPClient(gomock.NewController(t))
+		httpClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "https://wallet.example.com/.well-known/openid-configuration", req.URL.String())
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`{"issuer":"https://wallet.example.com",` +
+					`"pushed_authorization_request_endpoint":"https://wallet.example.com/par"}`)),
+			}, nil
+		})
+
+		srv := wellknown.NewService(httpClient)
+
+		resp, err := srv.FetchWalletMetadata(context.TODO(), "https://wallet.example.com/")
+		assert.NoError(t, err)
+		assert.Equal(t, "https://wallet.example.com", resp.Issuer)
+		assert.True(t, resp.SupportsPushedAuthorizationRequests())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		httpClient := NewMockHTTPClient(gomock.NewController(t))
+		httpClient.EXPECT().Do(gomock.Any()).Return(&http.Response{
+			StatusCode: http.StatusNotFound,
+		}, nil)
+
+		srv := wellknown.NewService(httpClient)
+
+		resp, err := srv.FetchWalletMetadata(context.TODO(), "https://wallet.example.com")
+		assert.Nil(t, resp)
+		assert.ErrorContains(t, err, "fetch wallet metadata: got unexpected status code: 404")
+	})
+}
@@ -75,6 +75,11 @@ func (c *Client) ContextWithTimeout() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), c.timeout)
 }
 
+// ContextWithTimeoutFrom returns a context derived from ctx that is also cancelled after the client timeout.
+func (c *Client) ContextWithTimeoutFrom(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, c.timeout)
+}
+
 func (c *Client) Close() error {
 	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
@@ -69,7 +69,7 @@ func (s *Store) migrate(ctx context.Context) error {
 	return nil
 }
 
-func (s *Store) Create(claims *oidc4vp.ClaimData) (string, error) {
+func (s *Store) Create(ctx context.Context, claims *oidc4vp.ClaimData) (string, error) {
 	var err error
 
 	doc := &mongoDocument{
@@ -77,7 +77,7 @@ func (s *Store) Create(claims *oidc4vp.ClaimData) (string, error) {
 		ClaimData: claims,
 	}
 
-	ctxWithTimeout, cancel := s.mongoClient.ContextWithTimeout()
+	ctxWithTimeout, cancel := s.mongoClient.ContextWithTimeoutFrom(ctx)
 	defer cancel()
 
 	result, err := s.mongoClient.Database().Collection(collectionName).InsertOne(ctxWithTimeout, doc)
@@ -88,7 +88,7 @@ func (s *Store) Create(claims *oidc4vp.ClaimData) (string, error) {
 	return result.InsertedID.(primitive.ObjectID).Hex(), nil
 }
 
-func (s *Store) Get(claimDataID string) (*oidc4vp.ClaimData, error) {
+func (s *Store) Get(ctx context.Context, claimDataID string) (*oidc4vp.ClaimData, error) {
 	id, err := primitive.ObjectIDFromHex(claimDataID)
 	if err != nil {
 		return nil, fmt.Errorf("parse id %s: %w", claimDataID, err)
@@ -96,7 +96,7 @@ func (s *Store) Get(claimDataID string) (*oidc4vp.ClaimData, error) {
 
 	var doc mongoDocument
 
-	ctxWithTimeout, cancel := s.mongoClient.ContextWithTimeout()
+	ctxWithTimeout, cancel := s.mongoClient.ContextWithTimeoutFrom(ctx)
 	defer cancel()
 
 	err = s.mongoClient.Database().Collection(collectionName).FindOne(ctxWithTimeout, bson.M{"_id": id}).Decode(&doc)
@@ -117,7 +117,7 @@ func (s *Store) Get(claimDataID string) (*oidc4vp.ClaimData, error) {
 }
 
 // Delete deletes claims by id.
-func (s *Store) Delete(claimDataID string) error {
+func (s *Store) Delete(ctx context.Context, claimDataID string) error {
 	id, err := primitive.ObjectIDFromHex(claimDataID)
 	if err != nil {
 		return fmt.Errorf("parse id %s: %w", claimDataID, err)
@@ -125,7 +125,7 @@ func (s *Store) Delete(claimDataID string) error {
 
 	collection := s.mongoClient.Database().Collection(collectionName)
 
-	ctxWithTimeout, cancel := s.mongoClient.ContextWithTimeout()
+	ctxWithTimeout, cancel := s.mongoClient.ContextWithTimeoutFrom(ctx)
 	defer cancel()
 
 	_, err = collection.DeleteOne(ctxWithTimeout,
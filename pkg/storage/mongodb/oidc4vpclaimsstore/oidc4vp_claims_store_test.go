@@ -58,19 +58,19 @@ func TestStore(t *testing.T) {
 			},
 		}
 
-		id, err := store.Create(receivedClaims)
+		id, err := store.Create(context.Background(), receivedClaims)
 		assert.NoError(t, err)
 
-		claimsInDB, err := store.Get(id)
+		claimsInDB, err := store.Get(context.Background(), id)
 		assert.NoError(t, err)
 		require.NotNil(t, claimsInDB)
 
 		require.Equal(t, *receivedClaims, *claimsInDB)
 
-		err = store.Delete(id)
+		err = store.Delete(context.Background(), id)
 		require.NoError(t, err)
 
-		claimsInDB, err = store.Get(id)
+		claimsInDB, err = store.Get(context.Background(), id)
 		assert.Nil(t, claimsInDB)
 		assert.ErrorIs(t, err, oidc4vp.ErrDataNotFound)
 	})
@@ -78,19 +78,19 @@ func TestStore(t *testing.T) {
 	t.Run("get non existing document", func(t *testing.T) {
 		id := primitive.NewObjectID().Hex()
 
-		resp, err := store.Get(id)
+		resp, err := store.Get(context.Background(), id)
 		assert.Nil(t, resp)
 		assert.ErrorIs(t, err, oidc4vp.ErrDataNotFound)
 	})
 
 	t.Run("get invalid document id - get", func(t *testing.T) {
-		resp, err := store.Get("invalid id")
+		resp, err := store.Get(context.Background(), "invalid id")
 		assert.Nil(t, resp)
 		assert.ErrorContains(t, err, "parse id")
 	})
 
 	t.Run("get invalid document id - delete", func(t *testing.T) {
-		err := store.Delete("invalid id")
+		err := store.Delete(context.Background(), "invalid id")
 		assert.Error(t, err)
 		assert.ErrorContains(t, err, "parse id")
 	})
@@ -106,15 +106,31 @@ func TestStore(t *testing.T) {
 			},
 		}
 
-		id, err := storeExpired.Create(receivedClaims)
+		id, err := storeExpired.Create(context.Background(), receivedClaims)
 		require.NoError(t, err)
 
 		time.Sleep(2 * time.Second)
 
-		claimsInDB, err := storeExpired.Get(id)
+		claimsInDB, err := storeExpired.Get(context.Background(), id)
 		assert.Nil(t, claimsInDB)
 		assert.ErrorIs(t, err, oidc4vp.ErrDataNotFound)
 	})
+
+	t.Run("Context deadline exceeded", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		<-ctx.Done()
+
+		_, err := store.Create(ctx, &oidc4vp.ClaimData{})
+		require.ErrorContains(t, err, context.DeadlineExceeded.Error())
+
+		_, err = store.Get(ctx, "121212121212121212121212")
+		require.ErrorContains(t, err, context.DeadlineExceeded.Error())
+
+		err = store.Delete(ctx, "121212121212121212121212")
+		require.ErrorContains(t, err, context.DeadlineExceeded.Error())
+	})
 }
 
 func TestMigrate(t *testing.T) {
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package oidc4vpnoncestore
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -69,8 +70,8 @@ func (ts *TxNonceStore) migrate() error {
 }
 
 // GetAndDelete get and then delete transaction by one time token.
-func (ts *TxNonceStore) GetAndDelete(nonce string) (oidc4vp.TxID, bool, error) {
-	ctxWithTimeout, cancel := ts.mongoClient.ContextWithTimeout()
+func (ts *TxNonceStore) GetAndDelete(ctx context.Context, nonce string) (oidc4vp.TxID, bool, error) {
+	ctxWithTimeout, cancel := ts.mongoClient.ContextWithTimeoutFrom(ctx)
 	defer cancel()
 
 	collection := ts.mongoClient.Database().Collection(nonceCollection)
@@ -79,7 +80,7 @@ func (ts *TxNonceStore) GetAndDelete(nonce string) (oidc4vp.TxID, bool, error) {
 
 	err := collection.FindOneAndDelete(ctxWithTimeout, bson.M{"_id": nonce}).Decode(doc)
 
-	if errors.Is(err, mongo.ErrNoDocuments) || doc.ExpireAt.Before(time.Now().UTC()) {
+	if errors.Is(err, mongo.ErrNoDocuments) {
 		return "", false, nil
 	}
 
@@ -87,12 +88,26 @@ func (ts *TxNonceStore) GetAndDelete(nonce string) (oidc4vp.TxID, bool, error) {
 		return "", false, fmt.Errorf("mongo find failed: %w", err)
 	}
 
+	if doc.ExpireAt.Before(time.Now().UTC()) {
+		return "", false, nil
+	}
+
 	return doc.TxID, true, nil
 }
 
 // SetIfNotExist stores transaction if key not exists et.
-func (ts *TxNonceStore) SetIfNotExist(nonce string, txID oidc4vp.TxID) (bool, error) {
-	ctxWithTimeout, cancel := ts.mongoClient.ContextWithTimeout()
+// If ttl is zero, the store's default ttl is used.
+func (ts *TxNonceStore) SetIfNotExist(
+	ctx context.Context,
+	nonce string,
+	txID oidc4vp.TxID,
+	ttl time.Duration,
+) (bool, error) {
+	if ttl == 0 {
+		ttl = ts.ttl
+	}
+
+	ctxWithTimeout, cancel := ts.mongoClient.ContextWithTimeoutFrom(ctx)
 	defer cancel()
 
 	collection := ts.mongoClient.Database().Collection(nonceCollection)
@@ -100,7 +115,7 @@ func (ts *TxNonceStore) SetIfNotExist(nonce string, txID oidc4vp.TxID) (bool, er
 	doc := &nonceDocument{
 		ID:       nonce,
 		TxID:     txID,
-		ExpireAt: time.Now().Add(ts.ttl),
+		ExpireAt: time.Now().Add(ttl),
 	}
 
 	_, err := collection.InsertOne(ctxWithTimeout, doc)
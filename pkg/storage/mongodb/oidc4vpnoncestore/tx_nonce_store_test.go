@@ -48,34 +48,34 @@ func TestTxStore_Success(t *testing.T) {
 	assert.NoError(t, err)
 
 	t.Run("Set not exist", func(t *testing.T) {
-		isSet, err := store.SetIfNotExist("key", "value")
+		isSet, err := store.SetIfNotExist(context.Background(), "key", "value", 0)
 		require.NoError(t, err)
 		require.True(t, isSet)
 	})
 
 	t.Run("Set exist", func(t *testing.T) {
-		isSet, err := store.SetIfNotExist("key2", "value")
+		isSet, err := store.SetIfNotExist(context.Background(), "key2", "value", 0)
 		require.True(t, isSet)
 		require.NoError(t, err)
 
-		isSet, err = store.SetIfNotExist("key2", "txID")
+		isSet, err = store.SetIfNotExist(context.Background(), "key2", "txID", 0)
 		require.False(t, isSet)
 		require.NoError(t, err)
 	})
 
 	t.Run("Get not exist", func(t *testing.T) {
-		_, exists, err := store.GetAndDelete("key3")
+		_, exists, err := store.GetAndDelete(context.Background(), "key3")
 
 		require.False(t, exists)
 		require.NoError(t, err)
 	})
 
 	t.Run("Get exist", func(t *testing.T) {
-		isSet, err := store.SetIfNotExist("key3", "txID")
+		isSet, err := store.SetIfNotExist(context.Background(), "key3", "txID", 0)
 		require.True(t, isSet)
 		require.NoError(t, err)
 
-		data, exists, err := store.GetAndDelete("key3")
+		data, exists, err := store.GetAndDelete(context.Background(), "key3")
 
 		require.True(t, exists)
 		require.NoError(t, err)
@@ -83,17 +83,17 @@ func TestTxStore_Success(t *testing.T) {
 	})
 
 	t.Run("Get exist and check if deleted", func(t *testing.T) {
-		isSet, err := store.SetIfNotExist("key3", "txID")
+		isSet, err := store.SetIfNotExist(context.Background(), "key3", "txID", 0)
 		require.True(t, isSet)
 		require.NoError(t, err)
 
-		data, exists, err := store.GetAndDelete("key3")
+		data, exists, err := store.GetAndDelete(context.Background(), "key3")
 
 		require.True(t, exists)
 		require.NoError(t, err)
 		require.Equal(t, oidc4vp.TxID("txID"), data)
 
-		_, exists, err = store.GetAndDelete("key3")
+		_, exists, err = store.GetAndDelete(context.Background(), "key3")
 
 		require.False(t, exists)
 		require.NoError(t, err)
@@ -103,18 +103,44 @@ func TestTxStore_Success(t *testing.T) {
 		storeExpired, err := oidc4vpnoncestore.New(client, 1)
 		require.NoError(t, err)
 
-		isSet, err := storeExpired.SetIfNotExist("key4", "txID")
+		isSet, err := storeExpired.SetIfNotExist(context.Background(), "key4", "txID", 0)
 		require.True(t, isSet)
 		require.NoError(t, err)
 
 		time.Sleep(time.Second * 2)
 
-		data, exists, err := storeExpired.GetAndDelete("key4")
+		data, exists, err := storeExpired.GetAndDelete(context.Background(), "key4")
 
 		require.False(t, exists)
 		require.NoError(t, err)
 		require.Empty(t, data)
 	})
+	t.Run("Get expired with ttl override", func(t *testing.T) {
+		isSet, err := store.SetIfNotExist(context.Background(), "key5", "txID", time.Second)
+		require.True(t, isSet)
+		require.NoError(t, err)
+
+		time.Sleep(time.Second * 2)
+
+		data, exists, err := store.GetAndDelete(context.Background(), "key5")
+
+		require.False(t, exists)
+		require.NoError(t, err)
+		require.Empty(t, data)
+	})
+
+	t.Run("Context deadline exceeded", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		<-ctx.Done()
+
+		_, err := store.SetIfNotExist(ctx, "key6", "txID", 0)
+		require.ErrorContains(t, err, context.DeadlineExceeded.Error())
+
+		_, _, err = store.GetAndDelete(ctx, "key6")
+		require.ErrorContains(t, err, context.DeadlineExceeded.Error())
+	})
 }
 
 func TestTxStore_ConnectoinFail(t *testing.T) {
@@ -28,16 +28,16 @@ const (
 )
 
 type txDocument struct {
-	ID                     primitive.ObjectID     `bson:"_id,omitempty"`
-	ProfileID              string                 `bson:"profileIDID"`
-	ProfileVersion         string                 `bson:"profileVersion"`
-	PresentationDefinition map[string]interface{} `bson:"presentationDefinition"`
-	ReceivedClaimsID       string                 `bson:"receivedClaimsID"`
-	ExpireAt               time.Time              `bson:"expire_at"`
-}
-
-type txUpdateDocument struct {
-	ReceivedClaimsID string `bson:"receivedClaimsID"`
+	ID                     primitive.ObjectID        `bson:"_id,omitempty"`
+	ProfileID              string                    `bson:"profileIDID"`
+	ProfileVersion         string                    `bson:"profileVersion"`
+	PresentationDefinition map[string]interface{}    `bson:"presentationDefinition"`
+	ReceivedClaimsID       string                    `bson:"receivedClaimsID"`
+	Purpose                string                    `bson:"purpose,omitempty"`
+	Status                 oidc4vp.TransactionStatus `bson:"status,omitempty"`
+	CustomData             map[string]interface{}    `bson:"customData,omitempty"`
+	CreatedAt              time.Time                 `bson:"createdAt"`
+	ExpireAt               time.Time                 `bson:"expire_at"`
 }
 
 // TxStore manages profile in mongodb.
@@ -80,10 +80,20 @@ func (p *TxStore) migrate(ctx context.Context) error {
 	return nil
 }
 
-// Create creates transaction document in a database.
-func (p *TxStore) Create(pd *presexch.PresentationDefinition, profileID, profileVersion string) (oidc4vp.TxID,
-	*oidc4vp.Transaction, error) {
-	ctxWithTimeout, cancel := p.mongoClient.ContextWithTimeout()
+// Create creates transaction document in a database. The transaction expires after the given ttl,
+// or after the default ttl of the store if ttl is zero.
+func (p *TxStore) Create(
+	ctx context.Context,
+	pd *presexch.PresentationDefinition,
+	profileID, profileVersion, purpose string,
+	ttl time.Duration,
+	customData map[string]interface{},
+) (oidc4vp.TxID, *oidc4vp.Transaction, error) {
+	if ttl <= 0 {
+		ttl = p.ttl
+	}
+
+	ctxWithTimeout, cancel := p.mongoClient.ContextWithTimeoutFrom(ctx)
 	defer cancel()
 
 	collection := p.mongoClient.Database().Collection(txCollection)
@@ -93,11 +103,25 @@ func (p *TxStore) Create(pd *presexch.PresentationDefinition, profileID, profile
 		return "", nil, fmt.Errorf("create tx doc: %w", err)
 	}
 
+	var customDataContent map[string]interface{}
+
+	if customData != nil {
+		customDataContent, err = mongodb.StructureToMap(customData)
+		if err != nil {
+			return "", nil, fmt.Errorf("create tx doc: custom data: %w", err)
+		}
+	}
+
+	now := time.Now()
+
 	txDoc := &txDocument{
-		ExpireAt:               time.Now().Add(p.ttl),
+		CreatedAt:              now,
+		ExpireAt:               now.Add(ttl),
 		ProfileID:              profileID,
 		ProfileVersion:         profileVersion,
+		Purpose:                purpose,
 		PresentationDefinition: pdContent,
+		CustomData:             customDataContent,
 	}
 
 	result, err := collection.InsertOne(ctxWithTimeout, txDoc)
@@ -118,8 +142,8 @@ func (p *TxStore) Create(pd *presexch.PresentationDefinition, profileID, profile
 }
 
 // Get oidc4vp.Transaction by given strID.
-func (p *TxStore) Get(strID oidc4vp.TxID) (*oidc4vp.Transaction, error) {
-	ctxWithTimeout, cancel := p.mongoClient.ContextWithTimeout()
+func (p *TxStore) Get(ctx context.Context, strID oidc4vp.TxID) (*oidc4vp.Transaction, error) {
+	ctxWithTimeout, cancel := p.mongoClient.ContextWithTimeoutFrom(ctx)
 	defer cancel()
 
 	collection := p.mongoClient.Database().Collection(txCollection)
@@ -148,8 +172,8 @@ func (p *TxStore) Get(strID oidc4vp.TxID) (*oidc4vp.Transaction, error) {
 	return txFromDocument(txDoc)
 }
 
-func (p *TxStore) Update(update oidc4vp.TransactionUpdate) error {
-	ctxWithTimeout, cancel := p.mongoClient.ContextWithTimeout()
+func (p *TxStore) Update(ctx context.Context, update oidc4vp.TransactionUpdate) error {
+	ctxWithTimeout, cancel := p.mongoClient.ContextWithTimeoutFrom(ctx)
 	defer cancel()
 
 	collection := p.mongoClient.Database().Collection(txCollection)
@@ -159,22 +183,66 @@ func (p *TxStore) Update(update oidc4vp.TransactionUpdate) error {
 		return err
 	}
 
-	//nolint: govet
-	result, err := collection.UpdateOne(ctxWithTimeout,
-		bson.D{{"_id", id}}, bson.D{{"$set", txUpdateDocument{
-			ReceivedClaimsID: update.ReceivedClaimsID,
-		}}})
+	set, err := txUpdateFields(update)
+	if err != nil {
+		return err
+	}
+
+	var matched int64
+
+	if len(set) == 0 {
+		matched, err = collection.CountDocuments(ctxWithTimeout, bson.M{"_id": id})
+	} else {
+		var result *mongo.UpdateResult
+
+		result, err = collection.UpdateOne(ctxWithTimeout, bson.M{"_id": id}, bson.M{"$set": set})
+		if result != nil {
+			matched = result.MatchedCount
+		}
+	}
+
 	if err != nil {
 		return err
 	}
 
-	if result.MatchedCount == 0 {
+	if matched == 0 {
 		return fmt.Errorf("profile with given id not found")
 	}
 
 	return nil
 }
 
+func txUpdateFields(update oidc4vp.TransactionUpdate) (bson.M, error) {
+	set := bson.M{}
+
+	if update.ReceivedClaimsID != "" {
+		set["receivedClaimsID"] = update.ReceivedClaimsID
+	}
+
+	if update.PresentationDefinition != nil {
+		pdContent, err := mongodb.StructureToMap(update.PresentationDefinition)
+		if err != nil {
+			return nil, fmt.Errorf("update tx doc: %w", err)
+		}
+
+		set["presentationDefinition"] = pdContent
+	}
+
+	if update.Purpose != nil {
+		set["purpose"] = *update.Purpose
+	}
+
+	if update.ExpiresAt != nil {
+		set["expire_at"] = *update.ExpiresAt
+	}
+
+	if update.Status != nil {
+		set["status"] = *update.Status
+	}
+
+	return set, nil
+}
+
 func txIDFromString(strID oidc4vp.TxID) (primitive.ObjectID, error) {
 	if strID == "" {
 		return primitive.NilObjectID, nil
@@ -196,11 +264,25 @@ func txFromDocument(txDoc *txDocument) (*oidc4vp.Transaction, error) {
 		return nil, fmt.Errorf("oidc4vp tx manager: pd deserialization failed: %w", err)
 	}
 
+	var customData map[string]interface{}
+
+	if txDoc.CustomData != nil {
+		// round-trip through json to replace bson-specific types (e.g. primitive.A) with plain go types
+		if err = mongodb.MapToStructure(txDoc.CustomData, &customData); err != nil {
+			return nil, fmt.Errorf("oidc4vp tx manager: custom data deserialization failed: %w", err)
+		}
+	}
+
 	return &oidc4vp.Transaction{
 		ID:                     oidc4vp.TxID(txDoc.ID.Hex()),
 		ProfileID:              txDoc.ProfileID,
 		ProfileVersion:         txDoc.ProfileVersion,
 		PresentationDefinition: pd,
 		ReceivedClaimsID:       txDoc.ReceivedClaimsID,
+		Purpose:                txDoc.Purpose,
+		Status:                 txDoc.Status,
+		CustomData:             customData,
+		CreatedAt:              txDoc.CreatedAt,
+		ExpiresAt:              txDoc.ExpireAt,
 	}, nil
 }
@@ -60,39 +60,122 @@ func TestTxStore_Success(t *testing.T) {
 	}()
 
 	t.Run("Create tx", func(t *testing.T) {
-		id, _, err := store.Create(&presexch.PresentationDefinition{}, profileID, profileVersion)
+		id, _, err := store.Create(context.Background(),
+			&presexch.PresentationDefinition{}, profileID, profileVersion, "", 0, nil)
 		require.NoError(t, err)
 		require.NotNil(t, id)
 	})
 
 	t.Run("Create tx then Get by id", func(t *testing.T) {
-		id, _, err := store.Create(&presexch.PresentationDefinition{}, profileID, profileVersion)
+		id, _, err := store.Create(context.Background(),
+			&presexch.PresentationDefinition{}, profileID, profileVersion, "", 0, nil)
 
 		require.NoError(t, err)
 		require.NotNil(t, id)
 
-		tx, err := store.Get(id)
+		tx, err := store.Get(context.Background(), id)
 		require.NoError(t, err)
 		require.NotNil(t, tx)
+		require.False(t, tx.CreatedAt.IsZero())
+		require.True(t, tx.ExpiresAt.After(tx.CreatedAt))
+	})
+
+	t.Run("Create tx with purpose and ttl", func(t *testing.T) {
+		id, _, err := store.Create(context.Background(),
+			&presexch.PresentationDefinition{}, profileID, profileVersion, "purpose", time.Minute, nil)
+		require.NoError(t, err)
+
+		tx, err := store.Get(context.Background(), id)
+		require.NoError(t, err)
+		require.Equal(t, "purpose", tx.Purpose)
+		require.WithinDuration(t, tx.CreatedAt.Add(time.Minute), tx.ExpiresAt, time.Second)
+	})
+
+	t.Run("Create tx with custom data then Get by id", func(t *testing.T) {
+		customData := map[string]interface{}{
+			"sessionID": "session-1",
+			"user": map[string]interface{}{
+				"ref":   "user-1",
+				"roles": []interface{}{"admin", "auditor"},
+				"age":   float64(42),
+			},
+		}
+
+		id, _, err := store.Create(context.Background(),
+			&presexch.PresentationDefinition{}, profileID, profileVersion, "", 0, customData)
+		require.NoError(t, err)
+
+		tx, err := store.Get(context.Background(), id)
+		require.NoError(t, err)
+		require.Equal(t, customData, tx.CustomData)
 	})
 
 	t.Run("Create tx then update with received claims ID", func(t *testing.T) {
-		id, _, err := store.Create(&presexch.PresentationDefinition{}, profileID, profileVersion)
+		id, _, err := store.Create(context.Background(),
+			&presexch.PresentationDefinition{}, profileID, profileVersion, "", 0, nil)
 
 		require.NoError(t, err)
 		require.NotNil(t, id)
 
-		err = store.Update(oidc4vp.TransactionUpdate{
+		err = store.Update(context.Background(), oidc4vp.TransactionUpdate{
 			ID:               id,
 			ReceivedClaimsID: receivedClaimsID,
 		})
 		require.NoError(t, err)
 
-		tx, err := store.Get(id)
+		tx, err := store.Get(context.Background(), id)
 		require.NoError(t, err)
 		require.NotNil(t, tx)
 		require.Nil(t, tx.ReceivedClaims)
 	})
+
+	t.Run("Create tx then amend", func(t *testing.T) {
+		id, _, err := store.Create(context.Background(),
+			&presexch.PresentationDefinition{ID: "test"}, profileID, profileVersion, "", 0, nil)
+		require.NoError(t, err)
+
+		err = store.Update(context.Background(), oidc4vp.TransactionUpdate{
+			ID:               id,
+			ReceivedClaimsID: receivedClaimsID,
+		})
+		require.NoError(t, err)
+
+		purpose := "amended purpose"
+		expiresAt := time.Now().Add(time.Hour)
+		status := oidc4vp.TransactionStatusFailed
+
+		err = store.Update(context.Background(), oidc4vp.TransactionUpdate{
+			ID:                     id,
+			ExpiresAt:              &expiresAt,
+			PresentationDefinition: &presexch.PresentationDefinition{ID: "amended"},
+			Purpose:                &purpose,
+			Status:                 &status,
+		})
+		require.NoError(t, err)
+
+		tx, err := store.Get(context.Background(), id)
+		require.NoError(t, err)
+		require.Equal(t, "amended", tx.PresentationDefinition.ID)
+		require.Equal(t, purpose, tx.Purpose)
+		require.Equal(t, status, tx.Status)
+		require.Equal(t, receivedClaimsID, tx.ReceivedClaimsID)
+	})
+
+	t.Run("Context deadline exceeded", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		<-ctx.Done()
+
+		_, _, err := store.Create(ctx, &presexch.PresentationDefinition{}, profileID, profileVersion, "", 0, nil)
+		require.ErrorContains(t, err, context.DeadlineExceeded.Error())
+
+		_, err = store.Get(ctx, "121212121212121212121212")
+		require.ErrorContains(t, err, context.DeadlineExceeded.Error())
+
+		err = store.Update(ctx, oidc4vp.TransactionUpdate{ID: "121212121212121212121212"})
+		require.ErrorContains(t, err, context.DeadlineExceeded.Error())
+	})
 }
 
 func TestTxStore_Fails(t *testing.T) {
@@ -113,36 +196,36 @@ func TestTxStore_Fails(t *testing.T) {
 	}()
 
 	t.Run("Get invalid tx id", func(t *testing.T) {
-		_, err := store.Get("invalid")
+		_, err := store.Get(context.Background(), "invalid")
 		require.Contains(t, err.Error(), "tx invalid id")
 	})
 
 	t.Run("Get empty tx id", func(t *testing.T) {
-		_, err := store.Get("")
+		_, err := store.Get(context.Background(), "")
 		require.Contains(t, err.Error(), oidc4vp.ErrDataNotFound.Error())
 	})
 
 	t.Run("Get not existing tx id", func(t *testing.T) {
-		_, err := store.Get("121212121212121212121212")
+		_, err := store.Get(context.Background(), "121212121212121212121212")
 		require.EqualError(t, err, oidc4vp.ErrDataNotFound.Error())
 	})
 
 	t.Run("Get update tx id", func(t *testing.T) {
-		err := store.Update(oidc4vp.TransactionUpdate{
+		err := store.Update(context.Background(), oidc4vp.TransactionUpdate{
 			ID: "invalid",
 		})
 		require.Contains(t, err.Error(), "tx invalid id")
 	})
 
 	t.Run("Get empty tx id", func(t *testing.T) {
-		err := store.Update(oidc4vp.TransactionUpdate{
+		err := store.Update(context.Background(), oidc4vp.TransactionUpdate{
 			ID: "",
 		})
 		require.Contains(t, err.Error(), "profile with given id not found")
 	})
 
 	t.Run("Get not existing tx id", func(t *testing.T) {
-		err := store.Update(oidc4vp.TransactionUpdate{
+		err := store.Update(context.Background(), oidc4vp.TransactionUpdate{
 			ID: "121212121212121212121212",
 		})
 		require.EqualError(t, err, "profile with given id not found")
@@ -163,13 +246,14 @@ func TestTxStore_Fails(t *testing.T) {
 		storeExpired, err := NewTxStore(context.Background(), client, testutil.DocumentLoader(t), 1)
 		require.NoError(t, err)
 
-		id, _, err := storeExpired.Create(&presexch.PresentationDefinition{}, profileID, profileVersion)
+		id, _, err := storeExpired.Create(context.Background(),
+			&presexch.PresentationDefinition{}, profileID, profileVersion, "", 0, nil)
 		require.NoError(t, err)
 		require.NotNil(t, id)
 
 		time.Sleep(2 * time.Second)
 
-		tx, err := storeExpired.Get(id)
+		tx, err := storeExpired.Get(context.Background(), id)
 		require.Nil(t, tx)
 		require.ErrorIs(t, err, oidc4vp.ErrDataNotFound)
 	})
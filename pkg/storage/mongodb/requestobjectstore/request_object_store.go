@@ -10,6 +10,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -33,6 +34,7 @@ type mongoDocument struct {
 	ID                       primitive.ObjectID     `bson:"_id,omitempty"`
 	Content                  string                 `bson:"content"`
 	AccessRequestObjectEvent map[string]interface{} `bson:"accessRequestObjectEvent"`
+	ExpireAt                 time.Time              `bson:"expireAt,omitempty"`
 }
 
 // NewStore creates Store.
@@ -56,6 +58,7 @@ func (p *Store) Create(
 		ID:                       primitive.ObjectID{},
 		Content:                  request.Content,
 		AccessRequestObjectEvent: event,
+		ExpireAt:                 request.ExpireAt,
 	}
 
 	result, err := collection.InsertOne(ctx, obj)
@@ -105,6 +108,7 @@ func (p *Store) Find(
 		ID:                       txDoc.ID.Hex(),
 		Content:                  txDoc.Content,
 		AccessRequestObjectEvent: event,
+		ExpireAt:                 txDoc.ExpireAt,
 	}, nil
 }
 
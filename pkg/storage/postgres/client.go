@@ -0,0 +1,82 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/lib/pq" // registers the postgres driver
+)
+
+const (
+	driverName     = "postgres"
+	defaultTimeout = 15 * time.Second
+)
+
+type clientOpts struct {
+	timeout time.Duration
+}
+
+type ClientOpt func(opts *clientOpts)
+
+func WithTimeout(timeout time.Duration) ClientOpt {
+	return func(opts *clientOpts) {
+		opts.timeout = timeout
+	}
+}
+
+// Client is a PostgreSQL client.
+type Client struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// New opens the PostgreSQL database with the given connection string and checks the connection.
+func New(connString string, opts ...ClientOpt) (*Client, error) {
+	opt := &clientOpts{
+		timeout: defaultTimeout,
+	}
+
+	for _, f := range opts {
+		f(opt)
+	}
+
+	db, err := sql.Open(driverName, connString)
+	if err != nil {
+		return nil, fmt.Errorf("open postgres database: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opt.timeout)
+	defer cancel()
+
+	if err = db.PingContext(ctx); err != nil {
+		_ = db.Close() //nolint:errcheck
+
+		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+
+	return &Client{
+		db:      db,
+		timeout: opt.timeout,
+	}, nil
+}
+
+// ContextWithTimeoutFrom returns a context derived from ctx that is also cancelled after the client timeout.
+func (c *Client) ContextWithTimeoutFrom(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+func (c *Client) DB() *sql.DB {
+	return c.db
+}
+
+func (c *Client) Close() error {
+	return c.db.Close()
+}
@@ -0,0 +1,9 @@
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+CREATE TABLE IF NOT EXISTS received_claims (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    encrypted_data BYTEA       NOT NULL,
+    expire_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS received_claims_expire_at_idx ON received_claims (expire_at);
@@ -0,0 +1,168 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc4vpclaimsstore
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trustbloc/vcs/pkg/service/oidc4vp"
+	"github.com/trustbloc/vcs/pkg/storage/postgres"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Store stores claim data with expiration in the received_claims table. The serialized claim data is encrypted
+// with pgcrypto's pgp_sym_encrypt, so that the claims are not readable from the table or its backups without
+// the encryption key.
+type Store struct {
+	client        *postgres.Client
+	encryptionKey string
+	ttl           time.Duration
+}
+
+// New creates presentation claims store and applies the migration scripts.
+func New(
+	ctx context.Context,
+	client *postgres.Client,
+	encryptionKey string,
+	ttl int32,
+) (*Store, error) {
+	if encryptionKey == "" {
+		return nil, errors.New("encryption key must be set")
+	}
+
+	s := &Store{
+		client:        client,
+		encryptionKey: encryptionKey,
+		ttl:           time.Duration(ttl) * time.Second,
+	}
+
+	if err := s.migrate(ctx); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Store) migrate(ctx context.Context) error {
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return fmt.Errorf("list migrations: %w", err)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		script, readErr := migrations.ReadFile(name)
+		if readErr != nil {
+			return fmt.Errorf("read migration %s: %w", name, readErr)
+		}
+
+		if _, err = s.client.DB().ExecContext(ctx, string(script)); err != nil {
+			return fmt.Errorf("apply migration %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Create stores claims and returns their id. Expired claims are purged on each call.
+func (s *Store) Create(ctx context.Context, claims *oidc4vp.ClaimData) (string, error) {
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("marshal received claims data: %w", err)
+	}
+
+	ctxWithTimeout, cancel := s.client.ContextWithTimeoutFrom(ctx)
+	defer cancel()
+
+	if _, err = s.client.DB().ExecContext(ctxWithTimeout,
+		`DELETE FROM received_claims WHERE expire_at < now()`); err != nil {
+		return "", fmt.Errorf("purge expired received claims data: %w", err)
+	}
+
+	var id string
+
+	err = s.client.DB().QueryRowContext(ctxWithTimeout,
+		`INSERT INTO received_claims (encrypted_data, expire_at) VALUES (pgp_sym_encrypt($1, $2), $3) RETURNING id`,
+		string(data), s.encryptionKey, time.Now().Add(s.ttl).UTC(),
+	).Scan(&id)
+	if err != nil {
+		return "", fmt.Errorf("insert received claims data: %w", err)
+	}
+
+	return id, nil
+}
+
+// Get returns claims by id. oidc4vp.ErrDataNotFound is returned if claims do not exist or have expired.
+func (s *Store) Get(ctx context.Context, claimDataID string) (*oidc4vp.ClaimData, error) {
+	id, err := uuid.Parse(claimDataID)
+	if err != nil {
+		return nil, fmt.Errorf("parse id %s: %w", claimDataID, err)
+	}
+
+	ctxWithTimeout, cancel := s.client.ContextWithTimeoutFrom(ctx)
+	defer cancel()
+
+	var (
+		data     string
+		expireAt time.Time
+	)
+
+	err = s.client.DB().QueryRowContext(ctxWithTimeout,
+		`SELECT pgp_sym_decrypt(encrypted_data, $2), expire_at FROM received_claims WHERE id = $1`,
+		id.String(), s.encryptionKey,
+	).Scan(&data, &expireAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, oidc4vp.ErrDataNotFound
+		}
+
+		return nil, fmt.Errorf("find: %w", err)
+	}
+
+	if expireAt.Before(time.Now().UTC()) {
+		return nil, oidc4vp.ErrDataNotFound
+	}
+
+	claims := &oidc4vp.ClaimData{}
+
+	if err = json.Unmarshal([]byte(data), claims); err != nil {
+		return nil, fmt.Errorf("unmarshal received claims data: %w", err)
+	}
+
+	return claims, nil
+}
+
+// Delete deletes claims by id.
+func (s *Store) Delete(ctx context.Context, claimDataID string) error {
+	id, err := uuid.Parse(claimDataID)
+	if err != nil {
+		return fmt.Errorf("parse id %s: %w", claimDataID, err)
+	}
+
+	ctxWithTimeout, cancel := s.client.ContextWithTimeoutFrom(ctx)
+	defer cancel()
+
+	if _, err = s.client.DB().ExecContext(ctxWithTimeout,
+		`DELETE FROM received_claims WHERE id = $1`, id.String()); err != nil {
+		return fmt.Errorf("failed to delete received claims with id[%s]: %w", id, err)
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package oidc4vpclaimsstore
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -37,13 +38,13 @@ func New(redisClient *redis.Client, ttlSec int32) *Store {
 	}
 }
 
-func (s *Store) Create(claims *oidc4vp.ClaimData) (string, error) {
+func (s *Store) Create(ctx context.Context, claims *oidc4vp.ClaimData) (string, error) {
 	doc := &claimDataDocument{
 		ExpireAt:  time.Now().Add(s.ttl),
 		ClaimData: claims,
 	}
 
-	ctxWithTimeout, cancel := s.redisClient.ContextWithTimeout()
+	ctxWithTimeout, cancel := s.redisClient.ContextWithTimeoutFrom(ctx)
 	defer cancel()
 
 	key := resolveRedisKey(uuid.NewString())
@@ -55,8 +56,8 @@ func (s *Store) Create(claims *oidc4vp.ClaimData) (string, error) {
 	return key, nil
 }
 
-func (s *Store) Get(claimDataID string) (*oidc4vp.ClaimData, error) {
-	ctxWithTimeout, cancel := s.redisClient.ContextWithTimeout()
+func (s *Store) Get(ctx context.Context, claimDataID string) (*oidc4vp.ClaimData, error) {
+	ctxWithTimeout, cancel := s.redisClient.ContextWithTimeoutFrom(ctx)
 	defer cancel()
 
 	b, err := s.redisClient.API().Get(ctxWithTimeout, claimDataID).Bytes()
@@ -80,8 +81,8 @@ func (s *Store) Get(claimDataID string) (*oidc4vp.ClaimData, error) {
 	return doc.ClaimData, nil
 }
 
-func (s *Store) Delete(claimDataID string) error {
-	ctxWithTimeout, cancel := s.redisClient.ContextWithTimeout()
+func (s *Store) Delete(ctx context.Context, claimDataID string) error {
+	ctxWithTimeout, cancel := s.redisClient.ContextWithTimeoutFrom(ctx)
 	defer cancel()
 
 	err := s.redisClient.API().Del(ctxWithTimeout, claimDataID).Err()
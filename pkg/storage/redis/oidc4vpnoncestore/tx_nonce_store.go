@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package oidc4vpnoncestore
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -22,6 +23,16 @@ const (
 	keyPrefix = "oidc4vpnonce"
 )
 
+// getAndDeleteScript gets and deletes the key in a single atomic step, so that a nonce can be used only once
+// even if the same nonce is presented concurrently.
+var getAndDeleteScript = redisapi.NewScript(`
+local value = redis.call("GET", KEYS[1])
+if value then
+	redis.call("DEL", KEYS[1])
+end
+return value
+`)
+
 // TxNonceStore stores oidc transactions in redis.
 type TxNonceStore struct {
 	redisClient *redis.Client
@@ -36,29 +47,24 @@ func New(redisClient *redis.Client, ttlSec int32) *TxNonceStore {
 	}
 }
 
-// GetAndDelete get and then delete transaction by one time token.
-func (ts *TxNonceStore) GetAndDelete(nonce string) (oidc4vp.TxID, bool, error) {
-	ctxWithTimeout, cancel := ts.redisClient.ContextWithTimeout()
+// GetAndDelete atomically gets and deletes transaction by one time token.
+func (ts *TxNonceStore) GetAndDelete(ctx context.Context, nonce string) (oidc4vp.TxID, bool, error) {
+	ctxWithTimeout, cancel := ts.redisClient.ContextWithTimeoutFrom(ctx)
 	defer cancel()
 
 	key := resolveRedisKey(nonce)
-	clientAPI := ts.redisClient.API()
 
-	b, err := clientAPI.Get(ctxWithTimeout, key).Bytes()
+	value, err := getAndDeleteScript.Run(ctxWithTimeout, ts.redisClient.API(), []string{key}).Text()
 	if err != nil {
 		if errors.Is(err, redisapi.Nil) {
 			return "", false, nil
 		}
 
-		return "", false, fmt.Errorf("tx find failed: %w", err)
-	}
-
-	if err = clientAPI.Del(ctxWithTimeout, key).Err(); err != nil {
-		return "", false, fmt.Errorf("tx delete failed: %w", err)
+		return "", false, fmt.Errorf("tx get and delete failed: %w", err)
 	}
 
 	doc := &nonceDocument{}
-	if err = json.Unmarshal(b, &doc); err != nil {
+	if err = json.Unmarshal([]byte(value), &doc); err != nil {
 		return "", false, fmt.Errorf("tx decode failed: %w", err)
 	}
 
@@ -70,15 +76,25 @@ func (ts *TxNonceStore) GetAndDelete(nonce string) (oidc4vp.TxID, bool, error) {
 }
 
 // SetIfNotExist stores transaction if key not exists et.
-func (ts *TxNonceStore) SetIfNotExist(nonce string, txID oidc4vp.TxID) (bool, error) {
-	ctxWithTimeout, cancel := ts.redisClient.ContextWithTimeout()
+// If ttl is zero, the store's default ttl is used.
+func (ts *TxNonceStore) SetIfNotExist(
+	ctx context.Context,
+	nonce string,
+	txID oidc4vp.TxID,
+	ttl time.Duration,
+) (bool, error) {
+	if ttl == 0 {
+		ttl = ts.ttl
+	}
+
+	ctxWithTimeout, cancel := ts.redisClient.ContextWithTimeoutFrom(ctx)
 	defer cancel()
 
 	clientAPI := ts.redisClient.API()
 
 	doc := &nonceDocument{
 		TxID:     txID,
-		ExpireAt: time.Now().Add(ts.ttl),
+		ExpireAt: time.Now().Add(ttl),
 	}
 
 	key := resolveRedisKey(nonce)
@@ -92,7 +108,7 @@ func (ts *TxNonceStore) SetIfNotExist(nonce string, txID oidc4vp.TxID) (bool, er
 		return false, nil
 	}
 
-	if err = clientAPI.Set(ctxWithTimeout, key, doc, ts.ttl).Err(); err != nil {
+	if err = clientAPI.Set(ctxWithTimeout, key, doc, ttl).Err(); err != nil {
 		return false, fmt.Errorf("tx set: %w", err)
 	}
 
@@ -8,6 +8,8 @@ package oidc4vpnoncestore_test
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -42,34 +44,34 @@ func TestTxStore_Success(t *testing.T) {
 	store := oidc4vpnoncestore.New(client, defaultTTL)
 
 	t.Run("Set not exist", func(t *testing.T) {
-		isSet, err := store.SetIfNotExist("key", "value")
+		isSet, err := store.SetIfNotExist(context.Background(), "key", "value", 0)
 		require.NoError(t, err)
 		require.True(t, isSet)
 	})
 
 	t.Run("Set exist", func(t *testing.T) {
-		isSet, err := store.SetIfNotExist("key2", "value")
+		isSet, err := store.SetIfNotExist(context.Background(), "key2", "value", 0)
 		require.True(t, isSet)
 		require.NoError(t, err)
 
-		isSet, err = store.SetIfNotExist("key2", "txID")
+		isSet, err = store.SetIfNotExist(context.Background(), "key2", "txID", 0)
 		require.False(t, isSet)
 		require.NoError(t, err)
 	})
 
 	t.Run("Get not exist", func(t *testing.T) {
-		_, exists, err := store.GetAndDelete("key3")
+		_, exists, err := store.GetAndDelete(context.Background(), "key3")
 
 		require.False(t, exists)
 		require.NoError(t, err)
 	})
 
 	t.Run("Get exist", func(t *testing.T) {
-		isSet, err := store.SetIfNotExist("key3", "txID")
+		isSet, err := store.SetIfNotExist(context.Background(), "key3", "txID", 0)
 		require.True(t, isSet)
 		require.NoError(t, err)
 
-		data, exists, err := store.GetAndDelete("key3")
+		data, exists, err := store.GetAndDelete(context.Background(), "key3")
 
 		require.True(t, exists)
 		require.NoError(t, err)
@@ -77,37 +79,95 @@ func TestTxStore_Success(t *testing.T) {
 	})
 
 	t.Run("Get exist and check if deleted", func(t *testing.T) {
-		isSet, err := store.SetIfNotExist("key3", "txID")
+		isSet, err := store.SetIfNotExist(context.Background(), "key3", "txID", 0)
 		require.True(t, isSet)
 		require.NoError(t, err)
 
-		data, exists, err := store.GetAndDelete("key3")
+		data, exists, err := store.GetAndDelete(context.Background(), "key3")
 
 		require.True(t, exists)
 		require.NoError(t, err)
 		require.Equal(t, oidc4vp.TxID("txID"), data)
 
-		_, exists, err = store.GetAndDelete("key3")
+		_, exists, err = store.GetAndDelete(context.Background(), "key3")
 
 		require.False(t, exists)
 		require.NoError(t, err)
 	})
 
+	t.Run("Concurrent get and delete returns nonce once", func(t *testing.T) {
+		isSet, err := store.SetIfNotExist(context.Background(), "key7", "txID", 0)
+		require.True(t, isSet)
+		require.NoError(t, err)
+
+		const workers = 20
+
+		var (
+			wg    sync.WaitGroup
+			found atomic.Int32
+		)
+
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				_, exists, getErr := store.GetAndDelete(context.Background(), "key7")
+				assert.NoError(t, getErr)
+
+				if exists {
+					found.Add(1)
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		require.EqualValues(t, 1, found.Load())
+	})
+
 	t.Run("Get expired", func(t *testing.T) {
 		storeExpired := oidc4vpnoncestore.New(client, 1)
 
-		isSet, err := storeExpired.SetIfNotExist("key4", "txID")
+		isSet, err := storeExpired.SetIfNotExist(context.Background(), "key4", "txID", 0)
 		require.True(t, isSet)
 		require.NoError(t, err)
 
 		time.Sleep(time.Second * 2)
 
-		data, exists, err := storeExpired.GetAndDelete("key4")
+		data, exists, err := storeExpired.GetAndDelete(context.Background(), "key4")
 
 		require.False(t, exists)
 		require.NoError(t, err)
 		require.Empty(t, data)
 	})
+	t.Run("Get expired with ttl override", func(t *testing.T) {
+		isSet, err := store.SetIfNotExist(context.Background(), "key5", "txID", time.Second)
+		require.True(t, isSet)
+		require.NoError(t, err)
+
+		time.Sleep(time.Second * 2)
+
+		data, exists, err := store.GetAndDelete(context.Background(), "key5")
+
+		require.False(t, exists)
+		require.NoError(t, err)
+		require.Empty(t, data)
+	})
+
+	t.Run("Context deadline exceeded", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		<-ctx.Done()
+
+		_, err := store.SetIfNotExist(ctx, "key6", "txID", 0)
+		require.ErrorContains(t, err, context.DeadlineExceeded.Error())
+
+		_, _, err = store.GetAndDelete(ctx, "key6")
+		require.ErrorContains(t, err, context.DeadlineExceeded.Error())
+	})
 }
 
 func waitForRedisToBeUp() error {
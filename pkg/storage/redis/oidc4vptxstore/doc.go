@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/trustbloc/vc-go/presexch"
+
+	"github.com/trustbloc/vcs/pkg/service/oidc4vp"
 )
 
 type txDocument struct {
@@ -18,6 +20,10 @@ type txDocument struct {
 	ProfileVersion         string                           `json:"profileVersion"`
 	ReceivedClaimsID       string                           `json:"receivedClaimsId,omitempty"`
 	PresentationDefinition *presexch.PresentationDefinition `json:"presentationDefinition"`
+	Purpose                string                           `json:"purpose,omitempty"`
+	Status                 oidc4vp.TransactionStatus        `json:"status,omitempty"`
+	CustomData             map[string]interface{}           `json:"customData,omitempty"`
+	CreatedAt              time.Time                        `json:"createdAt"`
 	ExpireAt               time.Time                        `json:"expireAt"`
 }
 
@@ -45,23 +45,38 @@ func NewTxStore(
 	}
 }
 
-// Create creates transaction document in a database.
-func (p *TxStore) Create(pd *presexch.PresentationDefinition, profileID, profileVersion string) (oidc4vp.TxID,
-	*oidc4vp.Transaction, error) {
-	ctxWithTimeout, cancel := p.redisClient.ContextWithTimeout()
+// Create creates transaction document in a database. The transaction expires after the given ttl,
+// or after the default ttl of the store if ttl is zero.
+func (p *TxStore) Create(
+	ctx context.Context,
+	pd *presexch.PresentationDefinition,
+	profileID, profileVersion, purpose string,
+	ttl time.Duration,
+	customData map[string]interface{},
+) (oidc4vp.TxID, *oidc4vp.Transaction, error) {
+	if ttl <= 0 {
+		ttl = p.ttl
+	}
+
+	ctxWithTimeout, cancel := p.redisClient.ContextWithTimeoutFrom(ctx)
 	defer cancel()
 
+	now := time.Now()
+
 	txDoc := &txDocument{
-		ExpireAt:               time.Now().Add(p.ttl),
+		CreatedAt:              now,
+		ExpireAt:               now.Add(ttl),
 		ProfileID:              profileID,
 		ProfileVersion:         profileVersion,
+		Purpose:                purpose,
 		PresentationDefinition: pd,
+		CustomData:             customData,
 	}
 
 	txID := uuid.NewString()
 	key := resolveRedisKey(txID)
 
-	if err := p.redisClient.API().Set(ctxWithTimeout, key, txDoc, p.ttl).Err(); err != nil {
+	if err := p.redisClient.API().Set(ctxWithTimeout, key, txDoc, ttl).Err(); err != nil {
 		return "", nil, fmt.Errorf("tx set: %w", err)
 	}
 
@@ -71,8 +86,8 @@ func (p *TxStore) Create(pd *presexch.PresentationDefinition, profileID, profile
 }
 
 // Get profile by give id.
-func (p *TxStore) Get(strID oidc4vp.TxID) (*oidc4vp.Transaction, error) {
-	ctxWithTimeout, cancel := p.redisClient.ContextWithTimeout()
+func (p *TxStore) Get(ctx context.Context, strID oidc4vp.TxID) (*oidc4vp.Transaction, error) {
+	ctxWithTimeout, cancel := p.redisClient.ContextWithTimeoutFrom(ctx)
 	defer cancel()
 
 	doc, err := p.getTxDocument(ctxWithTimeout, strID)
@@ -108,8 +123,8 @@ func (p *TxStore) getTxDocument(ctx context.Context, strID oidc4vp.TxID) (*txDoc
 	return txDoc, nil
 }
 
-func (p *TxStore) Update(update oidc4vp.TransactionUpdate) error {
-	ctxWithTimeout, cancel := p.redisClient.ContextWithTimeout()
+func (p *TxStore) Update(ctx context.Context, update oidc4vp.TransactionUpdate) error {
+	ctxWithTimeout, cancel := p.redisClient.ContextWithTimeoutFrom(ctx)
 	defer cancel()
 
 	txDoc, err := p.getTxDocument(ctxWithTimeout, update.ID)
@@ -117,11 +132,32 @@ func (p *TxStore) Update(update oidc4vp.TransactionUpdate) error {
 		return err
 	}
 
-	txDoc.ReceivedClaimsID = update.ReceivedClaimsID
+	if update.ReceivedClaimsID != "" {
+		txDoc.ReceivedClaimsID = update.ReceivedClaimsID
+	}
+
+	if update.PresentationDefinition != nil {
+		txDoc.PresentationDefinition = update.PresentationDefinition
+	}
+
+	if update.Purpose != nil {
+		txDoc.Purpose = *update.Purpose
+	}
+
+	if update.Status != nil {
+		txDoc.Status = *update.Status
+	}
+
+	ttl := p.ttl
+
+	if update.ExpiresAt != nil {
+		txDoc.ExpireAt = *update.ExpiresAt
+		ttl = time.Until(*update.ExpiresAt)
+	}
 
 	key := resolveRedisKey(string(update.ID))
 
-	if err = p.redisClient.API().Set(ctxWithTimeout, key, txDoc, p.ttl).Err(); err != nil {
+	if err = p.redisClient.API().Set(ctxWithTimeout, key, txDoc, ttl).Err(); err != nil {
 		return fmt.Errorf("tx update: %w", err)
 	}
 
@@ -135,6 +171,11 @@ func txFromDocument(id oidc4vp.TxID, txDoc *txDocument) *oidc4vp.Transaction {
 		ProfileVersion:         txDoc.ProfileVersion,
 		PresentationDefinition: txDoc.PresentationDefinition,
 		ReceivedClaimsID:       txDoc.ReceivedClaimsID,
+		Purpose:                txDoc.Purpose,
+		Status:                 txDoc.Status,
+		CustomData:             txDoc.CustomData,
+		CreatedAt:              txDoc.CreatedAt,
+		ExpiresAt:              txDoc.ExpireAt,
 	}
 }
 
@@ -0,0 +1,113 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package requestobjectstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	redisapi "github.com/redis/go-redis/v9"
+
+	"github.com/trustbloc/vcs/pkg/service/requestobject"
+	"github.com/trustbloc/vcs/pkg/storage/redis"
+)
+
+const (
+	keyPrefix = "request_object"
+)
+
+// Store manages request objects in redis. Request objects are stored with a TTL, so redis evicts them
+// automatically once they expire.
+type Store struct {
+	redisClient *redis.Client
+	ttl         time.Duration
+}
+
+// NewStore creates Store. The ttl is used for request objects that are created without an expiration time.
+func NewStore(redisClient *redis.Client, ttl time.Duration) *Store {
+	return &Store{
+		redisClient: redisClient,
+		ttl:         ttl,
+	}
+}
+
+// Create stores the request object with SETEX until its expiration time.
+func (p *Store) Create(
+	ctx context.Context,
+	request requestobject.RequestObject,
+) (*requestobject.RequestObject, error) {
+	ttl := p.ttl
+
+	if !request.ExpireAt.IsZero() {
+		ttl = time.Until(request.ExpireAt)
+	} else {
+		request.ExpireAt = time.Now().UTC().Add(ttl)
+	}
+
+	if ttl <= 0 {
+		return nil, fmt.Errorf("request object ttl must be positive: %s", ttl)
+	}
+
+	request.ID = uuid.NewString()
+
+	b, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request object: %w", err)
+	}
+
+	if err = p.redisClient.API().SetEx(ctx, resolveRedisKey(request.ID), b, ttl).Err(); err != nil {
+		return nil, fmt.Errorf("request object set: %w", err)
+	}
+
+	return &request, nil
+}
+
+// Find returns the request object with the given id or requestobject.ErrObjectExpiredOrNotFound
+// if it doesn't exist or has been evicted.
+func (p *Store) Find(
+	ctx context.Context,
+	id string,
+) (*requestobject.RequestObject, error) {
+	b, err := p.redisClient.API().Get(ctx, resolveRedisKey(id)).Bytes()
+	if err != nil {
+		if errors.Is(err, redisapi.Nil) {
+			return nil, requestobject.ErrObjectExpiredOrNotFound
+		}
+
+		return nil, fmt.Errorf("request object get: %w", err)
+	}
+
+	request := &requestobject.RequestObject{}
+
+	if err = json.Unmarshal(b, request); err != nil {
+		return nil, fmt.Errorf("unmarshal request object: %w", err)
+	}
+
+	return request, nil
+}
+
+// Delete deletes the request object with the given id.
+func (p *Store) Delete(
+	ctx context.Context,
+	id string,
+) error {
+	return p.redisClient.API().Del(ctx, resolveRedisKey(id)).Err()
+}
+
+// GetResourceURL should return an empty string in current implementation.
+// VCS service will build own url.
+func (p *Store) GetResourceURL(_ string) string {
+	return ""
+}
+
+func resolveRedisKey(id string) string {
+	return fmt.Sprintf("%s-%s", keyPrefix, id)
+}
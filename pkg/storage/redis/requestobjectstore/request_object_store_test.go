@@ -0,0 +1,123 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package requestobjectstore_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	dctest "github.com/ory/dockertest/v3"
+	dc "github.com/ory/dockertest/v3/docker"
+	redisapi "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/vcs/pkg/event/spi"
+	"github.com/trustbloc/vcs/pkg/service/requestobject"
+	"github.com/trustbloc/vcs/pkg/storage/redis"
+	"github.com/trustbloc/vcs/pkg/storage/redis/requestobjectstore"
+)
+
+const (
+	redisConnString  = "localhost:6386"
+	dockerRedisImage = "redis"
+	dockerRedisTag   = "alpine3.17"
+)
+
+func TestStore(t *testing.T) {
+	pool, redisResource := startRedisContainer(t)
+	defer func() {
+		require.NoError(t, pool.Purge(redisResource), "failed to purge Redis resource")
+	}()
+
+	client, err := redis.New([]string{redisConnString})
+	assert.NoError(t, err)
+
+	store := requestobjectstore.NewStore(client, time.Hour)
+
+	t.Run("Create, find and delete", func(t *testing.T) {
+		event := spi.NewEventWithPayload("id", "source", spi.VerifierOIDCInteractionQRScanned, nil)
+
+		created, err := store.Create(context.Background(), requestobject.RequestObject{
+			Content:                  "request object",
+			AccessRequestObjectEvent: event,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, created.ID)
+		require.False(t, created.ExpireAt.IsZero())
+
+		found, err := store.Find(context.Background(), created.ID)
+		require.NoError(t, err)
+		require.Equal(t, "request object", found.Content)
+		require.Equal(t, event.ID, found.AccessRequestObjectEvent.ID)
+		require.Empty(t, store.GetResourceURL(created.ID))
+
+		require.NoError(t, store.Delete(context.Background(), created.ID))
+
+		_, err = store.Find(context.Background(), created.ID)
+		require.ErrorIs(t, err, requestobject.ErrObjectExpiredOrNotFound)
+		require.ErrorIs(t, err, requestobject.ErrDataNotFound)
+	})
+
+	t.Run("Evicted after expiration", func(t *testing.T) {
+		created, err := store.Create(context.Background(), requestobject.RequestObject{
+			Content:  "request object",
+			ExpireAt: time.Now().Add(time.Second),
+		})
+		require.NoError(t, err)
+
+		time.Sleep(2 * time.Second)
+
+		_, err = store.Find(context.Background(), created.ID)
+		require.ErrorIs(t, err, requestobject.ErrObjectExpiredOrNotFound)
+	})
+
+	t.Run("Already expired", func(t *testing.T) {
+		_, err := store.Create(context.Background(), requestobject.RequestObject{
+			Content:  "request object",
+			ExpireAt: time.Now().Add(-time.Second),
+		})
+		require.ErrorContains(t, err, "request object ttl must be positive")
+	})
+}
+
+func waitForRedisToBeUp() error {
+	return backoff.Retry(pingRedis, backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second), 30))
+}
+
+func pingRedis() error {
+	rdb := redisapi.NewClient(&redisapi.Options{
+		Addr: redisConnString,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return rdb.Ping(ctx).Err()
+}
+
+func startRedisContainer(t *testing.T) (*dctest.Pool, *dctest.Resource) {
+	t.Helper()
+
+	pool, err := dctest.NewPool("")
+	require.NoError(t, err)
+
+	redisResource, err := pool.RunWithOptions(&dctest.RunOptions{
+		Repository: dockerRedisImage,
+		Tag:        dockerRedisTag,
+		PortBindings: map[dc.Port][]dc.PortBinding{
+			"6379/tcp": {{HostIP: "", HostPort: "6386"}},
+		},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, waitForRedisToBeUp())
+
+	return pool, redisResource
+}
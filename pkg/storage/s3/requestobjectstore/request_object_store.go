@@ -75,12 +75,18 @@ func (p *Store) Create(
 ) (*requestobject.RequestObject, error) {
 	request.ID = uuid.NewString()
 
-	_, err := p.s3Client.PutObject(ctx, &s3.PutObjectInput{
+	input := &s3.PutObjectInput{
 		Body:        bytes.NewReader([]byte(request.Content)),
 		Key:         aws.String(request.ID),
 		Bucket:      aws.String(p.bucket),
 		ContentType: aws.String(contentType),
-	})
+	}
+
+	if !request.ExpireAt.IsZero() {
+		input.Expires = aws.Time(request.ExpireAt)
+	}
+
+	_, err := p.s3Client.PutObject(ctx, input)
 	if err != nil {
 		return nil, err
 	}
@@ -107,9 +113,15 @@ func (p *Store) Find(
 		return nil, err
 	}
 
-	return &requestobject.RequestObject{
+	ro := &requestobject.RequestObject{
 		Content: buf.String(),
-	}, nil
+	}
+
+	if res.Expires != nil {
+		ro.ExpireAt = *res.Expires
+	}
+
+	return ro, nil
 }
 
 func (p *Store) Delete(